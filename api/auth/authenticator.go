@@ -0,0 +1,408 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// PathPrefix is the path under which the login endpoints are served
+const PathPrefix = "/auth"
+
+const (
+	oauthStateCookieName = "git-bug-oauth-state"
+
+	// metaKeyOAuthSubject is the immutable identity metadata holding the
+	// provider and the user id of the identities created by an OAuth login,
+	// or linked with LinkOAuthIdentity
+	metaKeyOAuthSubject = "oauth-subject"
+)
+
+// Authenticator authenticate the requests to the web server, either with an
+// API token given in the Authorization header, or with a session opened by
+// logging in with a token or through an OAuth provider.
+//
+// The session cookie is SameSite=Lax, so it's not sent along the cross-site
+// POST requests, which protect the mutations against CSRF.
+type Authenticator struct {
+	repo     *cache.RepoCache
+	sessions *Sessions
+	oauth    *oauthProvider
+}
+
+// NewAuthenticator create an Authenticator using the tokens stored for a
+// repository, and resolving the users in it. If oauthConf is not nil, the
+// login through the OAuth provider is enabled.
+func NewAuthenticator(ctx context.Context, repo *cache.RepoCache, oauthConf *OAuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		repo:     repo,
+		sessions: NewSessions(DefaultSessionTTL),
+	}
+
+	if oauthConf != nil {
+		provider, err := newOAuthProvider(ctx, *oauthConf)
+		if err != nil {
+			return nil, fmt.Errorf("OAuth provider: %w", err)
+		}
+		a.oauth = provider
+	}
+
+	return a, nil
+}
+
+// Middleware attach the authenticated identity, if any, to the request
+// context. A request with an invalid API token is rejected, while a request
+// without credentials goes through anonymously.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get("Authorization"); header != "" {
+			token, err := a.tokenFromHeader(header)
+			switch {
+			case errors.Is(err, ErrTokenNotExist):
+				w.Header().Set("WWW-Authenticate", `Bearer realm="git-bug"`)
+				http.Error(w, "invalid API token", http.StatusUnauthorized)
+				return
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(CtxWithUser(r.Context(), token.IdentityId)))
+			return
+		}
+
+		if cookie, err := r.Cookie(SessionCookieName); err == nil {
+			if identityId, ok := a.sessions.Get(cookie.Value); ok {
+				next.ServeHTTP(w, r.WithContext(CtxWithUser(r.Context(), identityId)))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tokenFromHeader resolve the API token of an Authorization header, either
+// "Bearer <token>" or "token <token>"
+func (a *Authenticator) tokenFromHeader(header string) (*Token, error) {
+	scheme, value, ok := strings.Cut(header, " ")
+	if !ok || !(strings.EqualFold(scheme, "bearer") || strings.EqualFold(scheme, "token")) {
+		return nil, ErrTokenNotExist
+	}
+	return ResolveToken(a.repo, strings.TrimSpace(value))
+}
+
+// Handler return the http.Handler serving the login endpoints, under
+// PathPrefix:
+//   - GET  /login: a login form
+//   - POST /login: open a session with an API token, given as a form value
+//     or in a JSON body
+//   - POST /logout: close the session
+//   - GET  /user: the logged-in identity
+//   - GET  /oauth/login: start a login through the OAuth provider
+//   - GET  /oauth/callback: the OAuth redirect URL
+//
+// It must be served behind the Middleware.
+func (a *Authenticator) Handler() http.Handler {
+	router := mux.NewRouter()
+	router.Path(PathPrefix + "/login").Methods(http.MethodGet).HandlerFunc(a.loginForm)
+	router.Path(PathPrefix + "/login").Methods(http.MethodPost).HandlerFunc(a.login)
+	router.Path(PathPrefix + "/logout").Methods(http.MethodPost).HandlerFunc(a.logout)
+	router.Path(PathPrefix + "/user").Methods(http.MethodGet).HandlerFunc(a.user)
+	router.Path(PathPrefix + "/oauth/login").Methods(http.MethodGet).HandlerFunc(a.oauthLogin)
+	router.Path(PathPrefix + "/oauth/callback").Methods(http.MethodGet).HandlerFunc(a.oauthCallback)
+	return router
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>git-bug login</title></head>
+<body>
+{{if .Error}}<p>{{.Error}}</p>{{end}}
+<form method="post" action="{{.Prefix}}/login">
+  <label>API token <input type="password" name="token" autocomplete="off"></label>
+  <button type="submit">Log in</button>
+</form>
+{{if .OAuth}}<p><a href="{{.Prefix}}/oauth/login">Log in with your account</a></p>{{end}}
+</body>
+</html>
+`))
+
+func (a *Authenticator) loginForm(w http.ResponseWriter, r *http.Request) {
+	a.renderLoginForm(w, http.StatusOK, "")
+}
+
+func (a *Authenticator) renderLoginForm(w http.ResponseWriter, status int, errMsg string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = loginTemplate.Execute(w, struct {
+		Prefix string
+		OAuth  bool
+		Error  string
+	}{PathPrefix, a.oauth != nil, errMsg})
+}
+
+func (a *Authenticator) login(w http.ResponseWriter, r *http.Request) {
+	form := isForm(r)
+
+	var value string
+	if form {
+		value = r.PostFormValue("token")
+	} else {
+		var body struct {
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		value = body.Token
+	}
+
+	token, err := ResolveToken(a.repo, strings.TrimSpace(value))
+	switch {
+	case errors.Is(err, ErrTokenNotExist) && form:
+		a.renderLoginForm(w, http.StatusUnauthorized, "Invalid API token")
+		return
+	case errors.Is(err, ErrTokenNotExist):
+		http.Error(w, "invalid API token", http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	i, err := a.repo.Identities().Resolve(token.IdentityId)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("resolving the identity: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := a.openSession(w, r, i.Id()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if form {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	writeUser(w, i)
+}
+
+func (a *Authenticator) logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(SessionCookieName); err == nil {
+		a.sessions.Delete(cookie.Value)
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if isForm(r) {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Authenticator) user(w http.ResponseWriter, r *http.Request) {
+	i, err := UserFromCtx(r.Context(), a.repo)
+	switch {
+	case errors.Is(err, ErrNotAuthenticated):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeUser(w, i)
+}
+
+func (a *Authenticator) oauthLogin(w http.ResponseWriter, r *http.Request) {
+	if a.oauth == nil {
+		http.Error(w, "no OAuth provider configured", http.StatusNotFound)
+		return
+	}
+
+	state, err := randomString()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookieName,
+		Value:    state,
+		Path:     PathPrefix + "/oauth",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, a.oauth.config.AuthCodeURL(state), http.StatusFound)
+}
+
+func (a *Authenticator) oauthCallback(w http.ResponseWriter, r *http.Request) {
+	if a.oauth == nil {
+		http.Error(w, "no OAuth provider configured", http.StatusNotFound)
+		return
+	}
+
+	cookie, err := r.Cookie(oauthStateCookieName)
+	if err != nil || cookie.Value == "" || cookie.Value != r.URL.Query().Get("state") {
+		http.Error(w, "invalid OAuth state", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oauthStateCookieName, Path: PathPrefix + "/oauth", MaxAge: -1})
+
+	if msg := r.URL.Query().Get("error"); msg != "" {
+		http.Error(w, "OAuth login failed: "+msg, http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	info, err := a.oauth.fetchUser(ctx, r.URL.Query().Get("code"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("OAuth login failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	i, err := a.resolveOAuthUser(info)
+	if errors.Is(err, ErrOAuthNotAllowed) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("resolving the identity: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := a.openSession(w, r, i.Id()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// resolveOAuthUser find the identity of a user logged in through the OAuth
+// provider, if the user is allowed by the configuration: the identity linked
+// to its account of the provider, or else a new identity. The identities are
+// never matched by email, as the emails of the identities are self-declared.
+func (a *Authenticator) resolveOAuthUser(info *oauthUser) (*cache.IdentityCache, error) {
+	if !a.oauth.allowUser(info) {
+		return nil, ErrOAuthNotAllowed
+	}
+
+	subject := oauthSubject(a.oauth.issuer, info.Subject)
+
+	i, err := a.repo.Identities().ResolveIdentityImmutableMetadata(metaKeyOAuthSubject, subject)
+	if err == nil || !entity.IsErrNotFound(err) {
+		return i, err
+	}
+
+	return a.repo.Identities().NewRaw(info.Name, info.Email, info.Login, info.AvatarUrl, nil,
+		map[string]string{metaKeyOAuthSubject: subject})
+}
+
+// oauthSubject return the value of metaKeyOAuthSubject for a user id of a
+// provider
+func oauthSubject(issuer string, userId string) string {
+	return issuer + "#" + userId
+}
+
+// LinkOAuthIdentity link an existing identity to a user id of the configured
+// OAuth provider, so that this user logs in as this identity. An identity
+// can only be linked once, and to a user not linked yet.
+func LinkOAuthIdentity(repo *cache.RepoCache, conf *OAuthConfig, i *cache.IdentityCache, userId string) error {
+	if userId == "" {
+		return fmt.Errorf("empty user id")
+	}
+	if linked, ok := i.ImmutableMetadata()[metaKeyOAuthSubject]; ok {
+		return fmt.Errorf("%s is already linked to %s", i.DisplayName(), linked)
+	}
+
+	subject := oauthSubject(conf.issuerId(), userId)
+
+	other, err := repo.Identities().ResolveIdentityImmutableMetadata(metaKeyOAuthSubject, subject)
+	if err == nil {
+		return fmt.Errorf("%s is already linked to %s", subject, other.DisplayName())
+	}
+	if !entity.IsErrNotFound(err) {
+		return err
+	}
+
+	i.SetMetadata(metaKeyOAuthSubject, subject)
+	return i.Commit()
+}
+
+// openSession create a session for an identity, and give it to the browser
+func (a *Authenticator) openSession(w http.ResponseWriter, r *http.Request, identityId entity.Id) error {
+	sessionId, expire, err := a.sessions.Create(identityId)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    sessionId,
+		Path:     "/",
+		Expires:  expire,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+func writeUser(w http.ResponseWriter, i *cache.IdentityCache) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Id          string `json:"id"`
+		HumanId     string `json:"human_id"`
+		Name        string `json:"name"`
+		Email       string `json:"email,omitempty"`
+		Login       string `json:"login,omitempty"`
+		DisplayName string `json:"display_name"`
+	}{
+		Id:          i.Id().String(),
+		HumanId:     i.Id().Human(),
+		Name:        i.Name(),
+		Email:       i.Email(),
+		Login:       i.Login(),
+		DisplayName: i.DisplayName(),
+	})
+}
+
+func isForm(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data"
+}
+
+func randomString() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
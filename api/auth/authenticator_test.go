@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func newTestAuthenticator(t *testing.T, oauthConf *OAuthConfig) (*cache.RepoCache, http.Handler) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	a, err := NewAuthenticator(context.Background(), backend, oauthConf)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix+"/", a.Handler())
+	mux.HandleFunc("/whoami", func(w http.ResponseWriter, r *http.Request) {
+		i, err := UserFromCtx(r.Context(), backend)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(i.Name()))
+	})
+
+	return backend, a.Middleware(mux)
+}
+
+func serve(handler http.Handler, method string, target string, body string, header http.Header, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, values := range header {
+		r.Header[key] = values
+	}
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func findCookie(t *testing.T, w *httptest.ResponseRecorder, name string) *http.Cookie {
+	t.Helper()
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	t.Fatalf("no cookie %s", name)
+	return nil
+}
+
+func TestTokenAuthentication(t *testing.T) {
+	backend, handler := newTestAuthenticator(t, nil)
+
+	alice, err := backend.Identities().New("Alice", "alice@example.com")
+	require.NoError(t, err)
+	value, _, err := NewToken(backend, alice.Id(), "")
+	require.NoError(t, err)
+
+	// anonymous
+	w := serve(handler, "GET", "/whoami", "", nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// API token
+	w = serve(handler, "GET", "/whoami", "", http.Header{"Authorization": {"Bearer " + value}})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "Alice", w.Body.String())
+
+	w = serve(handler, "GET", "/whoami", "", http.Header{"Authorization": {"token " + value}})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serve(handler, "GET", "/whoami", "", http.Header{"Authorization": {"Bearer gbt_invalid"}})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+	// login with the token, in JSON
+	w = serve(handler, "POST", "/auth/login", `{"token": "gbt_invalid"}`, nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = serve(handler, "POST", "/auth/login", `{"token": "`+value+`"}`, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	session := findCookie(t, w, SessionCookieName)
+	require.True(t, session.HttpOnly)
+	require.Equal(t, http.SameSiteLaxMode, session.SameSite)
+
+	var user map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	require.Equal(t, alice.Id().String(), user["id"])
+
+	w = serve(handler, "GET", "/whoami", "", nil, session)
+	require.Equal(t, "Alice", w.Body.String())
+	w = serve(handler, "GET", "/auth/user", "", nil, session)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// an unknown session is anonymous
+	w = serve(handler, "GET", "/whoami", "", nil, &http.Cookie{Name: SessionCookieName, Value: "invalid"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = serve(handler, "POST", "/auth/logout", "", nil, session)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(handler, "GET", "/whoami", "", nil, session)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// login with the token, with the form
+	w = serve(handler, "GET", "/auth/login", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `name="token"`)
+	require.NotContains(t, w.Body.String(), "/auth/oauth/login")
+
+	form := http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}
+	w = serve(handler, "POST", "/auth/login", "token="+url.QueryEscape(value), form)
+	require.Equal(t, http.StatusSeeOther, w.Code)
+	w = serve(handler, "GET", "/whoami", "", nil, findCookie(t, w, SessionCookieName))
+	require.Equal(t, "Alice", w.Body.String())
+
+	// no OAuth provider
+	w = serve(handler, "GET", "/auth/oauth/login", "", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOAuthAuthentication(t *testing.T) {
+	var userInfo map[string]interface{}
+
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"authorization_endpoint": "https://provider.example.com/authorize",
+				"token_endpoint":         "http://" + r.Host + "/token",
+				"userinfo_endpoint":      "http://" + r.Host + "/userinfo",
+			})
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "the-code", r.PostForm.Get("code"))
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"access_token": "access",
+				"token_type":   "Bearer",
+			})
+		case "/userinfo":
+			require.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+			_ = json.NewEncoder(w).Encode(userInfo)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer provider.Close()
+
+	conf := &OAuthConfig{
+		Issuer:      provider.URL,
+		ClientId:    "client",
+		RedirectURL: "http://localhost/auth/oauth/callback",
+
+		AllowedEmails:   []string{"@example.com"},
+		AllowedSubjects: []string{"1234"},
+	}
+	backend, handler := newTestAuthenticator(t, conf)
+
+	login := func() *httptest.ResponseRecorder {
+		w := serve(handler, "GET", "/auth/oauth/login", "", nil)
+		require.Equal(t, http.StatusFound, w.Code)
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "provider.example.com", location.Host)
+		require.Equal(t, "client", location.Query().Get("client_id"))
+		state := findCookie(t, w, oauthStateCookieName)
+		require.Equal(t, state.Value, location.Query().Get("state"))
+
+		// a forged callback is rejected
+		w = serve(handler, "GET", "/auth/oauth/callback?code=the-code&state=forged", "", nil, state)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		return serve(handler, "GET", "/auth/oauth/callback?code=the-code&state="+url.QueryEscape(state.Value), "", nil, state)
+	}
+
+	// a user without identity is rejected, unless allowed
+	userInfo = map[string]interface{}{"sub": "666", "name": "Mallory", "email": "mallory@evil.com", "email_verified": true}
+	w := login()
+	require.Equal(t, http.StatusForbidden, w.Code)
+	userInfo = map[string]interface{}{"sub": "777", "name": "Carol", "email": "carol@example.com"}
+	w = login()
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Empty(t, backend.Identities().AllIds())
+
+	// an allowed user get a new identity
+	userInfo = map[string]interface{}{"sub": "1234", "name": "Alice", "email": "alice@example.com"}
+	w = login()
+	require.Equal(t, http.StatusFound, w.Code)
+	w = serve(handler, "GET", "/whoami", "", nil, findCookie(t, w, SessionCookieName))
+	require.Equal(t, "Alice", w.Body.String())
+	require.Len(t, backend.Identities().AllIds(), 1)
+
+	// and the same one on the next login
+	w = login()
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Len(t, backend.Identities().AllIds(), 1)
+
+	// an identity is never matched by email, anyone can claim one
+	bob, err := backend.Identities().New("Bob", "bob@example.com")
+	require.NoError(t, err)
+	userInfo = map[string]interface{}{"sub": "5678", "name": "Robert", "email": "bob@example.com", "email_verified": true}
+	w = login()
+	require.Equal(t, http.StatusFound, w.Code)
+	whoami := func(w *httptest.ResponseRecorder) string {
+		w = serve(handler, "GET", "/auth/user", "", nil, findCookie(t, w, SessionCookieName))
+		var user map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+		return user["id"]
+	}
+	require.NotEqual(t, bob.Id().String(), whoami(w))
+	require.Len(t, backend.Identities().AllIds(), 3)
+
+	// but an identity linked to the account is used
+	dave, err := backend.Identities().New("Dave", "dave@example.com")
+	require.NoError(t, err)
+	require.NoError(t, LinkOAuthIdentity(backend, conf, dave, "4321"))
+	require.Error(t, LinkOAuthIdentity(backend, conf, dave, "8765"))
+	require.Error(t, LinkOAuthIdentity(backend, conf, bob, "4321"))
+	userInfo = map[string]interface{}{"sub": "4321", "name": "David", "email": "dave@example.com", "email_verified": true}
+	w = login()
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Equal(t, dave.Id().String(), whoami(w))
+	require.Len(t, backend.Identities().AllIds(), 4)
+
+	// the configuration apply to the linked identities too
+	eve, err := backend.Identities().New("Eve", "eve@evil.com")
+	require.NoError(t, err)
+	require.NoError(t, LinkOAuthIdentity(backend, conf, eve, "999"))
+	userInfo = map[string]interface{}{"sub": "999", "name": "Eve", "email": "eve@evil.com", "email_verified": true}
+	w = login()
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// a user of an allowed domain get a new identity, if the email is verified
+	userInfo = map[string]interface{}{"sub": "777", "name": "Carol", "email": "carol@example.com", "email_verified": true}
+	w = login()
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Len(t, backend.Identities().AllIds(), 6)
+}
@@ -1,4 +1,4 @@
-// Package auth contains helpers for managing identities within the GraphQL API.
+// Package auth contains helpers for managing identities within the web APIs.
 package auth
 
 import (
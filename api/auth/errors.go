@@ -4,3 +4,6 @@ import "errors"
 
 // ErrNotAuthenticated is returned to the client if the user requests an action requiring authentication, and they are not authenticated.
 var ErrNotAuthenticated = errors.New("not authenticated or read-only")
+
+// ErrOAuthNotAllowed is returned when a user logged in through the OAuth provider is not allowed by the configuration.
+var ErrOAuthNotAllowed = errors.New("this account is not allowed to log in")
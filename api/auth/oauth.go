@@ -0,0 +1,329 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// OAuthConfigKeyPrefix is the prefix of the git config keys configuring the
+// login through an OAuth provider
+const OAuthConfigKeyPrefix = "git-bug.webui.oauth"
+
+const (
+	oauthConfKeyIssuer          = "issuer"
+	oauthConfKeyClientId        = "client-id"
+	oauthConfKeyClientSecret    = "client-secret"
+	oauthConfKeyRedirectUrl     = "redirect-url"
+	oauthConfKeyAuthUrl         = "auth-url"
+	oauthConfKeyTokenUrl        = "token-url"
+	oauthConfKeyUserInfoUrl     = "userinfo-url"
+	oauthConfKeyScopes          = "scopes"
+	oauthConfKeyAllowedEmails   = "allowed-emails"
+	oauthConfKeyAllowedSubjects = "allowed-subjects"
+)
+
+// OAuthConfig configure the login through an OAuth2 or OpenID Connect
+// provider.
+type OAuthConfig struct {
+	// Issuer is the URL of an OpenID Connect provider. If set, the endpoints
+	// are discovered from it.
+	Issuer string
+
+	ClientId     string
+	ClientSecret string
+	// RedirectURL is the URL of the callback endpoint of the web server, as
+	// registered with the provider
+	RedirectURL string
+
+	// The endpoints of a plain OAuth2 provider, or to override the
+	// discovered ones
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+
+	// Scopes default to "openid profile email"
+	Scopes []string
+
+	// Only the users matching one of these verified emails, or of these
+	// domains given as "@example.com", or one of these user ids of the
+	// provider can log in. They use the identity linked to their account of
+	// the provider, or a new identity created for them.
+	AllowedEmails   []string
+	AllowedSubjects []string
+}
+
+// issuerId return the identifier of the provider, to tell apart the users of
+// different providers
+func (c OAuthConfig) issuerId() string {
+	if c.Issuer != "" {
+		return c.Issuer
+	}
+	return c.AuthURL
+}
+
+// LoadOAuthConfig read the OAuth configuration from the git config. It
+// return nil if no OAuth provider is configured.
+func LoadOAuthConfig(conf repository.ConfigRead) (*OAuthConfig, error) {
+	values := make(map[string]string)
+	for _, key := range []string{
+		oauthConfKeyIssuer, oauthConfKeyClientId, oauthConfKeyClientSecret, oauthConfKeyRedirectUrl,
+		oauthConfKeyAuthUrl, oauthConfKeyTokenUrl, oauthConfKeyUserInfoUrl, oauthConfKeyScopes,
+		oauthConfKeyAllowedEmails, oauthConfKeyAllowedSubjects,
+	} {
+		value, err := conf.ReadString(OAuthConfigKeyPrefix + "." + key)
+		switch {
+		case errors.Is(err, repository.ErrNoConfigEntry):
+		case err != nil:
+			return nil, err
+		default:
+			values[key] = strings.TrimSpace(value)
+		}
+	}
+
+	if values[oauthConfKeyClientId] == "" {
+		return nil, nil
+	}
+
+	result := &OAuthConfig{
+		Issuer:          values[oauthConfKeyIssuer],
+		ClientId:        values[oauthConfKeyClientId],
+		ClientSecret:    values[oauthConfKeyClientSecret],
+		RedirectURL:     values[oauthConfKeyRedirectUrl],
+		AuthURL:         values[oauthConfKeyAuthUrl],
+		TokenURL:        values[oauthConfKeyTokenUrl],
+		UserInfoURL:     values[oauthConfKeyUserInfoUrl],
+		Scopes:          splitList(values[oauthConfKeyScopes]),
+		AllowedEmails:   splitList(values[oauthConfKeyAllowedEmails]),
+		AllowedSubjects: splitList(values[oauthConfKeyAllowedSubjects]),
+	}
+
+	if result.Issuer == "" && (result.AuthURL == "" || result.TokenURL == "" || result.UserInfoURL == "") {
+		return nil, fmt.Errorf("%s.%s or all of %s, %s and %s must be configured",
+			OAuthConfigKeyPrefix, oauthConfKeyIssuer, oauthConfKeyAuthUrl, oauthConfKeyTokenUrl, oauthConfKeyUserInfoUrl)
+	}
+	if len(result.AllowedEmails) == 0 && len(result.AllowedSubjects) == 0 {
+		return nil, fmt.Errorf("%s.%s or %s.%s must be configured, otherwise nobody can log in",
+			OAuthConfigKeyPrefix, oauthConfKeyAllowedEmails, OAuthConfigKeyPrefix, oauthConfKeyAllowedSubjects)
+	}
+
+	return result, nil
+}
+
+// splitList split a configuration value separated by spaces or commas
+func splitList(value string) []string {
+	fields := strings.Fields(strings.ReplaceAll(value, ",", " "))
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// oauthUser is the user information returned by the provider
+type oauthUser struct {
+	Subject       string
+	Name          string
+	Email         string
+	EmailVerified bool
+	Login         string
+	AvatarUrl     string
+}
+
+type oauthProvider struct {
+	// the identifier of the provider, to tell apart the users of different
+	// providers
+	issuer      string
+	config      oauth2.Config
+	userInfoURL string
+
+	allowedEmails   []string
+	allowedSubjects []string
+}
+
+func newOAuthProvider(ctx context.Context, conf OAuthConfig) (*oauthProvider, error) {
+	if conf.ClientId == "" {
+		return nil, errors.New("missing OAuth client id")
+	}
+	if conf.RedirectURL == "" {
+		return nil, errors.New("missing OAuth redirect URL")
+	}
+
+	p := &oauthProvider{
+		issuer: conf.issuerId(),
+		config: oauth2.Config{
+			ClientID:     conf.ClientId,
+			ClientSecret: conf.ClientSecret,
+			RedirectURL:  conf.RedirectURL,
+			Scopes:       conf.Scopes,
+		},
+		allowedEmails:   conf.AllowedEmails,
+		allowedSubjects: conf.AllowedSubjects,
+	}
+
+	if conf.Issuer != "" {
+		discovered, err := discoverOIDC(ctx, conf.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		p.config.Endpoint.AuthURL = discovered.AuthorizationEndpoint
+		p.config.Endpoint.TokenURL = discovered.TokenEndpoint
+		p.userInfoURL = discovered.UserInfoEndpoint
+	}
+
+	if conf.AuthURL != "" {
+		p.config.Endpoint.AuthURL = conf.AuthURL
+	}
+	if conf.TokenURL != "" {
+		p.config.Endpoint.TokenURL = conf.TokenURL
+	}
+	if conf.UserInfoURL != "" {
+		p.userInfoURL = conf.UserInfoURL
+	}
+	if len(p.config.Scopes) == 0 {
+		p.config.Scopes = []string{"openid", "profile", "email"}
+	}
+
+	if p.config.Endpoint.AuthURL == "" || p.config.Endpoint.TokenURL == "" || p.userInfoURL == "" {
+		return nil, errors.New("incomplete OAuth provider configuration")
+	}
+
+	return p, nil
+}
+
+// allowUser tell if a user of the provider is allowed to log in
+func (p *oauthProvider) allowUser(info *oauthUser) bool {
+	for _, subject := range p.allowedSubjects {
+		if subject == info.Subject {
+			return true
+		}
+	}
+
+	if info.Email == "" || !info.EmailVerified {
+		return false
+	}
+	for _, allowed := range p.allowedEmails {
+		if strings.HasPrefix(allowed, "@") {
+			if strings.HasSuffix(strings.ToLower(info.Email), strings.ToLower(allowed)) {
+				return true
+			}
+		} else if strings.EqualFold(allowed, info.Email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type oidcDiscovery struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// discoverOIDC fetch the configuration of an OpenID Connect provider
+func discoverOIDC(ctx context.Context, issuer string) (*oidcDiscovery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OpenID Connect discovery: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenID Connect discovery: unexpected status %s", resp.Status)
+	}
+
+	var result oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("OpenID Connect discovery: %w", err)
+	}
+	return &result, nil
+}
+
+// fetchUser exchange the authorization code for a token, and fetch the
+// information on the user with it
+func (p *oauthProvider) fetchUser(ctx context.Context, code string) (*oauthUser, error) {
+	token, err := p.config.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.config.Client(ctx, token).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching the user information: unexpected status %s", resp.Status)
+	}
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("fetching the user information: %w", err)
+	}
+
+	return parseUserInfo(claims)
+}
+
+// parseUserInfo read the user information, either as standard OpenID Connect
+// claims or the common fields of the plain OAuth2 providers (id, login,
+// avatar_url)
+func parseUserInfo(claims map[string]interface{}) (*oauthUser, error) {
+	str := func(keys ...string) string {
+		for _, key := range keys {
+			switch value := claims[key].(type) {
+			case string:
+				if value != "" {
+					return value
+				}
+			case float64:
+				return fmt.Sprintf("%.0f", value)
+			}
+		}
+		return ""
+	}
+
+	user := &oauthUser{
+		Subject:   str("sub", "id"),
+		Name:      str("name"),
+		Email:     str("email"),
+		Login:     str("preferred_username", "login", "username"),
+		AvatarUrl: str("picture", "avatar_url"),
+	}
+	user.EmailVerified, _ = claims["email_verified"].(bool)
+
+	if user.Subject == "" {
+		return nil, errors.New("the provider didn't give a user identifier")
+	}
+	if user.Name == "" {
+		user.Name = user.Login
+	}
+	if user.Name == "" {
+		return nil, errors.New("the provider didn't give a user name")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestLoadOAuthConfig(t *testing.T) {
+	config := repository.NewMemConfig()
+
+	conf, err := LoadOAuthConfig(config)
+	require.NoError(t, err)
+	require.Nil(t, conf)
+
+	require.NoError(t, config.StoreString("git-bug.webui.oauth.client-id", "client"))
+	_, err = LoadOAuthConfig(config)
+	require.Error(t, err)
+
+	require.NoError(t, config.StoreString("git-bug.webui.oauth.issuer", "https://accounts.example.com"))
+	require.NoError(t, config.StoreString("git-bug.webui.oauth.scopes", "openid, email"))
+	// nobody could log in
+	_, err = LoadOAuthConfig(config)
+	require.Error(t, err)
+
+	require.NoError(t, config.StoreString("git-bug.webui.oauth.allowed-emails", "alice@example.com @example.org"))
+	conf, err = LoadOAuthConfig(config)
+	require.NoError(t, err)
+	require.Equal(t, &OAuthConfig{
+		Issuer:        "https://accounts.example.com",
+		ClientId:      "client",
+		Scopes:        []string{"openid", "email"},
+		AllowedEmails: []string{"alice@example.com", "@example.org"},
+	}, conf)
+}
+
+func TestParseUserInfo(t *testing.T) {
+	// a plain OAuth2 provider, like GitHub
+	user, err := parseUserInfo(map[string]interface{}{
+		"id":         float64(42),
+		"login":      "alice",
+		"avatar_url": "https://example.com/alice.png",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &oauthUser{
+		Subject:   "42",
+		Name:      "alice",
+		Login:     "alice",
+		AvatarUrl: "https://example.com/alice.png",
+	}, user)
+
+	_, err = parseUserInfo(map[string]interface{}{"name": "Alice"})
+	require.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// SessionCookieName is the name of the cookie holding the session of a
+// logged-in user
+const SessionCookieName = "git-bug-session"
+
+// DefaultSessionTTL is how long a session stays valid after the login
+const DefaultSessionTTL = 30 * 24 * time.Hour
+
+type session struct {
+	identityId entity.Id
+	expire     time.Time
+}
+
+// Sessions bind a session id, given to a browser in a cookie after the login,
+// to an identity. The sessions are kept in memory only, so restarting the
+// server log everyone out.
+type Sessions struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]session
+}
+
+func NewSessions(ttl time.Duration) *Sessions {
+	return &Sessions{
+		ttl:      ttl,
+		sessions: make(map[string]session),
+	}
+}
+
+// Create open a new session for an identity, and return its id
+func (s *Sessions) Create(identityId entity.Id) (string, time.Time, error) {
+	id, err := randomString()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expire := time.Now().Add(s.ttl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired()
+	s.sessions[id] = session{identityId: identityId, expire: expire}
+
+	return id, expire, nil
+}
+
+// Get return the identity bound to a session, if the session exist and is
+// still valid
+func (s *Sessions) Get(id string) (entity.Id, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(sess.expire) {
+		delete(s.sessions, id)
+		return "", false
+	}
+	return sess.identityId, true
+}
+
+// Delete close a session
+func (s *Sessions) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+}
+
+// removeExpired drop the expired sessions, to not grow forever.
+// The caller must hold the lock.
+func (s *Sessions) removeExpired() {
+	now := time.Now()
+	for id, sess := range s.sessions {
+		if now.After(sess.expire) {
+			delete(s.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const (
+	tokenKeyringPrefix = "api-token-"
+	// tokenScopeConfigKey is the repository config key holding the random
+	// scope of the API tokens of the repository. The keyring is user-wide,
+	// so the tokens are stored under this scope to not be shared with the
+	// other repositories.
+	tokenScopeConfigKey = "git-bug.webui.token-scope"
+	// tokenPrefix make the API tokens easy to recognize, for example by
+	// secret scanners
+	tokenPrefix = "gbt_"
+)
+
+var ErrTokenNotExist = errors.New("API token doesn't exist")
+
+// Token is an API token allowing to act as an identity on the web server of
+// a repository.
+//
+// Only the hash of the token value is stored, in the keyring, under the
+// scope of the repository. The hash is also the identifier of the token.
+type Token struct {
+	Hash       string    `json:"hash"`
+	IdentityId entity.Id `json:"identity"`
+	Name       string    `json:"name,omitempty"`
+	CreateTime time.Time `json:"createtime"`
+}
+
+// Human return the short form of the token identifier, to display it
+func (t *Token) Human() string {
+	return t.Hash[:entity.HumanIdLength]
+}
+
+type tokenRepo interface {
+	repository.RepoConfig
+	repository.RepoKeyring
+}
+
+// NewToken generate and store a new API token for an identity. The returned
+// value is the secret to give to the client, and can't be retrieved later.
+func NewToken(repo tokenRepo, identityId entity.Id, name string) (string, *Token, error) {
+	if err := identityId.Validate(); err != nil {
+		return "", nil, fmt.Errorf("invalid identity id: %w", err)
+	}
+
+	prefix, err := keyringPrefix(repo, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	value := tokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := &Token{
+		Hash:       hashToken(value),
+		IdentityId: identityId,
+		Name:       name,
+		CreateTime: time.Now(),
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	err = repo.Keyring().Set(repository.Item{
+		Key:  prefix + token.Hash,
+		Data: data,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return value, token, nil
+}
+
+// ResolveToken return the stored token matching a token value given by a
+// client
+func ResolveToken(repo tokenRepo, value string) (*Token, error) {
+	if !strings.HasPrefix(value, tokenPrefix) {
+		return nil, ErrTokenNotExist
+	}
+	prefix, err := keyringPrefix(repo, false)
+	if err != nil {
+		return nil, err
+	}
+	return loadToken(repo, prefix, hashToken(value))
+}
+
+// ListTokens return all the stored tokens of the repository, optionally only
+// the ones of an identity
+func ListTokens(repo tokenRepo, identityId entity.Id) ([]*Token, error) {
+	prefix, err := keyringPrefix(repo, false)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := repo.Keyring().Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Token
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		token, err := loadToken(repo, prefix, strings.TrimPrefix(key, prefix))
+		if err != nil {
+			return nil, err
+		}
+		if identityId != "" && token.IdentityId != identityId {
+			continue
+		}
+		result = append(result, token)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreateTime.Before(result[j].CreateTime)
+	})
+
+	return result, nil
+}
+
+// LoadTokenWithPrefix load a stored token from a prefix of its identifier
+func LoadTokenWithPrefix(repo tokenRepo, prefix string) (*Token, error) {
+	tokens, err := ListTokens(repo, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []*Token
+	for _, token := range tokens {
+		if strings.HasPrefix(token.Hash, prefix) {
+			matching = append(matching, token)
+		}
+	}
+
+	switch len(matching) {
+	case 0:
+		return nil, ErrTokenNotExist
+	case 1:
+		return matching[0], nil
+	default:
+		ids := make([]entity.Id, len(matching))
+		for i, token := range matching {
+			ids[i] = entity.Id(token.Hash)
+		}
+		return nil, entity.NewErrMultipleMatch("API token", ids)
+	}
+}
+
+// RemoveToken revoke a stored token
+func RemoveToken(repo tokenRepo, hash string) error {
+	prefix, err := keyringPrefix(repo, false)
+	if err != nil {
+		return err
+	}
+	// not all the keyring backends report a missing key
+	if _, err := loadToken(repo, prefix, hash); err != nil {
+		return err
+	}
+	return repo.Keyring().Remove(prefix + hash)
+}
+
+// keyringPrefix return the prefix of the keyring keys of the tokens of the
+// repository. If create is true, the scope of the repository is created if
+// needed, otherwise a repository without scope has no token and the
+// returned prefix matches nothing.
+func keyringPrefix(repo tokenRepo, create bool) (string, error) {
+	scope, err := repo.LocalConfig().ReadString(tokenScopeConfigKey)
+	switch {
+	case errors.Is(err, repository.ErrNoConfigEntry) && create:
+		raw := make([]byte, 16)
+		if _, err := rand.Read(raw); err != nil {
+			return "", err
+		}
+		scope = hex.EncodeToString(raw)
+		if err := repo.LocalConfig().StoreString(tokenScopeConfigKey, scope); err != nil {
+			return "", err
+		}
+	case errors.Is(err, repository.ErrNoConfigEntry):
+		// tokens are never stored with an empty scope
+		return tokenKeyringPrefix + "-", nil
+	case err != nil:
+		return "", err
+	}
+	return tokenKeyringPrefix + scope + "-", nil
+}
+
+func loadToken(repo repository.RepoKeyring, prefix string, hash string) (*Token, error) {
+	item, err := repo.Keyring().Get(prefix + hash)
+	if errors.Is(err, repository.ErrKeyringKeyNotFound) {
+		return nil, ErrTokenNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var token Token
+	if err := json.Unmarshal(item.Data, &token); err != nil {
+		return nil, fmt.Errorf("invalid API token %s: %w", hash, err)
+	}
+	return &token, nil
+}
+
+// hashToken return the hash of a token value. As the tokens are random and
+// long enough, a plain hash is as good as a password hashing function.
+func hashToken(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
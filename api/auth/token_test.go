@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestTokens(t *testing.T) {
+	repo := repository.NewMockRepo()
+
+	alice := entity.Id("a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1")
+	bob := entity.Id("b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2")
+
+	_, _, err := NewToken(repo, "invalid", "")
+	require.Error(t, err)
+
+	value1, token1, err := NewToken(repo, alice, "ci")
+	require.NoError(t, err)
+	require.Regexp(t, "^gbt_[A-Za-z0-9_-]{43}$", value1)
+	value2, _, err := NewToken(repo, bob, "")
+	require.NoError(t, err)
+	require.NotEqual(t, value1, value2)
+
+	// only the hash is stored
+	keys, err := repo.Keyring().Keys()
+	require.NoError(t, err)
+	for _, key := range keys {
+		item, err := repo.Keyring().Get(key)
+		require.NoError(t, err)
+		require.NotContains(t, string(item.Data), value1)
+		require.NotContains(t, string(item.Data), value2)
+	}
+
+	resolved, err := ResolveToken(repo, value1)
+	require.NoError(t, err)
+	require.Equal(t, alice, resolved.IdentityId)
+	require.Equal(t, "ci", resolved.Name)
+	require.Equal(t, token1.Hash, resolved.Hash)
+
+	_, err = ResolveToken(repo, value1+"x")
+	require.ErrorIs(t, err, ErrTokenNotExist)
+	_, err = ResolveToken(repo, "")
+	require.ErrorIs(t, err, ErrTokenNotExist)
+
+	all, err := ListTokens(repo, "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	ofAlice, err := ListTokens(repo, alice)
+	require.NoError(t, err)
+	require.Len(t, ofAlice, 1)
+
+	loaded, err := LoadTokenWithPrefix(repo, token1.Human())
+	require.NoError(t, err)
+	require.Equal(t, token1.Hash, loaded.Hash)
+
+	require.NoError(t, RemoveToken(repo, token1.Hash))
+	require.ErrorIs(t, RemoveToken(repo, token1.Hash), ErrTokenNotExist)
+	_, err = ResolveToken(repo, value1)
+	require.ErrorIs(t, err, ErrTokenNotExist)
+	_, err = ResolveToken(repo, value2)
+	require.NoError(t, err)
+}
+
+func TestTokensScopedToRepo(t *testing.T) {
+	// two repositories sharing the user-wide keyring
+	keyring := repository.NewMockRepoKeyring()
+	repo1 := struct {
+		repository.RepoConfig
+		repository.RepoKeyring
+	}{repository.NewMockRepoConfig(), keyring}
+	repo2 := struct {
+		repository.RepoConfig
+		repository.RepoKeyring
+	}{repository.NewMockRepoConfig(), keyring}
+
+	alice := entity.Id("a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1")
+
+	value, token, err := NewToken(repo1, alice, "")
+	require.NoError(t, err)
+
+	// the other repository doesn't see the token
+	_, err = ResolveToken(repo2, value)
+	require.ErrorIs(t, err, ErrTokenNotExist)
+	tokens, err := ListTokens(repo2, "")
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+	require.ErrorIs(t, RemoveToken(repo2, token.Hash), ErrTokenNotExist)
+
+	_, _, err = NewToken(repo2, alice, "")
+	require.NoError(t, err)
+	tokens, err = ListTokens(repo2, "")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.NotEqual(t, token.Hash, tokens[0].Hash)
+
+	tokens, err = ListTokens(repo1, "")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, token.Hash, tokens[0].Hash)
+}
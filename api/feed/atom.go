@@ -0,0 +1,98 @@
+// Package feed generate Atom feeds of bugs.
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// ContentType is the media type of an Atom feed
+const ContentType = "application/atom+xml; charset=utf-8"
+
+// Feed is an Atom feed, as defined in RFC 4287
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Id      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated Time     `xml:"updated"`
+	Links   []Link   `xml:"link"`
+	Entries []Entry  `xml:"entry"`
+}
+
+type Entry struct {
+	Id         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    Time       `xml:"updated"`
+	Published  *Time      `xml:"published,omitempty"`
+	Authors    []Person   `xml:"author"`
+	Links      []Link     `xml:"link"`
+	Categories []Category `xml:"category"`
+	Content    *Text      `xml:"content,omitempty"`
+}
+
+type Link struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type Person struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+type Text struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+// Time is a time formatted as RFC 3339, as Atom requires
+type Time time.Time
+
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(time.Time(t).UTC().Format(time.RFC3339)), nil
+}
+
+// NewFeed create an empty feed
+func NewFeed(id string, title string) *Feed {
+	return &Feed{
+		Xmlns: atomNamespace,
+		Id:    id,
+		Title: title,
+	}
+}
+
+// Add append entries to the feed, and move the feed update time if needed
+func (f *Feed) Add(entries ...Entry) {
+	for _, entry := range entries {
+		if time.Time(entry.Updated).After(time.Time(f.Updated)) {
+			f.Updated = entry.Updated
+		}
+		f.Entries = append(f.Entries, entry)
+	}
+}
+
+// Write encode the feed as XML
+func (f *Feed) Write(w io.Writer) error {
+	if time.Time(f.Updated).IsZero() {
+		f.Updated = Time(time.Now())
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
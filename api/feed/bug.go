@@ -0,0 +1,208 @@
+package feed
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/query"
+)
+
+// DefaultLimit is the default maximum number of entries in a feed
+const DefaultLimit = 50
+
+type Options struct {
+	// BaseURL is the URL of the web UI, used for the links of the feed and
+	// its entries. If empty, there is no links.
+	BaseURL string
+	// Limit is the maximum number of entries. If zero, DefaultLimit is used.
+	Limit int
+}
+
+func (o Options) limit() int {
+	if o.Limit <= 0 {
+		return DefaultLimit
+	}
+	return o.Limit
+}
+
+func (o Options) link(path string) []Link {
+	if o.BaseURL == "" {
+		return nil
+	}
+	return []Link{{Rel: "alternate", Href: strings.TrimSuffix(o.BaseURL, "/") + path, Type: "text/html"}}
+}
+
+// QueryFeed create a feed of the timelines of the bugs matching a query,
+// most recent events first. raw is the query as written by the user, for the
+// title and the links of the feed. me return the identity designated by "me"
+// in the query, and is only called if the query use it.
+func QueryFeed(repo *cache.RepoCache, q *query.Query, raw string, me func() (*cache.IdentityCache, error), opts Options) (*Feed, error) {
+	ids, err := repo.Bugs().QueryAs(q, me)
+	if err != nil {
+		return nil, err
+	}
+
+	excerpts := make([]*cache.BugExcerpt, 0, len(ids))
+	for _, id := range ids {
+		excerpt, err := repo.Bugs().ResolveExcerpt(id)
+		if err != nil {
+			return nil, err
+		}
+		excerpts = append(excerpts, excerpt)
+	}
+
+	// The events of a bug are never more recent than its last edition, so
+	// the most recent events are in the most recently edited bugs.
+	sort.Slice(excerpts, func(i, j int) bool {
+		return excerpts[i].EditUnixTime > excerpts[j].EditUnixTime
+	})
+	if len(excerpts) > opts.limit() {
+		excerpts = excerpts[:opts.limit()]
+	}
+
+	title := "Bugs"
+	id := "urn:git-bug:feed"
+	if raw != "" {
+		title = "Bugs matching " + raw
+		id += ":" + url.QueryEscape(raw)
+	}
+
+	feed := NewFeed(id, title)
+	feed.Links = opts.link("/?q=" + url.QueryEscape(raw))
+
+	var entries []Entry
+	for _, excerpt := range excerpts {
+		b, err := repo.Bugs().Resolve(excerpt.Id())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, TimelineEntries(b.Snapshot(), opts)...)
+	}
+
+	feed.Add(latest(entries, opts.limit())...)
+
+	return feed, nil
+}
+
+// BugFeed create a feed of the timeline of a single bug, with its comments
+// and changes, most recent first.
+func BugFeed(b *cache.BugCache, opts Options) *Feed {
+	snap := b.Snapshot()
+
+	feed := NewFeed("urn:git-bug:feed:bug:"+snap.Id().String(), snap.Title)
+	feed.Links = opts.link("/bug/" + snap.Id().Human())
+	feed.Add(latest(TimelineEntries(snap, opts), opts.limit())...)
+
+	return feed
+}
+
+// TimelineEntries create a feed entry for each item of the timeline of a bug
+func TimelineEntries(snap *bug.Snapshot, opts Options) []Entry {
+	entries := make([]Entry, 0, len(snap.Timeline))
+
+	for _, item := range snap.Timeline {
+		entry, ok := timelineEntry(snap, item, opts)
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func timelineEntry(snap *bug.Snapshot, item bug.TimelineItem, opts Options) (Entry, bool) {
+	var author identity.Interface
+	var action string
+
+	entry := Entry{
+		Id:    "urn:git-bug:" + item.CombinedId().String(),
+		Links: opts.link("/bug/" + snap.Id().Human()),
+	}
+
+	switch item := item.(type) {
+	case *bug.CreateTimelineItem:
+		author = item.Author
+		action = "opened the bug"
+		entry.Updated = Time(item.LastEdit.Time())
+		published := Time(item.CreatedAt.Time())
+		entry.Published = &published
+		entry.Content = &Text{Type: "text", Body: item.Message}
+
+	case *bug.AddCommentTimelineItem:
+		author = item.Author
+		action = "commented"
+		entry.Updated = Time(item.LastEdit.Time())
+		published := Time(item.CreatedAt.Time())
+		entry.Published = &published
+		entry.Content = &Text{Type: "text", Body: item.Message}
+
+	case *bug.LabelChangeTimelineItem:
+		author = item.Author
+		var changes []string
+		if len(item.Added) > 0 {
+			changes = append(changes, "added the label(s) "+joinLabels(item.Added))
+		}
+		if len(item.Removed) > 0 {
+			changes = append(changes, "removed the label(s) "+joinLabels(item.Removed))
+		}
+		action = strings.Join(changes, " and ")
+		entry.Updated = Time(item.UnixTime.Time())
+		for _, l := range item.Added {
+			entry.Categories = append(entry.Categories, Category{Term: l.String()})
+		}
+		for _, l := range item.Removed {
+			entry.Categories = append(entry.Categories, Category{Term: l.String()})
+		}
+
+	case *bug.SetStatusTimelineItem:
+		author = item.Author
+		action = "closed the bug"
+		if item.Status == common.OpenStatus {
+			action = "reopened the bug"
+		}
+		entry.Updated = Time(item.UnixTime.Time())
+
+	case *bug.SetTitleTimelineItem:
+		author = item.Author
+		action = fmt.Sprintf("changed the title from %q", item.Was)
+		entry.Updated = Time(item.UnixTime.Time())
+
+	default:
+		return Entry{}, false
+	}
+
+	entry.Title = fmt.Sprintf("%s: %s %s", snap.Title, author.DisplayName(), action)
+	entry.Authors = []Person{{Name: author.DisplayName(), Email: author.Email()}}
+
+	return entry, true
+}
+
+// latest return the most recent entries, most recent first
+func latest(entries []Entry, limit int) []Entry {
+	// the entries of a timeline are in chronological order, keep the later
+	// ones first when the times are equal
+	slices.Reverse(entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return time.Time(entries[i].Updated).After(time.Time(entries[j].Updated))
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+func joinLabels(labels []bug.Label) string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = `"` + l.String() + `"`
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,104 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestQueryFeed(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	c, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer c.Close()
+
+	author, err := c.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, c.SetUserIdentity(author))
+
+	now := time.Now().Unix()
+
+	bug1, _, err := c.Bugs().NewRaw(author, now-100, "first", "message", nil, nil)
+	require.NoError(t, err)
+	_, _, err = bug1.ChangeLabelsRaw(author, now-50, []string{"security"}, nil, nil)
+	require.NoError(t, err)
+	_, err = bug1.CloseRaw(author, now-10, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	bug2, _, err := c.Bugs().NewRaw(author, now-90, "second", "other message", nil, nil)
+	require.NoError(t, err)
+	_, _, err = bug2.AddCommentRaw(author, now-20, "a comment", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug2.Commit())
+
+	q, err := query.Parse("")
+	require.NoError(t, err)
+
+	f, err := QueryFeed(c, q, "", c.GetUserIdentity, Options{BaseURL: "http://localhost:1234/"})
+	require.NoError(t, err)
+
+	titles := func(f *Feed) []string {
+		var result []string
+		for _, entry := range f.Entries {
+			result = append(result, entry.Title)
+		}
+		return result
+	}
+
+	require.Equal(t, "Bugs", f.Title)
+	require.Equal(t, []string{
+		"first: René Descartes closed the bug",
+		"second: René Descartes commented",
+		`first: René Descartes added the label(s) "security"`,
+		"second: René Descartes opened the bug",
+		"first: René Descartes opened the bug",
+	}, titles(f))
+	require.Equal(t, time.Unix(now-10, 0), time.Time(f.Updated))
+	require.Equal(t, "a comment", f.Entries[1].Content.Body)
+	require.Equal(t, "http://localhost:1234/bug/"+bug2.Id().Human(), f.Entries[1].Links[0].Href)
+	require.Equal(t, "rene@descartes.fr", f.Entries[1].Authors[0].Email)
+	require.Equal(t, []Category{{Term: "security"}}, f.Entries[2].Categories)
+
+	// the feed is limited to the most recent events
+	q, err = query.Parse("label:security")
+	require.NoError(t, err)
+	f, err = QueryFeed(c, q, "label:security", c.GetUserIdentity, Options{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, "Bugs matching label:security", f.Title)
+	require.Equal(t, []string{
+		"first: René Descartes closed the bug",
+		`first: René Descartes added the label(s) "security"`,
+	}, titles(f))
+	require.Empty(t, f.Entries[0].Links)
+
+	f = BugFeed(bug2, Options{})
+	require.Equal(t, "second", f.Title)
+	require.Equal(t, []string{
+		"second: René Descartes commented",
+		"second: René Descartes opened the bug",
+	}, titles(f))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	var decoded struct {
+		XMLName xml.Name
+		Entries []struct {
+			Id      string `xml:"id"`
+			Updated string `xml:"updated"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, xml.Name{Space: atomNamespace, Local: "feed"}, decoded.XMLName)
+	require.Len(t, decoded.Entries, 2)
+	require.Equal(t, time.Unix(now-20, 0).UTC().Format(time.RFC3339), decoded.Entries[0].Updated)
+}
@@ -23,3 +23,14 @@ type LazyIdentityEdge struct {
 func (lbe LazyIdentityEdge) GetCursor() string {
 	return lbe.Cursor
 }
+
+// LazyPullRequestEdge is a special relay edge used to implement a lazy loading connection
+type LazyPullRequestEdge struct {
+	Id     entity.Id
+	Cursor string
+}
+
+// GetCursor return the cursor of a LazyPullRequestEdge
+func (lbe LazyPullRequestEdge) GetCursor() string {
+	return lbe.Cursor
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/introspection"
 	"github.com/MichaelMure/git-bug/api/graphql/models"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
 	gqlparser "github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
 )
@@ -45,8 +46,11 @@ type ResolverRoot interface {
 	LabelChangeOperation() LabelChangeOperationResolver
 	LabelChangeTimelineItem() LabelChangeTimelineItemResolver
 	Mutation() MutationResolver
+	PullRequest() PullRequestResolver
+	PullRequestComment() PullRequestCommentResolver
 	Query() QueryResolver
 	Repository() RepositoryResolver
+	Review() ReviewResolver
 	SetStatusOperation() SetStatusOperationResolver
 	SetStatusTimelineItem() SetStatusTimelineItemResolver
 	SetTitleOperation() SetTitleOperationResolver
@@ -111,6 +115,8 @@ type ComplexityRoot struct {
 		Status       func(childComplexity int) int
 		Timeline     func(childComplexity int, after *string, before *string, first *int, last *int) int
 		Title        func(childComplexity int) int
+		Unread       func(childComplexity int) int
+		Watching     func(childComplexity int) int
 	}
 
 	BugConnection struct {
@@ -125,6 +131,23 @@ type ComplexityRoot struct {
 		Node   func(childComplexity int) int
 	}
 
+	BulkEditPayload struct {
+		ClientMutationID func(childComplexity int) int
+		DryRun           func(childComplexity int) int
+		Results          func(childComplexity int) int
+	}
+
+	BulkEditResult struct {
+		AddedLabels   func(childComplexity int) int
+		Bug           func(childComplexity int) int
+		Changed       func(childComplexity int) int
+		Commented     func(childComplexity int) int
+		Error         func(childComplexity int) int
+		ID            func(childComplexity int) int
+		RemovedLabels func(childComplexity int) int
+		Status        func(childComplexity int) int
+	}
+
 	ChangeLabelPayload struct {
 		Bug              func(childComplexity int) int
 		ClientMutationID func(childComplexity int) int
@@ -269,6 +292,7 @@ type ComplexityRoot struct {
 		AddComment          func(childComplexity int, input models.AddCommentInput) int
 		AddCommentAndClose  func(childComplexity int, input models.AddCommentAndCloseBugInput) int
 		AddCommentAndReopen func(childComplexity int, input models.AddCommentAndReopenBugInput) int
+		BulkEdit            func(childComplexity int, input models.BulkEditInput) int
 		ChangeLabels        func(childComplexity int, input *models.ChangeLabelInput) int
 		CloseBug            func(childComplexity int, input models.CloseBugInput) int
 		EditComment         func(childComplexity int, input models.EditCommentInput) int
@@ -308,18 +332,77 @@ type ComplexityRoot struct {
 		StartCursor     func(childComplexity int) int
 	}
 
+	PullRequest struct {
+		Actors       func(childComplexity int) int
+		Author       func(childComplexity int) int
+		Comments     func(childComplexity int) int
+		Commits      func(childComplexity int) int
+		CreatedAt    func(childComplexity int) int
+		HumanID      func(childComplexity int) int
+		Id           func(childComplexity int) int
+		LastEdit     func(childComplexity int) int
+		MergeCommit  func(childComplexity int) int
+		Participants func(childComplexity int) int
+		Reviews      func(childComplexity int) int
+		SourceBranch func(childComplexity int) int
+		Status       func(childComplexity int) int
+		TargetBranch func(childComplexity int) int
+		Title        func(childComplexity int) int
+	}
+
+	PullRequestComment struct {
+		Author  func(childComplexity int) int
+		ID      func(childComplexity int) int
+		Message func(childComplexity int) int
+	}
+
+	PullRequestCommit struct {
+		Hash    func(childComplexity int) int
+		Message func(childComplexity int) int
+	}
+
+	PullRequestConnection struct {
+		Edges      func(childComplexity int) int
+		Nodes      func(childComplexity int) int
+		PageInfo   func(childComplexity int) int
+		TotalCount func(childComplexity int) int
+	}
+
+	PullRequestEdge struct {
+		Cursor func(childComplexity int) int
+		Node   func(childComplexity int) int
+	}
+
 	Query struct {
 		Repository func(childComplexity int, ref *string) int
 	}
 
 	Repository struct {
-		AllBugs       func(childComplexity int, after *string, before *string, first *int, last *int, query *string) int
-		AllIdentities func(childComplexity int, after *string, before *string, first *int, last *int) int
-		Bug           func(childComplexity int, prefix string) int
-		Identity      func(childComplexity int, prefix string) int
-		Name          func(childComplexity int) int
-		UserIdentity  func(childComplexity int) int
-		ValidLabels   func(childComplexity int, after *string, before *string, first *int, last *int) int
+		AllBugs         func(childComplexity int, after *string, before *string, first *int, last *int, query *string) int
+		AllIdentities   func(childComplexity int, after *string, before *string, first *int, last *int) int
+		AllPullRequests func(childComplexity int, after *string, before *string, first *int, last *int, status *pullrequest.Status) int
+		Bug             func(childComplexity int, prefix string) int
+		Identity        func(childComplexity int, prefix string) int
+		Name            func(childComplexity int) int
+		PullRequest     func(childComplexity int, prefix string) int
+		UserIdentity    func(childComplexity int) int
+		ValidLabels     func(childComplexity int, after *string, before *string, first *int, last *int) int
+	}
+
+	Review struct {
+		Author    func(childComplexity int) int
+		Comments  func(childComplexity int) int
+		CreatedAt func(childComplexity int) int
+		ID        func(childComplexity int) int
+		Message   func(childComplexity int) int
+		State     func(childComplexity int) int
+	}
+
+	ReviewComment struct {
+		DiffHunk func(childComplexity int) int
+		Line     func(childComplexity int) int
+		Message  func(childComplexity int) int
+		Path     func(childComplexity int) int
 	}
 
 	SetStatusOperation struct {
@@ -677,6 +760,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Bug.Title(childComplexity), true
 
+	case "Bug.unread":
+		if e.complexity.Bug.Unread == nil {
+			break
+		}
+
+		return e.complexity.Bug.Unread(childComplexity), true
+
+	case "Bug.watching":
+		if e.complexity.Bug.Watching == nil {
+			break
+		}
+
+		return e.complexity.Bug.Watching(childComplexity), true
+
 	case "BugConnection.edges":
 		if e.complexity.BugConnection.Edges == nil {
 			break
@@ -719,6 +816,83 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.BugEdge.Node(childComplexity), true
 
+	case "BulkEditPayload.clientMutationId":
+		if e.complexity.BulkEditPayload.ClientMutationID == nil {
+			break
+		}
+
+		return e.complexity.BulkEditPayload.ClientMutationID(childComplexity), true
+
+	case "BulkEditPayload.dryRun":
+		if e.complexity.BulkEditPayload.DryRun == nil {
+			break
+		}
+
+		return e.complexity.BulkEditPayload.DryRun(childComplexity), true
+
+	case "BulkEditPayload.results":
+		if e.complexity.BulkEditPayload.Results == nil {
+			break
+		}
+
+		return e.complexity.BulkEditPayload.Results(childComplexity), true
+
+	case "BulkEditResult.addedLabels":
+		if e.complexity.BulkEditResult.AddedLabels == nil {
+			break
+		}
+
+		return e.complexity.BulkEditResult.AddedLabels(childComplexity), true
+
+	case "BulkEditResult.bug":
+		if e.complexity.BulkEditResult.Bug == nil {
+			break
+		}
+
+		return e.complexity.BulkEditResult.Bug(childComplexity), true
+
+	case "BulkEditResult.changed":
+		if e.complexity.BulkEditResult.Changed == nil {
+			break
+		}
+
+		return e.complexity.BulkEditResult.Changed(childComplexity), true
+
+	case "BulkEditResult.commented":
+		if e.complexity.BulkEditResult.Commented == nil {
+			break
+		}
+
+		return e.complexity.BulkEditResult.Commented(childComplexity), true
+
+	case "BulkEditResult.error":
+		if e.complexity.BulkEditResult.Error == nil {
+			break
+		}
+
+		return e.complexity.BulkEditResult.Error(childComplexity), true
+
+	case "BulkEditResult.id":
+		if e.complexity.BulkEditResult.ID == nil {
+			break
+		}
+
+		return e.complexity.BulkEditResult.ID(childComplexity), true
+
+	case "BulkEditResult.removedLabels":
+		if e.complexity.BulkEditResult.RemovedLabels == nil {
+			break
+		}
+
+		return e.complexity.BulkEditResult.RemovedLabels(childComplexity), true
+
+	case "BulkEditResult.status":
+		if e.complexity.BulkEditResult.Status == nil {
+			break
+		}
+
+		return e.complexity.BulkEditResult.Status(childComplexity), true
+
 	case "ChangeLabelPayload.bug":
 		if e.complexity.ChangeLabelPayload.Bug == nil {
 			break
@@ -1315,6 +1489,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.AddCommentAndReopen(childComplexity, args["input"].(models.AddCommentAndReopenBugInput)), true
 
+	case "Mutation.bulkEdit":
+		if e.complexity.Mutation.BulkEdit == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_bulkEdit_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.BulkEdit(childComplexity, args["input"].(models.BulkEditInput)), true
+
 	case "Mutation.changeLabels":
 		if e.complexity.Mutation.ChangeLabels == nil {
 			break
@@ -1499,6 +1685,188 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PageInfo.StartCursor(childComplexity), true
 
+	case "PullRequest.actors":
+		if e.complexity.PullRequest.Actors == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Actors(childComplexity), true
+
+	case "PullRequest.author":
+		if e.complexity.PullRequest.Author == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Author(childComplexity), true
+
+	case "PullRequest.comments":
+		if e.complexity.PullRequest.Comments == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Comments(childComplexity), true
+
+	case "PullRequest.commits":
+		if e.complexity.PullRequest.Commits == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Commits(childComplexity), true
+
+	case "PullRequest.createdAt":
+		if e.complexity.PullRequest.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.CreatedAt(childComplexity), true
+
+	case "PullRequest.humanId":
+		if e.complexity.PullRequest.HumanID == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.HumanID(childComplexity), true
+
+	case "PullRequest.id":
+		if e.complexity.PullRequest.Id == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Id(childComplexity), true
+
+	case "PullRequest.lastEdit":
+		if e.complexity.PullRequest.LastEdit == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.LastEdit(childComplexity), true
+
+	case "PullRequest.mergeCommit":
+		if e.complexity.PullRequest.MergeCommit == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.MergeCommit(childComplexity), true
+
+	case "PullRequest.participants":
+		if e.complexity.PullRequest.Participants == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Participants(childComplexity), true
+
+	case "PullRequest.reviews":
+		if e.complexity.PullRequest.Reviews == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Reviews(childComplexity), true
+
+	case "PullRequest.sourceBranch":
+		if e.complexity.PullRequest.SourceBranch == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.SourceBranch(childComplexity), true
+
+	case "PullRequest.status":
+		if e.complexity.PullRequest.Status == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Status(childComplexity), true
+
+	case "PullRequest.targetBranch":
+		if e.complexity.PullRequest.TargetBranch == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.TargetBranch(childComplexity), true
+
+	case "PullRequest.title":
+		if e.complexity.PullRequest.Title == nil {
+			break
+		}
+
+		return e.complexity.PullRequest.Title(childComplexity), true
+
+	case "PullRequestComment.author":
+		if e.complexity.PullRequestComment.Author == nil {
+			break
+		}
+
+		return e.complexity.PullRequestComment.Author(childComplexity), true
+
+	case "PullRequestComment.id":
+		if e.complexity.PullRequestComment.ID == nil {
+			break
+		}
+
+		return e.complexity.PullRequestComment.ID(childComplexity), true
+
+	case "PullRequestComment.message":
+		if e.complexity.PullRequestComment.Message == nil {
+			break
+		}
+
+		return e.complexity.PullRequestComment.Message(childComplexity), true
+
+	case "PullRequestCommit.hash":
+		if e.complexity.PullRequestCommit.Hash == nil {
+			break
+		}
+
+		return e.complexity.PullRequestCommit.Hash(childComplexity), true
+
+	case "PullRequestCommit.message":
+		if e.complexity.PullRequestCommit.Message == nil {
+			break
+		}
+
+		return e.complexity.PullRequestCommit.Message(childComplexity), true
+
+	case "PullRequestConnection.edges":
+		if e.complexity.PullRequestConnection.Edges == nil {
+			break
+		}
+
+		return e.complexity.PullRequestConnection.Edges(childComplexity), true
+
+	case "PullRequestConnection.nodes":
+		if e.complexity.PullRequestConnection.Nodes == nil {
+			break
+		}
+
+		return e.complexity.PullRequestConnection.Nodes(childComplexity), true
+
+	case "PullRequestConnection.pageInfo":
+		if e.complexity.PullRequestConnection.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.PullRequestConnection.PageInfo(childComplexity), true
+
+	case "PullRequestConnection.totalCount":
+		if e.complexity.PullRequestConnection.TotalCount == nil {
+			break
+		}
+
+		return e.complexity.PullRequestConnection.TotalCount(childComplexity), true
+
+	case "PullRequestEdge.cursor":
+		if e.complexity.PullRequestEdge.Cursor == nil {
+			break
+		}
+
+		return e.complexity.PullRequestEdge.Cursor(childComplexity), true
+
+	case "PullRequestEdge.node":
+		if e.complexity.PullRequestEdge.Node == nil {
+			break
+		}
+
+		return e.complexity.PullRequestEdge.Node(childComplexity), true
+
 	case "Query.repository":
 		if e.complexity.Query.Repository == nil {
 			break
@@ -1535,6 +1903,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Repository.AllIdentities(childComplexity, args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int)), true
 
+	case "Repository.allPullRequests":
+		if e.complexity.Repository.AllPullRequests == nil {
+			break
+		}
+
+		args, err := ec.field_Repository_allPullRequests_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Repository.AllPullRequests(childComplexity, args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int), args["status"].(*pullrequest.Status)), true
+
 	case "Repository.bug":
 		if e.complexity.Repository.Bug == nil {
 			break
@@ -1566,6 +1946,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Repository.Name(childComplexity), true
 
+	case "Repository.pullRequest":
+		if e.complexity.Repository.PullRequest == nil {
+			break
+		}
+
+		args, err := ec.field_Repository_pullRequest_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Repository.PullRequest(childComplexity, args["prefix"].(string)), true
+
 	case "Repository.userIdentity":
 		if e.complexity.Repository.UserIdentity == nil {
 			break
@@ -1585,6 +1977,76 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Repository.ValidLabels(childComplexity, args["after"].(*string), args["before"].(*string), args["first"].(*int), args["last"].(*int)), true
 
+	case "Review.author":
+		if e.complexity.Review.Author == nil {
+			break
+		}
+
+		return e.complexity.Review.Author(childComplexity), true
+
+	case "Review.comments":
+		if e.complexity.Review.Comments == nil {
+			break
+		}
+
+		return e.complexity.Review.Comments(childComplexity), true
+
+	case "Review.createdAt":
+		if e.complexity.Review.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.Review.CreatedAt(childComplexity), true
+
+	case "Review.id":
+		if e.complexity.Review.ID == nil {
+			break
+		}
+
+		return e.complexity.Review.ID(childComplexity), true
+
+	case "Review.message":
+		if e.complexity.Review.Message == nil {
+			break
+		}
+
+		return e.complexity.Review.Message(childComplexity), true
+
+	case "Review.state":
+		if e.complexity.Review.State == nil {
+			break
+		}
+
+		return e.complexity.Review.State(childComplexity), true
+
+	case "ReviewComment.diffHunk":
+		if e.complexity.ReviewComment.DiffHunk == nil {
+			break
+		}
+
+		return e.complexity.ReviewComment.DiffHunk(childComplexity), true
+
+	case "ReviewComment.line":
+		if e.complexity.ReviewComment.Line == nil {
+			break
+		}
+
+		return e.complexity.ReviewComment.Line(childComplexity), true
+
+	case "ReviewComment.message":
+		if e.complexity.ReviewComment.Message == nil {
+			break
+		}
+
+		return e.complexity.ReviewComment.Message(childComplexity), true
+
+	case "ReviewComment.path":
+		if e.complexity.ReviewComment.Path == nil {
+			break
+		}
+
+		return e.complexity.ReviewComment.Path(childComplexity), true
+
 	case "SetStatusOperation.author":
 		if e.complexity.SetStatusOperation.Author == nil {
 			break
@@ -1785,6 +2247,8 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 		ec.unmarshalInputAddCommentAndCloseBugInput,
 		ec.unmarshalInputAddCommentAndReopenBugInput,
 		ec.unmarshalInputAddCommentInput,
+		ec.unmarshalInputAttachmentInput,
+		ec.unmarshalInputBulkEditInput,
 		ec.unmarshalInputChangeLabelInput,
 		ec.unmarshalInputCloseBugInput,
 		ec.unmarshalInputEditCommentInput,
@@ -1931,6 +2395,12 @@ type Bug implements Authored {
   createdAt: Time!
   lastEdit: Time!
 
+  """True if the authenticated user watch this bug."""
+  watching: Boolean!
+  """True if the bug changed since the authenticated user last saw it. Only the
+  watched bugs can have unread changes."""
+  unread: Boolean!
+
   """The actors of the bug. Actors are Identity that have interacted with the bug."""
   actors(
     """Returns the elements in the list that come after the specified cursor."""
@@ -2060,7 +2530,17 @@ type LabelEdge {
     cursor: String!
     node: Label!
 }`, BuiltIn: false},
-	{Name: "../schema/mutations.graphql", Input: `input NewBugInput {
+	{Name: "../schema/mutations.graphql", Input: `"""A file attached to a message, stored beforehand with the upload endpoint."""
+input AttachmentInput {
+    """The hash of the file."""
+    hash: Hash!
+    """The name of the file."""
+    name: String
+    """The MIME type of the file. If not set, it is detected from the name and the content."""
+    mime: String
+}
+
+input NewBugInput {
     """A unique identifier for the client performing the mutation."""
     clientMutationId: String
     """The name of the repository. If not set, the default repository is used."""
@@ -2071,6 +2551,8 @@ type LabelEdge {
     message: String!
     """The collection of file's hash required for the first message."""
     files: [Hash!]
+    """The files attached to the message, with their name and MIME type, as stored by the upload endpoint."""
+    attachments: [AttachmentInput!]
 }
 
 type NewBugPayload {
@@ -2093,6 +2575,8 @@ input AddCommentInput {
     message: String!
     """The collection of file's hash required for the first message."""
     files: [Hash!]
+    """The files attached to the message, with their name and MIME type, as stored by the upload endpoint."""
+    attachments: [AttachmentInput!]
 }
 
 type AddCommentPayload {
@@ -2115,6 +2599,8 @@ input AddCommentAndCloseBugInput {
     message: String!
     """The collection of file's hash required for the first message."""
     files: [Hash!]
+    """The files attached to the message, with their name and MIME type, as stored by the upload endpoint."""
+    attachments: [AttachmentInput!]
 }
 
 type AddCommentAndCloseBugPayload {
@@ -2139,6 +2625,8 @@ input AddCommentAndReopenBugInput {
     message: String!
     """The collection of file's hash required for the first message."""
     files: [Hash!]
+    """The files attached to the message, with their name and MIME type, as stored by the upload endpoint."""
+    attachments: [AttachmentInput!]
 }
 
 type AddCommentAndReopenBugPayload {
@@ -2268,6 +2756,53 @@ type SetTitlePayload {
     """The resulting operation"""
     operation: SetTitleOperation!
 }
+
+input BulkEditInput {
+    """A unique identifier for the client performing the mutation."""
+    clientMutationId: String
+    """The name of the repository. If not set, the default repository is used."""
+    repoRef: String
+    """The query selecting the bugs to edit."""
+    query: String!
+    """The status to set."""
+    status: Status
+    """The list of label to add."""
+    added: [String!]
+    """The list of label to remove."""
+    removed: [String!]
+    """A comment to add."""
+    message: String
+    """If true, only report the changes without applying them."""
+    dryRun: Boolean
+}
+
+type BulkEditResult {
+    """The ID of the bug."""
+    id: ID!
+    """The bug, if it could be loaded."""
+    bug: Bug
+    """Whether the bug was changed, or would be in a dry-run."""
+    changed: Boolean!
+    """The labels effectively added."""
+    addedLabels: [Label!]!
+    """The labels effectively removed."""
+    removedLabels: [Label!]!
+    """The new status, if it changed."""
+    status: Status
+    """Whether a comment was added."""
+    commented: Boolean!
+    """The error that prevented editing this bug, if any."""
+    error: String
+}
+
+type BulkEditPayload {
+    """A unique identifier for the client performing the mutation."""
+    clientMutationId: String
+    """Whether the changes were only computed and not applied."""
+    dryRun: Boolean!
+    """The outcome for each bug matching the query."""
+    results: [BulkEditResult!]!
+}
 `, BuiltIn: false},
 	{Name: "../schema/operations.graphql", Input: `"""An operation applied to a bug."""
 interface Operation {
@@ -2369,6 +2904,109 @@ type LabelChangeOperation implements Operation & Authored {
     added: [Label!]!
     removed: [Label!]!
 }
+`, BuiltIn: false},
+	{Name: "../schema/pullrequest.graphql", Input: `enum PullRequestStatus {
+  OPEN
+  CLOSED
+  MERGED
+}
+
+"""The outcome of a review."""
+enum ReviewState {
+  COMMENTED
+  APPROVED
+  CHANGES_REQUESTED
+  DISMISSED
+}
+
+"""A commit referenced by a pull request."""
+type PullRequestCommit {
+  hash: String!
+  """The first line of the commit message."""
+  message: String!
+}
+
+"""Represents a comment on a pull request."""
+type PullRequestComment implements Authored {
+  id: CombinedId!
+
+  """The author of this comment."""
+  author: Identity!
+
+  """The message of this comment."""
+  message: String!
+}
+
+"""A comment of a review, on a line of the changes."""
+type ReviewComment {
+  """The path of the commented file."""
+  path: String!
+  """The commented line, or 0 if unknown."""
+  line: Int!
+  """The part of the diff the comment refers to."""
+  diffHunk: String!
+  message: String!
+}
+
+"""A review of a pull request, with its line comments."""
+type Review implements Authored {
+  id: CombinedId!
+
+  """The author of this review."""
+  author: Identity!
+  state: ReviewState!
+  message: String!
+  comments: [ReviewComment!]!
+  createdAt: Time!
+}
+
+type PullRequest implements Authored {
+  """The identifier for this pull request"""
+  id: ID!
+  """The human version (truncated) identifier for this pull request"""
+  humanId: String!
+  status: PullRequestStatus!
+  title: String!
+  """The branch holding the changes."""
+  sourceBranch: String!
+  """The branch the changes are proposed for."""
+  targetBranch: String!
+  """The hash of the commit that merged the pull request, if merged."""
+  mergeCommit: String
+  author: Identity!
+  createdAt: Time!
+  lastEdit: Time!
+
+  """The actors of the pull request. Actors are Identity that have interacted with the pull request."""
+  actors: [Identity!]!
+
+  """The participants of the pull request. Participants are Identity that have
+  created or added a comment or a review on the pull request."""
+  participants: [Identity!]!
+
+  commits: [PullRequestCommit!]!
+  comments: [PullRequestComment!]!
+  reviews: [Review!]!
+}
+
+"""The connection type for PullRequest."""
+type PullRequestConnection {
+  """A list of edges."""
+  edges: [PullRequestEdge!]!
+  nodes: [PullRequest!]!
+  """Information to aid in pagination."""
+  pageInfo: PageInfo!
+  """Identifies the total count of items in the connection."""
+  totalCount: Int!
+}
+
+"""An edge in a connection."""
+type PullRequestEdge {
+  """A cursor for use in pagination."""
+  cursor: String!
+  """The item at the end of the edge."""
+  node: PullRequest!
+}
 `, BuiltIn: false},
 	{Name: "../schema/repository.graphql", Input: `
 type Repository {
@@ -2391,6 +3029,22 @@ type Repository {
 
     bug(prefix: String!): Bug
 
+    """All the pull requests, the most recent first"""
+    allPullRequests(
+        """Returns the elements in the list that come after the specified cursor."""
+        after: String
+        """Returns the elements in the list that come before the specified cursor."""
+        before: String
+        """Returns the first _n_ elements from the list."""
+        first: Int
+        """Returns the last _n_ elements from the list."""
+        last: Int
+        """Only return the pull requests with this status."""
+        status: PullRequestStatus
+    ): PullRequestConnection!
+
+    pullRequest(prefix: String!): PullRequest
+
     """All the identities"""
     allIdentities(
         """Returns the elements in the list that come after the specified cursor."""
@@ -2445,6 +3099,8 @@ type Mutation {
     closeBug(input: CloseBugInput!): CloseBugPayload!
     """Change a bug's title"""
     setTitle(input: SetTitleInput!): SetTitlePayload!
+    """Apply the same changes to all the bugs matching a query"""
+    bulkEdit(input: BulkEditInput!): BulkEditPayload!
 }
 `, BuiltIn: false},
 	{Name: "../schema/timeline.graphql", Input: `"""An item in the timeline of events"""
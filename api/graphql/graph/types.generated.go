@@ -14,6 +14,7 @@ import (
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/MichaelMure/git-bug/api/graphql/models"
 	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/repository"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -401,6 +402,25 @@ func (ec *executionContext) _Authored(ctx context.Context, sel ast.SelectionSet,
 			return graphql.Null
 		}
 		return ec._LabelChangeOperation(ctx, sel, obj)
+	case pullrequest.Comment:
+		return ec._PullRequestComment(ctx, sel, &obj)
+	case *pullrequest.Comment:
+		if obj == nil {
+			return graphql.Null
+		}
+		return ec._PullRequestComment(ctx, sel, obj)
+	case pullrequest.Review:
+		return ec._Review(ctx, sel, &obj)
+	case *pullrequest.Review:
+		if obj == nil {
+			return graphql.Null
+		}
+		return ec._Review(ctx, sel, obj)
+	case *pullrequest.Snapshot:
+		if obj == nil {
+			return graphql.Null
+		}
+		return ec._PullRequest(ctx, sel, obj)
 	case *bug.CreateTimelineItem:
 		if obj == nil {
 			return graphql.Null
@@ -1,14 +1,18 @@
 package graphql
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/99designs/gqlgen/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/MichaelMure/git-bug/api/auth"
 	"github.com/MichaelMure/git-bug/api/graphql/models"
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
 	"github.com/MichaelMure/git-bug/misc/random_bugs"
 	"github.com/MichaelMure/git-bug/repository"
 )
@@ -219,3 +223,346 @@ func TestQueries(t *testing.T) {
 	err := c.Post(query, &resp)
 	assert.NoError(t, err)
 }
+
+func TestPullRequestQueries(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	mrc := cache.NewMultiRepoCache()
+	rc, events := mrc.RegisterDefaultRepository(repo)
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	author, err := rc.Identities().New("John Doe", "jdoe@example.com")
+	require.NoError(t, err)
+
+	pr, _, err := rc.PullRequests().NewRaw(author, 1000, "a pull request", "description", "feature", "master", nil)
+	require.NoError(t, err)
+	_, _, err = pr.AddReviewRaw(author, 1001, pullrequest.ReviewApproved, "lgtm", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, pr.Commit())
+
+	c := client.New(NewHandler(mrc, nil))
+
+	query := `
+     query {
+        repository {
+          allPullRequests(first: 10, status: OPEN) {
+            totalCount
+            nodes {
+              humanId
+              status
+              title
+              sourceBranch
+              author { name }
+              reviews { state message }
+            }
+          }
+        }
+      }`
+
+	var resp struct {
+		Repository struct {
+			AllPullRequests struct {
+				TotalCount int
+				Nodes      []struct {
+					HumanId      string
+					Status       string
+					Title        string
+					SourceBranch string
+					Author       struct {
+						Name string
+					}
+					Reviews []struct {
+						State   string
+						Message string
+					}
+				}
+			}
+		}
+	}
+
+	err = c.Post(query, &resp)
+	require.NoError(t, err)
+
+	prs := resp.Repository.AllPullRequests
+	require.Equal(t, 1, prs.TotalCount)
+	require.Equal(t, pr.Id().Human(), prs.Nodes[0].HumanId)
+	require.Equal(t, "OPEN", prs.Nodes[0].Status)
+	require.Equal(t, "feature", prs.Nodes[0].SourceBranch)
+	require.Equal(t, "John Doe", prs.Nodes[0].Author.Name)
+	require.Equal(t, "APPROVED", prs.Nodes[0].Reviews[0].State)
+}
+
+func TestBulkEditMutation(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	mrc := cache.NewMultiRepoCache()
+	rc, events := mrc.RegisterDefaultRepository(repo)
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	author, err := rc.Identities().New("John Doe", "jdoe@example.com")
+	require.NoError(t, err)
+	require.NoError(t, rc.SetUserIdentity(author))
+
+	bug1, _, err := rc.Bugs().New("first", "message")
+	require.NoError(t, err)
+	bug2, _, err := rc.Bugs().New("second", "message")
+	require.NoError(t, err)
+	_, err = bug2.Close()
+	require.NoError(t, err)
+	require.NoError(t, bug2.Commit())
+
+	c := client.New(auth.Middleware(author.Id())(NewHandler(mrc, nil)))
+
+	mutation := `
+      mutation($dryRun: Boolean) {
+        bulkEdit(input: {query: "sort:creation-asc", status: CLOSED, added: ["triaged"], dryRun: $dryRun}) {
+          dryRun
+          results {
+            id
+            bug { title status labels { name } }
+            changed
+            addedLabels { name }
+            status
+            error
+          }
+        }
+      }`
+
+	type label struct {
+		Name string
+	}
+	var resp struct {
+		BulkEdit struct {
+			DryRun  bool
+			Results []struct {
+				Id  string
+				Bug struct {
+					Title  string
+					Status string
+					Labels []label
+				}
+				Changed     bool
+				AddedLabels []label
+				Status      *string
+				Error       *string
+			}
+		}
+	}
+
+	err = c.Post(mutation, &resp, client.Var("dryRun", true))
+	require.NoError(t, err)
+
+	results := resp.BulkEdit.Results
+	require.True(t, resp.BulkEdit.DryRun)
+	require.Len(t, results, 2)
+	require.Equal(t, bug1.Id().String(), results[0].Id)
+	require.True(t, results[0].Changed)
+	require.Equal(t, "CLOSED", *results[0].Status)
+	require.Equal(t, []label{{"triaged"}}, results[0].AddedLabels)
+	require.Equal(t, "OPEN", results[0].Bug.Status)
+	require.Nil(t, results[0].Error)
+	// already closed, only the label would change
+	require.Nil(t, results[1].Status)
+
+	err = c.Post(mutation, &resp, client.Var("dryRun", false))
+	require.NoError(t, err)
+
+	results = resp.BulkEdit.Results
+	require.False(t, resp.BulkEdit.DryRun)
+	require.Equal(t, "CLOSED", results[0].Bug.Status)
+	require.Equal(t, []label{{"triaged"}}, results[0].Bug.Labels)
+	require.Equal(t, []label{{"triaged"}}, results[1].Bug.Labels)
+}
+
+func TestAttachmentMutation(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	mrc := cache.NewMultiRepoCache()
+	rc, events := mrc.RegisterDefaultRepository(repo)
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	author, err := rc.Identities().New("John Doe", "jdoe@example.com")
+	require.NoError(t, err)
+	require.NoError(t, rc.SetUserIdentity(author))
+
+	b, _, err := rc.Bugs().New("first", "message")
+	require.NoError(t, err)
+
+	// as stored by the upload endpoint
+	stored, err := rc.StoreAttachment("trace.txt", []byte("panic: oops"))
+	require.NoError(t, err)
+
+	c := client.New(auth.Middleware(author.Id())(NewHandler(mrc, nil)))
+
+	mutation := `
+      mutation($prefix: String!, $hash: Hash!) {
+        addComment(input: {prefix: $prefix, message: "see the trace", attachments: [{hash: $hash, name: "trace.log", mime: "text/x-log"}]}) {
+          operation { files }
+        }
+      }`
+
+	var resp struct {
+		AddComment struct {
+			Operation struct {
+				Files []string
+			}
+		}
+	}
+
+	err = c.Post(mutation, &resp, client.Var("prefix", b.Id().Human()), client.Var("hash", stored.Hash))
+	require.NoError(t, err)
+	require.Equal(t, []string{string(stored.Hash)}, resp.AddComment.Operation.Files)
+
+	comments := b.Snapshot().Comments
+	require.Equal(t, []bug.Attachment{{
+		Hash:     stored.Hash,
+		Name:     "trace.log",
+		MimeType: "text/x-log",
+		Size:     int64(len("panic: oops")),
+	}}, comments[len(comments)-1].Attachments)
+
+	// the file must be stored beforehand
+	err = c.Post(mutation, &resp, client.Var("prefix", b.Id().Human()), client.Var("hash", "0123456789012345678901234567890123456789"))
+	require.Error(t, err)
+}
+
+func TestWatchQueries(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	mrc := cache.NewMultiRepoCache()
+	rc, events := mrc.RegisterDefaultRepository(repo)
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	user, err := rc.Identities().New("John Doe", "jdoe@example.com")
+	require.NoError(t, err)
+	require.NoError(t, rc.SetUserIdentity(user))
+	other, err := rc.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+
+	watched, _, err := rc.Bugs().New("watched", "message")
+	require.NoError(t, err)
+	_, _, err = rc.Bugs().New("not watched", "message")
+	require.NoError(t, err)
+
+	require.NoError(t, rc.Watches().Watch(user.Id(), watched.Id()))
+	_, _, err = watched.AddCommentRaw(other, 1234, "a change", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, watched.Commit())
+
+	query := `
+      query {
+        repository {
+          allBugs(query: "watching:me") {
+            nodes { title watching unread }
+          }
+        }
+      }`
+
+	type bugs struct {
+		Repository struct {
+			AllBugs struct {
+				Nodes []struct {
+					Title    string
+					Watching bool
+					Unread   bool
+				}
+			}
+		}
+	}
+
+	var resp bugs
+	c := client.New(auth.Middleware(user.Id())(NewHandler(mrc, nil)))
+	require.NoError(t, c.Post(query, &resp))
+	require.Len(t, resp.Repository.AllBugs.Nodes, 1)
+	require.Equal(t, "watched", resp.Repository.AllBugs.Nodes[0].Title)
+	require.True(t, resp.Repository.AllBugs.Nodes[0].Watching)
+	require.True(t, resp.Repository.AllBugs.Nodes[0].Unread)
+
+	require.NoError(t, rc.Watches().MarkSeen(user.Id(), watched.Id()))
+	resp = bugs{}
+	require.NoError(t, c.Post(query, &resp))
+	require.False(t, resp.Repository.AllBugs.Nodes[0].Unread)
+
+	// another identity doesn't watch the bug
+	resp = bugs{}
+	c = client.New(auth.Middleware(other.Id())(NewHandler(mrc, nil)))
+	require.NoError(t, c.Post(strings.Replace(query, "watching:me", "", 1), &resp))
+	require.Len(t, resp.Repository.AllBugs.Nodes, 2)
+	for _, node := range resp.Repository.AllBugs.Nodes {
+		require.False(t, node.Watching)
+		require.False(t, node.Unread)
+	}
+}
+
+func TestQueryMe(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	mrc := cache.NewMultiRepoCache()
+	rc, events := mrc.RegisterDefaultRepository(repo)
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	john, err := rc.Identities().New("John Doe", "jdoe@example.com")
+	require.NoError(t, err)
+	rene, err := rc.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+
+	_, _, err = rc.Bugs().NewRaw(john, 1234, "by john", "message", nil, nil)
+	require.NoError(t, err)
+	_, _, err = rc.Bugs().NewRaw(rene, 1235, "by rene", "message", nil, nil)
+	require.NoError(t, err)
+
+	query := `
+      query {
+        repository {
+          allBugs(query: "author:me") {
+            nodes { title }
+          }
+        }
+      }`
+
+	type bugs struct {
+		Repository struct {
+			AllBugs struct {
+				Nodes []struct {
+					Title string
+				}
+			}
+		}
+	}
+
+	// "me" is the authenticated user, not the user identity of the repository
+	for _, tc := range []struct {
+		user  *cache.IdentityCache
+		title string
+	}{
+		{john, "by john"},
+		{rene, "by rene"},
+	} {
+		var resp bugs
+		c := client.New(auth.Middleware(tc.user.Id())(NewHandler(mrc, nil)))
+		require.NoError(t, c.Post(query, &resp))
+		require.Len(t, resp.Repository.AllBugs.Nodes, 1)
+		require.Equal(t, tc.title, resp.Repository.AllBugs.Nodes[0].Title)
+	}
+
+	// without authentication, "me" can't be resolved
+	var resp bugs
+	c := client.New(NewHandler(mrc, nil))
+	require.Error(t, c.Post(query, &resp))
+
+	// but other queries still work
+	resp = bugs{}
+	require.NoError(t, c.Post(strings.Replace(query, "author:me", "author:descartes", 1), &resp))
+	require.Len(t, resp.Repository.AllBugs.Nodes, 1)
+	require.Equal(t, "by rene", resp.Repository.AllBugs.Nodes[0].Title)
+}
@@ -4,6 +4,9 @@ package models
 
 import (
 	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/entity/dag"
 	"github.com/MichaelMure/git-bug/repository"
 )
@@ -24,6 +27,8 @@ type AddCommentAndCloseBugInput struct {
 	Message string `json:"message"`
 	// The collection of file's hash required for the first message.
 	Files []repository.Hash `json:"files,omitempty"`
+	// The files attached to the message, with their name and MIME type, as stored by the upload endpoint.
+	Attachments []*AttachmentInput `json:"attachments,omitempty"`
 }
 
 type AddCommentAndCloseBugPayload struct {
@@ -48,6 +53,8 @@ type AddCommentAndReopenBugInput struct {
 	Message string `json:"message"`
 	// The collection of file's hash required for the first message.
 	Files []repository.Hash `json:"files,omitempty"`
+	// The files attached to the message, with their name and MIME type, as stored by the upload endpoint.
+	Attachments []*AttachmentInput `json:"attachments,omitempty"`
 }
 
 type AddCommentAndReopenBugPayload struct {
@@ -72,6 +79,8 @@ type AddCommentInput struct {
 	Message string `json:"message"`
 	// The collection of file's hash required for the first message.
 	Files []repository.Hash `json:"files,omitempty"`
+	// The files attached to the message, with their name and MIME type, as stored by the upload endpoint.
+	Attachments []*AttachmentInput `json:"attachments,omitempty"`
 }
 
 type AddCommentPayload struct {
@@ -83,6 +92,16 @@ type AddCommentPayload struct {
 	Operation *bug.AddCommentOperation `json:"operation"`
 }
 
+// A file attached to a message, stored beforehand with the upload endpoint.
+type AttachmentInput struct {
+	// The hash of the file.
+	Hash repository.Hash `json:"hash"`
+	// The name of the file.
+	Name *string `json:"name,omitempty"`
+	// The MIME type of the file. If not set, it is detected from the name and the content.
+	Mime *string `json:"mime,omitempty"`
+}
+
 // The connection type for Bug.
 type BugConnection struct {
 	// A list of edges.
@@ -102,6 +121,53 @@ type BugEdge struct {
 	Node BugWrapper `json:"node"`
 }
 
+type BulkEditInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	// The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef,omitempty"`
+	// The query selecting the bugs to edit.
+	Query string `json:"query"`
+	// The status to set.
+	Status *common.Status `json:"status,omitempty"`
+	// The list of label to add.
+	Added []string `json:"added,omitempty"`
+	// The list of label to remove.
+	Removed []string `json:"removed,omitempty"`
+	// A comment to add.
+	Message *string `json:"message,omitempty"`
+	// If true, only report the changes without applying them.
+	DryRun *bool `json:"dryRun,omitempty"`
+}
+
+type BulkEditPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	// Whether the changes were only computed and not applied.
+	DryRun bool `json:"dryRun"`
+	// The outcome for each bug matching the query.
+	Results []*BulkEditResult `json:"results"`
+}
+
+type BulkEditResult struct {
+	// The ID of the bug.
+	ID entity.Id `json:"id"`
+	// The bug, if it could be loaded.
+	Bug BugWrapper `json:"bug,omitempty"`
+	// Whether the bug was changed, or would be in a dry-run.
+	Changed bool `json:"changed"`
+	// The labels effectively added.
+	AddedLabels []bug.Label `json:"addedLabels"`
+	// The labels effectively removed.
+	RemovedLabels []bug.Label `json:"removedLabels"`
+	// The new status, if it changed.
+	Status *common.Status `json:"status,omitempty"`
+	// Whether a comment was added.
+	Commented bool `json:"commented"`
+	// The error that prevented editing this bug, if any.
+	Error *string `json:"error,omitempty"`
+}
+
 type ChangeLabelInput struct {
 	// A unique identifier for the client performing the mutation.
 	ClientMutationID *string `json:"clientMutationId,omitempty"`
@@ -213,6 +279,8 @@ type NewBugInput struct {
 	Message string `json:"message"`
 	// The collection of file's hash required for the first message.
 	Files []repository.Hash `json:"files,omitempty"`
+	// The files attached to the message, with their name and MIME type, as stored by the upload endpoint.
+	Attachments []*AttachmentInput `json:"attachments,omitempty"`
 }
 
 type NewBugPayload struct {
@@ -268,6 +336,25 @@ type PageInfo struct {
 	EndCursor string `json:"endCursor"`
 }
 
+// The connection type for PullRequest.
+type PullRequestConnection struct {
+	// A list of edges.
+	Edges []*PullRequestEdge      `json:"edges"`
+	Nodes []*pullrequest.Snapshot `json:"nodes"`
+	// Information to aid in pagination.
+	PageInfo *PageInfo `json:"pageInfo"`
+	// Identifies the total count of items in the connection.
+	TotalCount int `json:"totalCount"`
+}
+
+// An edge in a connection.
+type PullRequestEdge struct {
+	// A cursor for use in pagination.
+	Cursor string `json:"cursor"`
+	// The item at the end of the edge.
+	Node *pullrequest.Snapshot `json:"node"`
+}
+
 type SetTitleInput struct {
 	// A unique identifier for the client performing the mutation.
 	ClientMutationID *string `json:"clientMutationId,omitempty"`
@@ -27,6 +27,7 @@ type BugWrapper interface {
 	CreatedAt() time.Time
 	Timeline() ([]bug.TimelineItem, error)
 	Operations() ([]dag.Operation, error)
+	Repo() *cache.RepoCache
 
 	IsAuthored()
 }
@@ -154,14 +155,23 @@ func (lb *lazyBug) Operations() ([]dag.Operation, error) {
 	return lb.snap.Operations, nil
 }
 
+func (lb *lazyBug) Repo() *cache.RepoCache {
+	return lb.cache
+}
+
 var _ BugWrapper = &loadedBug{}
 
 type loadedBug struct {
 	*bug.Snapshot
+	repo *cache.RepoCache
+}
+
+func NewLoadedBug(repo *cache.RepoCache, snap *bug.Snapshot) *loadedBug {
+	return &loadedBug{Snapshot: snap, repo: repo}
 }
 
-func NewLoadedBug(snap *bug.Snapshot) *loadedBug {
-	return &loadedBug{Snapshot: snap}
+func (l *loadedBug) Repo() *cache.RepoCache {
+	return l.repo
 }
 
 func (l *loadedBug) LastEdit() time.Time {
@@ -2,7 +2,9 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 
+	"github.com/MichaelMure/git-bug/api/auth"
 	"github.com/MichaelMure/git-bug/api/graphql/connections"
 	"github.com/MichaelMure/git-bug/api/graphql/graph"
 	"github.com/MichaelMure/git-bug/api/graphql/models"
@@ -18,6 +20,28 @@ func (bugResolver) HumanID(_ context.Context, obj models.BugWrapper) (string, er
 	return obj.Id().Human(), nil
 }
 
+func (bugResolver) Watching(ctx context.Context, obj models.BugWrapper) (bool, error) {
+	user, err := auth.UserFromCtx(ctx, obj.Repo())
+	if errors.Is(err, auth.ErrNotAuthenticated) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return obj.Repo().Watches().IsWatching(user.Id(), obj.Id())
+}
+
+func (bugResolver) Unread(ctx context.Context, obj models.BugWrapper) (bool, error) {
+	user, err := auth.UserFromCtx(ctx, obj.Repo())
+	if errors.Is(err, auth.ErrNotAuthenticated) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return obj.Repo().Watches().HasUnread(user.Id(), obj.Id())
+}
+
 func (bugResolver) Comments(_ context.Context, obj models.BugWrapper, after *string, before *string, first *int, last *int) (*models.CommentConnection, error) {
 	input := models.ConnectionInput{
 		Before: before,
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/MichaelMure/git-bug/api/auth"
@@ -9,6 +10,8 @@ import (
 	"github.com/MichaelMure/git-bug/api/graphql/models"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/repository"
 	"github.com/MichaelMure/git-bug/util/text"
 )
 
@@ -39,6 +42,36 @@ func (r mutationResolver) getBug(repoRef *string, bugPrefix string) (*cache.Repo
 	return repo, b, nil
 }
 
+// attachmentFiles return the files of a message, and the metadata describing
+// the attachments among them
+func attachmentFiles(repo *cache.RepoCache, files []repository.Hash, inputs []*models.AttachmentInput) ([]repository.Hash, map[string]string, error) {
+	if len(inputs) == 0 {
+		return files, nil, nil
+	}
+
+	attachments := make([]bug.Attachment, len(inputs))
+	for i, input := range inputs {
+		data, err := repo.ReadData(input.Hash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading the attachment %s: %w", input.Hash, err)
+		}
+		var name string
+		if input.Name != nil {
+			name = *input.Name
+		}
+		// the data is already stored, this only describe it
+		attachments[i], err = repo.StoreAttachment(name, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if input.Mime != nil && *input.Mime != "" {
+			attachments[i].MimeType = *input.Mime
+		}
+	}
+
+	return append(files, bug.AttachmentHashes(attachments)...), bug.AttachmentsMetadata(attachments), nil
+}
+
 func (r mutationResolver) NewBug(ctx context.Context, input models.NewBugInput) (*models.NewBugPayload, error) {
 	repo, err := r.getRepo(input.RepoRef)
 	if err != nil {
@@ -50,19 +83,24 @@ func (r mutationResolver) NewBug(ctx context.Context, input models.NewBugInput)
 		return nil, err
 	}
 
+	files, metadata, err := attachmentFiles(repo, input.Files, input.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
 	b, op, err := repo.Bugs().NewRaw(author,
 		time.Now().Unix(),
 		text.CleanupOneLine(input.Title),
 		text.Cleanup(input.Message),
-		input.Files,
-		nil)
+		files,
+		metadata)
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.NewBugPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
@@ -78,11 +116,16 @@ func (r mutationResolver) AddComment(ctx context.Context, input models.AddCommen
 		return nil, err
 	}
 
+	files, metadata, err := attachmentFiles(repo, input.Files, input.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
 	_, op, err := b.AddCommentRaw(author,
 		time.Now().Unix(),
 		text.Cleanup(input.Message),
-		input.Files,
-		nil)
+		files,
+		metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +137,7 @@ func (r mutationResolver) AddComment(ctx context.Context, input models.AddCommen
 
 	return &models.AddCommentPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
@@ -110,11 +153,16 @@ func (r mutationResolver) AddCommentAndClose(ctx context.Context, input models.A
 		return nil, err
 	}
 
+	files, metadata, err := attachmentFiles(repo, input.Files, input.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
 	_, opAddComment, err := b.AddCommentRaw(author,
 		time.Now().Unix(),
 		text.Cleanup(input.Message),
-		input.Files,
-		nil)
+		files,
+		metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +179,7 @@ func (r mutationResolver) AddCommentAndClose(ctx context.Context, input models.A
 
 	return &models.AddCommentAndCloseBugPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		CommentOperation: opAddComment,
 		StatusOperation:  opClose,
 	}, nil
@@ -148,11 +196,16 @@ func (r mutationResolver) AddCommentAndReopen(ctx context.Context, input models.
 		return nil, err
 	}
 
+	files, metadata, err := attachmentFiles(repo, input.Files, input.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
 	_, opAddComment, err := b.AddCommentRaw(author,
 		time.Now().Unix(),
 		text.Cleanup(input.Message),
-		input.Files,
-		nil)
+		files,
+		metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -169,7 +222,7 @@ func (r mutationResolver) AddCommentAndReopen(ctx context.Context, input models.
 
 	return &models.AddCommentAndReopenBugPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		CommentOperation: opAddComment,
 		StatusOperation:  opReopen,
 	}, nil
@@ -209,7 +262,7 @@ func (r mutationResolver) EditComment(ctx context.Context, input models.EditComm
 
 	return &models.EditCommentPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
@@ -248,7 +301,7 @@ func (r mutationResolver) ChangeLabels(ctx context.Context, input *models.Change
 
 	return &models.ChangeLabelPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 		Results:          resultsPtr,
 	}, nil
@@ -277,7 +330,7 @@ func (r mutationResolver) OpenBug(ctx context.Context, input models.OpenBugInput
 
 	return &models.OpenBugPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
@@ -305,7 +358,7 @@ func (r mutationResolver) CloseBug(ctx context.Context, input models.CloseBugInp
 
 	return &models.CloseBugPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
@@ -338,7 +391,78 @@ func (r mutationResolver) SetTitle(ctx context.Context, input models.SetTitleInp
 
 	return &models.SetTitlePayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
+
+func (r mutationResolver) BulkEdit(ctx context.Context, input models.BulkEditInput) (*models.BulkEditPayload, error) {
+	repo, err := r.getRepo(input.RepoRef)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := auth.UserFromCtx(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	edit := cache.BulkEdit{
+		AddLabels:    text.CleanupOneLineArray(input.Added),
+		RemoveLabels: text.CleanupOneLineArray(input.Removed),
+	}
+	if input.Status != nil {
+		edit.Status = *input.Status
+	}
+	if input.Message != nil {
+		edit.Comment = text.Cleanup(*input.Message)
+	}
+	dryRun := input.DryRun != nil && *input.DryRun
+
+	q, err := query.Parse(input.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	// "me" in the query is the authenticated user
+	ids, err := repo.Bugs().QueryAs(q, func() (*cache.IdentityCache, error) {
+		return author, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := repo.Bugs().BulkEdit(author, time.Now().Unix(), ids, edit, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &models.BulkEditPayload{
+		ClientMutationID: input.ClientMutationID,
+		DryRun:           dryRun,
+		Results:          make([]*models.BulkEditResult, len(results)),
+	}
+	for i, result := range results {
+		res := &models.BulkEditResult{
+			ID:            result.Id,
+			Changed:       result.Changed(),
+			AddedLabels:   append([]bug.Label{}, result.AddedLabels...),
+			RemovedLabels: append([]bug.Label{}, result.RemovedLabels...),
+			Commented:     result.Commented,
+		}
+		if result.Status != 0 {
+			status := result.Status
+			res.Status = &status
+		}
+		if result.Err != nil {
+			msg := result.Err.Error()
+			res.Error = &msg
+		}
+		if excerpt, err := repo.Bugs().ResolveExcerpt(result.Id); err == nil {
+			res.Bug = models.NewLazyBug(repo, excerpt)
+		}
+		payload.Results[i] = res
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,78 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/MichaelMure/git-bug/api/graphql/graph"
+	"github.com/MichaelMure/git-bug/api/graphql/models"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+var _ graph.PullRequestResolver = &pullRequestResolver{}
+
+type pullRequestResolver struct{}
+
+func (pullRequestResolver) HumanID(_ context.Context, obj *pullrequest.Snapshot) (string, error) {
+	return obj.Id().Human(), nil
+}
+
+func (pullRequestResolver) Author(_ context.Context, obj *pullrequest.Snapshot) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (pullRequestResolver) CreatedAt(_ context.Context, obj *pullrequest.Snapshot) (*time.Time, error) {
+	return &obj.CreateTime, nil
+}
+
+func (pullRequestResolver) LastEdit(_ context.Context, obj *pullrequest.Snapshot) (*time.Time, error) {
+	t := obj.EditTime()
+	return &t, nil
+}
+
+func (pullRequestResolver) Actors(_ context.Context, obj *pullrequest.Snapshot) ([]models.IdentityWrapper, error) {
+	return loadedIdentities(obj.Actors), nil
+}
+
+func (pullRequestResolver) Participants(_ context.Context, obj *pullrequest.Snapshot) ([]models.IdentityWrapper, error) {
+	return loadedIdentities(obj.Participants), nil
+}
+
+func loadedIdentities(identities []identity.Interface) []models.IdentityWrapper {
+	result := make([]models.IdentityWrapper, len(identities))
+	for i, id := range identities {
+		result[i] = models.NewLoadedIdentity(id)
+	}
+	return result
+}
+
+var _ graph.PullRequestCommentResolver = &pullRequestCommentResolver{}
+
+type pullRequestCommentResolver struct{}
+
+func (pullRequestCommentResolver) ID(_ context.Context, obj *pullrequest.Comment) (entity.CombinedId, error) {
+	return obj.CombinedId(), nil
+}
+
+func (pullRequestCommentResolver) Author(_ context.Context, obj *pullrequest.Comment) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+var _ graph.ReviewResolver = &reviewResolver{}
+
+type reviewResolver struct{}
+
+func (reviewResolver) ID(_ context.Context, obj *pullrequest.Review) (entity.CombinedId, error) {
+	return obj.CombinedId(), nil
+}
+
+func (reviewResolver) Author(_ context.Context, obj *pullrequest.Review) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (reviewResolver) CreatedAt(_ context.Context, obj *pullrequest.Review) (*time.Time, error) {
+	t := obj.UnixTime().Time()
+	return &t, nil
+}
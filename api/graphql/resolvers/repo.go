@@ -7,7 +7,9 @@ import (
 	"github.com/MichaelMure/git-bug/api/graphql/connections"
 	"github.com/MichaelMure/git-bug/api/graphql/graph"
 	"github.com/MichaelMure/git-bug/api/graphql/models"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/query"
 )
@@ -21,7 +23,7 @@ func (repoResolver) Name(_ context.Context, obj *models.Repository) (*string, er
 	return &name, nil
 }
 
-func (repoResolver) AllBugs(_ context.Context, obj *models.Repository, after *string, before *string, first *int, last *int, queryStr *string) (*models.BugConnection, error) {
+func (repoResolver) AllBugs(ctx context.Context, obj *models.Repository, after *string, before *string, first *int, last *int, queryStr *string) (*models.BugConnection, error) {
 	input := models.ConnectionInput{
 		Before: before,
 		After:  after,
@@ -40,8 +42,13 @@ func (repoResolver) AllBugs(_ context.Context, obj *models.Repository, after *st
 		q = query.NewQuery()
 	}
 
+	// "me" in the query is the authenticated user
+	me := func() (*cache.IdentityCache, error) {
+		return auth.UserFromCtx(ctx, obj.Repo)
+	}
+
 	// Simply pass a []string with the ids to the pagination algorithm
-	source, err := obj.Repo.Bugs().Query(q)
+	source, err := obj.Repo.Bugs().QueryAs(q, me)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +101,70 @@ func (repoResolver) Bug(_ context.Context, obj *models.Repository, prefix string
 	return models.NewLazyBug(obj.Repo, excerpt), nil
 }
 
+func (repoResolver) AllPullRequests(_ context.Context, obj *models.Repository, after *string, before *string, first *int, last *int, status *pullrequest.Status) (*models.PullRequestConnection, error) {
+	input := models.ConnectionInput{
+		Before: before,
+		After:  after,
+		First:  first,
+		Last:   last,
+	}
+
+	var statuses []pullrequest.Status
+	if status != nil {
+		statuses = append(statuses, *status)
+	}
+
+	// Simply pass a []string with the ids to the pagination algorithm
+	source := obj.Repo.PullRequests().QueryStatus(statuses...)
+
+	// The edger create a custom edge holding just the id
+	edger := func(id entity.Id, offset int) connections.Edge {
+		return connections.LazyPullRequestEdge{
+			Id:     id,
+			Cursor: connections.OffsetToCursor(offset),
+		}
+	}
+
+	// The conMaker will finally load and compile pull requests from git to replace the selected edges
+	conMaker := func(lazyEdges []*connections.LazyPullRequestEdge, lazyNode []entity.Id, info *models.PageInfo, totalCount int) (*models.PullRequestConnection, error) {
+		edges := make([]*models.PullRequestEdge, len(lazyEdges))
+		nodes := make([]*pullrequest.Snapshot, len(lazyEdges))
+
+		for i, lazyEdge := range lazyEdges {
+			pr, err := obj.Repo.PullRequests().Resolve(lazyEdge.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			snap := pr.Snapshot()
+
+			edges[i] = &models.PullRequestEdge{
+				Cursor: lazyEdge.Cursor,
+				Node:   snap,
+			}
+			nodes[i] = snap
+		}
+
+		return &models.PullRequestConnection{
+			Edges:      edges,
+			Nodes:      nodes,
+			PageInfo:   info,
+			TotalCount: totalCount,
+		}, nil
+	}
+
+	return connections.LazyPullRequestCon(source, edger, conMaker, input)
+}
+
+func (repoResolver) PullRequest(_ context.Context, obj *models.Repository, prefix string) (*pullrequest.Snapshot, error) {
+	pr, err := obj.Repo.PullRequests().ResolvePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return pr.Snapshot(), nil
+}
+
 func (repoResolver) AllIdentities(_ context.Context, obj *models.Repository, after *string, before *string, first *int, last *int) (*models.IdentityConnection, error) {
 	input := models.ConnectionInput{
 		Before: before,
@@ -46,6 +46,18 @@ func (r RootResolver) Comment() graph.CommentResolver {
 	return &commentResolver{}
 }
 
+func (RootResolver) PullRequest() graph.PullRequestResolver {
+	return &pullRequestResolver{}
+}
+
+func (RootResolver) PullRequestComment() graph.PullRequestCommentResolver {
+	return &pullRequestCommentResolver{}
+}
+
+func (RootResolver) Review() graph.ReviewResolver {
+	return &reviewResolver{}
+}
+
 func (RootResolver) Label() graph.LabelResolver {
 	return &labelResolver{}
 }
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+// implement a http.Handler that will receive the webhooks of a remote bug
+// tracker and import the affected issue with the corresponding bridge.
+//
+// Expected gorilla/mux parameters:
+//   - "repo" : the ref of the repo or "" for the default one
+//   - "bridge" : the name of the bridge receiving the webhook
+type bridgeWebhookHandler struct {
+	mrc *cache.MultiRepoCache
+
+	// imports are serialized to avoid importing concurrently the same issue
+	mu sync.Mutex
+}
+
+func NewBridgeWebhookHandler(mrc *cache.MultiRepoCache) http.Handler {
+	return &bridgeWebhookHandler{mrc: mrc}
+}
+
+func (bwh *bridgeWebhookHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	var repo *cache.RepoCache
+	var err error
+
+	repoVar := mux.Vars(r)["repo"]
+	switch repoVar {
+	case "":
+		repo, err = bwh.mrc.DefaultRepo()
+	default:
+		repo, err = bwh.mrc.ResolveRepo(repoVar)
+	}
+
+	if err != nil {
+		http.Error(rw, "invalid repo reference", http.StatusBadRequest)
+		return
+	}
+
+	name := mux.Vars(r)["bridge"]
+	if !core.BridgeExist(repo, name) {
+		http.Error(rw, "unknown bridge", http.StatusNotFound)
+		return
+	}
+
+	b, err := bridge.LoadBridge(repo, name)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("loading bridge: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// 25MB (github limit)
+	var maxPayloadSize int64 = 25 * 1000 * 1000
+	payload, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(rw, "payload too big (25MB max)", http.StatusBadRequest)
+		return
+	}
+
+	bwh.mu.Lock()
+	defer bwh.mu.Unlock()
+
+	events, err := b.ImportWebhook(r.Context(), r.Header, payload)
+	switch {
+	case errors.Is(err, core.ErrWebhookSignature):
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	case errors.Is(err, core.ErrWebhookNotSupported), errors.Is(err, core.ErrWebhookNotConfigured):
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var errs []string
+	for result := range events {
+		if result.Event == core.ImportEventError && result.Err != context.Canceled {
+			errs = append(errs, result.String())
+		}
+	}
+
+	if len(errs) > 0 {
+		http.Error(rw, strings.Join(errs, "\n"), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBridgeWebhookHandler(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	mrc := cache.NewMultiRepoCache()
+	_, events := mrc.RegisterDefaultRepository(repo)
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	for key, value := range map[string]string{
+		"git-bug.bridge.gh.target":        "github",
+		"git-bug.bridge.gh.owner":         "MichaelMure",
+		"git-bug.bridge.gh.project":       "git-bug",
+		"git-bug.bridge.gh.default-login": "test",
+	} {
+		require.NoError(t, repo.LocalConfig().StoreString(key, value))
+	}
+
+	token := auth.NewToken("github", "token")
+	token.SetMetadata(auth.MetaKeyLogin, "test")
+	require.NoError(t, auth.Store(repo, token))
+
+	handler := NewBridgeWebhookHandler(mrc)
+
+	post := func(bridge string, header http.Header) int {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewBufferString(`{}`))
+		for key, values := range header {
+			r.Header[key] = values
+		}
+		r = mux.SetURLVars(r, map[string]string{
+			"repo":   "",
+			"bridge": bridge,
+		})
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusNotFound, post("unknown", nil))
+
+	// no secret configured, the webhook is disabled
+	require.Equal(t, http.StatusNotFound, post("gh", nil))
+
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.bridge.gh.webhook-secret", "secret"))
+
+	require.Equal(t, http.StatusUnauthorized, post("gh", http.Header{
+		"X-Github-Event":      {"issues"},
+		"X-Hub-Signature-256": {"sha256=0000"},
+	}))
+
+	// HMAC-SHA256 of "{}" with "secret"
+	require.Equal(t, http.StatusNoContent, post("gh", http.Header{
+		"X-Github-Event":      {"ping"},
+		"X-Hub-Signature-256": {"sha256=77325902caca812dc259733aacd046b73817372c777b8d95b402647474516e13"},
+	}))
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/api/feed"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/query"
+)
+
+// implement a http.Handler that serve Atom feeds of the bug timelines. As it
+// only reads the bugs, it's available in read-only mode and to anonymous
+// users, except for the queries using "me", that designate the authenticated
+// user.
+//
+// Expected gorilla/mux parameters:
+//   - "repo" : the ref of the repo or "" for the default one
+//
+// Query parameters:
+//   - "q" : the query selecting the bugs, for a feed of all their events
+//   - "bug" : the id or id prefix of a bug, for a feed of its comments and changes
+//   - "limit" : the maximum number of entries
+type feedHandler struct {
+	mrc *cache.MultiRepoCache
+}
+
+func NewFeedHandler(mrc *cache.MultiRepoCache) http.Handler {
+	return &feedHandler{mrc: mrc}
+}
+
+func (fh *feedHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	var repo *cache.RepoCache
+	var err error
+
+	repoVar := mux.Vars(r)["repo"]
+	switch repoVar {
+	case "":
+		repo, err = fh.mrc.DefaultRepo()
+	default:
+		repo, err = fh.mrc.ResolveRepo(repoVar)
+	}
+
+	if err != nil {
+		http.Error(rw, "invalid repo reference", http.StatusBadRequest)
+		return
+	}
+
+	opts := feed.Options{BaseURL: baseURL(r)}
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		opts.Limit, err = strconv.Atoi(raw)
+		if err != nil || opts.Limit <= 0 {
+			http.Error(rw, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var f *feed.Feed
+
+	if prefix := r.URL.Query().Get("bug"); prefix != "" {
+		b, err := repo.Bugs().ResolvePrefix(prefix)
+		if entity.IsErrMultipleMatch(err) {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+		f = feed.BugFeed(b, opts)
+	} else {
+		raw := r.URL.Query().Get("q")
+		q, err := query.Parse(raw)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid query: %v", err), http.StatusBadRequest)
+			return
+		}
+		f, err = feed.QueryFeed(repo, q, raw, func() (*cache.IdentityCache, error) {
+			return auth.UserFromCtx(r.Context(), repo)
+		}, opts)
+		if errors.Is(err, auth.ErrNotAuthenticated) {
+			http.Error(rw, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	rw.Header().Set("Content-Type", feed.ContentType)
+	if err := f.Write(rw); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// baseURL return the URL of the server, as seen by the client
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/api/feed"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestFeedHandler(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	mrc := cache.NewMultiRepoCache()
+	repoCache, events := mrc.RegisterDefaultRepository(repo)
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	author, err := repoCache.Identities().New("test identity", "test@test.org")
+	require.NoError(t, err)
+	require.NoError(t, repoCache.SetUserIdentity(author))
+
+	b, _, err := repoCache.Bugs().New("crash on start", "it crashes")
+	require.NoError(t, err)
+	_, _, err = b.AddComment("same here")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	handler := NewFeedHandler(mrc)
+
+	// no authentication needed, as in read-only mode
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", target, nil)
+		r = mux.SetURLVars(r, map[string]string{"repo": ""})
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get("/feed?q=status:open")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, feed.ContentType, w.Header().Get("Content-Type"))
+	require.Contains(t, w.Body.String(), "<title>Bugs matching status:open</title>")
+	require.Contains(t, w.Body.String(), "crash on start: test identity commented")
+	require.Contains(t, w.Body.String(), `href="http://example.com/bug/`+b.Id().Human()+`"`)
+
+	w = get("/feed?q=status:closed")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "<entry>")
+
+	w = get("/feed?bug=" + b.Id().Human() + "&limit=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "<title>crash on start</title>")
+	require.Contains(t, w.Body.String(), "same here")
+	require.NotContains(t, w.Body.String(), "it crashes")
+
+	// "me" is the authenticated user
+	require.Equal(t, http.StatusUnauthorized, get("/feed?q=author:me").Code)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/feed?q=author:me", nil)
+	r = mux.SetURLVars(r, map[string]string{"repo": ""})
+	r = r.WithContext(auth.CtxWithUser(r.Context(), author.Id()))
+	handler.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "crash on start: test identity opened the bug")
+
+	require.Equal(t, http.StatusNotFound, get("/feed?bug=ffffff").Code)
+	require.Equal(t, http.StatusBadRequest, get("/feed?q=status:").Code)
+	require.Equal(t, http.StatusBadRequest, get("/feed?limit=-1").Code)
+}
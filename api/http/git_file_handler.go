@@ -57,5 +57,26 @@ func (gfh *gitFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Any file can be uploaded. To not have an HTML or SVG file executed on
+	// the origin of the webui, only the raster images are displayed inline,
+	// and the browser must not guess another content type.
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	contentType := http.DetectContentType(data)
+	if inlineContentTypes[contentType] {
+		rw.Header().Set("Content-Type", contentType)
+	} else {
+		rw.Header().Set("Content-Type", "application/octet-stream")
+		rw.Header().Set("Content-Disposition", "attachment")
+	}
+
 	http.ServeContent(rw, r, "", time.Now(), bytes.NewReader(data))
 }
+
+// inlineContentTypes are the content types of the files that are safe to
+// display in the browser
+var inlineContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
@@ -2,11 +2,13 @@ package http
 
 import (
 	"bytes"
+	"encoding/json"
 	"image"
 	"image/png"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/MichaelMure/git-bug/api/auth"
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/repository"
 )
 
@@ -35,58 +38,109 @@ func TestGitFileHandlers(t *testing.T) {
 
 	// UPLOAD
 
-	uploadHandler := NewGitUploadFileHandler(mrc)
-
 	img := image.NewNRGBA(image.Rect(0, 0, 50, 50))
 	data := &bytes.Buffer{}
 	err = png.Encode(data, img)
 	require.NoError(t, err)
 
+	w := uploadFile(t, mrc, author.Id(), "noname", data.Bytes())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	hash := uploadedHash(t, w)
+	assert.Equal(t, `{"hash":"`+hash+`","name":"noname","mime":"image/png","size":`+strconv.Itoa(data.Len())+`}`, w.Body.String())
+
+	// DOWNLOAD
+
+	w = downloadFile(t, mrc, author.Id(), hash)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+
+	assert.Equal(t, data.Bytes(), w.Body.Bytes())
+}
+
+func TestGitFileHandlersHTML(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	mrc := cache.NewMultiRepoCache()
+	repoCache, events := mrc.RegisterDefaultRepository(repo)
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	author, err := repoCache.Identities().New("test identity", "test@test.org")
+	require.NoError(t, err)
+
+	data := []byte(`<html><body><script>alert(document.cookie)</script></body></html>`)
+
+	w := uploadFile(t, mrc, author.Id(), "page.html", data)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// the file is never rendered by the browser
+	w = downloadFile(t, mrc, author.Id(), uploadedHash(t, w))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	require.Equal(t, "attachment", w.Header().Get("Content-Disposition"))
+	require.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	require.Equal(t, data, w.Body.Bytes())
+}
+
+func uploadFile(t *testing.T, mrc *cache.MultiRepoCache, userId entity.Id, name string, data []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("uploadfile", "noname")
-	assert.NoError(t, err)
+	part, err := writer.CreateFormFile("uploadfile", name)
+	require.NoError(t, err)
 
-	_, err = part.Write(data.Bytes())
-	assert.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
 
 	err = writer.Close()
-	assert.NoError(t, err)
+	require.NoError(t, err)
 
 	w := httptest.NewRecorder()
 	r, _ := http.NewRequest("GET", "/", body)
 	r.Header.Add("Content-Type", writer.FormDataContentType())
 
 	// Simulate auth
-	r = r.WithContext(auth.CtxWithUser(r.Context(), author.Id()))
+	r = r.WithContext(auth.CtxWithUser(r.Context(), userId))
 
 	// Handler's params
 	r = mux.SetURLVars(r, map[string]string{
 		"repo": "",
 	})
 
-	uploadHandler.ServeHTTP(w, r)
+	NewGitUploadFileHandler(mrc).ServeHTTP(w, r)
+	return w
+}
 
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, `{"hash":"3426a1488292d8f3f3c59ca679681336542b986f"}`, w.Body.String())
-	// DOWNLOAD
+func uploadedHash(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var resp struct {
+		Hash string `json:"hash"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Hash
+}
 
-	downloadHandler := NewGitFileHandler(mrc)
+func downloadFile(t *testing.T, mrc *cache.MultiRepoCache, userId entity.Id, hash string) *httptest.ResponseRecorder {
+	t.Helper()
 
-	w = httptest.NewRecorder()
-	r, _ = http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
 
 	// Simulate auth
-	r = r.WithContext(auth.CtxWithUser(r.Context(), author.Id()))
+	r = r.WithContext(auth.CtxWithUser(r.Context(), userId))
 
 	// Handler's params
 	r = mux.SetURLVars(r, map[string]string{
 		"repo": "",
-		"hash": "3426a1488292d8f3f3c59ca679681336542b986f",
+		"hash": hash,
 	})
 
-	downloadHandler.ServeHTTP(w, r)
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	assert.Equal(t, data.Bytes(), w.Body.Bytes())
+	NewGitFileHandler(mrc).ServeHTTP(w, r)
+	return w
 }
@@ -2,17 +2,20 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/dustin/go-humanize"
 	"github.com/gorilla/mux"
 
 	"github.com/MichaelMure/git-bug/api/auth"
 	"github.com/MichaelMure/git-bug/cache"
 )
 
-// implement a http.Handler that will accept and store content into git blob.
+// implement a http.Handler that will accept and store content into git blob,
+// to be attached to a comment.
 //
 // Expected gorilla/mux parameters:
 //   - "repo" : the ref of the repo or "" for the default one
@@ -50,15 +53,20 @@ func (gufh *gitUploadFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// 100MB (github limit)
-	var maxUploadSize int64 = 100 * 1000 * 1000
-	r.Body = http.MaxBytesReader(rw, r.Body, maxUploadSize)
+	maxUploadSize, err := repo.AttachmentMaxSize()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// leave some room for the multipart encoding
+	r.Body = http.MaxBytesReader(rw, r.Body, maxUploadSize+1024*1024)
 	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
-		http.Error(rw, "file too big (100MB max)", http.StatusBadRequest)
+		http.Error(rw, fmt.Sprintf("file too big (%s max)", humanize.Bytes(uint64(maxUploadSize))), http.StatusBadRequest)
 		return
 	}
 
-	file, _, err := r.FormFile("uploadfile")
+	file, header, err := r.FormFile("uploadfile")
 	if err != nil {
 		http.Error(rw, "invalid file", http.StatusBadRequest)
 		return
@@ -70,24 +78,29 @@ func (gufh *gitUploadFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	filetype := http.DetectContentType(fileBytes)
-	if filetype != "image/jpeg" && filetype != "image/jpg" &&
-		filetype != "image/gif" && filetype != "image/png" {
-		http.Error(rw, "invalid file type", http.StatusBadRequest)
+	attachment, err := repo.StoreAttachment(header.Filename, fileBytes)
+	if errors.Is(err, cache.ErrAttachmentTooBig) {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
 		return
 	}
-
-	hash, err := repo.StoreData(fileBytes)
 	if err != nil {
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	type response struct {
-		Hash string `json:"hash"`
+		Hash     string `json:"hash"`
+		Name     string `json:"name"`
+		MimeType string `json:"mime"`
+		Size     int64  `json:"size"`
 	}
 
-	resp := response{Hash: string(hash)}
+	resp := response{
+		Hash:     string(attachment.Hash),
+		Name:     attachment.Name,
+		MimeType: attachment.MimeType,
+		Size:     attachment.Size,
+	}
 
 	js, err := json.Marshal(resp)
 	if err != nil {
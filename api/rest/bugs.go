@@ -0,0 +1,309 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// the number of bugs returned when the request doesn't give a limit
+const defaultLimit = 50
+
+// getBug return the bug targeted by a request
+func (h *handler) getBug(r *http.Request, repo *cache.RepoCache) (*cache.BugCache, error) {
+	return repo.Bugs().ResolvePrefix(mux.Vars(r)["id"])
+}
+
+func (h *handler) listBugs(w http.ResponseWriter, r *http.Request) {
+	repo, err := h.getRepo(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	q := query.NewQuery()
+	if qs := r.URL.Query().Get("q"); qs != "" {
+		q, err = query.Parse(qs)
+		if err != nil {
+			handleError(w, fmt.Errorf("%w: invalid query: %v", errBadRequest, err))
+			return
+		}
+	}
+
+	// "me" in the query is the authenticated user
+	ids, err := repo.Bugs().QueryAs(q, func() (*cache.IdentityCache, error) {
+		return auth.UserFromCtx(r.Context(), repo)
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	result := BugList{Total: len(ids), Bugs: []BugExcerpt{}}
+	for _, id := range paginate(ids, limit, offset) {
+		excerpt, err := repo.Bugs().ResolveExcerpt(id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		jsonBug, err := NewBugExcerpt(repo, excerpt)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		result.Bugs = append(result.Bugs, jsonBug)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *handler) showBug(w http.ResponseWriter, r *http.Request) {
+	repo, err := h.getRepo(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	b, err := h.getBug(r, repo)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewBug(b.Snapshot()))
+}
+
+func (h *handler) createBug(w http.ResponseWriter, r *http.Request) {
+	repo, author, err := h.getAuthor(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var input NewBugInput
+	if err := readBody(w, r, &input); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	b, _, err := repo.Bugs().NewRaw(author,
+		time.Now().Unix(),
+		text.CleanupOneLine(input.Title),
+		text.Cleanup(input.Message),
+		nil,
+		nil)
+	if err != nil {
+		handleError(w, fmt.Errorf("%w: %v", errBadRequest, err))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, NewBug(b.Snapshot()))
+}
+
+func (h *handler) addComment(w http.ResponseWriter, r *http.Request) {
+	repo, author, err := h.getAuthor(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	b, err := h.getBug(r, repo)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var input NewCommentInput
+	if err := readBody(w, r, &input); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	id, _, err := b.AddCommentRaw(author,
+		time.Now().Unix(),
+		text.Cleanup(input.Message),
+		nil,
+		nil)
+	if err != nil {
+		handleError(w, fmt.Errorf("%w: %v", errBadRequest, err))
+		return
+	}
+
+	err = b.Commit()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	item, err := b.Snapshot().SearchTimelineItem(id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	comment, ok := item.(*bug.AddCommentTimelineItem)
+	if !ok {
+		handleError(w, fmt.Errorf("unexpected timeline item %T", item))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, NewComment(comment.CommentTimelineItem))
+}
+
+func (h *handler) changeLabels(w http.ResponseWriter, r *http.Request) {
+	repo, author, err := h.getAuthor(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	b, err := h.getBug(r, repo)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var input ChangeLabelsInput
+	if err := readBody(w, r, &input); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	results, _, err := b.ChangeLabelsRaw(author,
+		time.Now().Unix(),
+		text.CleanupOneLineArray(input.Added),
+		text.CleanupOneLineArray(input.Removed),
+		nil)
+	if err != nil {
+		handleError(w, fmt.Errorf("%w: %v", errBadRequest, err))
+		return
+	}
+
+	err = b.Commit()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	result := LabelChange{
+		Results: make([]LabelChangeResult, len(results)),
+		Bug:     NewBug(b.Snapshot()),
+	}
+	for i, res := range results {
+		result.Results[i] = LabelChangeResult{
+			Label:  res.Label,
+			Status: labelChangeStatus(res.Status),
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *handler) setStatus(w http.ResponseWriter, r *http.Request) {
+	repo, author, err := h.getAuthor(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	b, err := h.getBug(r, repo)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var input SetStatusInput
+	if err := readBody(w, r, &input); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	status, err := common.StatusFromString(input.Status)
+	if err != nil {
+		handleError(w, fmt.Errorf("%w: %v", errBadRequest, err))
+		return
+	}
+
+	// setting the current status is a no-op
+	if b.Snapshot().Status != status {
+		switch status {
+		case common.OpenStatus:
+			_, err = b.OpenRaw(author, time.Now().Unix(), nil)
+		case common.ClosedStatus:
+			_, err = b.CloseRaw(author, time.Now().Unix(), nil)
+		}
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		err = b.Commit()
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, NewBug(b.Snapshot()))
+}
+
+func labelChangeStatus(status bug.LabelChangeStatus) string {
+	switch status {
+	case bug.LabelChangeAdded:
+		return "added"
+	case bug.LabelChangeRemoved:
+		return "removed"
+	case bug.LabelChangeDuplicateInOp:
+		return "duplicate_in_op"
+	case bug.LabelChangeAlreadySet:
+		return "already_exist"
+	case bug.LabelChangeDoesntExist:
+		return "doesnt_exist"
+	default:
+		panic("missing case")
+	}
+}
+
+// pagination read the limit and offset parameters of a request
+func pagination(r *http.Request) (limit int, offset int, err error) {
+	limit = defaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return 0, 0, fmt.Errorf("%w: invalid limit", errBadRequest)
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("%w: invalid offset", errBadRequest)
+		}
+	}
+	return limit, offset, nil
+}
+
+func paginate[T any](items []T, limit int, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
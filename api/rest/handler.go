@@ -0,0 +1,158 @@
+// Package rest implement a versioned JSON REST API, on top of the same cache
+// and authentication as the GraphQL API.
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// PathPrefix is the path under which the REST API is served
+const PathPrefix = "/api"
+
+// the maximum size of a request body
+const maxBodySize = 1 << 20
+
+var (
+	// errBadRequest is wrapped by the errors caused by an invalid request
+	errBadRequest = errors.New("bad request")
+	// errNotFound is wrapped by the errors caused by an unknown resource
+	errNotFound = errors.New("not found")
+	// errUnsupportedMediaType is wrapped by the errors caused by a body that
+	// is not JSON
+	errUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+type handler struct {
+	mrc *cache.MultiRepoCache
+}
+
+// NewHandler return a http.Handler serving the REST API under PathPrefix.
+//
+// Every resource is available for the default repository, and for a given
+// repository under /repos/{repo}:
+//   - GET  /v1/bugs?q=<query>&limit=<n>&offset=<n>
+//   - POST /v1/bugs
+//   - GET  /v1/bugs/{id}
+//   - POST /v1/bugs/{id}/comments
+//   - POST /v1/bugs/{id}/labels
+//   - POST /v1/bugs/{id}/status
+//   - GET  /v1/identities
+//   - GET  /v1/identities/{id}
+//
+// The OpenAPI description is served at /v1/openapi.json.
+func NewHandler(mrc *cache.MultiRepoCache) http.Handler {
+	h := &handler{mrc: mrc}
+
+	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, errNotFound)
+	})
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	})
+
+	router.Path(PathPrefix + "/v1/openapi.json").Methods(http.MethodGet).HandlerFunc(serveOpenAPI)
+
+	for _, prefix := range []string{PathPrefix + "/v1", PathPrefix + "/v1/repos/{repo}"} {
+		router.Path(prefix + "/bugs").Methods(http.MethodGet).HandlerFunc(h.listBugs)
+		router.Path(prefix + "/bugs").Methods(http.MethodPost).HandlerFunc(h.createBug)
+		router.Path(prefix + "/bugs/{id}").Methods(http.MethodGet).HandlerFunc(h.showBug)
+		router.Path(prefix + "/bugs/{id}/comments").Methods(http.MethodPost).HandlerFunc(h.addComment)
+		router.Path(prefix + "/bugs/{id}/labels").Methods(http.MethodPost).HandlerFunc(h.changeLabels)
+		router.Path(prefix + "/bugs/{id}/status").Methods(http.MethodPost).HandlerFunc(h.setStatus)
+		router.Path(prefix + "/identities").Methods(http.MethodGet).HandlerFunc(h.listIdentities)
+		router.Path(prefix + "/identities/{id}").Methods(http.MethodGet).HandlerFunc(h.showIdentity)
+	}
+
+	return router
+}
+
+// getRepo return the repository targeted by a request
+func (h *handler) getRepo(r *http.Request) (*cache.RepoCache, error) {
+	ref, ok := mux.Vars(r)["repo"]
+	if !ok {
+		return h.mrc.DefaultRepo()
+	}
+	repo, err := h.mrc.ResolveRepo(ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNotFound, err)
+	}
+	return repo, nil
+}
+
+// getAuthor return the repository targeted by a request, along with the
+// authenticated user acting on it
+func (h *handler) getAuthor(r *http.Request) (*cache.RepoCache, *cache.IdentityCache, error) {
+	repo, err := h.getRepo(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	author, err := auth.UserFromCtx(r.Context(), repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, author, nil
+}
+
+// readBody decode the JSON body of a request.
+//
+// The body must be declared as JSON: a browser can send a cross-site form
+// or a "simple" fetch without a CORS preflight, but only with another
+// content type.
+func readBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return fmt.Errorf("%w: the body must be application/json", errUnsupportedMediaType)
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(v)
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: empty body", errBadRequest)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: invalid body: %w", errBadRequest, err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, Error{Error: err.Error()})
+}
+
+// handleError write an error with the matching status code
+func handleError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, auth.ErrNotAuthenticated):
+		writeError(w, http.StatusUnauthorized, err)
+	case entity.IsErrNotFound(err), errors.Is(err, errNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.As(err, &maxBytesErr):
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+	case errors.Is(err, errUnsupportedMediaType):
+		writeError(w, http.StatusUnsupportedMediaType, err)
+	case entity.IsErrMultipleMatch(err), errors.Is(err, errBadRequest):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
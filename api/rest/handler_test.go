@@ -0,0 +1,179 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/misc/random_bugs"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func setupHandler(t *testing.T) (*cache.RepoCache, http.Handler) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	random_bugs.FillRepoWithSeed(repo, 10, 42)
+
+	mrc := cache.NewMultiRepoCache()
+	rc, events := mrc.RegisterRepository(repo, "test")
+	for event := range events {
+		require.NoError(t, event.Err)
+	}
+
+	return rc, NewHandler(mrc)
+}
+
+func doRequest(t *testing.T, server *httptest.Server, method string, path string, body string, out interface{}) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestReadOnly(t *testing.T) {
+	repo, handler := setupHandler(t)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var list BugList
+	require.Equal(t, http.StatusOK, doRequest(t, server, "GET", "/api/v1/bugs?limit=3", "", &list))
+	require.Equal(t, 10, list.Total)
+	require.Len(t, list.Bugs, 3)
+
+	// the same, in the named repository
+	var named BugList
+	require.Equal(t, http.StatusOK, doRequest(t, server, "GET", "/api/v1/repos/test/bugs?limit=3", "", &named))
+	require.Equal(t, list, named)
+
+	var page BugList
+	require.Equal(t, http.StatusOK, doRequest(t, server, "GET", "/api/v1/bugs?limit=3&offset=9", "", &page))
+	require.Len(t, page.Bugs, 1)
+
+	var open BugList
+	require.Equal(t, http.StatusOK, doRequest(t, server, "GET", "/api/v1/bugs?q=status:open", "", &open))
+	for _, b := range open.Bugs {
+		require.Equal(t, "open", b.Status)
+	}
+
+	var b Bug
+	require.Equal(t, http.StatusOK, doRequest(t, server, "GET", "/api/v1/bugs/"+list.Bugs[0].HumanId, "", &b))
+	require.Equal(t, list.Bugs[0].Id, b.Id)
+	require.Equal(t, list.Bugs[0].Title, b.Title)
+	require.Len(t, b.Comments, list.Bugs[0].Comments)
+
+	var identities IdentityList
+	require.Equal(t, http.StatusOK, doRequest(t, server, "GET", "/api/v1/identities", "", &identities))
+	require.Equal(t, len(repo.Identities().AllIds()), identities.Total)
+
+	var i Identity
+	require.Equal(t, http.StatusOK, doRequest(t, server, "GET", "/api/v1/identities/"+b.Author.Id, "", &i))
+	require.Equal(t, b.Author, i)
+
+	var apiErr Error
+	require.Equal(t, http.StatusNotFound, doRequest(t, server, "GET", "/api/v1/bugs/ffffffff", "", &apiErr))
+	require.NotEmpty(t, apiErr.Error)
+	require.Equal(t, http.StatusNotFound, doRequest(t, server, "GET", "/api/v1/repos/unknown/bugs", "", &apiErr))
+	require.Equal(t, http.StatusNotFound, doRequest(t, server, "GET", "/api/v1/unknown", "", &apiErr))
+	require.Equal(t, http.StatusBadRequest, doRequest(t, server, "GET", "/api/v1/bugs?q=foo:bar", "", &apiErr))
+	require.Equal(t, http.StatusBadRequest, doRequest(t, server, "GET", "/api/v1/bugs?limit=0", "", &apiErr))
+
+	// without authentication, nothing can be changed
+	require.Equal(t, http.StatusUnauthorized, doRequest(t, server, "POST", "/api/v1/bugs", `{"title": "title"}`, &apiErr))
+	require.Equal(t, http.StatusUnauthorized, doRequest(t, server, "POST", "/api/v1/bugs/"+b.Id+"/status", `{"status": "closed"}`, &apiErr))
+
+	var doc map[string]interface{}
+	require.Equal(t, http.StatusOK, doRequest(t, server, "GET", "/api/v1/openapi.json", "", &doc))
+	require.Equal(t, "3.0.3", doc["openapi"])
+}
+
+func TestMutations(t *testing.T) {
+	repo, handler := setupHandler(t)
+
+	user, err := repo.Identities().New("Alice", "alice@example.com")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(auth.Middleware(user.Id())(handler))
+	defer server.Close()
+
+	var created Bug
+	require.Equal(t, http.StatusCreated, doRequest(t, server, "POST", "/api/v1/bugs",
+		`{"title": "new bug", "message": "description"}`, &created))
+	require.Equal(t, "new bug", created.Title)
+	require.Equal(t, "open", created.Status)
+	require.Equal(t, user.Id().String(), created.Author.Id)
+	require.Len(t, created.Comments, 1)
+	require.Equal(t, "description", created.Comments[0].Message)
+
+	var comment Comment
+	require.Equal(t, http.StatusCreated, doRequest(t, server, "POST", "/api/v1/bugs/"+created.HumanId+"/comments",
+		`{"message": "a comment"}`, &comment))
+	require.Equal(t, "a comment", comment.Message)
+	require.Equal(t, "Alice", comment.Author.Name)
+
+	var labels LabelChange
+	require.Equal(t, http.StatusOK, doRequest(t, server, "POST", "/api/v1/repos/test/bugs/"+created.Id+"/labels",
+		`{"added": ["bug", "ui"], "removed": ["missing"]}`, &labels))
+	require.Equal(t, []LabelChangeResult{
+		{Label: "bug", Status: "added"},
+		{Label: "ui", Status: "added"},
+		{Label: "missing", Status: "doesnt_exist"},
+	}, labels.Results)
+
+	var closed Bug
+	require.Equal(t, http.StatusOK, doRequest(t, server, "POST", "/api/v1/bugs/"+created.Id+"/status",
+		`{"status": "closed"}`, &closed))
+	require.Equal(t, "closed", closed.Status)
+	require.Len(t, closed.Comments, 2)
+	require.ElementsMatch(t, []bug.Label{"bug", "ui"}, closed.Labels)
+
+	// the changes are visible in the cache
+	b, err := repo.Bugs().Resolve(entity.Id(created.Id))
+	require.NoError(t, err)
+	require.Equal(t, "closed", b.Snapshot().Status.String())
+
+	var apiErr Error
+	require.Equal(t, http.StatusBadRequest, doRequest(t, server, "POST", "/api/v1/bugs", `{"title": ""}`, &apiErr))
+	require.Equal(t, http.StatusBadRequest, doRequest(t, server, "POST", "/api/v1/bugs", `{"name": "foo"}`, &apiErr))
+	require.Equal(t, http.StatusBadRequest, doRequest(t, server, "POST", "/api/v1/bugs", ``, &apiErr))
+	require.Equal(t, http.StatusBadRequest, doRequest(t, server, "POST", "/api/v1/bugs/"+created.Id+"/status",
+		`{"status": "wontfix"}`, &apiErr))
+	require.Equal(t, http.StatusBadRequest, doRequest(t, server, "POST", "/api/v1/bugs/"+created.Id+"/labels",
+		`{"added": ["bug"]}`, &apiErr))
+	require.Equal(t, http.StatusMethodNotAllowed, doRequest(t, server, "DELETE", "/api/v1/bugs/"+created.Id, "", &apiErr))
+
+	// a cross-site form or fetch can't send a JSON body without a preflight
+	count := len(repo.Bugs().AllIds())
+	for _, path := range []string{"/api/v1/bugs", "/api/v1/bugs/" + created.Id + "/comments",
+		"/api/v1/bugs/" + created.Id + "/labels", "/api/v1/bugs/" + created.Id + "/status"} {
+		resp, err := server.Client().Post(server.URL+path, "text/plain", bytes.NewBufferString(`{"title": "csrf", "message": "csrf", "status": "open", "added": ["csrf"]}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode, path)
+	}
+
+	b, err = repo.Bugs().Resolve(entity.Id(created.Id))
+	require.NoError(t, err)
+	require.Len(t, b.Snapshot().Comments, 2)
+	require.Equal(t, "closed", b.Snapshot().Status.String())
+	require.Len(t, repo.Bugs().AllIds(), count)
+}
@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *handler) listIdentities(w http.ResponseWriter, r *http.Request) {
+	repo, err := h.getRepo(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ids := repo.Identities().AllIds()
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	result := IdentityList{Total: len(ids), Identities: []Identity{}}
+	for _, id := range paginate(ids, limit, offset) {
+		excerpt, err := repo.Identities().ResolveExcerpt(id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		result.Identities = append(result.Identities, NewIdentityFromExcerpt(excerpt))
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *handler) showIdentity(w http.ResponseWriter, r *http.Request) {
+	repo, err := h.getRepo(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	i, err := repo.Identities().ResolvePrefix(mux.Vars(r)["id"])
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewIdentity(i))
+}
@@ -0,0 +1,213 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/identity"
+)
+
+// Error is the body of every error response
+type Error struct {
+	Error string `json:"error"`
+}
+
+type Identity struct {
+	Id          string `json:"id"`
+	HumanId     string `json:"human_id"`
+	Name        string `json:"name"`
+	Email       string `json:"email,omitempty"`
+	Login       string `json:"login,omitempty"`
+	AvatarUrl   string `json:"avatar_url,omitempty"`
+	DisplayName string `json:"display_name"`
+	IsProtected bool   `json:"is_protected"`
+}
+
+func NewIdentity(i identity.Interface) Identity {
+	return Identity{
+		Id:          i.Id().String(),
+		HumanId:     i.Id().Human(),
+		Name:        i.Name(),
+		Email:       i.Email(),
+		Login:       i.Login(),
+		AvatarUrl:   i.AvatarUrl(),
+		DisplayName: i.DisplayName(),
+		IsProtected: i.IsProtected(),
+	}
+}
+
+// NewIdentityFromExcerpt build an Identity from the cache, without the
+// values not kept in the excerpt
+func NewIdentityFromExcerpt(excerpt *cache.IdentityExcerpt) Identity {
+	return Identity{
+		Id:          excerpt.Id().String(),
+		HumanId:     excerpt.Id().Human(),
+		Name:        excerpt.Name,
+		Login:       excerpt.Login,
+		DisplayName: excerpt.DisplayName(),
+	}
+}
+
+type IdentityList struct {
+	Total      int        `json:"total"`
+	Identities []Identity `json:"identities"`
+}
+
+// BugExcerpt is the summary of a bug returned when listing bugs
+type BugExcerpt struct {
+	Id         string            `json:"id"`
+	HumanId    string            `json:"human_id"`
+	Title      string            `json:"title"`
+	Status     string            `json:"status"`
+	Labels     []bug.Label       `json:"labels"`
+	Author     Identity          `json:"author"`
+	Comments   int               `json:"comments"`
+	CreateTime time.Time         `json:"create_time"`
+	EditTime   time.Time         `json:"edit_time"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+func NewBugExcerpt(repo *cache.RepoCache, excerpt *cache.BugExcerpt) (BugExcerpt, error) {
+	author, err := repo.Identities().ResolveExcerpt(excerpt.AuthorId)
+	if err != nil {
+		return BugExcerpt{}, err
+	}
+
+	return BugExcerpt{
+		Id:         excerpt.Id().String(),
+		HumanId:    excerpt.Id().Human(),
+		Title:      excerpt.Title,
+		Status:     excerpt.Status.String(),
+		Labels:     nonNilLabels(excerpt.Labels),
+		Author:     NewIdentityFromExcerpt(author),
+		Comments:   excerpt.LenComments,
+		CreateTime: excerpt.CreateTime(),
+		EditTime:   excerpt.EditTime(),
+		Metadata:   excerpt.CreateMetadata,
+	}, nil
+}
+
+type BugList struct {
+	Total int          `json:"total"`
+	Bugs  []BugExcerpt `json:"bugs"`
+}
+
+// Bug is the complete view of a bug, with its comments
+type Bug struct {
+	Id           string            `json:"id"`
+	HumanId      string            `json:"human_id"`
+	Title        string            `json:"title"`
+	Status       string            `json:"status"`
+	Labels       []bug.Label       `json:"labels"`
+	Author       Identity          `json:"author"`
+	Actors       []Identity        `json:"actors"`
+	Participants []Identity        `json:"participants"`
+	CreateTime   time.Time         `json:"create_time"`
+	EditTime     time.Time         `json:"edit_time"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Comments     []Comment         `json:"comments"`
+}
+
+func NewBug(snap *bug.Snapshot) Bug {
+	result := Bug{
+		Id:           snap.Id().String(),
+		HumanId:      snap.Id().Human(),
+		Title:        snap.Title,
+		Status:       snap.Status.String(),
+		Labels:       nonNilLabels(snap.Labels),
+		Author:       NewIdentity(snap.Author),
+		Actors:       make([]Identity, len(snap.Actors)),
+		Participants: make([]Identity, len(snap.Participants)),
+		CreateTime:   snap.CreateTime,
+		EditTime:     snap.EditTime(),
+		Comments:     []Comment{},
+	}
+
+	if len(snap.Operations) > 0 {
+		result.Metadata = snap.Operations[0].AllMetadata()
+	}
+	for i, actor := range snap.Actors {
+		result.Actors[i] = NewIdentity(actor)
+	}
+	for i, participant := range snap.Participants {
+		result.Participants[i] = NewIdentity(participant)
+	}
+
+	for _, item := range snap.Timeline {
+		switch item := item.(type) {
+		case *bug.CreateTimelineItem:
+			result.Comments = append(result.Comments, NewComment(item.CommentTimelineItem))
+		case *bug.AddCommentTimelineItem:
+			result.Comments = append(result.Comments, NewComment(item.CommentTimelineItem))
+		}
+	}
+
+	return result
+}
+
+type Comment struct {
+	Id         string    `json:"id"`
+	HumanId    string    `json:"human_id"`
+	Author     Identity  `json:"author"`
+	Message    string    `json:"message"`
+	Files      []string  `json:"files,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+	EditTime   time.Time `json:"edit_time"`
+	Edited     bool      `json:"edited"`
+}
+
+func NewComment(item bug.CommentTimelineItem) Comment {
+	result := Comment{
+		Id:         item.CombinedId().String(),
+		HumanId:    item.CombinedId().Human(),
+		Author:     NewIdentity(item.Author),
+		Message:    item.Message,
+		CreateTime: item.CreatedAt.Time(),
+		EditTime:   item.LastEdit.Time(),
+		Edited:     item.Edited(),
+	}
+	for _, file := range item.Files {
+		result.Files = append(result.Files, file.String())
+	}
+	return result
+}
+
+type LabelChangeResult struct {
+	Label  bug.Label `json:"label"`
+	Status string    `json:"status"`
+}
+
+type LabelChange struct {
+	Results []LabelChangeResult `json:"results"`
+	Bug     Bug                 `json:"bug"`
+}
+
+// NewBugInput is the body to create a bug
+type NewBugInput struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// NewCommentInput is the body to comment a bug
+type NewCommentInput struct {
+	Message string `json:"message"`
+}
+
+// ChangeLabelsInput is the body to add or remove labels on a bug
+type ChangeLabelsInput struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// SetStatusInput is the body to open or close a bug
+type SetStatusInput struct {
+	Status string `json:"status"`
+}
+
+func nonNilLabels(labels []bug.Label) []bug.Label {
+	if labels == nil {
+		return []bug.Label{}
+	}
+	return labels
+}
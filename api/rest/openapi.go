@@ -0,0 +1,16 @@
+package rest
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// the OpenAPI description of the API, to keep in sync with the handlers
+//
+//go:embed openapi.json
+var openAPI []byte
+
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPI)
+}
@@ -2,7 +2,10 @@
 package bridge
 
 import (
+	"github.com/MichaelMure/git-bug/bridge/bugzilla"
 	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/file"
+	"github.com/MichaelMure/git-bug/bridge/gitea"
 	"github.com/MichaelMure/git-bug/bridge/github"
 	"github.com/MichaelMure/git-bug/bridge/gitlab"
 	"github.com/MichaelMure/git-bug/bridge/jira"
@@ -13,9 +16,12 @@ import (
 
 func init() {
 	core.Register(&github.Github{})
+	core.Register(&gitea.Gitea{})
 	core.Register(&gitlab.Gitlab{})
 	core.Register(&launchpad.Launchpad{})
 	core.Register(&jira.Jira{})
+	core.Register(&file.File{})
+	core.Register(&bugzilla.Bugzilla{})
 }
 
 // Targets return all known bridge implementation target
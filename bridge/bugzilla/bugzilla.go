@@ -0,0 +1,58 @@
+// Package bugzilla contains the Bugzilla bridge implementation
+package bugzilla
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+)
+
+const (
+	target = "bugzilla"
+
+	metaKeyBugzillaId      = "bugzilla-id"
+	metaKeyBugzillaUrl     = "bugzilla-url"
+	metaKeyBugzillaLogin   = "bugzilla-login"
+	metaKeyBugzillaProduct = "bugzilla-product"
+	metaKeyBugzillaBaseUrl = "bugzilla-base-url"
+
+	confKeyBaseUrl      = "base-url"
+	confKeyProduct      = "product"
+	confKeyDefaultLogin = "default-login"
+
+	defaultTimeout = 60 * time.Second
+)
+
+var _ core.BridgeImpl = &Bugzilla{}
+
+// Bugzilla is the bridge implementation for Bugzilla, import only
+type Bugzilla struct{}
+
+func (Bugzilla) Target() string {
+	return target
+}
+
+func (Bugzilla) LoginMetaKey() string {
+	return metaKeyBugzillaLogin
+}
+
+func (Bugzilla) NewImporter() core.Importer {
+	return &bugzillaImporter{}
+}
+
+func (Bugzilla) NewExporter() core.Exporter {
+	return nil
+}
+
+// buildClient create a client, authenticated if a token is given
+func buildClient(transport http.RoundTripper, baseURL string, token *auth.Token) *Client {
+	apiKey := ""
+	if token != nil {
+		apiKey = token.Value
+	}
+	client := NewClient(baseURL, apiKey)
+	client.client.Transport = transport
+	return client
+}
@@ -0,0 +1,277 @@
+package bugzilla
+
+/*
+ * A thin wrapper around the Bugzilla REST API, available since Bugzilla 5.0.
+ * The documentation can be found at:
+ * https://bugzilla.readthedocs.io/en/latest/api/
+ */
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// number of bugs requested per page
+const pageSize = 50
+
+// User describes a Bugzilla user. The login is the name, usually an email.
+type User struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name"`
+	Email    string `json:"email"`
+}
+
+// Bug describes a Bugzilla bug
+type Bug struct {
+	ID             int64     `json:"id"`
+	Summary        string    `json:"summary"`
+	Status         string    `json:"status"`
+	Resolution     string    `json:"resolution"`
+	Product        string    `json:"product"`
+	Keywords       []string  `json:"keywords"`
+	Creator        string    `json:"creator"`
+	CreatorDetail  *User     `json:"creator_detail"`
+	CreationTime   time.Time `json:"creation_time"`
+	LastChangeTime time.Time `json:"last_change_time"`
+}
+
+// Comment describes a comment on a Bugzilla bug. The comment with a count of
+// 0 is the description of the bug.
+type Comment struct {
+	ID           int64     `json:"id"`
+	Count        int       `json:"count"`
+	Text         string    `json:"text"`
+	Creator      string    `json:"creator"`
+	CreationTime time.Time `json:"creation_time"`
+	AttachmentID *int64    `json:"attachment_id"`
+	IsPrivate    bool      `json:"is_private"`
+}
+
+// History describes a set of changes made at once on a Bugzilla bug
+type History struct {
+	When    time.Time `json:"when"`
+	Who     string    `json:"who"`
+	Changes []Change  `json:"changes"`
+}
+
+// Change describes the change of a single field of a bug. For fields holding
+// multiple values, like the keywords, the values are separated by ", ".
+type Change struct {
+	FieldName string `json:"field_name"`
+	Removed   string `json:"removed"`
+	Added     string `json:"added"`
+}
+
+// Attachment describes a file attached to a Bugzilla bug. The content is
+// encoded in base64.
+type Attachment struct {
+	ID           int64     `json:"id"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"`
+	Data         []byte    `json:"data"`
+	Creator      string    `json:"creator"`
+	CreationTime time.Time `json:"creation_time"`
+	IsPrivate    bool      `json:"is_private"`
+}
+
+// Product describes a Bugzilla product
+type Product struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// splitValues split the values of a multi-valued field in a Change
+func splitValues(values string) []string {
+	var result []string
+	for _, value := range strings.Split(values, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// APIError is returned when the Bugzilla API answer with an error.
+type APIError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("bugzilla API error (%d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("bugzilla API error (%d)", e.StatusCode)
+}
+
+// Client is a minimal Bugzilla API client, authenticated with an API key if
+// one is given.
+type Client struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+// NewClient return a Client for the Bugzilla instance at baseURL
+func NewClient(baseURL string, apiKey string) *Client {
+	return &Client{
+		client: &http.Client{
+			Timeout: defaultTimeout,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+	}
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := fmt.Sprintf("%s/rest%s", c.baseURL, path)
+	if len(query) > 0 {
+		u = u + "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-BUGZILLA-API-KEY", c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var msg struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&msg)
+		return &APIError{StatusCode: resp.StatusCode, Code: msg.Code, Message: msg.Message}
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Whoami return the user authenticated by the API key
+func (c *Client) Whoami(ctx context.Context) (*User, error) {
+	var user User
+	err := c.get(ctx, "/whoami", nil, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetProduct return the given product
+func (c *Client) GetProduct(ctx context.Context, name string) (*Product, error) {
+	var result struct {
+		Products []Product `json:"products"`
+	}
+	err := c.get(ctx, "/product", url.Values{"names": {name}}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Products) == 0 {
+		return nil, fmt.Errorf("unknown product %s", name)
+	}
+	return &result.Products[0], nil
+}
+
+// Bugs return a page of the bugs of a product changed after the given date,
+// in the order of their ids
+func (c *Client) Bugs(ctx context.Context, product string, since time.Time, offset int) ([]Bug, error) {
+	query := url.Values{
+		"product":        {product},
+		"include_fields": {"_default,creator_detail"},
+		"order":          {"bug_id"},
+		"limit":          {strconv.Itoa(pageSize)},
+		"offset":         {strconv.Itoa(offset)},
+	}
+	if !since.IsZero() {
+		query.Set("last_change_time", since.UTC().Format(time.RFC3339))
+	}
+
+	var result struct {
+		Bugs []Bug `json:"bugs"`
+	}
+	err := c.get(ctx, "/bug", query, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Bugs, nil
+}
+
+// Comments return all the comments of a bug, oldest first
+func (c *Client) Comments(ctx context.Context, bugID int64) ([]Comment, error) {
+	var result struct {
+		Bugs map[string]struct {
+			Comments []Comment `json:"comments"`
+		} `json:"bugs"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/bug/%d/comment", bugID), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Bugs[strconv.FormatInt(bugID, 10)].Comments, nil
+}
+
+// History return all the changes made on a bug, oldest first
+func (c *Client) History(ctx context.Context, bugID int64) ([]History, error) {
+	var result struct {
+		Bugs []struct {
+			ID      int64     `json:"id"`
+			History []History `json:"history"`
+		} `json:"bugs"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/bug/%d/history", bugID), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	for _, bug := range result.Bugs {
+		if bug.ID == bugID {
+			return bug.History, nil
+		}
+	}
+	return nil, nil
+}
+
+// Attachments return all the attachments of a bug, with their content
+func (c *Client) Attachments(ctx context.Context, bugID int64) ([]Attachment, error) {
+	var result struct {
+		Bugs map[string][]Attachment `json:"bugs"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/bug/%d/attachment", bugID), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Bugs[strconv.FormatInt(bugID, 10)], nil
+}
+
+// GetUser return the user with the given login
+func (c *Client) GetUser(ctx context.Context, login string) (*User, error) {
+	var result struct {
+		Users []User `json:"users"`
+	}
+	err := c.get(ctx, "/user", url.Values{"names": {login}}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Users) == 0 {
+		return nil, fmt.Errorf("unknown user %s", login)
+	}
+	return &result.Users[0], nil
+}
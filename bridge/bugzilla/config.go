@@ -0,0 +1,218 @@
+package bugzilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/input"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func (Bugzilla) ValidParams() map[string]interface{} {
+	return map[string]interface{}{
+		"BaseURL":    nil,
+		"Project":    nil,
+		"CredPrefix": nil,
+		"TokenRaw":   nil,
+	}
+}
+
+func (b Bugzilla) Configure(repo *cache.RepoCache, params core.BridgeParams, interactive bool) (core.Configuration, error) {
+	var err error
+	var baseUrl string
+
+	switch {
+	case params.BaseURL != "":
+		baseUrl = params.BaseURL
+	default:
+		if !interactive {
+			return nil, fmt.Errorf("Non-interactive-mode is active. Please specify the Bugzilla instance URL via the --base-url option.")
+		}
+		baseUrl, err = input.Prompt("Bugzilla server URL", "URL", input.Required, input.IsURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "base url prompt")
+		}
+	}
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+
+	var product string
+
+	switch {
+	case params.Project != "":
+		product = params.Project
+	default:
+		if !interactive {
+			return nil, fmt.Errorf("Non-interactive-mode is active. Please specify the product via the --project option.")
+		}
+		product, err = input.Prompt("Bugzilla product", "product", input.Required)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// An API key is only needed for the private bugs, a bridge without one
+	// can still import the public bugs anonymously.
+	var login string
+	var cred auth.Credential
+
+	switch {
+	case params.CredPrefix != "":
+		cred, err = auth.LoadWithPrefix(repo, params.CredPrefix)
+		if err != nil {
+			return nil, err
+		}
+		l, ok := cred.GetMetadata(auth.MetaKeyLogin)
+		if !ok {
+			return nil, fmt.Errorf("credential doesn't have a login")
+		}
+		login = l
+	case params.TokenRaw != "":
+		token := auth.NewToken(target, params.TokenRaw)
+		login, err = getLoginFromToken(baseUrl, token)
+		if err != nil {
+			return nil, err
+		}
+		token.SetMetadata(auth.MetaKeyLogin, login)
+		token.SetMetadata(auth.MetaKeyBaseURL, baseUrl)
+		cred = token
+	case interactive:
+		cred, err = promptTokenOptions(repo, baseUrl)
+		if err != nil {
+			return nil, err
+		}
+		if cred != nil {
+			login, _ = cred.GetMetadata(auth.MetaKeyLogin)
+		}
+	}
+
+	var token *auth.Token
+	if cred != nil {
+		var ok bool
+		token, ok = cred.(*auth.Token)
+		if !ok {
+			return nil, fmt.Errorf("the Bugzilla bridge only handle token credentials")
+		}
+	}
+
+	// validate the product and the token access
+	_, err = buildClient(http.DefaultTransport, baseUrl, token).GetProduct(context.Background(), product)
+	if err != nil {
+		return nil, errors.Wrap(err, "product validation")
+	}
+
+	conf := make(core.Configuration)
+	conf[core.ConfigKeyTarget] = target
+	conf[confKeyBaseUrl] = baseUrl
+	conf[confKeyProduct] = product
+	if login != "" {
+		conf[confKeyDefaultLogin] = login
+	}
+
+	err = b.ValidateConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if cred == nil {
+		return conf, nil
+	}
+
+	// don't forget to store the now known valid token
+	if !auth.IdExist(repo, cred.ID()) {
+		err = auth.Store(repo, cred)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return conf, core.FinishConfig(repo, metaKeyBugzillaLogin, login)
+}
+
+func (Bugzilla) ValidateConfig(conf core.Configuration) error {
+	if v, ok := conf[core.ConfigKeyTarget]; !ok {
+		return fmt.Errorf("missing %s key", core.ConfigKeyTarget)
+	} else if v != target {
+		return fmt.Errorf("unexpected target name: %v", v)
+	}
+	if _, ok := conf[confKeyBaseUrl]; !ok {
+		return fmt.Errorf("missing %s key", confKeyBaseUrl)
+	}
+	if _, ok := conf[confKeyProduct]; !ok {
+		return fmt.Errorf("missing %s key", confKeyProduct)
+	}
+
+	return nil
+}
+
+func promptTokenOptions(repo repository.RepoKeyring, baseUrl string) (auth.Credential, error) {
+	creds, err := auth.List(repo,
+		auth.WithTarget(target),
+		auth.WithKind(auth.KindToken),
+		auth.WithMeta(auth.MetaKeyBaseURL, baseUrl),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, index, err := input.PromptCredential(target, "API key", creds, []string{
+		"enter my API key",
+		"don't authenticate (public bugs only)",
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case cred != nil:
+		return cred, nil
+	case index == 0:
+		return promptToken(baseUrl)
+	case index == 1:
+		return nil, nil
+	default:
+		panic("missed case")
+	}
+}
+
+func promptToken(baseUrl string) (*auth.Token, error) {
+	fmt.Printf("You can generate a new API key by visiting %s.\n", baseUrl+"/userprefs.cgi?tab=apikey")
+	fmt.Println()
+
+	var login string
+
+	validator := func(name string, value string) (complaint string, err error) {
+		login, err = getLoginFromToken(baseUrl, auth.NewToken(target, value))
+		if err != nil {
+			return fmt.Sprintf("API key is invalid: %v", err), nil
+		}
+		return "", nil
+	}
+
+	rawToken, err := input.Prompt("Enter API key", "API key", input.Required, validator)
+	if err != nil {
+		return nil, err
+	}
+
+	token := auth.NewToken(target, rawToken)
+	token.SetMetadata(auth.MetaKeyLogin, login)
+	token.SetMetadata(auth.MetaKeyBaseURL, baseUrl)
+
+	return token, nil
+}
+
+func getLoginFromToken(baseUrl string, token *auth.Token) (string, error) {
+	user, err := buildClient(http.DefaultTransport, baseUrl, token).Whoami(context.Background())
+	if err != nil {
+		return "", err
+	}
+	if user.Name == "" {
+		return "", fmt.Errorf("bugzilla doesn't seem to know this API key")
+	}
+
+	return user.Name, nil
+}
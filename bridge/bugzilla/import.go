@@ -0,0 +1,498 @@
+package bugzilla
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// the statuses considered as closed, the others being open
+var closedStatuses = map[string]bool{
+	"RESOLVED": true,
+	"VERIFIED": true,
+	"CLOSED":   true,
+}
+
+func isClosed(status string) bool {
+	return closedStatuses[strings.ToUpper(status)]
+}
+
+// bugzillaImporter implement the Importer interface
+type bugzillaImporter struct {
+	conf core.Configuration
+
+	// label and status mapping
+	mapping *core.Mapping
+
+	// default client, anonymous if no API key is available
+	client *Client
+
+	// send only channel
+	out chan<- core.ImportResult
+}
+
+func (bi *bugzillaImporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
+	bi.conf = conf
+
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	bi.mapping = mapping
+
+	opts := []auth.ListOption{
+		auth.WithTarget(target),
+		auth.WithKind(auth.KindToken),
+		auth.WithMeta(auth.MetaKeyBaseURL, conf[confKeyBaseUrl]),
+	}
+	if login, ok := conf[confKeyDefaultLogin]; ok {
+		opts = append(opts, auth.WithMeta(auth.MetaKeyLogin, login))
+	}
+
+	creds, err := auth.List(repo, opts...)
+	if err != nil {
+		return err
+	}
+
+	var token *auth.Token
+	if len(creds) > 0 {
+		token = creds[0].(*auth.Token)
+	}
+	bi.client = buildClient(core.HTTPTransport(ctx), conf[confKeyBaseUrl], token)
+
+	return nil
+}
+
+// ImportAll iterate over all the bugs of the product changed since the given
+// date and ensure the creation of the missing bugs / comments / status and
+// keyword changes ...
+func (bi *bugzillaImporter) ImportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ImportResult, error) {
+	out := make(chan core.ImportResult)
+	bi.out = out
+
+	go func() {
+		defer close(out)
+
+		for offset := 0; ; offset += pageSize {
+			bugs, err := bi.client.Bugs(ctx, bi.conf[confKeyProduct], since, offset)
+			if err != nil {
+				out <- core.NewImportError(err, "")
+				return
+			}
+
+			for _, remote := range bugs {
+				select {
+				case <-ctx.Done():
+					out <- core.NewImportError(ctx.Err(), "")
+					return
+				default:
+				}
+
+				if err := bi.importBug(ctx, repo, remote); err != nil {
+					err := fmt.Errorf("bug %d: %v", remote.ID, err)
+					out <- core.NewImportError(err, "")
+					return
+				}
+			}
+
+			if len(bugs) < pageSize {
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// timelineItem is either a comment or a set of changes, to apply them in
+// chronological order
+type timelineItem struct {
+	time    time.Time
+	comment *Comment
+	history *History
+}
+
+func (bi *bugzillaImporter) importBug(ctx context.Context, repo *cache.RepoCache, remote Bug) error {
+	comments, err := bi.client.Comments(ctx, remote.ID)
+	if err != nil {
+		return err
+	}
+	history, err := bi.client.History(ctx, remote.ID)
+	if err != nil {
+		return err
+	}
+	attachments, err := bi.client.Attachments(ctx, remote.ID)
+	if err != nil {
+		return err
+	}
+
+	attachmentsById := make(map[int64]Attachment, len(attachments))
+	for _, attachment := range attachments {
+		attachmentsById[attachment.ID] = attachment
+	}
+
+	var description *Comment
+	var timeline []timelineItem
+	for i := range comments {
+		if comments[i].Count == 0 {
+			description = &comments[i]
+			continue
+		}
+		timeline = append(timeline, timelineItem{time: comments[i].CreationTime, comment: &comments[i]})
+	}
+	for i := range history {
+		timeline = append(timeline, timelineItem{time: history[i].When, history: &history[i]})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].time.Before(timeline[j].time)
+	})
+
+	b, err := bi.ensureBug(ctx, repo, remote, description, history, attachmentsById)
+	if err != nil {
+		return fmt.Errorf("bug creation: %v", err)
+	}
+
+	// collect the remote ids of all the operations already imported
+	known := knownBugzillaIds(b)
+
+	for _, item := range timeline {
+		if item.comment != nil {
+			err = bi.ensureComment(ctx, repo, b, known, remote.ID, *item.comment, attachmentsById)
+		} else {
+			err = bi.ensureChanges(ctx, repo, b, known, *item.history)
+		}
+		if err != nil {
+			bi.out <- core.NewImportError(err, b.Id())
+		}
+	}
+
+	if !b.NeedCommit() {
+		bi.out <- core.NewImportNothing(b.Id(), "no imported operation")
+	} else if err := b.Commit(); err != nil {
+		return fmt.Errorf("bug commit: %v", err)
+	}
+
+	return nil
+}
+
+func (bi *bugzillaImporter) ensureBug(ctx context.Context, repo *cache.RepoCache, remote Bug, description *Comment, history []History, attachments map[int64]Attachment) (*cache.BugCache, error) {
+	bugId := strconv.FormatInt(remote.ID, 10)
+
+	// resolve bug
+	b, err := repo.Bugs().ResolveMatcher(func(excerpt *cache.BugExcerpt) bool {
+		return excerpt.CreateMetadata[core.MetaKeyOrigin] == target &&
+			excerpt.CreateMetadata[metaKeyBugzillaId] == bugId &&
+			excerpt.CreateMetadata[metaKeyBugzillaBaseUrl] == bi.conf[confKeyBaseUrl] &&
+			excerpt.CreateMetadata[metaKeyBugzillaProduct] == bi.conf[confKeyProduct]
+	})
+	if err == nil {
+		return b, nil
+	}
+	if !entity.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	author, err := bi.ensurePerson(ctx, repo, remote.Creator, remote.CreatorDetail)
+	if err != nil {
+		return nil, err
+	}
+
+	// the bug only hold the current values, the original ones need to be
+	// found in the history
+	title, keywords, status := originalValues(remote, history)
+
+	var message string
+	var files []bug.Attachment
+	if description != nil {
+		message = description.Text
+		files = bi.importAttachments(repo, description, attachments)
+	}
+
+	// if bug was never imported, create bug
+	b, _, err = repo.Bugs().NewRaw(
+		author,
+		remote.CreationTime.Unix(),
+		text.CleanupOneLine(title),
+		text.Cleanup(message),
+		bug.AttachmentHashes(files),
+		core.WithAttachmentsMetadata(map[string]string{
+			core.MetaKeyOrigin:     target,
+			metaKeyBugzillaId:      bugId,
+			metaKeyBugzillaUrl:     fmt.Sprintf("%s/show_bug.cgi?id=%s", bi.conf[confKeyBaseUrl], bugId),
+			metaKeyBugzillaProduct: bi.conf[confKeyProduct],
+			metaKeyBugzillaBaseUrl: bi.conf[confKeyBaseUrl],
+		}, files),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// importing a new bug
+	bi.out <- core.NewImportBug(b.Id())
+
+	// the keywords and the status set when filing the bug
+	labels := bi.mapping.ImportLabels(keywords)
+	if len(labels) > 0 {
+		op, err := b.ForceChangeLabelsRaw(author, remote.CreationTime.Unix(), labels, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		bi.out <- core.NewImportLabelChange(b.Id(), op.Id())
+	}
+	if _, mapped := bi.mapping.ImportStatus(status); mapped || isClosed(status) {
+		op, labelOp, err := bi.mapping.ApplyStatus(b, author, remote.CreationTime.Unix(), status, statusFallback(status), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		bi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+		if labelOp != nil {
+			bi.out <- core.NewImportLabelChange(b.Id(), labelOp.Id())
+		}
+	}
+
+	return b, nil
+}
+
+// originalValues walk back the history to find the title, the keywords and
+// the status of a bug when it was filed
+func originalValues(remote Bug, history []History) (string, []string, string) {
+	title := remote.Summary
+	status := remote.Status
+	keywords := make(map[string]bool)
+	for _, keyword := range remote.Keywords {
+		keywords[keyword] = true
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		for _, change := range history[i].Changes {
+			switch change.FieldName {
+			case "summary":
+				title = change.Removed
+			case "status":
+				status = change.Removed
+			case "keywords":
+				for _, keyword := range splitValues(change.Added) {
+					delete(keywords, keyword)
+				}
+				for _, keyword := range splitValues(change.Removed) {
+					keywords[keyword] = true
+				}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(keywords))
+	for keyword := range keywords {
+		result = append(result, keyword)
+	}
+	sort.Strings(result)
+
+	return title, result, status
+}
+
+func (bi *bugzillaImporter) ensureComment(ctx context.Context, repo *cache.RepoCache, b *cache.BugCache, known map[string]struct{}, bugId int64, comment Comment, attachments map[int64]Attachment) error {
+	commentId := strconv.FormatInt(comment.ID, 10)
+	if _, ok := known[commentId]; ok {
+		return nil
+	}
+
+	// private comments are only visible with an API key, and should not end
+	// up in a repository that may be public
+	if comment.IsPrivate {
+		return nil
+	}
+
+	author, err := bi.ensurePerson(ctx, repo, comment.Creator, nil)
+	if err != nil {
+		return err
+	}
+
+	files := bi.importAttachments(repo, &comment, attachments)
+
+	combinedId, _, err := b.AddCommentRaw(
+		author,
+		comment.CreationTime.Unix(),
+		text.Cleanup(comment.Text),
+		bug.AttachmentHashes(files),
+		core.WithAttachmentsMetadata(map[string]string{
+			metaKeyBugzillaId:  commentId,
+			metaKeyBugzillaUrl: fmt.Sprintf("%s/show_bug.cgi?id=%d#c%d", bi.conf[confKeyBaseUrl], bugId, comment.Count),
+		}, files),
+	)
+	if err != nil {
+		return err
+	}
+
+	bi.out <- core.NewImportComment(b.Id(), combinedId)
+	known[commentId] = struct{}{}
+	return nil
+}
+
+// ensureChanges apply the changes of the supported fields: the status, the
+// keywords and the summary
+func (bi *bugzillaImporter) ensureChanges(ctx context.Context, repo *cache.RepoCache, b *cache.BugCache, known map[string]struct{}, history History) error {
+	for _, change := range history.Changes {
+		changeId := fmt.Sprintf("%d-%s", history.When.Unix(), change.FieldName)
+		if _, ok := known[changeId]; ok {
+			continue
+		}
+
+		switch change.FieldName {
+		case "status":
+			// a change between two open or two closed statuses is only
+			// relevant if mapped to some labels
+			_, mapped := bi.mapping.ImportStatus(change.Added)
+			if !mapped && isClosed(change.Added) == isClosed(change.Removed) {
+				continue
+			}
+		case "keywords", "summary":
+		default:
+			// other fields (assignee, cc, priority ...) are not supported
+			continue
+		}
+
+		author, err := bi.ensurePerson(ctx, repo, history.Who, nil)
+		if err != nil {
+			return err
+		}
+
+		metadata := map[string]string{
+			metaKeyBugzillaId: changeId,
+		}
+
+		switch change.FieldName {
+		case "status":
+			labelsMetadata := map[string]string{
+				metaKeyBugzillaId: core.StatusLabelsId(changeId),
+			}
+			op, labelOp, err := bi.mapping.ApplyStatus(b, author, history.When.Unix(), change.Added, statusFallback(change.Added), metadata, labelsMetadata)
+			if err != nil {
+				return err
+			}
+			bi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+			if labelOp != nil {
+				bi.out <- core.NewImportLabelChange(b.Id(), labelOp.Id())
+			}
+
+		case "keywords":
+			added := bi.mapping.ImportLabels(splitValues(change.Added))
+			removed := bi.mapping.ImportLabels(splitValues(change.Removed))
+			if len(added) == 0 && len(removed) == 0 {
+				continue
+			}
+			op, err := b.ForceChangeLabelsRaw(author, history.When.Unix(), added, removed, metadata)
+			if err != nil {
+				return err
+			}
+			bi.out <- core.NewImportLabelChange(b.Id(), op.Id())
+
+		case "summary":
+			op, err := b.SetTitleRaw(author, history.When.Unix(), text.CleanupOneLine(change.Added), metadata)
+			if err != nil {
+				return err
+			}
+			bi.out <- core.NewImportTitleEdition(b.Id(), op.Id())
+		}
+
+		known[changeId] = struct{}{}
+	}
+
+	return nil
+}
+
+// importAttachments store the file attached along with a comment, if any
+func (bi *bugzillaImporter) importAttachments(repo *cache.RepoCache, comment *Comment, attachments map[int64]Attachment) []bug.Attachment {
+	if comment.AttachmentID == nil {
+		return nil
+	}
+
+	remote, ok := attachments[*comment.AttachmentID]
+	if !ok || remote.IsPrivate {
+		return nil
+	}
+
+	attachment, err := repo.StoreAttachment(remote.FileName, remote.Data)
+	if err != nil {
+		bi.out <- core.NewImportWarning(fmt.Errorf("attachment %d: %v", remote.ID, err), "")
+		return nil
+	}
+	if remote.ContentType != "" {
+		attachment.MimeType = remote.ContentType
+	}
+
+	return []bug.Attachment{attachment}
+}
+
+func (bi *bugzillaImporter) ensurePerson(ctx context.Context, repo *cache.RepoCache, login string, detail *User) (*cache.IdentityCache, error) {
+	// Look first in the cache
+	i, err := repo.Identities().ResolveIdentityImmutableMetadata(metaKeyBugzillaLogin, login)
+	if err == nil {
+		return i, nil
+	}
+	if entity.IsErrMultipleMatch(err) {
+		return nil, err
+	}
+
+	// the details of the users are not always available anonymously
+	if detail == nil {
+		detail, err = bi.client.GetUser(ctx, login)
+		if err != nil {
+			detail = &User{Name: login}
+		}
+	}
+
+	name := detail.RealName
+	if name == "" {
+		name = login
+	}
+
+	i, err = repo.Identities().NewRaw(
+		name,
+		detail.Email,
+		login,
+		"",
+		nil,
+		map[string]string{
+			metaKeyBugzillaLogin: login,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	bi.out <- core.NewImportIdentity(i.Id())
+	return i, nil
+}
+
+func statusFallback(status string) common.Status {
+	if isClosed(status) {
+		return common.ClosedStatus
+	}
+	return common.OpenStatus
+}
+
+// knownBugzillaIds return the set of the Bugzilla comment ids and change ids
+// already attached to the operations of a bug
+func knownBugzillaIds(b *cache.BugCache) map[string]struct{} {
+	result := make(map[string]struct{})
+
+	// the create operation hold the bug id
+	for _, op := range b.Snapshot().Operations[1:] {
+		if value, ok := op.GetMetadata(metaKeyBugzillaId); ok {
+			result[value] = struct{}{}
+		}
+	}
+
+	return result
+}
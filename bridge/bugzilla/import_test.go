@@ -0,0 +1,188 @@
+package bugzilla
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBugzillaImport(t *testing.T) {
+	server := newFakeBugzilla(t, "Widget")
+	server.addUser("alice@example.com", "Alice", "")
+	server.addUser("bob@example.com", "Bob", "")
+
+	// simple bug, with a private comment
+	simple := server.createBug("alice@example.com", "simple bug", "description", nil)
+	server.addComment(simple, "bob@example.com", "first comment", false)
+	server.addComment(simple, "bob@example.com", "secret comment", true)
+
+	// complex bug
+	complexBug := server.createBug("alice@example.com", "complex bug", "description", []string{"crash"})
+	server.attach(complexBug, "alice@example.com", "trace.txt", "text/plain", []byte("stack trace"), true)
+	server.change(complexBug, "bob@example.com", Change{FieldName: "summary", Removed: "complex bug", Added: "complex bug edited"})
+	patch := server.attach(complexBug, "bob@example.com", "patch.diff", "text/x-patch", []byte("a patch"), false)
+	server.change(complexBug, "bob@example.com", Change{FieldName: "keywords", Added: "regression"})
+	server.change(complexBug, "alice@example.com",
+		Change{FieldName: "status", Removed: "NEW", Added: "RESOLVED"},
+		Change{FieldName: "resolution", Added: "FIXED"},
+	)
+	server.change(complexBug, "alice@example.com", Change{FieldName: "status", Removed: "RESOLVED", Added: "VERIFIED"})
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	alice, err := identity.NewIdentity(repo, "Alice", "alice@example.com")
+	require.NoError(t, err)
+	bob, err := identity.NewIdentity(repo, "Bob", "bob@example.com")
+	require.NoError(t, err)
+
+	conf := core.Configuration{
+		confKeyBaseUrl: server.URL,
+		confKeyProduct: "Widget",
+	}
+
+	ctx := context.Background()
+
+	runImport := func(since time.Time) []core.ImportResult {
+		importer := &bugzillaImporter{}
+		err := importer.Init(ctx, backend, conf)
+		require.NoError(t, err)
+
+		events, err := importer.ImportAll(ctx, backend, since)
+		require.NoError(t, err)
+
+		var results []core.ImportResult
+		for result := range events {
+			require.NoError(t, result.Err)
+			results = append(results, result)
+		}
+		return results
+	}
+
+	runImport(time.Time{})
+
+	tests := []struct {
+		name string
+		id   int64
+		ops  []dag.Operation
+	}{
+		{
+			name: "simple bug",
+			id:   simple,
+			ops: []dag.Operation{
+				bug.NewCreateOp(alice, 0, "simple bug", "description", nil),
+				bug.NewAddCommentOp(bob, 0, "first comment", nil),
+			},
+		},
+		{
+			name: "complex bug",
+			id:   complexBug,
+			ops: []dag.Operation{
+				bug.NewCreateOp(alice, 0, "complex bug", "description", nil),
+				bug.NewLabelChangeOperation(alice, 0, []bug.Label{"crash"}, nil),
+				bug.NewSetTitleOp(bob, 0, "complex bug edited", "complex bug"),
+				bug.NewAddCommentOp(bob, 0, fmt.Sprintf("Created attachment %d", patch), nil),
+				bug.NewLabelChangeOperation(bob, 0, []bug.Label{"regression"}, nil),
+				bug.NewSetStatusOp(alice, 0, common.ClosedStatus),
+			},
+		},
+	}
+
+	require.Len(t, backend.Bugs().AllIds(), len(tests))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := fmt.Sprintf("%s/show_bug.cgi?id=%d", server.URL, tt.id)
+			b, err := backend.Bugs().ResolveBugCreateMetadata(metaKeyBugzillaUrl, url)
+			require.NoError(t, err)
+
+			requireSameOperations(t, tt.ops, b.Snapshot().Operations)
+		})
+	}
+
+	// the original authors are kept
+	i, err := backend.Identities().ResolveIdentityImmutableMetadata(metaKeyBugzillaLogin, "bob@example.com")
+	require.NoError(t, err)
+	require.Equal(t, "Bob", i.Name())
+	require.Equal(t, "bob@example.com", i.Email())
+
+	// the attachments are imported with their comment
+	b, err := backend.Bugs().ResolveBugCreateMetadata(metaKeyBugzillaUrl, fmt.Sprintf("%s/show_bug.cgi?id=%d", server.URL, complexBug))
+	require.NoError(t, err)
+	comments := b.Snapshot().Comments
+	require.Len(t, comments[0].Attachments, 1)
+	require.Equal(t, "trace.txt", comments[0].Attachments[0].Name)
+	require.Equal(t, "text/plain", comments[0].Attachments[0].MimeType)
+	require.Len(t, comments[1].Attachments, 1)
+	require.Equal(t, "patch.diff", comments[1].Attachments[0].Name)
+	require.Equal(t, "text/x-patch", comments[1].Attachments[0].MimeType)
+
+	// a second import doesn't do anything
+	for _, result := range runImport(time.Time{}) {
+		require.Equal(t, core.ImportEventNothing, result.Event)
+	}
+
+	// an incremental import only look at the bugs changed since then
+	since := server.now.Add(time.Second)
+	server.tick()
+	server.addComment(simple, "alice@example.com", "new comment", false)
+	server.change(simple, "bob@example.com", Change{FieldName: "keywords", Added: "easy"})
+
+	b, err = backend.Bugs().ResolveBugCreateMetadata(metaKeyBugzillaUrl, fmt.Sprintf("%s/show_bug.cgi?id=%d", server.URL, simple))
+	require.NoError(t, err)
+
+	for _, result := range runImport(since) {
+		require.Equal(t, b.Id(), result.EntityId)
+	}
+
+	requireSameOperations(t, []dag.Operation{
+		bug.NewCreateOp(alice, 0, "simple bug", "description", nil),
+		bug.NewAddCommentOp(bob, 0, "first comment", nil),
+		bug.NewAddCommentOp(alice, 0, "new comment", nil),
+		bug.NewLabelChangeOperation(bob, 0, []bug.Label{"easy"}, nil),
+	}, b.Snapshot().Operations)
+}
+
+func requireSameOperations(t *testing.T, expected []dag.Operation, actual []dag.Operation) {
+	t.Helper()
+
+	require.Len(t, actual, len(expected))
+
+	for i, op := range expected {
+		require.IsType(t, op, actual[i])
+		require.Equal(t, op.Author().Name(), actual[i].Author().Name())
+
+		switch op := op.(type) {
+		case *bug.CreateOperation:
+			require.Equal(t, op.Title, actual[i].(*bug.CreateOperation).Title)
+			require.Equal(t, op.Message, actual[i].(*bug.CreateOperation).Message)
+		case *bug.SetStatusOperation:
+			require.Equal(t, op.Status, actual[i].(*bug.SetStatusOperation).Status)
+		case *bug.SetTitleOperation:
+			require.Equal(t, op.Was, actual[i].(*bug.SetTitleOperation).Was)
+			require.Equal(t, op.Title, actual[i].(*bug.SetTitleOperation).Title)
+		case *bug.LabelChangeOperation:
+			require.ElementsMatch(t, op.Added, actual[i].(*bug.LabelChangeOperation).Added)
+			require.ElementsMatch(t, op.Removed, actual[i].(*bug.LabelChangeOperation).Removed)
+		case *bug.AddCommentOperation:
+			require.Equal(t, op.Message, actual[i].(*bug.AddCommentOperation).Message)
+
+		default:
+			panic("unknown operation type")
+		}
+	}
+}
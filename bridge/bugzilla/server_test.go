@@ -0,0 +1,336 @@
+package bugzilla
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBugzilla is an in-memory stand-in of the subset of the Bugzilla REST API
+// used by the bridge.
+type fakeBugzilla struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	product string
+	users   map[string]User // login --> user
+	apiKeys map[string]string
+	bugs    []*fakeBug
+	lastId  int64
+	now     time.Time
+}
+
+type fakeBug struct {
+	Bug
+	comments    []Comment
+	history     []History
+	attachments []Attachment
+}
+
+func newFakeBugzilla(t *testing.T, product string) *fakeBugzilla {
+	f := &fakeBugzilla{
+		product: product,
+		users:   make(map[string]User),
+		apiKeys: make(map[string]string),
+		now:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/whoami", f.handleWhoami)
+	mux.HandleFunc("GET /rest/product", f.handleProduct)
+	mux.HandleFunc("GET /rest/user", f.handleUser)
+	mux.HandleFunc("GET /rest/bug", f.handleListBugs)
+	mux.HandleFunc("GET /rest/bug/{id}/comment", f.handleComments)
+	mux.HandleFunc("GET /rest/bug/{id}/history", f.handleHistory)
+	mux.HandleFunc("GET /rest/bug/{id}/attachment", f.handleAttachments)
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// tick advance the clock of the server, to order the changes
+func (f *fakeBugzilla) tick() time.Time {
+	f.now = f.now.Add(time.Minute)
+	return f.now
+}
+
+func (f *fakeBugzilla) addUser(login, realName, apiKey string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastId++
+	f.users[login] = User{ID: f.lastId, Name: login, RealName: realName, Email: login}
+	if apiKey != "" {
+		f.apiKeys[apiKey] = login
+	}
+}
+
+func (f *fakeBugzilla) createBug(creator, summary, description string, keywords []string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.tick()
+	f.lastId++
+	b := &fakeBug{
+		Bug: Bug{
+			ID:             f.lastId,
+			Summary:        summary,
+			Status:         "NEW",
+			Product:        f.product,
+			Keywords:       keywords,
+			Creator:        creator,
+			CreationTime:   now,
+			LastChangeTime: now,
+		},
+	}
+	f.lastId++
+	b.comments = append(b.comments, Comment{
+		ID:           f.lastId,
+		Count:        0,
+		Text:         description,
+		Creator:      creator,
+		CreationTime: now,
+	})
+	f.bugs = append(f.bugs, b)
+	return b.ID
+}
+
+func (f *fakeBugzilla) bug(id int64) *fakeBug {
+	for _, b := range f.bugs {
+		if b.ID == id {
+			return b
+		}
+	}
+	return nil
+}
+
+func (f *fakeBugzilla) addComment(bugId int64, creator, text string, private bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b := f.bug(bugId)
+	now := f.tick()
+	f.lastId++
+	b.comments = append(b.comments, Comment{
+		ID:           f.lastId,
+		Count:        len(b.comments),
+		Text:         text,
+		Creator:      creator,
+		CreationTime: now,
+		IsPrivate:    private,
+	})
+	b.LastChangeTime = now
+}
+
+// attach add a file to a bug, along with the comment announcing it, or to the
+// description of the bug.
+func (f *fakeBugzilla) attach(bugId int64, creator, fileName, contentType string, data []byte, toDescription bool) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b := f.bug(bugId)
+	f.lastId++
+	attachmentId := f.lastId
+	b.attachments = append(b.attachments, Attachment{
+		ID:           attachmentId,
+		FileName:     fileName,
+		ContentType:  contentType,
+		Data:         data,
+		Creator:      creator,
+		CreationTime: f.now,
+	})
+
+	if toDescription {
+		b.comments[0].AttachmentID = &attachmentId
+		return attachmentId
+	}
+
+	now := f.tick()
+	f.lastId++
+	b.comments = append(b.comments, Comment{
+		ID:           f.lastId,
+		Count:        len(b.comments),
+		Text:         "Created attachment " + strconv.FormatInt(attachmentId, 10),
+		Creator:      creator,
+		CreationTime: now,
+		AttachmentID: &attachmentId,
+	})
+	b.LastChangeTime = now
+	return attachmentId
+}
+
+func (f *fakeBugzilla) change(bugId int64, who string, changes ...Change) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b := f.bug(bugId)
+	now := f.tick()
+	for _, change := range changes {
+		switch change.FieldName {
+		case "summary":
+			b.Summary = change.Added
+		case "status":
+			b.Status = change.Added
+		case "keywords":
+			keywords := make(map[string]bool)
+			for _, keyword := range b.Keywords {
+				keywords[keyword] = true
+			}
+			for _, keyword := range splitValues(change.Removed) {
+				delete(keywords, keyword)
+			}
+			for _, keyword := range splitValues(change.Added) {
+				keywords[keyword] = true
+			}
+			b.Keywords = nil
+			for keyword := range keywords {
+				b.Keywords = append(b.Keywords, keyword)
+			}
+		}
+	}
+	b.history = append(b.history, History{When: now, Who: who, Changes: changes})
+	b.LastChangeTime = now
+}
+
+func (f *fakeBugzilla) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeBugzilla) writeError(w http.ResponseWriter, status int, code int, msg string) {
+	f.writeJSON(w, status, map[string]interface{}{"error": true, "code": code, "message": msg})
+}
+
+func (f *fakeBugzilla) handleWhoami(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	login, ok := f.apiKeys[r.Header.Get("X-BUGZILLA-API-KEY")]
+	if !ok {
+		f.writeError(w, http.StatusUnauthorized, 306, "The API key you specified is invalid.")
+		return
+	}
+	f.writeJSON(w, http.StatusOK, f.users[login])
+}
+
+func (f *fakeBugzilla) handleProduct(w http.ResponseWriter, r *http.Request) {
+	var products []Product
+	if r.URL.Query().Get("names") == f.product {
+		products = append(products, Product{ID: 1, Name: f.product})
+	}
+	f.writeJSON(w, http.StatusOK, map[string]interface{}{"products": products})
+}
+
+func (f *fakeBugzilla) handleUser(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	user, ok := f.users[r.URL.Query().Get("names")]
+	if !ok {
+		f.writeError(w, http.StatusNotFound, 51, "There is no user with that name.")
+		return
+	}
+	f.writeJSON(w, http.StatusOK, map[string]interface{}{"users": []User{user}})
+}
+
+func (f *fakeBugzilla) handleListBugs(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query()
+	var since time.Time
+	if raw := query.Get("last_change_time"); raw != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			f.writeError(w, http.StatusBadRequest, 32000, err.Error())
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	var bugs []Bug
+	for _, b := range f.bugs {
+		if query.Get("product") != b.Product || b.LastChangeTime.Before(since) {
+			continue
+		}
+		bug := b.Bug
+		creator := f.users[b.Creator]
+		bug.CreatorDetail = &creator
+		bugs = append(bugs, bug)
+	}
+
+	if offset > len(bugs) {
+		offset = len(bugs)
+	}
+	bugs = bugs[offset:]
+	if limit > 0 && limit < len(bugs) {
+		bugs = bugs[:limit]
+	}
+
+	f.writeJSON(w, http.StatusOK, map[string]interface{}{"bugs": bugs})
+}
+
+func (f *fakeBugzilla) pathBug(w http.ResponseWriter, r *http.Request) *fakeBug {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err == nil {
+		if b := f.bug(id); b != nil {
+			return b
+		}
+	}
+	f.writeError(w, http.StatusNotFound, 101, "Bug does not exist.")
+	return nil
+}
+
+func (f *fakeBugzilla) handleComments(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b := f.pathBug(w, r)
+	if b == nil {
+		return
+	}
+	f.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"bugs": map[string]interface{}{
+			strconv.FormatInt(b.ID, 10): map[string]interface{}{"comments": b.comments},
+		},
+	})
+}
+
+func (f *fakeBugzilla) handleHistory(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b := f.pathBug(w, r)
+	if b == nil {
+		return
+	}
+	f.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"bugs": []interface{}{
+			map[string]interface{}{"id": b.ID, "history": b.history},
+		},
+	})
+}
+
+func (f *fakeBugzilla) handleAttachments(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b := f.pathBug(w, r)
+	if b == nil {
+		return
+	}
+	f.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"bugs": map[string]interface{}{
+			strconv.FormatInt(b.ID, 10): b.attachments,
+		},
+	})
+}
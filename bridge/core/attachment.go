@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// DownloadAttachment download a file linked from a remote issue and store it
+// in the repository as an attachment. The given headers are added to the
+// request, to authenticate on the remote if needed.
+func DownloadAttachment(ctx context.Context, repo *cache.RepoCache, client *http.Client, rawUrl string, header http.Header) (bug.Attachment, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return bug.Attachment{}, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return bug.Attachment{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return bug.Attachment{}, fmt.Errorf("downloading %s: %s", rawUrl, resp.Status)
+	}
+
+	maxSize, err := repo.AttachmentMaxSize()
+	if err != nil {
+		return bug.Attachment{}, err
+	}
+
+	// read one more byte than allowed so that StoreAttachment can reject it
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return bug.Attachment{}, fmt.Errorf("downloading %s: %v", rawUrl, err)
+	}
+
+	return repo.StoreAttachment(attachmentName(rawUrl, resp.Header), data)
+}
+
+// DownloadAttachments download all the given files and return the resulting
+// attachments. As a missing attachment should not prevent importing a comment,
+// the failing downloads are only reported as warnings.
+func DownloadAttachments(ctx context.Context, repo *cache.RepoCache, client *http.Client, urls []string, header http.Header, out chan<- ImportResult, entityId entity.Id) []bug.Attachment {
+	var attachments []bug.Attachment
+	for _, rawUrl := range urls {
+		attachment, err := DownloadAttachment(ctx, repo, client, rawUrl, header)
+		if err != nil {
+			out <- NewImportWarning(fmt.Errorf("attachment: %v", err), entityId)
+			continue
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments
+}
+
+// WithAttachmentsMetadata return the given operation metadata, completed with
+// the metadata describing the attachments.
+func WithAttachmentsMetadata(metadata map[string]string, attachments []bug.Attachment) map[string]string {
+	if len(attachments) == 0 {
+		return metadata
+	}
+	result := make(map[string]string, len(metadata)+len(attachments))
+	for key, value := range metadata {
+		result[key] = value
+	}
+	for key, value := range bug.AttachmentsMetadata(attachments) {
+		result[key] = value
+	}
+	return result
+}
+
+// attachmentName find the name of a downloaded file, preferably from the
+// Content-Disposition header, or else from the url.
+func attachmentName(rawUrl string, header http.Header) string {
+	_, params, err := mime.ParseMediaType(header.Get("Content-Disposition"))
+	if err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return ""
+	}
+	return name
+}
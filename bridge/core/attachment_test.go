@@ -0,0 +1,72 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestDownloadAttachments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/uploads/screenshot.png":
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		case "/assets/1234":
+			w.Header().Set("Content-Disposition", `attachment; filename="report.txt"`)
+			_, _ = w.Write([]byte("some report"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", "secret")
+
+	out := make(chan ImportResult, 10)
+	attachments := DownloadAttachments(context.Background(), backend, server.Client(), []string{
+		server.URL + "/uploads/screenshot.png",
+		server.URL + "/missing",
+		server.URL + "/assets/1234",
+	}, header, out, "")
+	close(out)
+
+	require.Len(t, attachments, 2)
+	require.Equal(t, "screenshot.png", attachments[0].Name)
+	require.Equal(t, "image/png", attachments[0].MimeType)
+	require.Equal(t, "report.txt", attachments[1].Name)
+	require.Equal(t, int64(11), attachments[1].Size)
+
+	data, err := backend.ReadData(attachments[1].Hash)
+	require.NoError(t, err)
+	require.Equal(t, "some report", string(data))
+
+	// the missing file is reported as a warning
+	var warnings []ImportResult
+	for result := range out {
+		warnings = append(warnings, result)
+	}
+	require.Len(t, warnings, 1)
+	require.Equal(t, ImportEventWarning, warnings[0].Event)
+
+	metadata := WithAttachmentsMetadata(map[string]string{"foo": "bar"}, attachments)
+	require.Equal(t, "bar", metadata["foo"])
+	require.Contains(t, metadata, bug.MetaKeyAttachmentPrefix+string(attachments[0].Hash))
+	require.Len(t, metadata, 3)
+}
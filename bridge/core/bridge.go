@@ -4,6 +4,7 @@ package core
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"reflect"
 	"regexp"
@@ -19,6 +20,7 @@ import (
 
 var ErrImportNotSupported = errors.New("import is not supported")
 var ErrExportNotSupported = errors.New("export is not supported")
+var ErrDryRunNotSupported = errors.New("dry-run is not supported")
 
 const (
 	ConfigKeyTarget = "target"
@@ -42,6 +44,13 @@ type Bridge struct {
 	conf           Configuration
 	initImportDone bool
 	initExportDone bool
+
+	// dryRun is set on the bridges working on a sandbox, see DryRun
+	dryRun bool
+
+	// transport records or replays the HTTP interactions, if configured to
+	transport     http.RoundTripper
+	transportDone bool
 }
 
 // Register will register a new BridgeImpl
@@ -123,6 +132,11 @@ func LoadBridge(repo *cache.RepoCache, name string) (*Bridge, error) {
 		return nil, errors.Wrap(err, "invalid configuration")
 	}
 
+	_, err = LoadMapping(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid configuration")
+	}
+
 	// will avoid reloading configuration before an export or import call
 	bridge.conf = conf
 	return bridge, nil
@@ -219,6 +233,85 @@ func (b *Bridge) Configure(params BridgeParams, interactive bool) error {
 	return b.storeConfig(conf)
 }
 
+// Mapping return the label and status mapping of the bridge
+func (b *Bridge) Mapping() (*Mapping, error) {
+	err := b.ensureConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadMapping(b.conf)
+}
+
+// SetMapping replace the label and status mapping of the bridge
+func (b *Bridge) SetMapping(mapping *Mapping) error {
+	err := b.ensureConfig()
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]bool)
+	for _, key := range []string{ConfigKeyLabelMapping, ConfigKeyStatusMapping} {
+		_, previous[key] = b.conf[key]
+	}
+
+	err = mapping.Store(b.conf)
+	if err != nil {
+		return err
+	}
+
+	// remove the emptied parts
+	for key, existed := range previous {
+		if _, ok := b.conf[key]; ok || !existed {
+			continue
+		}
+		storeKey := fmt.Sprintf("git-bug.bridge.%s.%s", b.Name, key)
+		err := b.repo.LocalConfig().RemoveAll(storeKey)
+		if err != nil {
+			return errors.Wrap(err, "error while removing bridge configuration")
+		}
+	}
+
+	return b.storeConfig(b.conf)
+}
+
+// DryRun return a copy of the bridge working on a sandbox of the repository.
+// Its imports and exports go through the whole process and emit the same
+// events, tagged as planned, but nothing is written in the repository and
+// nothing is changed on the remote tracker. Close must be called once done.
+func (b *Bridge) DryRun() (*Bridge, error) {
+	err := b.ensureConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	sandbox, err := b.repo.Sandbox()
+	if err != nil {
+		return nil, errors.Wrap(err, "creating the sandbox")
+	}
+
+	conf := make(Configuration, len(b.conf))
+	for key, value := range b.conf {
+		conf[key] = value
+	}
+
+	return &Bridge{
+		Name:   b.Name,
+		repo:   sandbox,
+		impl:   b.impl,
+		conf:   conf,
+		dryRun: true,
+	}, nil
+}
+
+// Close release the sandbox of a dry-run bridge
+func (b *Bridge) Close() error {
+	if !b.dryRun {
+		return nil
+	}
+	return b.repo.Close()
+}
+
 func validateParams(params BridgeParams, impl BridgeImpl) {
 	validParams := impl.ValidParams()
 
@@ -227,9 +320,8 @@ func validateParams(params BridgeParams, impl BridgeImpl) {
 
 	for i := 0; i < paramsValue.NumField(); i++ {
 		name := paramsType.Field(i).Name
-		val := paramsValue.Field(i).Interface().(string)
 		_, valid := validParams[name]
-		if val != "" && !valid {
+		if !paramsValue.Field(i).IsZero() && !valid {
 			_, _ = fmt.Fprintln(os.Stderr, params.fieldWarning(name, impl.Target()))
 		}
 	}
@@ -293,6 +385,21 @@ func (b *Bridge) getExporter() Exporter {
 	return b.exporter
 }
 
+// httpContext return a context giving to the importer and exporter the HTTP
+// transport of the bridge, see WithHTTPTransport.
+func (b *Bridge) httpContext(ctx context.Context) (context.Context, error) {
+	if !b.transportDone {
+		transport, err := NewHTTPFixturesTransport(b.conf)
+		if err != nil {
+			return nil, err
+		}
+		b.transport = transport
+		b.transportDone = true
+	}
+
+	return WithHTTPTransport(ctx, b.transport), nil
+}
+
 func (b *Bridge) ensureImportInit(ctx context.Context) error {
 	if b.initImportDone {
 		return nil
@@ -341,6 +448,11 @@ func (b *Bridge) ImportAllSince(ctx context.Context, since time.Time) (<-chan Im
 		return nil, err
 	}
 
+	ctx, err = b.httpContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	err = b.ensureImportInit(ctx)
 	if err != nil {
 		return nil, err
@@ -361,13 +473,17 @@ func (b *Bridge) ImportAllSince(ctx context.Context, since time.Time) (<-chan Im
 			if event.Event == ImportEventError {
 				noError = false
 			}
+			event.Planned = b.dryRun
 			out <- event
 		}
 
-		// store the last import time ONLY if no error happened
-		if noError {
-			key := fmt.Sprintf("git-bug.bridge.%s.lastImportTime", b.Name)
-			err = b.repo.LocalConfig().StoreTimestamp(key, importStartTime)
+		// store the last import time ONLY if no error happened and the import
+		// was not interrupted, so that the next import resume from there
+		if noError && ctx.Err() == nil {
+			err := b.repo.LocalConfig().StoreTimestamp(b.lastImportKey(), importStartTime)
+			if err != nil {
+				out <- NewImportError(err, "")
+			}
 		}
 	}()
 
@@ -376,29 +492,69 @@ func (b *Bridge) ImportAllSince(ctx context.Context, since time.Time) (<-chan Im
 
 func (b *Bridge) ImportAll(ctx context.Context) (<-chan ImportResult, error) {
 	// If possible, restart from the last import time
-	lastImport, err := b.repo.LocalConfig().ReadTimestamp(fmt.Sprintf("git-bug.bridge.%s.lastImportTime", b.Name))
-	if err == nil {
+	lastImport, ok := b.LastImportTime()
+	if ok {
 		return b.ImportAllSince(ctx, lastImport)
 	}
 
 	return b.ImportAllSince(ctx, time.Time{})
 }
 
+// LastImportTime return the start time of the last complete import, if any.
+func (b *Bridge) LastImportTime() (time.Time, bool) {
+	lastImport, err := b.repo.LocalConfig().ReadTimestamp(b.lastImportKey())
+	if err != nil {
+		return time.Time{}, false
+	}
+	return lastImport, true
+}
+
+func (b *Bridge) lastImportKey() string {
+	return fmt.Sprintf("git-bug.bridge.%s.lastImportTime", b.Name)
+}
+
 func (b *Bridge) ExportAll(ctx context.Context, since time.Time) (<-chan ExportResult, error) {
 	exporter := b.getExporter()
 	if exporter == nil {
 		return nil, ErrExportNotSupported
 	}
 
+	if b.dryRun {
+		dryRunExporter, ok := exporter.(DryRunExporter)
+		if !ok {
+			return nil, ErrDryRunNotSupported
+		}
+		dryRunExporter.SetDryRun(true)
+	}
+
 	err := b.ensureConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	ctx, err = b.httpContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	err = b.ensureExportInit(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return exporter.ExportAll(ctx, b.repo, since)
+	events, err := exporter.ExportAll(ctx, b.repo, since)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan ExportResult)
+	go func() {
+		defer close(out)
+		for event := range events {
+			event.Planned = b.dryRun
+			out <- event
+		}
+	}()
+
+	return out, nil
 }
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// ConfigKeyConflictPolicy holds the policy applied by the exporter when a local
+// change conflicts with a remote one.
+const ConfigKeyConflictPolicy = "conflict-policy"
+
+// ConflictPolicy tells an exporter what to do when the remote value it's about
+// to overwrite has been changed since the last synchronization.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicySkip leave the local change unexported, until the conflict
+	// is resolved with another policy. This is the default.
+	ConflictPolicySkip ConflictPolicy = "skip"
+	// ConflictPolicyLocalWins overwrite the remote change with the local one.
+	ConflictPolicyLocalWins ConflictPolicy = "local-wins"
+	// ConflictPolicyRemoteWins keep the remote change and consider the local
+	// one as exported. The remote change will come back with the next import.
+	ConflictPolicyRemoteWins ConflictPolicy = "remote-wins"
+	// ConflictPolicyFail stop the export of the bug with an error.
+	ConflictPolicyFail ConflictPolicy = "fail"
+)
+
+// ConflictPolicies return all the valid conflict policies
+func ConflictPolicies() []ConflictPolicy {
+	return []ConflictPolicy{
+		ConflictPolicySkip,
+		ConflictPolicyLocalWins,
+		ConflictPolicyRemoteWins,
+		ConflictPolicyFail,
+	}
+}
+
+// ConflictPolicyFromString parse a conflict policy
+func ConflictPolicyFromString(str string) (ConflictPolicy, error) {
+	for _, policy := range ConflictPolicies() {
+		if string(policy) == str {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown conflict policy \"%s\"", str)
+}
+
+// LoadConflictPolicy read the conflict policy from a bridge configuration,
+// defaulting to ConflictPolicySkip.
+func LoadConflictPolicy(conf Configuration) (ConflictPolicy, error) {
+	raw, ok := conf[ConfigKeyConflictPolicy]
+	if !ok || raw == "" {
+		return ConflictPolicySkip, nil
+	}
+	return ConflictPolicyFromString(raw)
+}
+
+// ConflictPolicy return the conflict policy of the bridge
+func (b *Bridge) ConflictPolicy() (ConflictPolicy, error) {
+	err := b.ensureConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return LoadConflictPolicy(b.conf)
+}
+
+// SetConflictPolicy store the conflict policy of the bridge
+func (b *Bridge) SetConflictPolicy(policy ConflictPolicy) error {
+	err := b.ensureConfig()
+	if err != nil {
+		return err
+	}
+
+	if _, err := ConflictPolicyFromString(string(policy)); err != nil {
+		return err
+	}
+
+	b.conf[ConfigKeyConflictPolicy] = string(policy)
+	return b.storeConfig(b.conf)
+}
+
+// The conflicts are recorded in the metadata of the conflicting operation, as
+// "<target>-conflict" holding the description of the conflict and, once
+// resolved by a policy, "<target>-conflict-resolution" holding that policy.
+func conflictMetaKey(target string) string {
+	return target + "-conflict"
+}
+
+func conflictResolutionMetaKey(target string) string {
+	return target + "-conflict-resolution"
+}
+
+// RecordConflict store in the operation metadata that exporting it to the
+// given target conflicted with a remote change, and how the policy resolved
+// it. Nothing is stored if the operation already carries that information.
+func RecordConflict(b *cache.BugCache, op dag.Operation, target string, policy ConflictPolicy, reason string) error {
+	metadata := make(map[string]string)
+
+	if _, ok := op.GetMetadata(conflictMetaKey(target)); !ok {
+		metadata[conflictMetaKey(target)] = reason
+	}
+
+	switch policy {
+	case ConflictPolicyLocalWins, ConflictPolicyRemoteWins:
+		if _, ok := op.GetMetadata(conflictResolutionMetaKey(target)); !ok {
+			metadata[conflictResolutionMetaKey(target)] = string(policy)
+		}
+	}
+
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	_, err := b.SetMetadata(op.Id(), metadata)
+	if err != nil {
+		return errors.Wrap(err, "recording conflict")
+	}
+
+	return b.CommitAsNeeded()
+}
+
+// Conflict is a local operation that couldn't be exported because of a
+// conflicting remote change, and that no policy resolved yet.
+type Conflict struct {
+	BugId       entity.Id
+	OperationId entity.Id
+	Time        time.Time
+	Reason      string
+}
+
+// ListConflicts return the unresolved conflicts recorded for the given target,
+// oldest first.
+func ListConflicts(repo *cache.RepoCache, target string) ([]Conflict, error) {
+	var result []Conflict
+
+	for _, id := range repo.Bugs().AllIds() {
+		b, err := repo.Bugs().Resolve(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, op := range b.Snapshot().Operations {
+			reason, ok := op.GetMetadata(conflictMetaKey(target))
+			if !ok {
+				continue
+			}
+			if _, resolved := op.GetMetadata(conflictResolutionMetaKey(target)); resolved {
+				continue
+			}
+			result = append(result, Conflict{
+				BugId:       b.Id(),
+				OperationId: op.Id(),
+				Time:        op.Time(),
+				Reason:      reason,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+
+	return result, nil
+}
+
+// Conflicts return the unresolved conflicts of the bridge
+func (b *Bridge) Conflicts() ([]Conflict, error) {
+	return ListConflicts(b.repo, b.impl.Target())
+}
+
+// PreviousCommentMessage return the message of the comment targeted by an
+// edition, as it was right before that edition. This is what the remote
+// tracker is expected to hold if nothing changed there since the last
+// synchronization.
+func PreviousCommentMessage(snapshot *bug.Snapshot, edit *bug.EditCommentOperation) string {
+	var message string
+
+	for _, op := range snapshot.Operations {
+		if op.Id() == edit.Id() {
+			break
+		}
+
+		switch op := op.(type) {
+		case *bug.CreateOperation:
+			if op.Id() == edit.Target {
+				message = op.Message
+			}
+		case *bug.AddCommentOperation:
+			if op.Id() == edit.Target {
+				message = op.Message
+			}
+		case *bug.EditCommentOperation:
+			if op.Target == edit.Target {
+				message = op.Message
+			}
+		}
+	}
+
+	return message
+}
+
+// RemoteChanged tell if a remote value differ from both the value expected
+// from the last synchronization and the local value about to be exported,
+// once cleaned up the same way the importers do. A remote value already
+// equal to the local one isn't a conflict.
+func RemoteChanged(remote, expected, local string) bool {
+	remote = text.Cleanup(remote)
+	return remote != text.Cleanup(expected) && remote != text.Cleanup(local)
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestLoadConflictPolicy(t *testing.T) {
+	policy, err := LoadConflictPolicy(Configuration{})
+	require.NoError(t, err)
+	require.Equal(t, ConflictPolicySkip, policy)
+
+	policy, err = LoadConflictPolicy(Configuration{ConfigKeyConflictPolicy: "remote-wins"})
+	require.NoError(t, err)
+	require.Equal(t, ConflictPolicyRemoteWins, policy)
+
+	_, err = LoadConflictPolicy(Configuration{ConfigKeyConflictPolicy: "coin-flip"})
+	require.Error(t, err)
+}
+
+func TestBridgeConflicts(t *testing.T) {
+	Register(&fakeBridge{})
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	rene, err := backend.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	b, err := NewBridge(backend, "fake", "test")
+	require.NoError(t, err)
+	require.NoError(t, b.Configure(BridgeParams{}, false))
+
+	require.NoError(t, b.SetConflictPolicy(ConflictPolicyLocalWins))
+	policy, err := b.ConflictPolicy()
+	require.NoError(t, err)
+	require.Equal(t, ConflictPolicyLocalWins, policy)
+
+	bug1, _, err := backend.Bugs().New("title", "message")
+	require.NoError(t, err)
+	titleOp, err := bug1.SetTitle("new title")
+	require.NoError(t, err)
+	_, commentOp, err := bug1.AddComment("comment")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	conflicts, err := b.Conflicts()
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+
+	// an unresolved conflict is listed, and recorded only once
+	require.NoError(t, RecordConflict(bug1, findOperation(t, bug1, titleOp), "fake", ConflictPolicySkip, "title changed"))
+	opsCount := len(bug1.Snapshot().Operations)
+	require.NoError(t, RecordConflict(bug1, findOperation(t, bug1, titleOp), "fake", ConflictPolicySkip, "title changed"))
+	require.Len(t, bug1.Snapshot().Operations, opsCount)
+	require.NoError(t, RecordConflict(bug1, findOperation(t, bug1, commentOp), "fake", ConflictPolicyFail, "comment changed"))
+
+	conflicts, err = b.Conflicts()
+	require.NoError(t, err)
+	require.Len(t, conflicts, 2)
+	require.Equal(t, bug1.Id(), conflicts[0].BugId)
+	require.Equal(t, titleOp.Id(), conflicts[0].OperationId)
+	require.Equal(t, "title changed", conflicts[0].Reason)
+	require.Equal(t, commentOp.Id(), conflicts[1].OperationId)
+
+	// conflicts of other targets are ignored
+	others, err := ListConflicts(backend, "other")
+	require.NoError(t, err)
+	require.Empty(t, others)
+
+	// resolving the conflict with a policy remove it from the list
+	require.NoError(t, RecordConflict(bug1, findOperation(t, bug1, titleOp), "fake", ConflictPolicyRemoteWins, "title changed"))
+
+	conflicts, err = b.Conflicts()
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	require.Equal(t, commentOp.Id(), conflicts[0].OperationId)
+}
+
+func TestPreviousCommentMessage(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	rene, err := backend.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	b, createOp, err := backend.Bugs().New("title", "first")
+	require.NoError(t, err)
+	commentId, _, err := b.AddComment("comment")
+	require.NoError(t, err)
+	_, edit1, err := b.EditCreateComment("second")
+	require.NoError(t, err)
+	edit2, err := b.EditComment(commentId, "comment edited")
+	require.NoError(t, err)
+	_, edit3, err := b.EditCreateComment("third")
+	require.NoError(t, err)
+
+	snap := b.Snapshot()
+	require.Equal(t, createOp.Message, PreviousCommentMessage(snap, edit1))
+	require.Equal(t, "comment", PreviousCommentMessage(snap, edit2))
+	require.Equal(t, "second", PreviousCommentMessage(snap, edit3))
+}
+
+func TestRemoteChanged(t *testing.T) {
+	// unchanged, or already equal to the local value
+	require.False(t, RemoteChanged("old", "old", "new"))
+	require.False(t, RemoteChanged("new", "old", "new"))
+	require.False(t, RemoteChanged("old\r\n", "old", "new"))
+
+	require.True(t, RemoteChanged("other", "old", "new"))
+}
+
+// findOperation return the operation as found in the snapshot, with the
+// metadata added later on
+func findOperation(t *testing.T, b *cache.BugCache, target dag.Operation) dag.Operation {
+	for _, op := range b.Snapshot().Operations {
+		if op.Id() == target.Id() {
+			return op
+		}
+	}
+	t.Fatalf("operation %s not found", target.Id())
+	return nil
+}
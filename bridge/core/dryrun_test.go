@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBridgeDryRun(t *testing.T) {
+	Register(&fakeBridge{})
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	b, err := NewBridge(backend, "fake", "test")
+	require.NoError(t, err)
+	require.NoError(t, b.Configure(BridgeParams{}, false))
+
+	rene, err := backend.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	_, _, err = backend.Bugs().New("existing", "message")
+	require.NoError(t, err)
+
+	dry, err := b.DryRun()
+	require.NoError(t, err)
+	defer dry.Close()
+
+	id := entity.Id("1234567890123456789012345678901234567890123456789012345678901234")
+	fakeImportEvents = []ImportResult{NewImportBug(id)}
+
+	events, err := dry.ImportAll(context.Background())
+	require.NoError(t, err)
+	for event := range events {
+		require.True(t, event.Planned)
+		require.Equal(t, ImportEventBug, event.Event)
+	}
+
+	// the sandbox sees the existing bugs, but what is written there stays there
+	require.Len(t, dry.repo.Bugs().AllIds(), 1)
+	_, _, err = dry.repo.Bugs().New("planned", "message")
+	require.NoError(t, err)
+	require.Len(t, dry.repo.Bugs().AllIds(), 2)
+	require.Len(t, backend.Bugs().AllIds(), 1)
+
+	_, ok := b.LastImportTime()
+	require.False(t, ok)
+
+	// the fake exporter can't guarantee to not touch the remote
+	_, err = dry.ExportAll(context.Background(), time.Time{})
+	require.ErrorIs(t, err, ErrDryRunNotSupported)
+}
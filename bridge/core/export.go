@@ -24,6 +24,10 @@ const (
 	// Bug's labels have been changed on the remote tracker
 	ExportEventLabelChange
 
+	// A local change conflicts with a change made on the remote tracker since
+	// the last synchronization
+	ExportEventConflict
+
 	// Nothing changed on the bug
 	ExportEventNothing
 
@@ -46,6 +50,9 @@ type ExportResult struct {
 	Event    ExportEvent
 	EntityId entity.Id // optional for err, warning
 	Reason   string
+
+	// Planned is true for the events of a dry-run, where nothing actually changed
+	Planned bool
 }
 
 func (er ExportResult) String() string {
@@ -62,6 +69,8 @@ func (er ExportResult) String() string {
 		return fmt.Sprintf("[%s] changed title", er.EntityId.Human())
 	case ExportEventLabelChange:
 		return fmt.Sprintf("[%s] changed label", er.EntityId.Human())
+	case ExportEventConflict:
+		return fmt.Sprintf("[%s] conflict: %s", er.EntityId.Human(), er.Reason)
 	case ExportEventNothing:
 		if er.EntityId != "" {
 			return fmt.Sprintf("no actions taken on entity %s: %s", er.EntityId, er.Reason)
@@ -151,6 +160,14 @@ func NewExportTitleEdition(entityId entity.Id) ExportResult {
 	}
 }
 
+func NewExportConflict(entityId entity.Id, reason string) ExportResult {
+	return ExportResult{
+		EntityId: entityId,
+		Reason:   reason,
+		Event:    ExportEventConflict,
+	}
+}
+
 func NewExportRateLimiting(msg string) ExportResult {
 	return ExportResult{
 		Reason: msg,
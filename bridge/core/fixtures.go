@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/MichaelMure/git-bug/bridge/core/httpfixture"
+)
+
+const (
+	// ConfigKeyHTTPFixtures, if set to "record:DIR", records the HTTP
+	// interactions of the bridge in DIR. If set to "replay:DIR", the bridge
+	// doesn't access the network and replays those interactions instead.
+	ConfigKeyHTTPFixtures = "http-fixtures"
+
+	// EnvHTTPFixtures is the environment variable equivalent of
+	// ConfigKeyHTTPFixtures, and takes precedence over it.
+	EnvHTTPFixtures = "GIT_BUG_HTTP_FIXTURES"
+)
+
+// NewHTTPFixturesTransport return the recording or replaying transport
+// configured for a bridge, or nil if the bridge use the network normally.
+func NewHTTPFixturesTransport(conf Configuration) (http.RoundTripper, error) {
+	spec := os.Getenv(EnvHTTPFixtures)
+	if spec == "" {
+		spec = conf[ConfigKeyHTTPFixtures]
+	}
+	if spec == "" {
+		return nil, nil
+	}
+
+	mode, dir, err := httpfixture.ParseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpfixture.NewTransport(mode, dir, http.DefaultTransport), nil
+}
+
+type httpTransportKey struct{}
+
+// WithHTTPTransport return a context making the importers and exporters
+// initialized or run with it use the given transport for their HTTP requests.
+// A nil transport is ignored.
+func WithHTTPTransport(ctx context.Context, transport http.RoundTripper) context.Context {
+	if transport == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, httpTransportKey{}, transport)
+}
+
+// HTTPTransport return the transport the importers and exporters must use for
+// their HTTP requests: the one set with WithHTTPTransport, or
+// http.DefaultTransport.
+func HTTPTransport(ctx context.Context) http.RoundTripper {
+	if transport, ok := ctx.Value(httpTransportKey{}).(http.RoundTripper); ok {
+		return transport
+	}
+	return http.DefaultTransport
+}
+
+// TestHTTPTransport return the HTTP transport of a bridge test: the one
+// configured with EnvHTTPFixtures if any, or else a replay of the fixtures
+// committed in dir if they exist, or nil to use the network. replay is true if
+// the test doesn't access the network, and so doesn't need real credentials.
+func TestHTTPTransport(dir string) (transport http.RoundTripper, replay bool, err error) {
+	spec := os.Getenv(EnvHTTPFixtures)
+	if spec == "" {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, false, nil
+		}
+		spec = httpfixture.Replay.String() + ":" + dir
+	}
+
+	mode, fixturesDir, err := httpfixture.ParseSpec(spec)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return httpfixture.NewTransport(mode, fixturesDir, http.DefaultTransport), mode == httpfixture.Replay, nil
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core/httpfixture"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBridgeHTTPFixtures(t *testing.T) {
+	Register(&fakeBridge{})
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	for _, name := range []string{"replayed", "normal"} {
+		b, err := NewBridge(backend, "fake", name)
+		require.NoError(t, err)
+		require.NoError(t, b.Configure(BridgeParams{}, false))
+	}
+	err = backend.LocalConfig().StoreString("git-bug.bridge.replayed."+ConfigKeyHTTPFixtures, "replay:"+t.TempDir())
+	require.NoError(t, err)
+
+	fakeImportEvents = nil
+
+	// the fixtures transport is given to the bridge configured with it only,
+	// without changing the process-wide transport
+	replayed, err := LoadBridge(backend, "replayed")
+	require.NoError(t, err)
+	events, err := replayed.ImportAll(context.Background())
+	require.NoError(t, err)
+	for range events {
+	}
+	require.IsType(t, &httpfixture.Transport{}, fakeImportTransport)
+
+	normal, err := LoadBridge(backend, "normal")
+	require.NoError(t, err)
+	events, err = normal.ImportAll(context.Background())
+	require.NoError(t, err)
+	for range events {
+	}
+	require.Equal(t, http.DefaultTransport, fakeImportTransport)
+}
@@ -0,0 +1,268 @@
+// Package httpfixture provide an http.RoundTripper able to record the HTTP
+// interactions of a bridge into a fixture directory, and to replay them later
+// without network access.
+package httpfixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNoFixture = errors.New("no recorded fixture for this request")
+
+type Mode int
+
+const (
+	_ Mode = iota
+	// Record send the requests to the network and store the interactions
+	Record
+	// Replay answer the requests with the stored interactions only
+	Replay
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Record:
+		return "record"
+	case Replay:
+		return "replay"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSpec parse a fixture specification of the form "record:DIR" or
+// "replay:DIR".
+func ParseSpec(spec string) (Mode, string, error) {
+	mode, dir, found := strings.Cut(spec, ":")
+	if !found || dir == "" {
+		return 0, "", fmt.Errorf("invalid HTTP fixtures \"%s\", expected record:DIR or replay:DIR", spec)
+	}
+
+	switch mode {
+	case Record.String():
+		return Record, dir, nil
+	case Replay.String():
+		return Replay, dir, nil
+	default:
+		return 0, "", fmt.Errorf("invalid HTTP fixtures mode \"%s\", expected record or replay", mode)
+	}
+}
+
+// interaction is what is stored on disk for a request
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+// recordedRequest is kept for the humans reading the fixtures, the requests
+// are matched with the key in the file name. The headers, query and body are
+// deliberately left out as they can hold credentials.
+type recordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type recordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	// Body holds a textual body, BinaryBody anything else
+	Body       string `json:"body,omitempty"`
+	BinaryBody []byte `json:"binary_body,omitempty"`
+}
+
+var _ http.RoundTripper = &Transport{}
+
+// Transport record or replay the HTTP interactions in a directory, one file
+// per request. Requests are matched on their method, URL and body. When the
+// same request is sent multiple times, the answers are replayed in the same
+// order, the last one being repeated if needed.
+type Transport struct {
+	mode Mode
+	dir  string
+	base http.RoundTripper
+
+	mu          sync.Mutex
+	occurrences map[string]int
+}
+
+// NewTransport create a Transport storing the fixtures in dir. In Record
+// mode, the requests are sent with base, or http.DefaultTransport if nil.
+func NewTransport(mode Mode, dir string, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		mode:        mode,
+		dir:         dir,
+		base:        base,
+		occurrences: make(map[string]int),
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key := requestKey(req, body)
+
+	t.mu.Lock()
+	occurrence := t.occurrences[key]
+	t.occurrences[key]++
+	t.mu.Unlock()
+
+	switch t.mode {
+	case Record:
+		return t.record(req, body, key, occurrence)
+	case Replay:
+		return t.replay(req, key, occurrence)
+	default:
+		return nil, fmt.Errorf("unknown HTTP fixtures mode")
+	}
+}
+
+func (t *Transport) record(req *http.Request, body []byte, key string, occurrence int) (*http.Response, error) {
+	// RoundTrippers must not modify the request
+	outReq := req.Clone(req.Context())
+	if req.Body != nil {
+		outReq.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := t.base.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	it := interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+		},
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+		},
+	}
+	if utf8.Valid(respBody) {
+		it.Response.Body = string(respBody)
+	} else {
+		it.Response.BinaryBody = respBody
+	}
+
+	data, err := json.MarshalIndent(it, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(t.dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(t.fixturePath(key, occurrence), data, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "writing HTTP fixture")
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func (t *Transport) replay(req *http.Request, key string, occurrence int) (*http.Response, error) {
+	var data []byte
+	var err error
+
+	// fallback to the last recorded answer for requests sent more often
+	// than during the recording
+	for i := occurrence; i >= 0; i-- {
+		data, err = os.ReadFile(t.fixturePath(key, i))
+		if err == nil || !os.IsNotExist(err) {
+			break
+		}
+	}
+	if os.IsNotExist(err) {
+		return nil, errors.Wrapf(ErrNoFixture, "%s %s", req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var it interaction
+	err = json.Unmarshal(data, &it)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading HTTP fixture")
+	}
+
+	respBody := it.Response.BinaryBody
+	if respBody == nil {
+		respBody = []byte(it.Response.Body)
+	}
+
+	header := it.Response.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", it.Response.StatusCode, http.StatusText(it.Response.StatusCode)),
+		StatusCode:    it.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+func (t *Transport) fixturePath(key string, occurrence int) string {
+	return filepath.Join(t.dir, fmt.Sprintf("%s-%d.json", key, occurrence))
+}
+
+// redactURL return the URL without its user info and query
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	redacted.RawQuery = ""
+	redacted.ForceQuery = false
+	redacted.Fragment = ""
+	return redacted.String()
+}
+
+// requestKey identify a request by its method, URL and body
+func requestKey(req *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s %s\n", req.Method, req.URL.String())
+	_, _ = h.Write(body)
+	return strings.ToLower(req.Method) + "-" + hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,113 @@
+package httpfixture
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpec(t *testing.T) {
+	mode, dir, err := ParseSpec("record:/tmp/fixtures")
+	require.NoError(t, err)
+	require.Equal(t, Record, mode)
+	require.Equal(t, "/tmp/fixtures", dir)
+
+	mode, dir, err = ParseSpec("replay:testdata")
+	require.NoError(t, err)
+	require.Equal(t, Replay, mode)
+	require.Equal(t, "testdata", dir)
+
+	_, _, err = ParseSpec("replay:")
+	require.Error(t, err)
+	_, _, err = ParseSpec("rewind:testdata")
+	require.Error(t, err)
+}
+
+func TestRecordReplay(t *testing.T) {
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		count++
+		switch r.URL.Path {
+		case "/binary":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte{0xff, 0xfe, 0x00})
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = fmt.Fprintf(w, "%s %s %d", r.Method, body, count)
+		}
+	}))
+
+	dir := t.TempDir()
+
+	do := func(client *http.Client, method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	recorder := &http.Client{Transport: NewTransport(Record, dir, nil)}
+
+	status, body := do(recorder, "POST", "/query", "first")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "POST first 1", body)
+	_, body = do(recorder, "POST", "/query", "second")
+	require.Equal(t, "POST second 2", body)
+	_, body = do(recorder, "POST", "/query", "first")
+	require.Equal(t, "POST first 3", body)
+	_, body = do(recorder, "GET", "/binary", "")
+	require.Equal(t, string([]byte{0xff, 0xfe, 0x00}), body)
+	status, _ = do(recorder, "GET", "/missing", "")
+	require.Equal(t, http.StatusNotFound, status)
+
+	// the credentials in the URL and the body are not written to disk
+	status, _ = do(recorder, "POST", "/missing?token=secret-token", "password=secret-password")
+	require.Equal(t, http.StatusNotFound, status)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "secret-")
+	}
+
+	// no more network from here
+	server.Close()
+
+	replayer := &http.Client{Transport: NewTransport(Replay, dir, nil)}
+
+	_, body = do(replayer, "POST", "/query", "second")
+	require.Equal(t, "POST second 2", body)
+	_, body = do(replayer, "POST", "/query", "first")
+	require.Equal(t, "POST first 1", body)
+	_, body = do(replayer, "POST", "/query", "first")
+	require.Equal(t, "POST first 3", body)
+	// the last answer is repeated
+	_, body = do(replayer, "POST", "/query", "first")
+	require.Equal(t, "POST first 3", body)
+	_, body = do(replayer, "GET", "/binary", "")
+	require.Equal(t, string([]byte{0xff, 0xfe, 0x00}), body)
+	status, _ = do(replayer, "GET", "/missing", "")
+	require.Equal(t, http.StatusNotFound, status)
+	status, _ = do(replayer, "POST", "/missing?token=secret-token", "password=secret-password")
+	require.Equal(t, http.StatusNotFound, status)
+
+	req, err := http.NewRequest("GET", server.URL+"/unknown", nil)
+	require.NoError(t, err)
+	_, err = replayer.Do(req)
+	require.ErrorIs(t, err, ErrNoFixture)
+}
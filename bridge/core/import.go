@@ -39,6 +39,9 @@ const (
 
 	// Error happened during import
 	ImportEventError
+
+	// Pull request has been created
+	ImportEventPullRequest
 )
 
 // ImportResult is an event that is emitted during the import process, to
@@ -51,6 +54,9 @@ type ImportResult struct {
 	OperationId entity.Id         // optional
 	ComponentId entity.CombinedId // optional
 	Reason      string
+
+	// Planned is true for the events of a dry-run, where nothing actually changed
+	Planned bool
 }
 
 func (er ImportResult) String() string {
@@ -67,6 +73,8 @@ func (er ImportResult) String() string {
 		return fmt.Sprintf("[%s] changed title with op: %s", er.EntityId.Human(), er.OperationId)
 	case ImportEventLabelChange:
 		return fmt.Sprintf("[%s] changed label with op: %s", er.EntityId.Human(), er.OperationId)
+	case ImportEventPullRequest:
+		return fmt.Sprintf("[%s] new pull request: %s", er.EntityId.Human(), er.EntityId)
 	case ImportEventIdentity:
 		return fmt.Sprintf("[%s] new identity: %s", er.EntityId.Human(), er.EntityId)
 	case ImportEventNothing:
@@ -171,6 +179,13 @@ func NewImportTitleEdition(entityId entity.Id, opId entity.Id) ImportResult {
 	}
 }
 
+func NewImportPullRequest(entityId entity.Id) ImportResult {
+	return ImportResult{
+		EntityId: entityId,
+		Event:    ImportEventPullRequest,
+	}
+}
+
 func NewImportIdentity(entityId entity.Id) ImportResult {
 	return ImportResult{
 		EntityId: entityId,
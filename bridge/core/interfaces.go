@@ -44,3 +44,12 @@ type Exporter interface {
 	Init(ctx context.Context, repo *cache.RepoCache, conf Configuration) error
 	ExportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan ExportResult, error)
 }
+
+// DryRunExporter is implemented by the exporters able to go through an export
+// without changing anything on the remote tracker.
+type DryRunExporter interface {
+	// SetDryRun enable the dry-run mode, where the mutating endpoints are not
+	// called and placeholders are used for the IDs of the entities that would
+	// have been created.
+	SetDryRun(dryRun bool)
+}
@@ -0,0 +1,376 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+)
+
+const (
+	// ConfigKeyLabelMapping holds the label mapping, as a JSON object
+	ConfigKeyLabelMapping = "label-mapping"
+	// ConfigKeyStatusMapping holds the status mapping, as a JSON object
+	ConfigKeyStatusMapping = "status-mapping"
+)
+
+// Mapping describes how labels and statuses are translated when they cross a
+// bridge. It is stored in the bridge Configuration and applied the same way
+// by all importers and exporters.
+type Mapping struct {
+	// Labels map a remote label to a git-bug label. An empty value drops the
+	// label entirely. A key ending with "*" matches all the labels with that
+	// prefix, and a "*" in the value is then replaced with the rest of the
+	// label, for example "priority::*" => "priority:*".
+	Labels map[string]string
+
+	// Statuses map a remote state (or the state a transition leads to) to a
+	// git-bug status, with optionally some labels to add along.
+	Statuses map[string]StatusMapping
+}
+
+// StatusMapping is the git-bug side of a remote state
+type StatusMapping struct {
+	Status common.Status
+	Labels []string
+}
+
+type statusMappingJSON struct {
+	Status string   `json:"status"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+func (sm StatusMapping) MarshalJSON() ([]byte, error) {
+	return json.Marshal(statusMappingJSON{
+		Status: sm.Status.String(),
+		Labels: sm.Labels,
+	})
+}
+
+func (sm *StatusMapping) UnmarshalJSON(data []byte) error {
+	var raw statusMappingJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	status, err := common.StatusFromString(raw.Status)
+	if err != nil {
+		return fmt.Errorf("invalid status \"%s\"", raw.Status)
+	}
+
+	sm.Status = status
+	sm.Labels = raw.Labels
+	return nil
+}
+
+// LoadMapping read the label and status mapping from a bridge configuration.
+// A configuration without mapping gives an empty mapping, where everything is
+// kept as is.
+func LoadMapping(conf Configuration) (*Mapping, error) {
+	m := &Mapping{
+		Labels:   make(map[string]string),
+		Statuses: make(map[string]StatusMapping),
+	}
+
+	if raw, ok := conf[ConfigKeyLabelMapping]; ok && raw != "" {
+		if err := json.Unmarshal([]byte(raw), &m.Labels); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", ConfigKeyLabelMapping, err)
+		}
+	}
+
+	if raw, ok := conf[ConfigKeyStatusMapping]; ok && raw != "" {
+		if err := json.Unmarshal([]byte(raw), &m.Statuses); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", ConfigKeyStatusMapping, err)
+		}
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Validate check that the mapping is consistent
+func (m *Mapping) Validate() error {
+	for remote, local := range m.Labels {
+		if remote == "" {
+			return fmt.Errorf("empty remote label in the label mapping")
+		}
+		if strings.Contains(strings.TrimSuffix(remote, "*"), "*") {
+			return fmt.Errorf("label \"%s\": the wildcard is only allowed at the end", remote)
+		}
+		if strings.Count(local, "*") > 1 ||
+			(strings.Contains(local, "*") && !strings.HasSuffix(remote, "*")) {
+			return fmt.Errorf("label \"%s\": invalid wildcard in \"%s\"", remote, local)
+		}
+	}
+
+	for remote, sm := range m.Statuses {
+		if remote == "" {
+			return fmt.Errorf("empty remote state in the status mapping")
+		}
+		if err := sm.Status.Validate(); err != nil {
+			return fmt.Errorf("state \"%s\": invalid status", remote)
+		}
+		for _, label := range sm.Labels {
+			if err := bug.Label(label).Validate(); err != nil {
+				return fmt.Errorf("state \"%s\": invalid label \"%s\": %v", remote, label, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Store write the mapping into a bridge configuration. Empty parts are
+// removed from the configuration.
+func (m *Mapping) Store(conf Configuration) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	if len(m.Labels) == 0 {
+		delete(conf, ConfigKeyLabelMapping)
+	} else {
+		raw, err := json.Marshal(m.Labels)
+		if err != nil {
+			return err
+		}
+		conf[ConfigKeyLabelMapping] = string(raw)
+	}
+
+	if len(m.Statuses) == 0 {
+		delete(conf, ConfigKeyStatusMapping)
+	} else {
+		raw, err := json.Marshal(m.Statuses)
+		if err != nil {
+			return err
+		}
+		conf[ConfigKeyStatusMapping] = string(raw)
+	}
+
+	return nil
+}
+
+// ImportLabel translate a remote label into a git-bug label. It returns false
+// if the label is dropped.
+func (m *Mapping) ImportLabel(remote string) (string, bool) {
+	if local, ok := m.Labels[remote]; ok {
+		return local, local != ""
+	}
+
+	// the longest matching prefix wins
+	var best string
+	for key := range m.Labels {
+		prefix, ok := strings.CutSuffix(key, "*")
+		if ok && strings.HasPrefix(remote, prefix) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best == "" {
+		return remote, true
+	}
+
+	local := m.Labels[best]
+	if local == "" {
+		return "", false
+	}
+	rest := strings.TrimPrefix(remote, strings.TrimSuffix(best, "*"))
+	return strings.Replace(local, "*", rest, 1), true
+}
+
+// ImportLabels translate a set of remote labels, skipping the dropped ones
+func (m *Mapping) ImportLabels(remotes []string) []string {
+	result := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		if local, ok := m.ImportLabel(remote); ok {
+			result = append(result, local)
+		}
+	}
+	return result
+}
+
+// ExportLabel translate a git-bug label into a remote label. It returns false
+// if the label shouldn't be exported, which is the case for the labels added
+// by the status mapping as they are already conveyed by the remote state.
+func (m *Mapping) ExportLabel(local bug.Label) (string, bool) {
+	label := local.String()
+
+	for _, sm := range m.Statuses {
+		for _, l := range sm.Labels {
+			if l == label {
+				return "", false
+			}
+		}
+	}
+
+	// iterate in a stable order, as multiple remote labels can map to the
+	// same git-bug label
+	keys := make([]string, 0, len(m.Labels))
+	for key := range m.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !strings.HasSuffix(key, "*") && m.Labels[key] == label {
+			return key, true
+		}
+	}
+
+	for _, key := range keys {
+		value := m.Labels[key]
+		prefix, ok := strings.CutSuffix(key, "*")
+		if !ok {
+			continue
+		}
+		before, after, ok := strings.Cut(value, "*")
+		if !ok {
+			continue
+		}
+		if len(label) >= len(before)+len(after) &&
+			strings.HasPrefix(label, before) && strings.HasSuffix(label, after) {
+			return prefix + label[len(before):len(label)-len(after)], true
+		}
+	}
+
+	return label, true
+}
+
+// ExportLabels translate a set of git-bug labels, skipping the ones that
+// shouldn't be exported
+func (m *Mapping) ExportLabels(locals []bug.Label) []bug.Label {
+	result := make([]bug.Label, 0, len(locals))
+	for _, local := range locals {
+		if remote, ok := m.ExportLabel(local); ok {
+			result = append(result, bug.Label(remote))
+		}
+	}
+	return result
+}
+
+// ImportStatus return how a remote state translate in git-bug, if the
+// mapping defines it.
+func (m *Mapping) ImportStatus(remote string) (StatusMapping, bool) {
+	sm, ok := m.Statuses[remote]
+	return sm, ok
+}
+
+// ExportStatus return the remote state to use for a git-bug status, given the
+// labels of the bug. The remote state is chosen among the ones mapped to that
+// status, preferring the one with the most matching labels. It returns false
+// if the mapping doesn't define any remote state for that status.
+func (m *Mapping) ExportStatus(status common.Status, labels []bug.Label) (string, bool) {
+	has := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		has[label.String()] = true
+	}
+
+	keys := make([]string, 0, len(m.Statuses))
+	for key := range m.Statuses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var best string
+	bestScore := -1
+
+outer:
+	for _, key := range keys {
+		sm := m.Statuses[key]
+		if sm.Status != status {
+			continue
+		}
+		for _, label := range sm.Labels {
+			if !has[label] {
+				continue outer
+			}
+		}
+		if len(sm.Labels) > bestScore {
+			best = key
+			bestScore = len(sm.Labels)
+		}
+	}
+
+	return best, bestScore >= 0
+}
+
+// LabelsAt return the labels of a bug as of one of its operations, folding
+// the label changes that come before it. This is what the status set by that
+// operation must be exported with, rather than the current labels.
+func LabelsAt(snap *bug.Snapshot, target dag.Operation) []bug.Label {
+	var labels []bug.Label
+	for _, op := range snap.Operations {
+		if op.Id() == target.Id() {
+			break
+		}
+		change, ok := op.(*bug.LabelChangeOperation)
+		if !ok {
+			continue
+		}
+		for _, added := range change.Added {
+			if !slices.Contains(labels, added) {
+				labels = append(labels, added)
+			}
+		}
+		for _, removed := range change.Removed {
+			labels = slices.DeleteFunc(labels, func(label bug.Label) bool {
+				return label == removed
+			})
+		}
+	}
+	return labels
+}
+
+// ApplyStatus record a remote state change on a bug, translated with the
+// status mapping. If the remote state is not mapped, the fallback status is
+// used. When the mapping adds labels along, they are applied with a separate
+// label change operation carrying labelsMetadata, which is returned as well.
+func (m *Mapping) ApplyStatus(b *cache.BugCache, author identity.Interface, unixTime int64, remoteState string, fallback common.Status, metadata map[string]string, labelsMetadata map[string]string) (*bug.SetStatusOperation, *bug.LabelChangeOperation, error) {
+	status := fallback
+	var labels []string
+	if sm, ok := m.ImportStatus(remoteState); ok {
+		status = sm.Status
+		labels = sm.Labels
+	}
+
+	var op *bug.SetStatusOperation
+	var err error
+	switch status {
+	case common.OpenStatus:
+		op, err = b.OpenRaw(author, unixTime, metadata)
+	case common.ClosedStatus:
+		op, err = b.CloseRaw(author, unixTime, metadata)
+	default:
+		panic("unknown bug state")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(labels) == 0 {
+		return op, nil, nil
+	}
+
+	labelOp, err := b.ForceChangeLabelsRaw(author, unixTime, labels, nil, labelsMetadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return op, labelOp, nil
+}
+
+// StatusLabelsId derive the remote identifier given to the label change
+// operation created along an imported status change, when the status mapping
+// adds labels.
+func StatusLabelsId(id string) string {
+	return id + "-labels"
+}
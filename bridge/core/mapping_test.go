@@ -0,0 +1,166 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestMappingLabels(t *testing.T) {
+	m := &Mapping{
+		Labels: map[string]string{
+			"type::bug":   "bug",
+			"triage":      "",
+			"priority::*": "priority:*",
+			"wip*":        "",
+			"area::*":     "area",
+		},
+	}
+	require.NoError(t, m.Validate())
+
+	cases := []struct {
+		remote string
+		local  string
+		kept   bool
+	}{
+		{"type::bug", "bug", true},
+		{"triage", "", false},
+		{"priority::high", "priority:high", true},
+		{"wip-foo", "", false},
+		{"area::ui", "area", true},
+		{"unmapped", "unmapped", true},
+	}
+	for _, c := range cases {
+		local, ok := m.ImportLabel(c.remote)
+		require.Equal(t, c.kept, ok, c.remote)
+		require.Equal(t, c.local, local, c.remote)
+	}
+
+	require.Equal(t, []string{"bug", "priority:low", "other"},
+		m.ImportLabels([]string{"type::bug", "triage", "priority::low", "other"}))
+
+	remote, ok := m.ExportLabel("bug")
+	require.True(t, ok)
+	require.Equal(t, "type::bug", remote)
+
+	remote, ok = m.ExportLabel("priority:high")
+	require.True(t, ok)
+	require.Equal(t, "priority::high", remote)
+
+	// a wildcard without "*" in the value can't be reversed
+	remote, ok = m.ExportLabel("area")
+	require.True(t, ok)
+	require.Equal(t, "area", remote)
+}
+
+func TestMappingStatuses(t *testing.T) {
+	m := &Mapping{
+		Labels: map[string]string{},
+		Statuses: map[string]StatusMapping{
+			"Done":        {Status: common.ClosedStatus},
+			"Won't Do":    {Status: common.ClosedStatus, Labels: []string{"wontfix"}},
+			"In Progress": {Status: common.OpenStatus, Labels: []string{"in-progress"}},
+			"To Do":       {Status: common.OpenStatus},
+		},
+	}
+	require.NoError(t, m.Validate())
+
+	sm, ok := m.ImportStatus("Won't Do")
+	require.True(t, ok)
+	require.Equal(t, common.ClosedStatus, sm.Status)
+	require.Equal(t, []string{"wontfix"}, sm.Labels)
+
+	_, ok = m.ImportStatus("Unknown")
+	require.False(t, ok)
+
+	remote, ok := m.ExportStatus(common.ClosedStatus, nil)
+	require.True(t, ok)
+	require.Equal(t, "Done", remote)
+
+	remote, ok = m.ExportStatus(common.ClosedStatus, []bug.Label{"foo", "wontfix"})
+	require.True(t, ok)
+	require.Equal(t, "Won't Do", remote)
+
+	remote, ok = m.ExportStatus(common.OpenStatus, []bug.Label{"in-progress"})
+	require.True(t, ok)
+	require.Equal(t, "In Progress", remote)
+
+	_, ok = (&Mapping{}).ExportStatus(common.OpenStatus, nil)
+	require.False(t, ok)
+
+	// labels carried by the status are not exported as labels
+	require.Equal(t, []bug.Label{"foo"}, m.ExportLabels([]bug.Label{"foo", "wontfix"}))
+}
+
+func TestLabelsAt(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	rene, err := backend.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	b, createOp, err := backend.Bugs().New("title", "message")
+	require.NoError(t, err)
+	_, _, err = b.ChangeLabels([]string{"wontfix", "bug"}, nil)
+	require.NoError(t, err)
+	closeOp, err := b.Close()
+	require.NoError(t, err)
+	_, _, err = b.ChangeLabels(nil, []string{"wontfix"})
+	require.NoError(t, err)
+	reopenOp, err := b.Open()
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	snap := b.Snapshot()
+	require.Empty(t, LabelsAt(snap, findOperation(t, b, createOp)))
+	require.Equal(t, []bug.Label{"wontfix", "bug"}, LabelsAt(snap, findOperation(t, b, closeOp)))
+	require.Equal(t, []bug.Label{"bug"}, LabelsAt(snap, findOperation(t, b, reopenOp)))
+}
+
+func TestMappingStore(t *testing.T) {
+	conf := Configuration{"other": "value"}
+
+	m, err := LoadMapping(conf)
+	require.NoError(t, err)
+	require.Empty(t, m.Labels)
+	require.Empty(t, m.Statuses)
+
+	m.Labels["priority::*"] = "priority:*"
+	m.Statuses["Won't Do"] = StatusMapping{Status: common.ClosedStatus, Labels: []string{"wontfix"}}
+	require.NoError(t, m.Store(conf))
+	require.Contains(t, conf, ConfigKeyLabelMapping)
+	require.Contains(t, conf, ConfigKeyStatusMapping)
+
+	loaded, err := LoadMapping(conf)
+	require.NoError(t, err)
+	require.Equal(t, m, loaded)
+
+	// empty parts are removed from the configuration
+	loaded.Labels = map[string]string{}
+	require.NoError(t, loaded.Store(conf))
+	require.NotContains(t, conf, ConfigKeyLabelMapping)
+	require.Contains(t, conf, ConfigKeyStatusMapping)
+	require.Equal(t, "value", conf["other"])
+}
+
+func TestMappingInvalid(t *testing.T) {
+	for _, conf := range []Configuration{
+		{ConfigKeyLabelMapping: `not json`},
+		{ConfigKeyLabelMapping: `{"a*b": "c"}`},
+		{ConfigKeyLabelMapping: `{"a": "b*"}`},
+		{ConfigKeyLabelMapping: `{"a*": "b**"}`},
+		{ConfigKeyStatusMapping: `{"Done": {"status": "finished"}}`},
+		{ConfigKeyStatusMapping: `{"Done": {"status": "closed", "labels": [""]}}`},
+	} {
+		_, err := LoadMapping(conf)
+		require.Error(t, err, conf)
+	}
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SyncSummary aggregate what happened during a sync cycle of a bridge
+type SyncSummary struct {
+	Bridge string
+	Start  time.Time
+	End    time.Time
+
+	ImportedBugs       int
+	ImportedIdentities int
+	ImportedChanges    int
+	ExportedBugs       int
+	ExportedChanges    int
+
+	Conflicts int
+	Warnings  int
+	Errors    int
+
+	// RateLimited is true if the remote tracker throttled the bridge during
+	// the cycle, in which case the next cycle should be delayed.
+	RateLimited bool
+}
+
+func (s SyncSummary) String() string {
+	res := fmt.Sprintf("%s: imported %d issues, %d identities and %d changes, exported %d issues and %d changes",
+		s.Bridge, s.ImportedBugs, s.ImportedIdentities, s.ImportedChanges, s.ExportedBugs, s.ExportedChanges)
+	if s.Conflicts > 0 {
+		res += fmt.Sprintf(", %d conflicts", s.Conflicts)
+	}
+	if s.Warnings > 0 {
+		res += fmt.Sprintf(", %d warnings", s.Warnings)
+	}
+	if s.Errors > 0 {
+		res += fmt.Sprintf(", %d errors", s.Errors)
+	}
+	if s.RateLimited {
+		res += ", rate limited"
+	}
+	return fmt.Sprintf("%s (%s)", res, s.End.Sub(s.Start).Round(time.Second))
+}
+
+// Sync run an import, resuming from the last complete import, then an export
+// of the bridge. Events are passed to the optional callbacks as they happen.
+// Import or export not being supported by the bridge is not an error, that
+// half of the cycle is simply skipped.
+func (b *Bridge) Sync(ctx context.Context, onImport func(ImportResult), onExport func(ExportResult)) (SyncSummary, error) {
+	summary := SyncSummary{
+		Bridge: b.Name,
+		Start:  time.Now(),
+	}
+
+	importEvents, err := b.ImportAll(ctx)
+	switch {
+	case err == ErrImportNotSupported:
+		importEvents = closedChan[ImportResult]()
+	case err != nil:
+		summary.End = time.Now()
+		return summary, errors.Wrap(err, "import")
+	}
+
+	for result := range importEvents {
+		switch result.Event {
+		case ImportEventBug:
+			summary.ImportedBugs++
+		case ImportEventIdentity:
+			summary.ImportedIdentities++
+		case ImportEventComment, ImportEventCommentEdition, ImportEventStatusChange,
+			ImportEventTitleEdition, ImportEventLabelChange:
+			summary.ImportedChanges++
+		case ImportEventWarning:
+			summary.Warnings++
+		case ImportEventRateLimiting:
+			summary.RateLimited = true
+		case ImportEventError:
+			if result.Err != context.Canceled {
+				summary.Errors++
+			}
+		}
+		if onImport != nil {
+			onImport(result)
+		}
+	}
+
+	if ctx.Err() != nil {
+		summary.End = time.Now()
+		return summary, ctx.Err()
+	}
+
+	exportEvents, err := b.ExportAll(ctx, time.Time{})
+	switch {
+	case err == ErrExportNotSupported:
+		exportEvents = closedChan[ExportResult]()
+	case err != nil:
+		summary.End = time.Now()
+		return summary, errors.Wrap(err, "export")
+	}
+
+	for result := range exportEvents {
+		switch result.Event {
+		case ExportEventBug:
+			summary.ExportedBugs++
+		case ExportEventComment, ExportEventCommentEdition, ExportEventStatusChange,
+			ExportEventTitleEdition, ExportEventLabelChange:
+			summary.ExportedChanges++
+		case ExportEventConflict:
+			summary.Conflicts++
+		case ExportEventWarning:
+			summary.Warnings++
+		case ExportEventRateLimiting:
+			summary.RateLimited = true
+		case ExportEventError:
+			if result.Err != context.Canceled {
+				summary.Errors++
+			}
+		}
+		if onExport != nil {
+			onExport(result)
+		}
+	}
+
+	summary.End = time.Now()
+	return summary, ctx.Err()
+}
+
+func closedChan[T any]() <-chan T {
+	ch := make(chan T)
+	close(ch)
+	return ch
+}
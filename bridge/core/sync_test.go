@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// the bridges are instantiated by reflection, so the fake one is driven
+// through package variables
+var (
+	fakeImportEvents []ImportResult
+	fakeExportEvents []ExportResult
+	fakeImportSince  time.Time
+
+	// transport given to the importer at init
+	fakeImportTransport http.RoundTripper
+)
+
+type fakeBridge struct{}
+
+func (*fakeBridge) Target() string                      { return "fake" }
+func (*fakeBridge) NewImporter() Importer               { return &fakeImporter{} }
+func (*fakeBridge) NewExporter() Exporter               { return &fakeExporter{} }
+func (*fakeBridge) LoginMetaKey() string                { return "fake-login" }
+func (*fakeBridge) ValidateConfig(Configuration) error  { return nil }
+func (*fakeBridge) ValidParams() map[string]interface{} { return nil }
+func (*fakeBridge) Configure(*cache.RepoCache, BridgeParams, bool) (Configuration, error) {
+	return Configuration{ConfigKeyTarget: "fake"}, nil
+}
+
+type fakeImporter struct{}
+
+func (*fakeImporter) Init(ctx context.Context, _ *cache.RepoCache, _ Configuration) error {
+	fakeImportTransport = HTTPTransport(ctx)
+	return nil
+}
+func (*fakeImporter) ImportAll(_ context.Context, _ *cache.RepoCache, since time.Time) (<-chan ImportResult, error) {
+	fakeImportSince = since
+	out := make(chan ImportResult, len(fakeImportEvents))
+	for _, event := range fakeImportEvents {
+		out <- event
+	}
+	close(out)
+	return out, nil
+}
+
+type fakeExporter struct{}
+
+func (*fakeExporter) Init(context.Context, *cache.RepoCache, Configuration) error { return nil }
+func (*fakeExporter) ExportAll(context.Context, *cache.RepoCache, time.Time) (<-chan ExportResult, error) {
+	out := make(chan ExportResult, len(fakeExportEvents))
+	for _, event := range fakeExportEvents {
+		out <- event
+	}
+	close(out)
+	return out, nil
+}
+
+func TestBridgeSync(t *testing.T) {
+	Register(&fakeBridge{})
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	b, err := NewBridge(backend, "fake", "test")
+	require.NoError(t, err)
+	require.NoError(t, b.Configure(BridgeParams{}, false))
+
+	id := entity.Id("1234567890123456789012345678901234567890123456789012345678901234")
+
+	fakeImportEvents = []ImportResult{
+		NewImportBug(id),
+		NewImportIdentity(id),
+		NewImportTitleEdition(id, id),
+		NewImportRateLimiting("slow down"),
+		NewImportNothing(id, "nothing"),
+	}
+	fakeExportEvents = []ExportResult{
+		NewExportBug(id),
+		NewExportComment(id),
+		NewExportWarning(errors.New("warning"), id),
+	}
+
+	_, ok := b.LastImportTime()
+	require.False(t, ok)
+
+	summary, err := b.Sync(context.Background(), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "test", summary.Bridge)
+	require.Equal(t, 1, summary.ImportedBugs)
+	require.Equal(t, 1, summary.ImportedIdentities)
+	require.Equal(t, 1, summary.ImportedChanges)
+	require.Equal(t, 1, summary.ExportedBugs)
+	require.Equal(t, 1, summary.ExportedChanges)
+	require.Equal(t, 1, summary.Warnings)
+	require.Equal(t, 0, summary.Errors)
+	require.True(t, summary.RateLimited)
+	require.True(t, fakeImportSince.IsZero())
+
+	// the cursor is persisted, the next import resume from there
+	lastImport, ok := b.LastImportTime()
+	require.True(t, ok)
+
+	fakeImportEvents = []ImportResult{NewImportError(errors.New("failure"), "")}
+	fakeExportEvents = nil
+
+	summary, err = b.Sync(context.Background(), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.Errors)
+	require.Equal(t, lastImport, fakeImportSince)
+
+	// a failed import doesn't move the cursor
+	stillLastImport, ok := b.LastImportTime()
+	require.True(t, ok)
+	require.Equal(t, lastImport, stillLastImport)
+
+	// neither does an interrupted one
+	fakeImportEvents = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = b.Sync(ctx, nil, nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	stillLastImport, ok = b.LastImportTime()
+	require.True(t, ok)
+	require.Equal(t, lastImport, stillLastImport)
+}
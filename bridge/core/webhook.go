@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+// ConfigKeyWebhookSecret holds the secret shared with the remote tracker to
+// authenticate the webhook requests.
+const ConfigKeyWebhookSecret = "webhook-secret"
+
+var ErrWebhookNotSupported = errors.New("webhook is not supported")
+var ErrWebhookNotConfigured = errors.New("no webhook secret configured")
+var ErrWebhookSignature = errors.New("invalid webhook signature")
+
+// WebhookImporter is implemented by the importers able to import a single
+// issue when notified by the remote tracker, instead of polling for every
+// change.
+type WebhookImporter interface {
+	// ImportWebhook authenticate the webhook request with the given secret,
+	// then import the issue it refers to. Webhook events that don't concern
+	// an issue are acknowledged without importing anything.
+	ImportWebhook(ctx context.Context, repo *cache.RepoCache, secret string, header http.Header, payload []byte) (<-chan ImportResult, error)
+}
+
+// ImportWebhook import the issue referred by a webhook request sent by the
+// remote tracker. It returns ErrWebhookSignature if the request can't be
+// authenticated.
+func (b *Bridge) ImportWebhook(ctx context.Context, header http.Header, payload []byte) (<-chan ImportResult, error) {
+	importer, ok := b.getImporter().(WebhookImporter)
+	if !ok {
+		return nil, ErrWebhookNotSupported
+	}
+
+	err := b.ensureConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	secret := b.conf[ConfigKeyWebhookSecret]
+	if secret == "" {
+		return nil, ErrWebhookNotConfigured
+	}
+
+	ctx, err = b.httpContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.ensureImportInit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return importer.ImportWebhook(ctx, b.repo, secret, header, payload)
+}
+
+// SetWebhookSecret store the secret used to authenticate the webhook
+// requests. An empty secret disable the webhook.
+func (b *Bridge) SetWebhookSecret(secret string) error {
+	err := b.ensureConfig()
+	if err != nil {
+		return err
+	}
+
+	if secret == "" {
+		if _, ok := b.conf[ConfigKeyWebhookSecret]; !ok {
+			return nil
+		}
+		delete(b.conf, ConfigKeyWebhookSecret)
+		storeKey := fmt.Sprintf("git-bug.bridge.%s.%s", b.Name, ConfigKeyWebhookSecret)
+		err := b.repo.LocalConfig().RemoveAll(storeKey)
+		if err != nil {
+			return errors.Wrap(err, "error while removing bridge configuration")
+		}
+		return nil
+	}
+
+	b.conf[ConfigKeyWebhookSecret] = secret
+	return b.storeConfig(b.conf)
+}
+
+// NewWebhookIgnored return a closed channel holding a single
+// ImportEventNothing, for the webhook requests that don't require importing
+// anything.
+func NewWebhookIgnored(reason string) <-chan ImportResult {
+	out := make(chan ImportResult, 1)
+	out <- NewImportNothing("", reason)
+	close(out)
+	return out
+}
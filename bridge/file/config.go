@@ -0,0 +1,74 @@
+package file
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/input"
+)
+
+func (File) ValidParams() map[string]interface{} {
+	return map[string]interface{}{
+		"URL": nil,
+	}
+}
+
+func (f File) Configure(repo *cache.RepoCache, params core.BridgeParams, interactive bool) (core.Configuration, error) {
+	var err error
+	var path string
+
+	switch {
+	case params.URL != "":
+		path = strings.TrimPrefix(params.URL, "file://")
+	default:
+		if !interactive {
+			return nil, fmt.Errorf("Non-interactive-mode is active. Please specify the path of the file with the --url option.")
+		}
+		path, err = input.Prompt("Path of the JSON Lines (.jsonl) or CSV (.csv) file", "path", input.Required)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the bridge can be used from anywhere in the repository
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := formatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := make(core.Configuration)
+	conf[core.ConfigKeyTarget] = target
+	conf[confKeyPath] = path
+	conf[confKeyFormat] = string(format)
+
+	err = f.ValidateConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+func (File) ValidateConfig(conf core.Configuration) error {
+	if v, ok := conf[core.ConfigKeyTarget]; !ok {
+		return fmt.Errorf("missing %s key", core.ConfigKeyTarget)
+	} else if v != target {
+		return fmt.Errorf("unexpected target name: %v", v)
+	}
+	if _, ok := conf[confKeyPath]; !ok {
+		return fmt.Errorf("missing %s key", confKeyPath)
+	}
+	if _, err := formatFromString(conf[confKeyFormat]); err != nil {
+		return err
+	}
+
+	return nil
+}
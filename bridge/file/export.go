@@ -0,0 +1,224 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+var _ core.DryRunExporter = &fileExporter{}
+
+// fileExporter implement the Exporter interface
+type fileExporter struct {
+	conf core.Configuration
+
+	// if true, the file is not written
+	dryRun bool
+}
+
+// Init .
+func (fe *fileExporter) Init(_ context.Context, _ *cache.RepoCache, conf core.Configuration) error {
+	fe.conf = conf
+	return nil
+}
+
+// SetDryRun .
+func (fe *fileExporter) SetDryRun(dryRun bool) {
+	fe.dryRun = dryRun
+}
+
+// ExportAll write all the identities and bugs of the repository in the file,
+// replacing its content. As the file is always written as a whole, the since
+// date is ignored.
+func (fe *fileExporter) ExportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ExportResult, error) {
+	f, err := formatFromString(fe.conf[confKeyFormat])
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan core.ExportResult)
+
+	go func() {
+		defer close(out)
+
+		identityRefs := make(map[entity.Id]string)
+		var records []record
+
+		var identities []*cache.IdentityCache
+		for _, id := range repo.Identities().AllIds() {
+			i, err := repo.Identities().Resolve(id)
+			if err != nil {
+				out <- core.NewExportError(err, id)
+				return
+			}
+			identities = append(identities, i)
+		}
+
+		// by name, for a stable output
+		sort.Slice(identities, func(i, j int) bool {
+			if identities[i].Name() != identities[j].Name() {
+				return identities[i].Name() < identities[j].Name()
+			}
+			return identities[i].Id() < identities[j].Id()
+		})
+
+		for _, i := range identities {
+			ref := recordId(i.ImmutableMetadata(), i.Id())
+			identityRefs[i.Id()] = ref
+			records = append(records, record{
+				Type:  recordIdentity,
+				Id:    ref,
+				Name:  i.Name(),
+				Email: i.Email(),
+				Login: i.Login(),
+			})
+		}
+
+		var bugs []*cache.BugCache
+		for _, id := range repo.Bugs().AllIds() {
+			b, err := repo.Bugs().Resolve(id)
+			if err != nil {
+				out <- core.NewExportError(err, id)
+				return
+			}
+			bugs = append(bugs, b)
+		}
+
+		// oldest first, for a stable output
+		sort.Slice(bugs, func(i, j int) bool {
+			ti, tj := bugs[i].Snapshot().CreateTime, bugs[j].Snapshot().CreateTime
+			if !ti.Equal(tj) {
+				return ti.Before(tj)
+			}
+			return bugs[i].Id() < bugs[j].Id()
+		})
+
+		for _, b := range bugs {
+			select {
+			case <-ctx.Done():
+				out <- core.NewExportError(ctx.Err(), "")
+				return
+			default:
+			}
+
+			records = append(records, bugRecords(b.Snapshot(), identityRefs)...)
+			out <- core.NewExportBug(b.Id())
+		}
+
+		if fe.dryRun {
+			return
+		}
+
+		if err := fe.write(f, records); err != nil {
+			out <- core.NewExportError(err, "")
+		}
+	}()
+
+	return out, nil
+}
+
+// write replace the content of the file, without leaving a partially written
+// file in case of error
+func (fe *fileExporter) write(f format, records []record) error {
+	var buf bytes.Buffer
+	if err := writeRecords(&buf, f, records); err != nil {
+		return err
+	}
+
+	path := fe.conf[confKeyPath]
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// bugRecords convert the operations of a bug into records
+func bugRecords(snapshot *bug.Snapshot, identityRefs map[entity.Id]string) []record {
+	opRefs := make(map[entity.Id]string, len(snapshot.Operations))
+	bugRef := ""
+
+	records := make([]record, 0, len(snapshot.Operations))
+	for _, op := range snapshot.Operations {
+		ref := op.Id().String()
+		if value, ok := op.GetMetadata(metaKeyFileId); ok {
+			ref = value
+		}
+		opRefs[op.Id()] = ref
+
+		rec := record{
+			Id:     ref,
+			Bug:    bugRef,
+			Author: identityRefs[op.Author().Id()],
+			Time:   op.Time().UTC().Format(time.RFC3339),
+		}
+
+		switch op := op.(type) {
+		case *bug.CreateOperation:
+			bugRef = ref
+			rec.Type = recordBug
+			rec.Bug = ""
+			rec.Title = op.Title
+			rec.Message = op.Message
+		case *bug.AddCommentOperation:
+			rec.Type = recordComment
+			rec.Message = op.Message
+		case *bug.EditCommentOperation:
+			rec.Type = recordEdit
+			rec.Target = opRefs[op.Target]
+			rec.Message = op.Message
+		case *bug.LabelChangeOperation:
+			rec.Type = recordLabel
+			rec.Labels = labelsToStrings(op.Added)
+			rec.Removed = labelsToStrings(op.Removed)
+		case *bug.SetStatusOperation:
+			rec.Type = recordStatus
+			rec.Status = op.Status.String()
+		case *bug.SetTitleOperation:
+			rec.Type = recordTitle
+			rec.Title = op.Title
+		default:
+			// metadata changes and no-op don't have a representation
+			continue
+		}
+
+		records = append(records, rec)
+	}
+
+	return records
+}
+
+// recordId return the id of the record an entity was imported from, or its
+// own id otherwise
+func recordId(metadata map[string]string, id entity.Id) string {
+	if value, ok := metadata[metaKeyFileId]; ok && value != "" {
+		return value
+	}
+	return id.String()
+}
+
+func labelsToStrings(labels []bug.Label) []string {
+	result := make([]string, 0, len(labels))
+	for _, label := range labels {
+		result = append(result, string(label))
+	}
+	return result
+}
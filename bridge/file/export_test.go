@@ -0,0 +1,130 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestFileExportRoundTrip(t *testing.T) {
+	for _, f := range []format{formatJSONL, formatCSV} {
+		t.Run(string(f), func(t *testing.T) {
+			testRoundTrip(t, f)
+		})
+	}
+}
+
+func testRoundTrip(t *testing.T, f format) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	alice, err := backend.Identities().New("Alice", "alice@example.com")
+	require.NoError(t, err)
+	bob, err := backend.Identities().NewFull("Bob", "", "bob", "", nil)
+	require.NoError(t, err)
+
+	b1, _, err := backend.Bugs().NewRaw(alice, 1000, "first", "message, with \"quotes\"\nand lines", nil, nil)
+	require.NoError(t, err)
+	commentId, _, err := b1.AddCommentRaw(bob, 1001, "comment", nil, nil)
+	require.NoError(t, err)
+	_, err = b1.EditCommentRaw(bob, 1002, commentId, "comment edited", nil)
+	require.NoError(t, err)
+	_, _, err = b1.ChangeLabelsRaw(alice, 1003, []string{"bug", "ui"}, nil, nil)
+	require.NoError(t, err)
+	_, _, err = b1.ChangeLabelsRaw(alice, 1004, nil, []string{"ui"}, nil)
+	require.NoError(t, err)
+	_, err = b1.SetTitleRaw(alice, 1005, "first edited", nil)
+	require.NoError(t, err)
+	_, err = b1.CloseRaw(bob, 1006, nil)
+	require.NoError(t, err)
+	require.NoError(t, b1.Commit())
+
+	b2, _, err := backend.Bugs().NewRaw(bob, 2000, "second", "message", nil, nil)
+	require.NoError(t, err)
+	_, _, err = b2.EditCreateCommentRaw(bob, 2001, "message edited", nil)
+	require.NoError(t, err)
+	require.NoError(t, b2.Commit())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export."+string(f))
+	conf := core.Configuration{
+		confKeyPath:   path,
+		confKeyFormat: string(f),
+	}
+
+	// a dry-run doesn't write the file
+	exporter := &fileExporter{}
+	exporter.SetDryRun(true)
+	runExport(t, backend, exporter, conf)
+	require.NoFileExists(t, path)
+
+	runExport(t, backend, &fileExporter{}, conf)
+	exported, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// import in an empty repository
+	repo2 := repository.CreateGoGitTestRepo(t, false)
+	backend2, err := cache.NewRepoCacheNoEvents(repo2)
+	require.NoError(t, err)
+	defer backend2.Close()
+
+	require.Empty(t, runImport(t, backend2, conf))
+	require.Len(t, backend2.Bugs().AllIds(), 2)
+	require.Len(t, backend2.Identities().AllIds(), 2)
+
+	for _, original := range []*cache.BugCache{b1, b2} {
+		imported, err := backend2.Bugs().ResolveBugCreateMetadata(metaKeyFileId, original.Id().String())
+		require.NoError(t, err)
+
+		expected, actual := original.Snapshot(), imported.Snapshot()
+		require.Equal(t, expected.Title, actual.Title)
+		require.Equal(t, expected.Status, actual.Status)
+		require.Equal(t, expected.Labels, actual.Labels)
+		require.Equal(t, expected.CreateTime.Unix(), actual.CreateTime.Unix())
+		require.Equal(t, expected.Author.Name(), actual.Author.Name())
+		require.Equal(t, expected.Author.Email(), actual.Author.Email())
+		require.Equal(t, expected.Author.Login(), actual.Author.Login())
+		require.Len(t, actual.Comments, len(expected.Comments))
+		for i := range expected.Comments {
+			require.Equal(t, expected.Comments[i].Message, actual.Comments[i].Message)
+			require.Equal(t, expected.Comments[i].Author.Name(), actual.Comments[i].Author.Name())
+		}
+	}
+
+	// the ids of the records are kept, so exporting again give the same file
+	path2 := filepath.Join(dir, "export2."+string(f))
+	runExport(t, backend2, &fileExporter{}, core.Configuration{
+		confKeyPath:   path2,
+		confKeyFormat: string(f),
+	})
+	exported2, err := os.ReadFile(path2)
+	require.NoError(t, err)
+	require.Equal(t, string(exported), string(exported2))
+
+	// importing in the original repository doesn't duplicate anything
+	require.Empty(t, runImport(t, backend, conf))
+	require.Len(t, backend.Bugs().AllIds(), 2)
+	require.Len(t, backend.Identities().AllIds(), 2)
+	require.Len(t, b1.Snapshot().Operations, 7)
+}
+
+func runExport(t *testing.T, backend *cache.RepoCache, exporter *fileExporter, conf core.Configuration) {
+	require.NoError(t, exporter.Init(context.Background(), backend, conf))
+
+	events, err := exporter.ExportAll(context.Background(), backend, time.Time{})
+	require.NoError(t, err)
+
+	for result := range events {
+		require.NotEqual(t, core.ExportEventError, result.Event, result.String())
+	}
+}
@@ -0,0 +1,42 @@
+// Package file contains a bridge importing and exporting bugs from and to a
+// JSON Lines or CSV file. It allows migrating from trackers without a
+// dedicated bridge, or backing up a repository in a format that other tools
+// can read. The format is documented in doc/file_bridge.md.
+package file
+
+import (
+	"github.com/MichaelMure/git-bug/bridge/core"
+)
+
+const (
+	target = "file"
+
+	// metaKeyFileId hold the id of the record an entity or an operation has
+	// been imported from
+	metaKeyFileId = "file-id"
+
+	confKeyPath   = "path"
+	confKeyFormat = "format"
+)
+
+var _ core.BridgeImpl = &File{}
+
+type File struct{}
+
+func (File) Target() string {
+	return target
+}
+
+// LoginMetaKey return the metadata key holding the id of an identity in the
+// file, as there is no login or credential involved.
+func (File) LoginMetaKey() string {
+	return metaKeyFileId
+}
+
+func (File) NewImporter() core.Importer {
+	return &fileImporter{}
+}
+
+func (File) NewExporter() core.Exporter {
+	return &fileExporter{}
+}
@@ -0,0 +1,406 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// fileImporter implement the Importer interface
+type fileImporter struct {
+	conf core.Configuration
+
+	// identities and bugs met during the import, by record id
+	identities map[string]*cache.IdentityCache
+	bugs       map[string]*cache.BugCache
+
+	// the ids of the records already imported, by bug
+	known map[entity.Id]map[string]struct{}
+
+	// send only channel
+	out chan<- core.ImportResult
+}
+
+func (fi *fileImporter) Init(_ context.Context, _ *cache.RepoCache, conf core.Configuration) error {
+	fi.conf = conf
+	return nil
+}
+
+// ImportAll read the whole file and ensure the creation of the missing
+// identities, bugs and changes. As the records are identified by their id,
+// the since date is ignored.
+func (fi *fileImporter) ImportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ImportResult, error) {
+	f, err := formatFromString(fi.conf[confKeyFormat])
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(fi.conf[confKeyPath])
+	if err != nil {
+		return nil, err
+	}
+	records, err := readRecords(file, f)
+	_ = file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", fi.conf[confKeyPath], err)
+	}
+
+	fi.identities = make(map[string]*cache.IdentityCache)
+	fi.bugs = make(map[string]*cache.BugCache)
+	fi.known = make(map[entity.Id]map[string]struct{})
+
+	out := make(chan core.ImportResult)
+	fi.out = out
+
+	go func() {
+		defer close(out)
+
+		// bugs in the order they appear in the file
+		var touched []*cache.BugCache
+		seen := make(map[entity.Id]bool)
+
+		for _, rec := range records {
+			select {
+			case <-ctx.Done():
+				out <- core.NewImportError(ctx.Err(), "")
+				return
+			default:
+			}
+
+			b, err := fi.importRecord(repo, rec)
+			if err != nil {
+				err := fmt.Errorf("%s record %s: %v", rec.Type, rec.key(), err)
+				out <- core.NewImportError(err, "")
+				continue
+			}
+			if b != nil && !seen[b.Id()] {
+				seen[b.Id()] = true
+				touched = append(touched, b)
+			}
+		}
+
+		for _, b := range touched {
+			if !b.NeedCommit() {
+				out <- core.NewImportNothing(b.Id(), "no imported operation")
+				continue
+			}
+			if err := b.Commit(); err != nil {
+				out <- core.NewImportError(fmt.Errorf("bug commit: %v", err), b.Id())
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// importRecord apply a single record, and return the affected bug, if any
+func (fi *fileImporter) importRecord(repo *cache.RepoCache, rec record) (*cache.BugCache, error) {
+	if rec.Type == recordIdentity {
+		_, err := fi.ensureIdentity(repo, rec)
+		return nil, err
+	}
+
+	if rec.Type == recordBug {
+		return fi.ensureBug(repo, rec)
+	}
+
+	b, err := fi.resolveBug(repo, rec.Bug)
+	if err != nil {
+		return nil, err
+	}
+
+	known := fi.knownIds(b)
+	if _, ok := known[rec.key()]; ok {
+		return b, nil
+	}
+
+	author, err := fi.ensureAuthor(repo, rec.Author)
+	if err != nil {
+		return nil, err
+	}
+	t, err := rec.time()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]string{
+		metaKeyFileId: rec.key(),
+	}
+
+	switch rec.Type {
+	case recordComment:
+		commentId, _, err := b.AddCommentRaw(author, t.Unix(), text.Cleanup(rec.Message), nil, metadata)
+		if err != nil {
+			return nil, err
+		}
+		fi.out <- core.NewImportComment(b.Id(), commentId)
+
+	case recordEdit:
+		target, err := findOperation(b, rec.Target)
+		if err != nil {
+			return nil, err
+		}
+		comment, err := b.Snapshot().SearchCommentByOpId(target)
+		if err != nil {
+			return nil, err
+		}
+		_, err = b.EditCommentRaw(author, t.Unix(), comment.CombinedId(), text.Cleanup(rec.Message), metadata)
+		if err != nil {
+			return nil, err
+		}
+		fi.out <- core.NewImportCommentEdition(b.Id(), comment.CombinedId())
+
+	case recordLabel:
+		op, err := b.ForceChangeLabelsRaw(author, t.Unix(), cleanupLabels(rec.Labels), cleanupLabels(rec.Removed), metadata)
+		if err != nil {
+			return nil, err
+		}
+		fi.out <- core.NewImportLabelChange(b.Id(), op.Id())
+
+	case recordStatus:
+		status, err := common.StatusFromString(rec.Status)
+		if err != nil {
+			return nil, err
+		}
+		op, err := setStatus(b, author, t.Unix(), status, metadata)
+		if err != nil {
+			return nil, err
+		}
+		fi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+
+	case recordTitle:
+		op, err := b.SetTitleRaw(author, t.Unix(), text.CleanupOneLine(rec.Title), metadata)
+		if err != nil {
+			return nil, err
+		}
+		fi.out <- core.NewImportTitleEdition(b.Id(), op.Id())
+
+	default:
+		return nil, fmt.Errorf("unknown record type")
+	}
+
+	known[rec.key()] = struct{}{}
+	return b, nil
+}
+
+func (fi *fileImporter) ensureBug(repo *cache.RepoCache, rec record) (*cache.BugCache, error) {
+	if rec.Id == "" {
+		return nil, fmt.Errorf("missing id")
+	}
+
+	b, err := fi.resolveBug(repo, rec.Id)
+	if err == nil {
+		return b, nil
+	}
+	if !entity.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	author, err := fi.ensureAuthor(repo, rec.Author)
+	if err != nil {
+		return nil, err
+	}
+	t, err := rec.time()
+	if err != nil {
+		return nil, err
+	}
+
+	b, _, err = repo.Bugs().NewRaw(
+		author,
+		t.Unix(),
+		text.CleanupOneLine(rec.Title),
+		text.Cleanup(rec.Message),
+		nil,
+		map[string]string{
+			core.MetaKeyOrigin: target,
+			metaKeyFileId:      rec.Id,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	fi.bugs[rec.Id] = b
+
+	fi.out <- core.NewImportBug(b.Id())
+
+	// for a spreadsheet, the labels and the status are on the same row,
+	// which makes them part of the creation
+	if len(rec.Labels) > 0 {
+		op, err := b.ForceChangeLabelsRaw(author, t.Unix(), cleanupLabels(rec.Labels), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		fi.out <- core.NewImportLabelChange(b.Id(), op.Id())
+	}
+	if rec.Status != "" {
+		status, err := common.StatusFromString(rec.Status)
+		if err != nil {
+			return nil, err
+		}
+		if status != common.OpenStatus {
+			op, err := setStatus(b, author, t.Unix(), status, nil)
+			if err != nil {
+				return nil, err
+			}
+			fi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+		}
+	}
+
+	return b, nil
+}
+
+// resolveBug find a bug from the id of its record. A bug exported from this
+// repository has its own id as record id.
+func (fi *fileImporter) resolveBug(repo *cache.RepoCache, id string) (*cache.BugCache, error) {
+	if id == "" {
+		return nil, fmt.Errorf("missing bug")
+	}
+	if b, ok := fi.bugs[id]; ok {
+		return b, nil
+	}
+
+	b, err := repo.Bugs().ResolveMatcher(func(excerpt *cache.BugExcerpt) bool {
+		return excerpt.CreateMetadata[metaKeyFileId] == id
+	})
+	if entity.IsErrNotFound(err) && entity.Id(id).Validate() == nil {
+		b, err = repo.Bugs().Resolve(entity.Id(id))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fi.bugs[id] = b
+	return b, nil
+}
+
+// knownIds return the set of the record ids of the operations of a bug, along
+// with their own ids for the bugs exported from this repository
+func (fi *fileImporter) knownIds(b *cache.BugCache) map[string]struct{} {
+	if known, ok := fi.known[b.Id()]; ok {
+		return known
+	}
+
+	known := make(map[string]struct{})
+	for _, op := range b.Snapshot().Operations {
+		known[op.Id().String()] = struct{}{}
+		if value, ok := op.GetMetadata(metaKeyFileId); ok {
+			known[value] = struct{}{}
+		}
+	}
+
+	fi.known[b.Id()] = known
+	return known
+}
+
+func (fi *fileImporter) ensureIdentity(repo *cache.RepoCache, rec record) (*cache.IdentityCache, error) {
+	if rec.Id == "" {
+		return nil, fmt.Errorf("missing id")
+	}
+
+	i, err := fi.resolveIdentity(repo, rec.Id)
+	if err == nil {
+		return i, nil
+	}
+	if !entity.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	name := rec.Name
+	if name == "" {
+		name = rec.Id
+	}
+
+	return fi.newIdentity(repo, rec.Id, name, rec.Email, rec.Login)
+}
+
+// ensureAuthor find the identity referenced by a record. Identities don't have
+// to be declared in the file, in which case the reference is used as name.
+func (fi *fileImporter) ensureAuthor(repo *cache.RepoCache, ref string) (*cache.IdentityCache, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("missing author")
+	}
+
+	i, err := fi.resolveIdentity(repo, ref)
+	if err == nil {
+		return i, nil
+	}
+	if !entity.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	return fi.newIdentity(repo, ref, ref, "", "")
+}
+
+func (fi *fileImporter) resolveIdentity(repo *cache.RepoCache, id string) (*cache.IdentityCache, error) {
+	if i, ok := fi.identities[id]; ok {
+		return i, nil
+	}
+
+	i, err := repo.Identities().ResolveIdentityImmutableMetadata(metaKeyFileId, id)
+	if entity.IsErrNotFound(err) && entity.Id(id).Validate() == nil {
+		i, err = repo.Identities().Resolve(entity.Id(id))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fi.identities[id] = i
+	return i, nil
+}
+
+func (fi *fileImporter) newIdentity(repo *cache.RepoCache, id, name, email, login string) (*cache.IdentityCache, error) {
+	i, err := repo.Identities().NewRaw(
+		name,
+		email,
+		login,
+		"",
+		nil,
+		map[string]string{
+			metaKeyFileId: id,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fi.identities[id] = i
+	fi.out <- core.NewImportIdentity(i.Id())
+	return i, nil
+}
+
+// findOperation find the operation created from a record, or the operation
+// with the given id for the bugs exported from this repository
+func findOperation(b *cache.BugCache, id string) (entity.Id, error) {
+	for _, op := range b.Snapshot().Operations {
+		if op.Id().String() == id {
+			return op.Id(), nil
+		}
+		if value, ok := op.GetMetadata(metaKeyFileId); ok && value == id {
+			return op.Id(), nil
+		}
+	}
+	return "", fmt.Errorf("unknown target %s", id)
+}
+
+func setStatus(b *cache.BugCache, author *cache.IdentityCache, unixTime int64, status common.Status, metadata map[string]string) (*bug.SetStatusOperation, error) {
+	if status == common.ClosedStatus {
+		return b.CloseRaw(author, unixTime, metadata)
+	}
+	return b.OpenRaw(author, unixTime, metadata)
+}
+
+func cleanupLabels(labels []string) []string {
+	result := make([]string, 0, len(labels))
+	for _, label := range labels {
+		result = append(result, text.CleanupOneLine(label))
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const spreadsheet = `ID,Type,Author,Time,Title,Message,Status,Labels,Bug,Target,Name,Email
+alice,identity,,,,,,,,,Alice,alice@example.com
+BZ-1,bug,alice,2020-01-02,crash on start,it crashes,open,"crash, ui",,,,
+BZ-2,bug,bob,2020-01-03 10:00:00,typo,there is a typo,closed,,,,,
+,comment,bob,2020-01-04T10:00:00Z,,any news?,,,BZ-1,,,
+,edit,alice,2020-01-05T10:00:00Z,,it crashes on start,,,BZ-1,BZ-1,,
+,title,alice,2020-01-06T10:00:00Z,crash when starting,,,,BZ-1,,,
+,status,alice,2020-01-07T10:00:00Z,,,closed,,BZ-1,,,
+,comment,bob,2020-01-08T10:00:00Z,,missing bug,,,,,,
+`
+
+func TestFileImportCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bugs.csv")
+	require.NoError(t, os.WriteFile(path, []byte(spreadsheet), 0644))
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	conf := core.Configuration{
+		confKeyPath:   path,
+		confKeyFormat: string(formatCSV),
+	}
+
+	errs := runImport(t, backend, conf)
+	// the comment without bug
+	require.Len(t, errs, 1)
+
+	require.Len(t, backend.Bugs().AllIds(), 2)
+	require.Len(t, backend.Identities().AllIds(), 2)
+
+	b1, err := backend.Bugs().ResolveBugCreateMetadata(metaKeyFileId, "BZ-1")
+	require.NoError(t, err)
+	snap1 := b1.Snapshot()
+	require.Equal(t, "crash when starting", snap1.Title)
+	require.Equal(t, common.ClosedStatus, snap1.Status)
+	require.Equal(t, []bug.Label{"crash", "ui"}, snap1.Labels)
+	require.Equal(t, "Alice", snap1.Author.Name())
+	require.Equal(t, "alice@example.com", snap1.Author.Email())
+	require.Len(t, snap1.Comments, 2)
+	require.Equal(t, "it crashes on start", snap1.Comments[0].Message)
+	require.Equal(t, "any news?", snap1.Comments[1].Message)
+	require.Equal(t, "bob", snap1.Comments[1].Author.Name())
+	require.Equal(t, int64(1577923200), snap1.CreateTime.Unix())
+
+	b2, err := backend.Bugs().ResolveBugCreateMetadata(metaKeyFileId, "BZ-2")
+	require.NoError(t, err)
+	require.Equal(t, common.ClosedStatus, b2.Snapshot().Status)
+
+	// importing again doesn't change anything
+	errs = runImport(t, backend, conf)
+	require.Len(t, errs, 1)
+	require.Len(t, backend.Bugs().AllIds(), 2)
+	require.Len(t, backend.Identities().AllIds(), 2)
+	require.Len(t, b1.Snapshot().Operations, len(snap1.Operations))
+}
+
+func runImport(t *testing.T, backend *cache.RepoCache, conf core.Configuration) []error {
+	importer := &fileImporter{}
+	require.NoError(t, importer.Init(context.Background(), backend, conf))
+
+	events, err := importer.ImportAll(context.Background(), backend, time.Time{})
+	require.NoError(t, err)
+
+	var errs []error
+	for result := range events {
+		if result.Event == core.ImportEventError {
+			errs = append(errs, result.Err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,264 @@
+package file
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type format string
+
+const (
+	formatJSONL format = "jsonl"
+	formatCSV   format = "csv"
+)
+
+func formatFromString(str string) (format, error) {
+	switch format(str) {
+	case formatJSONL, formatCSV:
+		return format(str), nil
+	default:
+		return "", fmt.Errorf("unknown file format \"%s\"", str)
+	}
+}
+
+// formatFromPath deduce the format of a file from its extension
+func formatFromPath(path string) (format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson", ".json":
+		return formatJSONL, nil
+	case ".csv":
+		return formatCSV, nil
+	default:
+		return "", fmt.Errorf("unknown file format for %s, expected a .jsonl or .csv file", path)
+	}
+}
+
+type recordType string
+
+const (
+	recordIdentity recordType = "identity"
+	recordBug      recordType = "bug"
+	recordComment  recordType = "comment"
+	recordEdit     recordType = "edit"
+	recordLabel    recordType = "label"
+	recordStatus   recordType = "status"
+	recordTitle    recordType = "title"
+)
+
+// record is a line of the file. Depending on its type, it describes an
+// identity, the creation of a bug or a change on a bug.
+type record struct {
+	Type recordType `json:"type"`
+
+	// Id identify the record in the file, and is used to reference identities,
+	// bugs and comments from the other records. For changes, it is optional and
+	// only used to not import them twice.
+	Id string `json:"id,omitempty"`
+
+	// Bug is the id of the bug record a change applies to
+	Bug string `json:"bug,omitempty"`
+	// Target is the id of the bug or comment record an edit applies to
+	Target string `json:"target,omitempty"`
+	// Author is the id of an identity record
+	Author string `json:"author,omitempty"`
+	// Time is formatted with RFC 3339, or as a date
+	Time string `json:"time,omitempty"`
+
+	// identities
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Login string `json:"login,omitempty"`
+
+	Title   string   `json:"title,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Status  string   `json:"status,omitempty"`
+	Labels  []string `json:"labels,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+var csvHeader = []string{
+	"type", "id", "bug", "target", "author", "time",
+	"name", "email", "login",
+	"title", "message", "status", "labels", "removed",
+}
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func (r record) time() (time.Time, error) {
+	if r.Time == "" {
+		return time.Time{}, fmt.Errorf("missing time")
+	}
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, r.Time)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time \"%s\"", r.Time)
+}
+
+// key return the id of the record, or an id derived from its content if it
+// doesn't have one, so that importing the same file twice is idempotent.
+func (r record) key() string {
+	if r.Id != "" {
+		return r.Id
+	}
+
+	h := sha256.New()
+	for _, field := range r.csv() {
+		_, _ = h.Write([]byte(field))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+func (r record) csv() []string {
+	return []string{
+		string(r.Type), r.Id, r.Bug, r.Target, r.Author, r.Time,
+		r.Name, r.Email, r.Login,
+		r.Title, r.Message, r.Status, strings.Join(r.Labels, ","), strings.Join(r.Removed, ","),
+	}
+}
+
+// readRecords parse all the records of a file. For CSV, the first row must
+// name the columns, in any order. Unknown columns are ignored and missing ones
+// are left empty, so that a spreadsheet can be imported as is.
+func readRecords(r io.Reader, f format) ([]record, error) {
+	switch f {
+	case formatJSONL:
+		return readJSONL(r)
+	case formatCSV:
+		return readCSV(r)
+	default:
+		return nil, fmt.Errorf("unknown file format \"%s\"", f)
+	}
+}
+
+func readJSONL(r io.Reader) ([]record, error) {
+	var records []record
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, scanner.Err()
+}
+
+func readCSV(r io.Reader) ([]record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var records []record
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		get := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		records = append(records, record{
+			Type:    recordType(get("type")),
+			Id:      get("id"),
+			Bug:     get("bug"),
+			Target:  get("target"),
+			Author:  get("author"),
+			Time:    get("time"),
+			Name:    get("name"),
+			Email:   get("email"),
+			Login:   get("login"),
+			Title:   get("title"),
+			Message: get("message"),
+			Status:  get("status"),
+			Labels:  splitLabels(get("labels")),
+			Removed: splitLabels(get("removed")),
+		})
+	}
+}
+
+func splitLabels(str string) []string {
+	var labels []string
+	for _, label := range strings.Split(str, ",") {
+		label = strings.TrimSpace(label)
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func writeRecords(w io.Writer, f format, records []record) error {
+	switch f {
+	case formatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, rec := range records {
+			if err := encoder.Encode(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, rec := range records {
+			if err := writer.Write(rec.csv()); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	default:
+		return fmt.Errorf("unknown file format \"%s\"", f)
+	}
+}
@@ -0,0 +1,317 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/input"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+var (
+	ErrBadProjectURL = errors.New("bad project url")
+)
+
+func (g *Gitea) ValidParams() map[string]interface{} {
+	return map[string]interface{}{
+		"URL":        nil,
+		"BaseURL":    nil,
+		"Login":      nil,
+		"CredPrefix": nil,
+		"TokenRaw":   nil,
+	}
+}
+
+func (g *Gitea) Configure(repo *cache.RepoCache, params core.BridgeParams, interactive bool) (core.Configuration, error) {
+	var err error
+	var baseUrl string
+
+	switch {
+	case params.BaseURL != "":
+		baseUrl = params.BaseURL
+	default:
+		if !interactive {
+			return nil, fmt.Errorf("Non-interactive-mode is active. Please specify the gitea instance URL via the --base-url option.")
+		}
+		baseUrl, err = input.PromptDefault("Gitea server URL", "URL", defaultBaseURL, input.Required, input.IsURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "base url prompt")
+		}
+	}
+
+	var projectURL string
+
+	// get project url
+	switch {
+	case params.URL != "":
+		projectURL = params.URL
+	default:
+		// terminal prompt
+		if !interactive {
+			return nil, fmt.Errorf("Non-interactive-mode is active. Please specify the gitea project URL via the --url option.")
+		}
+		projectURL, err = promptProjectURL(repo, baseUrl)
+		if err != nil {
+			return nil, errors.Wrap(err, "url prompt")
+		}
+	}
+
+	owner, project, err := getProjectPath(baseUrl, projectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var login string
+	var cred auth.Credential
+
+	switch {
+	case params.CredPrefix != "":
+		cred, err = auth.LoadWithPrefix(repo, params.CredPrefix)
+		if err != nil {
+			return nil, err
+		}
+		l, ok := cred.GetMetadata(auth.MetaKeyLogin)
+		if !ok {
+			return nil, fmt.Errorf("credential doesn't have a login")
+		}
+		login = l
+	case params.TokenRaw != "":
+		token := auth.NewToken(target, params.TokenRaw)
+		login, err = getLoginFromToken(baseUrl, token)
+		if err != nil {
+			return nil, err
+		}
+		token.SetMetadata(auth.MetaKeyLogin, login)
+		token.SetMetadata(auth.MetaKeyBaseURL, baseUrl)
+		cred = token
+	default:
+		if params.Login == "" {
+			if !interactive {
+				return nil, fmt.Errorf("Non-interactive-mode is active. Please specify the login name via the --login option.")
+			}
+			login, err = input.Prompt("Gitea login", "login", input.Required)
+		} else {
+			login = params.Login
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !interactive {
+			return nil, fmt.Errorf("Non-interactive-mode is active. Please specify the access token via the --token option.")
+		}
+		cred, err = promptTokenOptions(repo, login, baseUrl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	token, ok := cred.(*auth.Token)
+	if !ok {
+		return nil, fmt.Errorf("the Gitea bridge only handle token credentials")
+	}
+
+	// validate the project and the token access
+	err = validateProject(baseUrl, owner, project, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "project validation")
+	}
+
+	conf := make(core.Configuration)
+	conf[core.ConfigKeyTarget] = target
+	conf[confKeyBaseUrl] = baseUrl
+	conf[confKeyOwner] = owner
+	conf[confKeyProject] = project
+	conf[confKeyDefaultLogin] = login
+
+	err = g.ValidateConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	// don't forget to store the now known valid token
+	if !auth.IdExist(repo, cred.ID()) {
+		err = auth.Store(repo, cred)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return conf, core.FinishConfig(repo, metaKeyGiteaLogin, login)
+}
+
+func (g *Gitea) ValidateConfig(conf core.Configuration) error {
+	if v, ok := conf[core.ConfigKeyTarget]; !ok {
+		return fmt.Errorf("missing %s key", core.ConfigKeyTarget)
+	} else if v != target {
+		return fmt.Errorf("unexpected target name: %v", v)
+	}
+	if _, ok := conf[confKeyBaseUrl]; !ok {
+		return fmt.Errorf("missing %s key", confKeyBaseUrl)
+	}
+	if _, ok := conf[confKeyOwner]; !ok {
+		return fmt.Errorf("missing %s key", confKeyOwner)
+	}
+	if _, ok := conf[confKeyProject]; !ok {
+		return fmt.Errorf("missing %s key", confKeyProject)
+	}
+	if _, ok := conf[confKeyDefaultLogin]; !ok {
+		return fmt.Errorf("missing %s key", confKeyDefaultLogin)
+	}
+
+	return nil
+}
+
+func promptTokenOptions(repo repository.RepoKeyring, login, baseUrl string) (auth.Credential, error) {
+	creds, err := auth.List(repo,
+		auth.WithTarget(target),
+		auth.WithKind(auth.KindToken),
+		auth.WithMeta(auth.MetaKeyLogin, login),
+		auth.WithMeta(auth.MetaKeyBaseURL, baseUrl),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, index, err := input.PromptCredential(target, "token", creds, []string{
+		"enter my token",
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case cred != nil:
+		return cred, nil
+	case index == 0:
+		return promptToken(baseUrl)
+	default:
+		panic("missed case")
+	}
+}
+
+func promptToken(baseUrl string) (*auth.Token, error) {
+	fmt.Printf("You can generate a new token by visiting %s.\n", strings.TrimSuffix(baseUrl, "/")+"/user/settings/applications")
+	fmt.Println("Choose 'Generate New Token' and set the necessary access scope for your repository.")
+	fmt.Println()
+	fmt.Println("'issue' read and write scope: to be able to read and modify issues")
+	fmt.Println("'user' read scope: to be able to read the user profiles")
+	fmt.Println()
+
+	var login string
+
+	validator := func(name string, value string) (complaint string, err error) {
+		login, err = getLoginFromToken(baseUrl, auth.NewToken(target, value))
+		if err != nil {
+			return fmt.Sprintf("token is invalid: %v", err), nil
+		}
+		return "", nil
+	}
+
+	rawToken, err := input.Prompt("Enter token", "token", input.Required, validator)
+	if err != nil {
+		return nil, err
+	}
+
+	token := auth.NewToken(target, rawToken)
+	token.SetMetadata(auth.MetaKeyLogin, login)
+	token.SetMetadata(auth.MetaKeyBaseURL, baseUrl)
+
+	return token, nil
+}
+
+func promptProjectURL(repo repository.RepoCommon, baseUrl string) (string, error) {
+	validRemotes, err := getValidGiteaRemoteURLs(repo, baseUrl)
+	if err != nil {
+		return "", err
+	}
+
+	return input.PromptURLWithRemote("Gitea project URL", "URL", validRemotes, input.Required)
+}
+
+// getProjectPath extract the owner and the project name from a project URL
+func getProjectPath(baseUrl, projectUrl string) (string, string, error) {
+	cleanUrl := strings.TrimSuffix(projectUrl, ".git")
+	if strings.HasPrefix(cleanUrl, "git@") {
+		// scp-like ssh URL: git@host:owner/project
+		cleanUrl = "https://" + strings.Replace(strings.TrimPrefix(cleanUrl, "git@"), ":", "/", 1)
+	}
+	objectUrl, err := url.Parse(cleanUrl)
+	if err != nil {
+		return "", "", ErrBadProjectURL
+	}
+
+	objectBaseUrl, err := url.Parse(baseUrl)
+	if err != nil {
+		return "", "", ErrBadProjectURL
+	}
+
+	if objectUrl.Hostname() != objectBaseUrl.Hostname() {
+		return "", "", fmt.Errorf("base url and project url hostnames doesn't match")
+	}
+
+	// the instance can be hosted under a sub-path
+	path := strings.TrimPrefix(objectUrl.Path, strings.TrimSuffix(objectBaseUrl.Path, "/"))
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", ErrBadProjectURL
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func getValidGiteaRemoteURLs(repo repository.RepoCommon, baseUrl string) ([]string, error) {
+	remotes, err := repo.GetRemotes()
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(remotes))
+	for _, u := range remotes {
+		owner, project, err := getProjectPath(baseUrl, u)
+		if err != nil {
+			continue
+		}
+
+		urls = append(urls, fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(baseUrl, "/"), owner, project))
+	}
+
+	return urls, nil
+}
+
+func validateProject(baseUrl, owner, project string, token *auth.Token) error {
+	client := buildClient(http.DefaultTransport, baseUrl, token)
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	_, err := client.GetRepository(ctx, owner, project)
+	if err != nil {
+		return errors.Wrap(err, "wrong token scope or non-existent project")
+	}
+
+	return nil
+}
+
+func getLoginFromToken(baseUrl string, token *auth.Token) (string, error) {
+	client := buildClient(http.DefaultTransport, baseUrl, token)
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	if user.Login == "" {
+		return "", fmt.Errorf("gitea say username is empty")
+	}
+
+	return user.Login, nil
+}
@@ -0,0 +1,98 @@
+package gitea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectPath(t *testing.T) {
+	type args struct {
+		baseUrl string
+		url     string
+	}
+	type want struct {
+		owner   string
+		project string
+		err     error
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "default url",
+			args: args{
+				baseUrl: "https://gitea.com/",
+				url:     "https://gitea.com/MichaelMure/git-bug",
+			},
+			want: want{
+				owner:   "MichaelMure",
+				project: "git-bug",
+			},
+		},
+		{
+			name: "default url with git extension",
+			args: args{
+				baseUrl: "https://codeberg.org/",
+				url:     "https://codeberg.org/MichaelMure/git-bug.git",
+			},
+			want: want{
+				owner:   "MichaelMure",
+				project: "git-bug",
+			},
+		},
+		{
+			name: "instance in a sub-path",
+			args: args{
+				baseUrl: "https://example.com/gitea/",
+				url:     "https://example.com/gitea/MichaelMure/git-bug",
+			},
+			want: want{
+				owner:   "MichaelMure",
+				project: "git-bug",
+			},
+		},
+		{
+			name: "ssh url",
+			args: args{
+				baseUrl: "https://codeberg.org",
+				url:     "git@codeberg.org:MichaelMure/git-bug.git",
+			},
+			want: want{
+				owner:   "MichaelMure",
+				project: "git-bug",
+			},
+		},
+		{
+			name: "missing project",
+			args: args{
+				baseUrl: "https://gitea.com/",
+				url:     "https://gitea.com/MichaelMure",
+			},
+			want: want{
+				err: ErrBadProjectURL,
+			},
+		},
+		{
+			name: "bad url",
+			args: args{
+				baseUrl: "https://gitea.com/",
+				url:     "---,%gitea.com/MichaelMure/git-bug.git",
+			},
+			want: want{
+				err: ErrBadProjectURL,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, project, err := getProjectPath(tt.args.baseUrl, tt.args.url)
+			assert.Equal(t, tt.want.err, err)
+			assert.Equal(t, tt.want.owner, owner)
+			assert.Equal(t, tt.want.project, project)
+		})
+	}
+}
@@ -0,0 +1,610 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+)
+
+var (
+	ErrMissingIdentityToken = errors.New("missing identity token")
+)
+
+// giteaExporter implement the Exporter interface
+type giteaExporter struct {
+	conf core.Configuration
+
+	// label and status mapping
+	mapping *core.Mapping
+
+	// if true, don't call the mutating endpoints
+	dryRun bool
+
+	// cache identities clients
+	identityClient map[entity.Id]*Client
+
+	// transport of the HTTP requests
+	transport http.RoundTripper
+
+	// cache labels ids, lazily loaded
+	cachedLabels map[string]int64
+
+	// cache identifiers used to speed up exporting operations
+	// cleared for each bug
+	cachedOperationIDs map[entity.Id]string
+}
+
+// Init .
+func (ge *giteaExporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
+	ge.conf = conf
+	ge.transport = core.HTTPTransport(ctx)
+	ge.identityClient = make(map[entity.Id]*Client)
+	ge.cachedOperationIDs = make(map[entity.Id]string)
+
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	ge.mapping = mapping
+
+	// preload all clients
+	err = ge.cacheAllClient(repo, ge.conf[confKeyBaseUrl])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetDryRun .
+func (ge *giteaExporter) SetDryRun(dryRun bool) {
+	ge.dryRun = dryRun
+}
+
+func (ge *giteaExporter) cacheAllClient(repo *cache.RepoCache, baseURL string) error {
+	creds, err := auth.List(repo,
+		auth.WithTarget(target),
+		auth.WithKind(auth.KindToken),
+		auth.WithMeta(auth.MetaKeyBaseURL, baseURL),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, cred := range creds {
+		login, ok := cred.GetMetadata(auth.MetaKeyLogin)
+		if !ok {
+			_, _ = fmt.Fprintf(os.Stderr, "credential %s is not tagged with a Gitea login\n", cred.ID().Human())
+			continue
+		}
+
+		user, err := repo.Identities().ResolveIdentityImmutableMetadata(metaKeyGiteaLogin, login)
+		if entity.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, ok := ge.identityClient[user.Id()]; !ok {
+			ge.identityClient[user.Id()] = buildClient(ge.transport, baseURL, cred.(*auth.Token))
+		}
+	}
+
+	return nil
+}
+
+// getIdentityClient return a Gitea API client configured with the access token of the given identity.
+func (ge *giteaExporter) getIdentityClient(userId entity.Id) (*Client, error) {
+	client, ok := ge.identityClient[userId]
+	if ok {
+		return client, nil
+	}
+
+	return nil, ErrMissingIdentityToken
+}
+
+// ExportAll export all event made by the current user to Gitea
+func (ge *giteaExporter) ExportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ExportResult, error) {
+	out := make(chan core.ExportResult)
+
+	go func() {
+		defer close(out)
+
+		allIdentitiesIds := make([]entity.Id, 0, len(ge.identityClient))
+		for id := range ge.identityClient {
+			allIdentitiesIds = append(allIdentitiesIds, id)
+		}
+
+		allBugsIds := repo.Bugs().AllIds()
+
+		for _, id := range allBugsIds {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				b, err := repo.Bugs().Resolve(id)
+				if err != nil {
+					out <- core.NewExportError(err, id)
+					return
+				}
+
+				snapshot := b.Snapshot()
+
+				// ignore issues created before since date
+				// TODO: compare the Lamport time instead of using the unix time
+				if snapshot.CreateTime.Before(since) {
+					out <- core.NewExportNothing(b.Id(), "bug created before the since date")
+					continue
+				}
+
+				if snapshot.HasAnyActor(allIdentitiesIds...) {
+					// try to export the bug and it associated events
+					ge.exportBug(ctx, b, out)
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// exportBug publish bugs and related events
+func (ge *giteaExporter) exportBug(ctx context.Context, b *cache.BugCache, out chan<- core.ExportResult) {
+	snapshot := b.Snapshot()
+
+	var bugUpdated bool
+	var err error
+	var bugGiteaNumber int64
+
+	owner := ge.conf[confKeyOwner]
+	project := ge.conf[confKeyProject]
+	fullProject := fmt.Sprintf("%s/%s", owner, project)
+
+	// Special case:
+	// if a user try to export a bug that is not already exported to Gitea (or imported
+	// from Gitea) and we do not have the token of the bug author, there is nothing we can do.
+
+	// skip bug if origin is not allowed
+	origin, ok := snapshot.GetCreateMetadata(core.MetaKeyOrigin)
+	if ok && origin != target {
+		out <- core.NewExportNothing(b.Id(), fmt.Sprintf("issue tagged with origin: %s", origin))
+		return
+	}
+
+	// first operation is always createOp
+	createOp := snapshot.Operations[0].(*bug.CreateOperation)
+	author := snapshot.Author
+
+	// get gitea issue number
+	giteaID, ok := snapshot.GetCreateMetadata(metaKeyGiteaId)
+	if ok {
+		giteaBaseUrl, ok := snapshot.GetCreateMetadata(metaKeyGiteaBaseUrl)
+		if ok && giteaBaseUrl != ge.conf[confKeyBaseUrl] {
+			out <- core.NewExportNothing(b.Id(), "skipping issue imported from another Gitea instance")
+			return
+		}
+
+		giteaProject, ok := snapshot.GetCreateMetadata(metaKeyGiteaProject)
+		if !ok {
+			err := fmt.Errorf("expected to find gitea project")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		if giteaProject != fullProject {
+			out <- core.NewExportNothing(b.Id(), "skipping issue imported from another repository")
+			return
+		}
+
+		bugGiteaNumber, err = strconv.ParseInt(giteaID, 10, 64)
+		if err != nil {
+			out <- core.NewExportError(fmt.Errorf("unexpected gitea id format: %s", giteaID), b.Id())
+			return
+		}
+
+	} else {
+		// check that we have a token for operation author
+		client, err := ge.getIdentityClient(author.Id())
+		if err != nil {
+			// if bug is still not exported and we do not have the author stop the execution
+			out <- core.NewExportNothing(b.Id(), "missing author token")
+			return
+		}
+
+		// create bug
+		issue, err := ge.createGiteaIssue(ctx, client, owner, project, createOp.Title, createOp.Message)
+		if err != nil {
+			err := errors.Wrap(err, "exporting gitea issue")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		out <- core.NewExportBug(b.Id())
+
+		_, err = b.SetMetadata(
+			createOp.Id(),
+			map[string]string{
+				core.MetaKeyOrigin:  target,
+				metaKeyGiteaId:      strconv.FormatInt(issue.Number, 10),
+				metaKeyGiteaUrl:     issue.HTMLURL,
+				metaKeyGiteaProject: fullProject,
+				metaKeyGiteaBaseUrl: ge.conf[confKeyBaseUrl],
+			},
+		)
+		if err != nil {
+			err := errors.Wrap(err, "marking operation as exported")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		// commit operation to avoid creating multiple issues with multiple pushes
+		if err := b.CommitAsNeeded(); err != nil {
+			err := errors.Wrap(err, "bug commit")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		bugGiteaNumber = issue.Number
+	}
+
+	// the ids of the timeline events already bound to an operation
+	known := knownGiteaIds(b)
+
+	for _, op := range snapshot.Operations[1:] {
+		// ignore SetMetadata operations
+		if _, ok := op.(dag.OperationDoesntChangeSnapshot); ok {
+			continue
+		}
+
+		// ignore operations already existing in gitea (due to import or export)
+		// cache the ID of already exported or imported comments from Gitea
+		if id, ok := op.GetMetadata(metaKeyGiteaId); ok {
+			ge.cachedOperationIDs[op.Id()] = id
+			continue
+		}
+
+		opAuthor := op.Author()
+		client, err := ge.getIdentityClient(opAuthor.Id())
+		if err != nil {
+			continue
+		}
+
+		var ids []string
+		var url string
+
+		switch op := op.(type) {
+		case *bug.AddCommentOperation:
+			comment, err := ge.createGiteaComment(ctx, client, owner, project, bugGiteaNumber, op.Message)
+			if err != nil {
+				err := errors.Wrap(err, "adding comment")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportComment(b.Id())
+
+			id := strconv.FormatInt(comment.ID, 10)
+			ge.cachedOperationIDs[op.Id()] = id
+			ids = []string{id}
+			url = comment.HTMLURL
+
+		case *bug.EditCommentOperation:
+			if op.Target == createOp.Id() {
+				// case bug creation operation: we need to edit the Gitea issue
+				err := ge.editGiteaIssue(ctx, client, owner, project, bugGiteaNumber, map[string]string{
+					"body": op.Message,
+				})
+				if err != nil {
+					err := errors.Wrap(err, "editing issue")
+					out <- core.NewExportError(err, b.Id())
+					return
+				}
+
+				// there is no timeline event for the description edition, the
+				// operation is marked with an empty id
+				out <- core.NewExportCommentEdition(b.Id())
+
+			} else {
+				// case comment edition operation: we need to edit the Gitea comment
+				commentID, ok := ge.cachedOperationIDs[op.Target]
+				if !ok {
+					out <- core.NewExportError(fmt.Errorf("unexpected error: comment id not found"), b.Id())
+					return
+				}
+
+				commentIDint, err := strconv.ParseInt(commentID, 10, 64)
+				if err != nil {
+					out <- core.NewExportError(fmt.Errorf("unexpected comment id format"), b.Id())
+					return
+				}
+
+				comment, err := ge.editGiteaComment(ctx, client, owner, project, commentIDint, op.Message)
+				if err != nil {
+					err := errors.Wrap(err, "editing comment")
+					out <- core.NewExportError(err, b.Id())
+					return
+				}
+
+				// as for the description, there is no timeline event for the
+				// comment edition
+				out <- core.NewExportCommentEdition(b.Id())
+				url = comment.HTMLURL
+			}
+
+		case *bug.SetStatusOperation:
+			if err := ge.updateGiteaIssueStatus(ctx, client, owner, project, bugGiteaNumber, op.Status); err != nil {
+				err := errors.Wrap(err, "editing status")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportStatusChange(b.Id())
+
+			kind := TimelineReopen
+			if op.Status == common.ClosedStatus {
+				kind = TimelineClose
+			}
+			ids, err = ge.newTimelineEventIds(ctx, client, owner, project, bugGiteaNumber, known, kind, 1)
+
+		case *bug.SetTitleOperation:
+			err := ge.editGiteaIssue(ctx, client, owner, project, bugGiteaNumber, map[string]string{
+				"title": op.Title,
+			})
+			if err != nil {
+				err := errors.Wrap(err, "editing title")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportTitleEdition(b.Id())
+
+			ids, err = ge.newTimelineEventIds(ctx, client, owner, project, bugGiteaNumber, known, TimelineChangeTitle, 1)
+
+		case *bug.LabelChangeOperation:
+			added := ge.mapping.ExportLabels(op.Added)
+			removed := ge.mapping.ExportLabels(op.Removed)
+
+			if err := ge.updateGiteaIssueLabels(ctx, client, owner, project, bugGiteaNumber, added, removed); err != nil {
+				err := errors.Wrap(err, "updating labels")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportLabelChange(b.Id())
+
+			count := len(added) + len(removed)
+			ids, err = ge.newTimelineEventIds(ctx, client, owner, project, bugGiteaNumber, known, TimelineLabel, count)
+
+		default:
+			panic("unhandled operation type case")
+		}
+
+		if err != nil {
+			err := errors.Wrap(err, "reading the issue timeline")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		for _, id := range ids {
+			known[id] = struct{}{}
+		}
+
+		// mark operation as exported
+		if err := markOperationAsExported(b, op.Id(), strings.Join(ids, ","), url); err != nil {
+			err := errors.Wrap(err, "marking operation as exported")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		// commit at each operation export to avoid exporting same events multiple times
+		if err := b.CommitAsNeeded(); err != nil {
+			err := errors.Wrap(err, "bug commit")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		bugUpdated = true
+	}
+
+	if !bugUpdated {
+		out <- core.NewExportNothing(b.Id(), "nothing has been exported")
+	}
+}
+
+func markOperationAsExported(b *cache.BugCache, target entity.Id, giteaID, giteaURL string) error {
+	metadata := map[string]string{
+		metaKeyGiteaId: giteaID,
+	}
+	if giteaURL != "" {
+		metadata[metaKeyGiteaUrl] = giteaURL
+	}
+
+	_, err := b.SetMetadata(target, metadata)
+	return err
+}
+
+// newTimelineEventIds return the ids of the last count events of the given kind
+// in the timeline of an issue that are not yet bound to an operation. This
+// allows to find the events generated by a mutation, as the API doesn't
+// return them.
+func (ge *giteaExporter) newTimelineEventIds(ctx context.Context, client *Client, owner, project string, number int64, known map[string]struct{}, kind string, count int) ([]string, error) {
+	// no event has been generated
+	if ge.dryRun {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var candidates []string
+
+	for page := 1; ; page++ {
+		events, err := client.Timeline(ctx, owner, project, number, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			id := strconv.FormatInt(event.ID, 10)
+			if _, ok := known[id]; event.Type == kind && !ok {
+				candidates = append(candidates, id)
+			}
+		}
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	if len(candidates) > count {
+		candidates = candidates[len(candidates)-count:]
+	}
+
+	return candidates, nil
+}
+
+// create a gitea issue and return it
+func (ge *giteaExporter) createGiteaIssue(ctx context.Context, client *Client, owner, project, title, body string) (*Issue, error) {
+	if ge.dryRun {
+		return &Issue{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	return client.CreateIssue(ctx, owner, project, title, body)
+}
+
+// add a comment to a gitea issue and return it
+func (ge *giteaExporter) createGiteaComment(ctx context.Context, client *Client, owner, project string, number int64, body string) (*Comment, error) {
+	if ge.dryRun {
+		return &Comment{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	return client.CreateComment(ctx, owner, project, number, body)
+}
+
+func (ge *giteaExporter) editGiteaComment(ctx context.Context, client *Client, owner, project string, id int64, body string) (*Comment, error) {
+	if ge.dryRun {
+		return &Comment{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	return client.EditComment(ctx, owner, project, id, body)
+}
+
+func (ge *giteaExporter) editGiteaIssue(ctx context.Context, client *Client, owner, project string, number int64, fields map[string]string) error {
+	if ge.dryRun {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	_, err := client.EditIssue(ctx, owner, project, number, fields)
+	return err
+}
+
+func (ge *giteaExporter) updateGiteaIssueStatus(ctx context.Context, client *Client, owner, project string, number int64, status common.Status) error {
+	var state string
+
+	switch status {
+	case common.OpenStatus:
+		state = "open"
+	case common.ClosedStatus:
+		state = "closed"
+	default:
+		panic("unknown bug state")
+	}
+
+	return ge.editGiteaIssue(ctx, client, owner, project, number, map[string]string{
+		"state": state,
+	})
+}
+
+func (ge *giteaExporter) updateGiteaIssueLabels(ctx context.Context, client *Client, owner, project string, number int64, added, removed []bug.Label) error {
+	if ge.dryRun {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if len(added) > 0 {
+		ids := make([]int64, 0, len(added))
+		for _, label := range added {
+			id, err := ge.getOrCreateGiteaLabelID(ctx, client, owner, project, label)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+
+		if err := client.AddIssueLabels(ctx, owner, project, number, ids); err != nil {
+			return err
+		}
+	}
+
+	for _, label := range removed {
+		id, err := ge.getOrCreateGiteaLabelID(ctx, client, owner, project, label)
+		if err != nil {
+			return err
+		}
+
+		if err := client.RemoveIssueLabel(ctx, owner, project, number, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ge *giteaExporter) getOrCreateGiteaLabelID(ctx context.Context, client *Client, owner, project string, label bug.Label) (int64, error) {
+	if ge.cachedLabels == nil {
+		labels, err := client.Labels(ctx, owner, project)
+		if err != nil {
+			return 0, err
+		}
+
+		ge.cachedLabels = make(map[string]int64, len(labels))
+		for _, l := range labels {
+			ge.cachedLabels[l.Name] = l.ID
+		}
+	}
+
+	if id, ok := ge.cachedLabels[label.String()]; ok {
+		return id, nil
+	}
+
+	// RGBA to hex color
+	rgba := label.Color().RGBA()
+	hexColor := fmt.Sprintf("%.2x%.2x%.2x", rgba.R, rgba.G, rgba.B)
+
+	created, err := client.CreateLabel(ctx, owner, project, label.String(), hexColor)
+	if err != nil {
+		return 0, err
+	}
+
+	ge.cachedLabels[label.String()] = created.ID
+	return created.ID, nil
+}
@@ -0,0 +1,326 @@
+package gitea
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+type testCase struct {
+	name     string
+	bug      *cache.BugCache
+	numOp    int // number of original operations
+	numOpExp int // number of operations after export
+	numOpImp int // number of operations after import
+}
+
+func testCases(t *testing.T, repo *cache.RepoCache) []*testCase {
+	// simple bug
+	simpleBug, _, err := repo.Bugs().New("simple bug", "new bug")
+	require.NoError(t, err)
+
+	// bug with comments
+	bugWithComments, _, err := repo.Bugs().New("bug with comments", "new bug")
+	require.NoError(t, err)
+
+	_, _, err = bugWithComments.AddComment("new comment")
+	require.NoError(t, err)
+
+	// bug with label changes
+	bugLabelChange, _, err := repo.Bugs().New("bug label change", "new bug")
+	require.NoError(t, err)
+
+	_, _, err = bugLabelChange.ChangeLabels([]string{"bug", "core"}, nil)
+	require.NoError(t, err)
+
+	_, _, err = bugLabelChange.ChangeLabels(nil, []string{"bug"})
+	require.NoError(t, err)
+
+	// bug with comments editions
+	bugWithCommentEditions, createOp, err := repo.Bugs().New("bug with comments editions", "new bug")
+	require.NoError(t, err)
+
+	_, err = bugWithCommentEditions.EditComment(
+		entity.CombineIds(bugWithCommentEditions.Id(), createOp.Id()), "first comment edited")
+	require.NoError(t, err)
+
+	commentId, _, err := bugWithCommentEditions.AddComment("first comment")
+	require.NoError(t, err)
+
+	_, err = bugWithCommentEditions.EditComment(commentId, "first comment edited")
+	require.NoError(t, err)
+
+	// bug status changed
+	bugStatusChanged, _, err := repo.Bugs().New("bug status changed", "new bug")
+	require.NoError(t, err)
+
+	_, err = bugStatusChanged.Close()
+	require.NoError(t, err)
+
+	_, err = bugStatusChanged.Open()
+	require.NoError(t, err)
+
+	// bug title changed
+	bugTitleEdited, _, err := repo.Bugs().New("bug title edited", "new bug")
+	require.NoError(t, err)
+
+	_, err = bugTitleEdited.SetTitle("bug title edited again")
+	require.NoError(t, err)
+
+	return []*testCase{
+		{
+			name:     "simple bug",
+			bug:      simpleBug,
+			numOp:    1,
+			numOpExp: 2,
+			numOpImp: 1,
+		},
+		{
+			name:     "bug with comments",
+			bug:      bugWithComments,
+			numOp:    2,
+			numOpExp: 4,
+			numOpImp: 2,
+		},
+		{
+			name:     "bug label change",
+			bug:      bugLabelChange,
+			numOp:    3,
+			numOpExp: 6,
+			numOpImp: 4,
+		},
+		{
+			name:     "bug with comment editions",
+			bug:      bugWithCommentEditions,
+			numOp:    4,
+			numOpExp: 8,
+			numOpImp: 2,
+		},
+		{
+			name:     "bug changed status",
+			bug:      bugStatusChanged,
+			numOp:    3,
+			numOpExp: 6,
+			numOpImp: 3,
+		},
+		{
+			name:     "bug title edited",
+			bug:      bugTitleEdited,
+			numOp:    2,
+			numOpExp: 4,
+			numOpImp: 2,
+		},
+	}
+}
+
+func TestGiteaPushPull(t *testing.T) {
+	server := newFakeGitea(t, "git-bug", "test")
+	server.addUser("test-token", "test-identity", "test identity")
+
+	// create repo backend
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	// set author identity
+	login := "test-identity"
+	author, err := backend.Identities().New("test identity", "test@test.org")
+	require.NoError(t, err)
+	author.SetMetadata(metaKeyGiteaLogin, login)
+	err = author.Commit()
+	require.NoError(t, err)
+
+	err = backend.SetUserIdentity(author)
+	require.NoError(t, err)
+
+	token := auth.NewToken(target, "test-token")
+	token.SetMetadata(auth.MetaKeyLogin, login)
+	token.SetMetadata(auth.MetaKeyBaseURL, server.URL)
+	err = auth.Store(repo, token)
+	require.NoError(t, err)
+
+	tests := testCases(t, backend)
+
+	conf := core.Configuration{
+		confKeyBaseUrl:      server.URL,
+		confKeyOwner:        "git-bug",
+		confKeyProject:      "test",
+		confKeyDefaultLogin: login,
+	}
+
+	ctx := context.Background()
+
+	runExport := func() []core.ExportResult {
+		exporter := &giteaExporter{}
+		err := exporter.Init(ctx, backend, conf)
+		require.NoError(t, err)
+
+		events, err := exporter.ExportAll(ctx, backend, time.Time{})
+		require.NoError(t, err)
+
+		var results []core.ExportResult
+		for result := range events {
+			require.NoError(t, result.Err)
+			results = append(results, result)
+		}
+		return results
+	}
+
+	runExport()
+
+	require.Len(t, server.issues, len(tests))
+
+	// a second export doesn't do anything
+	for _, result := range runExport() {
+		require.Equal(t, core.ExportEventNothing, result.Event)
+	}
+
+	repoTwo := repository.CreateGoGitTestRepo(t, false)
+
+	// create a second backend
+	backendTwo, err := cache.NewRepoCacheNoEvents(repoTwo)
+	require.NoError(t, err)
+	defer backendTwo.Close()
+
+	err = auth.Store(repoTwo, token)
+	require.NoError(t, err)
+
+	importer := &giteaImporter{}
+	err = importer.Init(ctx, backendTwo, conf)
+	require.NoError(t, err)
+
+	// import all exported bugs to the second backend
+	importEvents, err := importer.ImportAll(ctx, backendTwo, time.Time{})
+	require.NoError(t, err)
+
+	for result := range importEvents {
+		require.NoError(t, result.Err)
+	}
+
+	require.Len(t, backendTwo.Bugs().AllIds(), len(tests))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// for each operation a SetMetadataOperation will be added
+			// so number of operations should double
+			require.Len(t, tt.bug.Snapshot().Operations, tt.numOpExp)
+
+			// verify operation have correct metadata
+			for _, op := range tt.bug.Snapshot().Operations {
+				// Check if the originals operations (*not* SetMetadata) are tagged properly
+				if _, ok := op.(dag.OperationDoesntChangeSnapshot); !ok {
+					_, haveIDMetadata := op.GetMetadata(metaKeyGiteaId)
+					require.True(t, haveIDMetadata)
+				}
+			}
+
+			// get bug gitea URL
+			bugGiteaURL, ok := tt.bug.Snapshot().GetCreateMetadata(metaKeyGiteaUrl)
+			require.True(t, ok)
+
+			// retrieve bug from backendTwo
+			importedBug, err := backendTwo.Bugs().ResolveBugCreateMetadata(metaKeyGiteaUrl, bugGiteaURL)
+			require.NoError(t, err)
+
+			// verify bug have same number of original operations
+			require.Len(t, importedBug.Snapshot().Operations, tt.numOpImp)
+
+			// verify bugs are tagged with origin=gitea
+			issueOrigin, ok := importedBug.Snapshot().GetCreateMetadata(core.MetaKeyOrigin)
+			require.True(t, ok)
+			require.Equal(t, issueOrigin, target)
+
+			// verify the final state is the same
+			require.Equal(t, tt.bug.Snapshot().Title, importedBug.Snapshot().Title)
+			require.Equal(t, tt.bug.Snapshot().Status, importedBug.Snapshot().Status)
+			require.ElementsMatch(t, tt.bug.Snapshot().Labels, importedBug.Snapshot().Labels)
+			require.Equal(t, len(tt.bug.Snapshot().Comments), len(importedBug.Snapshot().Comments))
+			for i, comment := range tt.bug.Snapshot().Comments {
+				require.Equal(t, comment.Message, importedBug.Snapshot().Comments[i].Message)
+			}
+		})
+	}
+
+	// importing back in the original repository doesn't duplicate anything
+	importer = &giteaImporter{}
+	err = importer.Init(ctx, backend, conf)
+	require.NoError(t, err)
+
+	importEvents, err = importer.ImportAll(ctx, backend, time.Time{})
+	require.NoError(t, err)
+
+	for result := range importEvents {
+		require.NoError(t, result.Err)
+		require.Equal(t, core.ImportEventNothing, result.Event)
+	}
+}
+
+func TestGiteaExportDryRun(t *testing.T) {
+	server := newFakeGitea(t, "git-bug", "test")
+	server.addUser("test-token", "test-identity", "test identity")
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	login := "test-identity"
+	author, err := backend.Identities().New("test identity", "test@test.org")
+	require.NoError(t, err)
+	author.SetMetadata(metaKeyGiteaLogin, login)
+	err = author.Commit()
+	require.NoError(t, err)
+
+	err = backend.SetUserIdentity(author)
+	require.NoError(t, err)
+
+	token := auth.NewToken(target, "test-token")
+	token.SetMetadata(auth.MetaKeyLogin, login)
+	token.SetMetadata(auth.MetaKeyBaseURL, server.URL)
+	err = auth.Store(repo, token)
+	require.NoError(t, err)
+
+	tests := testCases(t, backend)
+
+	conf := core.Configuration{
+		confKeyBaseUrl:      server.URL,
+		confKeyOwner:        "git-bug",
+		confKeyProject:      "test",
+		confKeyDefaultLogin: login,
+	}
+
+	ctx := context.Background()
+
+	exporter := &giteaExporter{}
+	exporter.SetDryRun(true)
+	err = exporter.Init(ctx, backend, conf)
+	require.NoError(t, err)
+
+	events, err := exporter.ExportAll(ctx, backend, time.Time{})
+	require.NoError(t, err)
+
+	var created int
+	for result := range events {
+		require.NoError(t, result.Err)
+		if result.Event == core.ExportEventBug {
+			created++
+		}
+	}
+
+	// the export went through every bug without changing anything on Gitea
+	require.Equal(t, len(tests), created)
+	require.Empty(t, server.issues)
+	require.Empty(t, server.labels)
+}
@@ -0,0 +1,56 @@
+// Package gitea contains the Gitea/Forgejo bridge implementation
+package gitea
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+)
+
+const (
+	target = "gitea"
+
+	metaKeyGiteaId      = "gitea-id"
+	metaKeyGiteaUrl     = "gitea-url"
+	metaKeyGiteaLogin   = "gitea-login"
+	metaKeyGiteaProject = "gitea-project"
+	metaKeyGiteaBaseUrl = "gitea-base-url"
+
+	confKeyOwner        = "owner"
+	confKeyProject      = "project"
+	confKeyBaseUrl      = "base-url"
+	confKeyDefaultLogin = "default-login"
+
+	defaultBaseURL = "https://gitea.com/"
+	defaultTimeout = 60 * time.Second
+)
+
+var _ core.BridgeImpl = &Gitea{}
+
+// Gitea is the bridge implementation for Gitea and its Forgejo fork, which
+// share the same REST API.
+type Gitea struct{}
+
+func (Gitea) Target() string {
+	return target
+}
+
+func (*Gitea) LoginMetaKey() string {
+	return metaKeyGiteaLogin
+}
+
+func (Gitea) NewImporter() core.Importer {
+	return &giteaImporter{}
+}
+
+func (Gitea) NewExporter() core.Exporter {
+	return &giteaExporter{}
+}
+
+func buildClient(transport http.RoundTripper, baseURL string, token *auth.Token) *Client {
+	client := NewClient(baseURL, token.Value)
+	client.client.Transport = transport
+	return client
+}
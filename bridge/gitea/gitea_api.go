@@ -0,0 +1,345 @@
+package gitea
+
+/*
+ * A thin wrapper around the Gitea REST API (v1). Forgejo exposes the same API.
+ * The documentation can be found at:
+ * https://gitea.com/api/swagger
+ */
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// number of items requested per page
+const pageSize = 50
+
+// User describes a Gitea user (an issue author, a comment author, ...)
+type User struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// Repository describes a Gitea repository
+type Repository struct {
+	ID       int64  `json:"id"`
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url"`
+}
+
+// Label describes a Gitea repository label
+type Label struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Issue describes a Gitea issue
+type Issue struct {
+	ID        int64     `json:"id"`
+	Number    int64     `json:"number"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	User      User      `json:"user"`
+	Labels    []Label   `json:"labels"`
+	State     string    `json:"state"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Comment describes a comment on a Gitea issue
+type Comment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	User      User      `json:"user"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Timeline event types we know how to handle
+const (
+	TimelineComment     = "comment"
+	TimelineClose       = "close"
+	TimelineReopen      = "reopen"
+	TimelineLabel       = "label"
+	TimelineChangeTitle = "change_title"
+)
+
+// TimelineEvent describes an entry in the timeline of a Gitea issue. Depending
+// on the type, only some of the fields are set.
+type TimelineEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	Body      string    `json:"body"`
+	User      User      `json:"user"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Label     *Label    `json:"label"`
+	OldTitle  string    `json:"old_title"`
+	NewTitle  string    `json:"new_title"`
+}
+
+// LabelAdded return true if a label event is a label addition. Gitea store "1"
+// in the body of the event for an addition and nothing for a removal.
+func (te TimelineEvent) LabelAdded() bool {
+	return te.Body == "1"
+}
+
+// APIError is returned when the Gitea API answer with an unexpected status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("gitea API error (%d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("gitea API error (%d)", e.StatusCode)
+}
+
+// Client is a minimal Gitea API client authenticated with an access token
+type Client struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewClient return a Client for the Gitea instance at baseURL
+func NewClient(baseURL string, token string) *Client {
+	return &Client{
+		client: &http.Client{
+			Timeout: defaultTimeout,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, in interface{}, out interface{}) error {
+	u := fmt.Sprintf("%s/api/v1%s", c.baseURL, path)
+	if len(query) > 0 {
+		u = u + "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var msg struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&msg)
+		return &APIError{StatusCode: resp.StatusCode, Message: msg.Message}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func repoPath(owner, project string) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(project))
+}
+
+// CurrentUser return the user authenticated by the token
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	err := c.do(ctx, http.MethodGet, "/user", nil, nil, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetRepository return the given repository
+func (c *Client) GetRepository(ctx context.Context, owner, project string) (*Repository, error) {
+	var repo Repository
+	err := c.do(ctx, http.MethodGet, repoPath(owner, project), nil, nil, &repo)
+	if err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// Issues return the issues (pull requests excluded) of a repository updated
+// after the since date, one page at a time.
+func (c *Client) Issues(ctx context.Context, owner, project string, since time.Time, page int) ([]Issue, error) {
+	query := url.Values{}
+	query.Set("state", "all")
+	query.Set("type", "issues")
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(pageSize))
+	if !since.IsZero() {
+		query.Set("since", since.Format(time.RFC3339))
+	}
+
+	var issues []Issue
+	err := c.do(ctx, http.MethodGet, repoPath(owner, project)+"/issues", query, nil, &issues)
+	return issues, err
+}
+
+// Timeline return the timeline of an issue, one page at a time.
+func (c *Client) Timeline(ctx context.Context, owner, project string, number int64, page int) ([]TimelineEvent, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(pageSize))
+
+	var events []TimelineEvent
+	path := fmt.Sprintf("%s/issues/%d/timeline", repoPath(owner, project), number)
+	err := c.do(ctx, http.MethodGet, path, query, nil, &events)
+	return events, err
+}
+
+// CreateIssue create a new issue
+func (c *Client) CreateIssue(ctx context.Context, owner, project, title, body string) (*Issue, error) {
+	in := map[string]interface{}{
+		"title": title,
+		"body":  body,
+	}
+
+	var issue Issue
+	err := c.do(ctx, http.MethodPost, repoPath(owner, project)+"/issues", nil, in, &issue)
+	if err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// EditIssue update the given fields of an issue. Valid fields are "title",
+// "body" and "state".
+func (c *Client) EditIssue(ctx context.Context, owner, project string, number int64, fields map[string]string) (*Issue, error) {
+	var issue Issue
+	path := fmt.Sprintf("%s/issues/%d", repoPath(owner, project), number)
+	err := c.do(ctx, http.MethodPatch, path, nil, fields, &issue)
+	if err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// CreateComment add a comment to an issue
+func (c *Client) CreateComment(ctx context.Context, owner, project string, number int64, body string) (*Comment, error) {
+	in := map[string]string{
+		"body": body,
+	}
+
+	var comment Comment
+	path := fmt.Sprintf("%s/issues/%d/comments", repoPath(owner, project), number)
+	err := c.do(ctx, http.MethodPost, path, nil, in, &comment)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// EditComment update the body of a comment
+func (c *Client) EditComment(ctx context.Context, owner, project string, commentID int64, body string) (*Comment, error) {
+	in := map[string]string{
+		"body": body,
+	}
+
+	var comment Comment
+	path := fmt.Sprintf("%s/issues/comments/%d", repoPath(owner, project), commentID)
+	err := c.do(ctx, http.MethodPatch, path, nil, in, &comment)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Labels return all the labels defined in a repository
+func (c *Client) Labels(ctx context.Context, owner, project string) ([]Label, error) {
+	var result []Label
+
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(pageSize))
+
+		var labels []Label
+		err := c.do(ctx, http.MethodGet, repoPath(owner, project)+"/labels", query, nil, &labels)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, labels...)
+
+		if len(labels) < pageSize {
+			return result, nil
+		}
+	}
+}
+
+// CreateLabel create a new label in a repository. The color is an hexadecimal
+// RGB color, like "ff0000".
+func (c *Client) CreateLabel(ctx context.Context, owner, project, name, color string) (*Label, error) {
+	in := map[string]string{
+		"name":  name,
+		"color": "#" + color,
+	}
+
+	var label Label
+	err := c.do(ctx, http.MethodPost, repoPath(owner, project)+"/labels", nil, in, &label)
+	if err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// AddIssueLabels add the given labels to an issue
+func (c *Client) AddIssueLabels(ctx context.Context, owner, project string, number int64, labelIDs []int64) error {
+	in := map[string][]int64{
+		"labels": labelIDs,
+	}
+
+	path := fmt.Sprintf("%s/issues/%d/labels", repoPath(owner, project), number)
+	return c.do(ctx, http.MethodPost, path, nil, in, nil)
+}
+
+// RemoveIssueLabel remove a label from an issue
+func (c *Client) RemoveIssueLabel(ctx context.Context, owner, project string, number int64, labelID int64) error {
+	path := fmt.Sprintf("%s/issues/%d/labels/%d", repoPath(owner, project), number, labelID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
@@ -0,0 +1,432 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// giteaImporter implement the Importer interface
+type giteaImporter struct {
+	conf core.Configuration
+
+	// label and status mapping
+	mapping *core.Mapping
+
+	// default client
+	client *Client
+
+	// send only channel
+	out chan<- core.ImportResult
+}
+
+func (gi *giteaImporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
+	gi.conf = conf
+
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	gi.mapping = mapping
+
+	creds, err := auth.List(repo,
+		auth.WithTarget(target),
+		auth.WithKind(auth.KindToken),
+		auth.WithMeta(auth.MetaKeyBaseURL, conf[confKeyBaseUrl]),
+		auth.WithMeta(auth.MetaKeyLogin, conf[confKeyDefaultLogin]),
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(creds) == 0 {
+		return ErrMissingIdentityToken
+	}
+
+	gi.client = buildClient(core.HTTPTransport(ctx), conf[confKeyBaseUrl], creds[0].(*auth.Token))
+
+	return nil
+}
+
+// ImportAll iterate over all the configured repository issues and ensure the creation
+// of the missing issues / comments / label events / title changes ...
+func (gi *giteaImporter) ImportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ImportResult, error) {
+	out := make(chan core.ImportResult)
+	gi.out = out
+
+	go func() {
+		defer close(out)
+
+		owner := gi.conf[confKeyOwner]
+		project := gi.conf[confKeyProject]
+
+		for page := 1; ; page++ {
+			issues, err := gi.client.Issues(ctx, owner, project, since, page)
+			if err != nil {
+				out <- core.NewImportError(err, "")
+				return
+			}
+
+			for _, issue := range issues {
+				select {
+				case <-ctx.Done():
+					out <- core.NewImportError(ctx.Err(), "")
+					return
+				default:
+				}
+
+				if err := gi.importIssue(ctx, repo, issue); err != nil {
+					out <- core.NewImportError(err, "")
+					return
+				}
+			}
+
+			if len(issues) < pageSize {
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (gi *giteaImporter) importIssue(ctx context.Context, repo *cache.RepoCache, issue Issue) error {
+	var timeline []TimelineEvent
+
+	for page := 1; ; page++ {
+		events, err := gi.client.Timeline(ctx, gi.conf[confKeyOwner], gi.conf[confKeyProject], issue.Number, page)
+		if err != nil {
+			return err
+		}
+
+		timeline = append(timeline, events...)
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	// the issue only hold the current title, the original one need to be
+	// found in the title changes
+	title := issue.Title
+	for _, event := range timeline {
+		if event.Type == TimelineChangeTitle {
+			title = event.OldTitle
+			break
+		}
+	}
+
+	b, err := gi.ensureIssue(repo, issue, title)
+	if err != nil {
+		return fmt.Errorf("issue creation: %v", err)
+	}
+
+	// collect the remote ids of all the operations already imported or exported
+	known := knownGiteaIds(b)
+
+	for _, event := range timeline {
+		if err := gi.ensureTimelineEvent(repo, b, known, event); err != nil {
+			err := fmt.Errorf("issue event creation: %v", err)
+			gi.out <- core.NewImportError(err, entity.Id(strconv.FormatInt(event.ID, 10)))
+		}
+	}
+
+	// Gitea doesn't record the edition of the issue description in the
+	// timeline, so we compare with the current one instead.
+	if err := gi.ensureIssueBody(repo, b, issue); err != nil {
+		return fmt.Errorf("issue edition: %v", err)
+	}
+
+	if !b.NeedCommit() {
+		gi.out <- core.NewImportNothing(b.Id(), "no imported operation")
+	} else if err := b.Commit(); err != nil {
+		return fmt.Errorf("bug commit: %v", err)
+	}
+
+	return nil
+}
+
+func (gi *giteaImporter) ensureIssue(repo *cache.RepoCache, issue Issue, title string) (*cache.BugCache, error) {
+	// ensure issue author
+	author, err := gi.ensurePerson(repo, issue.User)
+	if err != nil {
+		return nil, err
+	}
+
+	project := fmt.Sprintf("%s/%s", gi.conf[confKeyOwner], gi.conf[confKeyProject])
+	issueId := strconv.FormatInt(issue.Number, 10)
+
+	// resolve bug
+	b, err := repo.Bugs().ResolveMatcher(func(excerpt *cache.BugExcerpt) bool {
+		return excerpt.CreateMetadata[core.MetaKeyOrigin] == target &&
+			excerpt.CreateMetadata[metaKeyGiteaId] == issueId &&
+			excerpt.CreateMetadata[metaKeyGiteaBaseUrl] == gi.conf[confKeyBaseUrl] &&
+			excerpt.CreateMetadata[metaKeyGiteaProject] == project
+	})
+	if err == nil {
+		return b, nil
+	}
+	if !entity.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	// if bug was never imported, create bug
+	b, _, err = repo.Bugs().NewRaw(
+		author,
+		issue.CreatedAt.Unix(),
+		text.CleanupOneLine(title),
+		text.Cleanup(issue.Body),
+		nil,
+		map[string]string{
+			core.MetaKeyOrigin:  target,
+			metaKeyGiteaId:      issueId,
+			metaKeyGiteaUrl:     issue.HTMLURL,
+			metaKeyGiteaProject: project,
+			metaKeyGiteaBaseUrl: gi.conf[confKeyBaseUrl],
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// importing a new bug
+	gi.out <- core.NewImportBug(b.Id())
+
+	return b, nil
+}
+
+func (gi *giteaImporter) ensureIssueBody(repo *cache.RepoCache, b *cache.BugCache, issue Issue) error {
+	firstComment := b.Snapshot().Comments[0]
+
+	cleanedBody := text.Cleanup(issue.Body)
+	if cleanedBody == firstComment.Message {
+		return nil
+	}
+
+	author, err := gi.ensurePerson(repo, issue.User)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.EditCommentRaw(
+		author,
+		issue.UpdatedAt.Unix(),
+		firstComment.CombinedId(),
+		cleanedBody,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	gi.out <- core.NewImportCommentEdition(b.Id(), firstComment.CombinedId())
+	return nil
+}
+
+func (gi *giteaImporter) ensureTimelineEvent(repo *cache.RepoCache, b *cache.BugCache, known map[string]struct{}, event TimelineEvent) error {
+	eventId := strconv.FormatInt(event.ID, 10)
+	_, alreadyKnown := known[eventId]
+
+	switch event.Type {
+	case TimelineComment:
+		return gi.ensureComment(repo, b, alreadyKnown, event)
+
+	case TimelineClose, TimelineReopen, TimelineChangeTitle, TimelineLabel:
+		if alreadyKnown {
+			return nil
+		}
+
+	default:
+		// other events (assignees, milestones, references ...) are not supported
+		return nil
+	}
+
+	author, err := gi.ensurePerson(repo, event.User)
+	if err != nil {
+		return err
+	}
+
+	metadata := map[string]string{
+		metaKeyGiteaId: eventId,
+	}
+
+	switch event.Type {
+	case TimelineClose, TimelineReopen:
+		state, fallback := "closed", common.ClosedStatus
+		if event.Type == TimelineReopen {
+			state, fallback = "open", common.OpenStatus
+		}
+
+		labelsMetadata := map[string]string{
+			metaKeyGiteaId: core.StatusLabelsId(eventId),
+		}
+
+		op, labelOp, err := gi.mapping.ApplyStatus(b, author, event.CreatedAt.Unix(), state, fallback, metadata, labelsMetadata)
+		if err != nil {
+			return err
+		}
+		gi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+		if labelOp != nil {
+			gi.out <- core.NewImportLabelChange(b.Id(), labelOp.Id())
+		}
+
+	case TimelineChangeTitle:
+		op, err := b.SetTitleRaw(author, event.CreatedAt.Unix(), text.CleanupOneLine(event.NewTitle), metadata)
+		if err != nil {
+			return err
+		}
+		gi.out <- core.NewImportTitleEdition(b.Id(), op.Id())
+
+	case TimelineLabel:
+		if event.Label == nil {
+			return nil
+		}
+
+		label, ok := gi.mapping.ImportLabel(text.CleanupOneLine(event.Label.Name))
+		if !ok {
+			return nil
+		}
+
+		var added, removed []string
+		if event.LabelAdded() {
+			added = []string{label}
+		} else {
+			removed = []string{label}
+		}
+
+		op, err := b.ForceChangeLabelsRaw(author, event.CreatedAt.Unix(), added, removed, metadata)
+		if err != nil {
+			return err
+		}
+		gi.out <- core.NewImportLabelChange(b.Id(), op.Id())
+	}
+
+	known[eventId] = struct{}{}
+	return nil
+}
+
+func (gi *giteaImporter) ensureComment(repo *cache.RepoCache, b *cache.BugCache, alreadyKnown bool, event TimelineEvent) error {
+	eventId := strconv.FormatInt(event.ID, 10)
+	cleanText := text.Cleanup(event.Body)
+
+	author, err := gi.ensurePerson(repo, event.User)
+	if err != nil {
+		return err
+	}
+
+	// if we didn't import the comment
+	if !alreadyKnown {
+		commentId, _, err := b.AddCommentRaw(
+			author,
+			event.CreatedAt.Unix(),
+			cleanText,
+			nil,
+			map[string]string{
+				metaKeyGiteaId:  eventId,
+				metaKeyGiteaUrl: event.HTMLURL,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		gi.out <- core.NewImportComment(b.Id(), commentId)
+		return nil
+	}
+
+	// if comment was already imported or exported
+
+	id, err := b.ResolveOperationWithMetadata(metaKeyGiteaId, eventId)
+	if err != nil {
+		return err
+	}
+
+	// search for last comment update
+	comment, err := b.Snapshot().SearchCommentByOpId(id)
+	if err != nil {
+		return err
+	}
+
+	// compare local bug comment with the new event body
+	if comment.Message != cleanText {
+		_, err := b.EditCommentRaw(
+			author,
+			event.UpdatedAt.Unix(),
+			comment.CombinedId(),
+			cleanText,
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+
+		gi.out <- core.NewImportCommentEdition(b.Id(), comment.CombinedId())
+	}
+
+	return nil
+}
+
+func (gi *giteaImporter) ensurePerson(repo *cache.RepoCache, user User) (*cache.IdentityCache, error) {
+	// Look first in the cache
+	i, err := repo.Identities().ResolveIdentityImmutableMetadata(metaKeyGiteaLogin, user.Login)
+	if err == nil {
+		return i, nil
+	}
+	if entity.IsErrMultipleMatch(err) {
+		return nil, err
+	}
+
+	name := user.FullName
+	if name == "" {
+		name = user.Login
+	}
+
+	i, err = repo.Identities().NewRaw(
+		name,
+		user.Email,
+		user.Login,
+		user.AvatarURL,
+		nil,
+		map[string]string{
+			metaKeyGiteaLogin: user.Login,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	gi.out <- core.NewImportIdentity(i.Id())
+	return i, nil
+}
+
+// knownGiteaIds return the set of the Gitea timeline ids already attached to
+// the operations of a bug, either by an import or an export. As a single
+// exported label change can result in multiple events on Gitea, the value can
+// hold a comma separated list of ids.
+func knownGiteaIds(b *cache.BugCache) map[string]struct{} {
+	result := make(map[string]struct{})
+
+	// the create operation hold the issue number, not a timeline id
+	for _, op := range b.Snapshot().Operations[1:] {
+		value, ok := op.GetMetadata(metaKeyGiteaId)
+		if !ok {
+			continue
+		}
+		for _, id := range strings.Split(value, ",") {
+			if id != "" {
+				result[id] = struct{}{}
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,210 @@
+package gitea
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestGiteaImport(t *testing.T) {
+	server := newFakeGitea(t, "git-bug", "test")
+	server.addUser("alice-token", "alice", "Alice")
+
+	ctx := context.Background()
+	remote := NewClient(server.URL, "alice-token")
+
+	// simple issue
+	simple, err := remote.CreateIssue(ctx, "git-bug", "test", "simple issue", "initial comment")
+	require.NoError(t, err)
+	_, err = remote.CreateComment(ctx, "git-bug", "test", simple.Number, "first comment")
+	require.NoError(t, err)
+	_, err = remote.CreateComment(ctx, "git-bug", "test", simple.Number, "second comment")
+	require.NoError(t, err)
+
+	// complex issue
+	complexIssue, err := remote.CreateIssue(ctx, "git-bug", "test", "complex issue", "initial comment")
+	require.NoError(t, err)
+	_, err = remote.EditIssue(ctx, "git-bug", "test", complexIssue.Number, map[string]string{"title": "complex issue edited"})
+	require.NoError(t, err)
+	_, err = remote.EditIssue(ctx, "git-bug", "test", complexIssue.Number, map[string]string{"state": "closed"})
+	require.NoError(t, err)
+	_, err = remote.EditIssue(ctx, "git-bug", "test", complexIssue.Number, map[string]string{"state": "open"})
+	require.NoError(t, err)
+	bugLabel, err := remote.CreateLabel(ctx, "git-bug", "test", "bug", "ff0000")
+	require.NoError(t, err)
+	criticalLabel, err := remote.CreateLabel(ctx, "git-bug", "test", "critical", "00ff00")
+	require.NoError(t, err)
+	err = remote.AddIssueLabels(ctx, "git-bug", "test", complexIssue.Number, []int64{bugLabel.ID, criticalLabel.ID})
+	require.NoError(t, err)
+	err = remote.RemoveIssueLabel(ctx, "git-bug", "test", complexIssue.Number, criticalLabel.ID)
+	require.NoError(t, err)
+
+	// editions
+	editions, err := remote.CreateIssue(ctx, "git-bug", "test", "editions", "initial comment")
+	require.NoError(t, err)
+	comment, err := remote.CreateComment(ctx, "git-bug", "test", editions.Number, "first comment")
+	require.NoError(t, err)
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	author, err := identity.NewIdentity(repo, "Alice", "alice@example.com")
+	require.NoError(t, err)
+
+	token := auth.NewToken(target, "alice-token")
+	token.SetMetadata(auth.MetaKeyLogin, "alice")
+	token.SetMetadata(auth.MetaKeyBaseURL, server.URL)
+	err = auth.Store(repo, token)
+	require.NoError(t, err)
+
+	conf := core.Configuration{
+		confKeyBaseUrl:      server.URL,
+		confKeyOwner:        "git-bug",
+		confKeyProject:      "test",
+		confKeyDefaultLogin: "alice",
+	}
+
+	runImport := func() []core.ImportResult {
+		importer := &giteaImporter{}
+		err := importer.Init(ctx, backend, conf)
+		require.NoError(t, err)
+
+		events, err := importer.ImportAll(ctx, backend, time.Time{})
+		require.NoError(t, err)
+
+		var results []core.ImportResult
+		for result := range events {
+			require.NoError(t, result.Err)
+			results = append(results, result)
+		}
+		return results
+	}
+
+	runImport()
+
+	tests := []struct {
+		name string
+		url  string
+		bug  *bug.Snapshot
+	}{
+		{
+			name: "simple issue",
+			url:  simple.HTMLURL,
+			bug: &bug.Snapshot{
+				Operations: []dag.Operation{
+					bug.NewCreateOp(author, 0, "simple issue", "initial comment", nil),
+					bug.NewAddCommentOp(author, 0, "first comment", nil),
+					bug.NewAddCommentOp(author, 0, "second comment", nil),
+				},
+			},
+		},
+		{
+			name: "complex issue",
+			url:  complexIssue.HTMLURL,
+			bug: &bug.Snapshot{
+				Operations: []dag.Operation{
+					bug.NewCreateOp(author, 0, "complex issue", "initial comment", nil),
+					bug.NewSetTitleOp(author, 0, "complex issue edited", "complex issue"),
+					bug.NewSetStatusOp(author, 0, common.ClosedStatus),
+					bug.NewSetStatusOp(author, 0, common.OpenStatus),
+					bug.NewLabelChangeOperation(author, 0, []bug.Label{"bug"}, []bug.Label{}),
+					bug.NewLabelChangeOperation(author, 0, []bug.Label{"critical"}, []bug.Label{}),
+					bug.NewLabelChangeOperation(author, 0, []bug.Label{}, []bug.Label{"critical"}),
+				},
+			},
+		},
+		{
+			name: "editions",
+			url:  editions.HTMLURL,
+			bug: &bug.Snapshot{
+				Operations: []dag.Operation{
+					bug.NewCreateOp(author, 0, "editions", "initial comment", nil),
+					bug.NewAddCommentOp(author, 0, "first comment", nil),
+				},
+			},
+		},
+	}
+
+	require.Len(t, backend.Bugs().AllIds(), len(tests))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := backend.Bugs().ResolveBugCreateMetadata(metaKeyGiteaUrl, tt.url)
+			require.NoError(t, err)
+
+			ops := b.Snapshot().Operations
+			requireSameOperations(t, tt.bug.Operations, ops)
+		})
+	}
+
+	// a second import doesn't do anything
+	for _, result := range runImport() {
+		require.Equal(t, core.ImportEventNothing, result.Event)
+	}
+
+	// remote editions are imported
+	_, err = remote.EditIssue(ctx, "git-bug", "test", editions.Number, map[string]string{"body": "initial comment edited"})
+	require.NoError(t, err)
+	_, err = remote.EditComment(ctx, "git-bug", "test", comment.ID, "first comment edited")
+	require.NoError(t, err)
+
+	runImport()
+
+	b, err := backend.Bugs().ResolveBugCreateMetadata(metaKeyGiteaUrl, editions.HTMLURL)
+	require.NoError(t, err)
+
+	requireSameOperations(t, []dag.Operation{
+		bug.NewCreateOp(author, 0, "editions", "initial comment", nil),
+		bug.NewAddCommentOp(author, 0, "first comment", nil),
+		bug.NewEditCommentOp(author, 0, "", "first comment edited", nil),
+		bug.NewEditCommentOp(author, 0, "", "initial comment edited", nil),
+	}, b.Snapshot().Operations)
+	require.Equal(t, "initial comment edited", b.Snapshot().Comments[0].Message)
+	require.Equal(t, "first comment edited", b.Snapshot().Comments[1].Message)
+}
+
+func requireSameOperations(t *testing.T, expected []dag.Operation, actual []dag.Operation) {
+	t.Helper()
+
+	require.Len(t, actual, len(expected))
+
+	for i, op := range expected {
+		require.IsType(t, op, actual[i])
+		require.Equal(t, op.Author().Name(), actual[i].Author().Name())
+
+		switch op := op.(type) {
+		case *bug.CreateOperation:
+			require.Equal(t, op.Title, actual[i].(*bug.CreateOperation).Title)
+			require.Equal(t, op.Message, actual[i].(*bug.CreateOperation).Message)
+		case *bug.SetStatusOperation:
+			require.Equal(t, op.Status, actual[i].(*bug.SetStatusOperation).Status)
+		case *bug.SetTitleOperation:
+			require.Equal(t, op.Was, actual[i].(*bug.SetTitleOperation).Was)
+			require.Equal(t, op.Title, actual[i].(*bug.SetTitleOperation).Title)
+		case *bug.LabelChangeOperation:
+			require.ElementsMatch(t, op.Added, actual[i].(*bug.LabelChangeOperation).Added)
+			require.ElementsMatch(t, op.Removed, actual[i].(*bug.LabelChangeOperation).Removed)
+		case *bug.AddCommentOperation:
+			require.Equal(t, op.Message, actual[i].(*bug.AddCommentOperation).Message)
+		case *bug.EditCommentOperation:
+			require.Equal(t, op.Message, actual[i].(*bug.EditCommentOperation).Message)
+
+		default:
+			panic("unknown operation type")
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGitea is an in-memory stand-in of the subset of the Gitea API used by
+// the bridge.
+type fakeGitea struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	owner  string
+	repo   string
+	users  map[string]User // token --> user
+	issues []*fakeIssue
+	labels []Label
+	lastId int64
+	now    time.Time
+}
+
+type fakeIssue struct {
+	Issue
+	comments []*Comment
+	timeline []TimelineEvent
+}
+
+func newFakeGitea(t *testing.T, owner, repo string) *fakeGitea {
+	f := &fakeGitea{
+		owner: owner,
+		repo:  repo,
+		users: make(map[string]User),
+		now:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	prefix := fmt.Sprintf("/api/v1/repos/%s/%s", owner, repo)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/user", f.auth(f.handleUser))
+	mux.HandleFunc("GET "+prefix, f.auth(f.handleRepo))
+	mux.HandleFunc("GET "+prefix+"/issues", f.auth(f.handleListIssues))
+	mux.HandleFunc("POST "+prefix+"/issues", f.auth(f.handleCreateIssue))
+	mux.HandleFunc("PATCH "+prefix+"/issues/{index}", f.auth(f.handleEditIssue))
+	mux.HandleFunc("GET "+prefix+"/issues/{index}/timeline", f.auth(f.handleTimeline))
+	mux.HandleFunc("POST "+prefix+"/issues/{index}/comments", f.auth(f.handleCreateComment))
+	mux.HandleFunc("PATCH "+prefix+"/issues/comments/{id}", f.auth(f.handleEditComment))
+	mux.HandleFunc("POST "+prefix+"/issues/{index}/labels", f.auth(f.handleAddLabels))
+	mux.HandleFunc("DELETE "+prefix+"/issues/{index}/labels/{id}", f.auth(f.handleRemoveLabel))
+	mux.HandleFunc("GET "+prefix+"/labels", f.auth(f.handleListLabels))
+	mux.HandleFunc("POST "+prefix+"/labels", f.auth(f.handleCreateLabel))
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// addUser register a user with the given access token
+func (f *fakeGitea) addUser(token string, login string, name string) User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	user := User{
+		ID:       int64(len(f.users) + 1),
+		Login:    login,
+		FullName: name,
+		Email:    login + "@example.com",
+	}
+	f.users[token] = user
+	return user
+}
+
+type userHandler func(w http.ResponseWriter, r *http.Request, user User)
+
+func (f *fakeGitea) auth(handler userHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "token ")
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		user, ok := f.users[token]
+		if !ok {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "token is required"})
+			return
+		}
+
+		handler(w, r, user)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// tick return a strictly increasing time, to have a stable ordering
+func (f *fakeGitea) tick() time.Time {
+	f.now = f.now.Add(time.Minute)
+	return f.now
+}
+
+func (f *fakeGitea) nextId() int64 {
+	f.lastId++
+	return f.lastId
+}
+
+func (f *fakeGitea) issue(w http.ResponseWriter, r *http.Request) *fakeIssue {
+	index, _ := strconv.ParseInt(r.PathValue("index"), 10, 64)
+	for _, issue := range f.issues {
+		if issue.Number == index {
+			return issue
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "issue not found"})
+	return nil
+}
+
+func paginate[T any](r *http.Request, items []T) []T {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 30
+	}
+
+	start := (page - 1) * limit
+	if start >= len(items) {
+		return []T{}
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+func (f *fakeGitea) addEvent(issue *fakeIssue, event TimelineEvent) {
+	event.ID = f.nextId()
+	event.CreatedAt = f.tick()
+	event.UpdatedAt = event.CreatedAt
+	issue.timeline = append(issue.timeline, event)
+	issue.UpdatedAt = event.CreatedAt
+}
+
+func (f *fakeGitea) handleUser(w http.ResponseWriter, _ *http.Request, user User) {
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (f *fakeGitea) handleRepo(w http.ResponseWriter, _ *http.Request, _ User) {
+	writeJSON(w, http.StatusOK, Repository{
+		ID:       1,
+		FullName: f.owner + "/" + f.repo,
+		HTMLURL:  fmt.Sprintf("%s/%s/%s", f.URL, f.owner, f.repo),
+	})
+}
+
+func (f *fakeGitea) handleListIssues(w http.ResponseWriter, r *http.Request, _ User) {
+	var since time.Time
+	if s := r.URL.Query().Get("since"); s != "" {
+		since, _ = time.Parse(time.RFC3339, s)
+	}
+
+	issues := make([]Issue, 0, len(f.issues))
+	for _, issue := range f.issues {
+		if issue.UpdatedAt.Before(since) {
+			continue
+		}
+		issues = append(issues, issue.Issue)
+	}
+
+	writeJSON(w, http.StatusOK, paginate(r, issues))
+}
+
+func (f *fakeGitea) handleCreateIssue(w http.ResponseWriter, r *http.Request, user User) {
+	var in struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&in)
+
+	number := int64(len(f.issues) + 1)
+	issue := &fakeIssue{Issue: Issue{
+		ID:        f.nextId(),
+		Number:    number,
+		Title:     in.Title,
+		Body:      in.Body,
+		User:      user,
+		State:     "open",
+		HTMLURL:   fmt.Sprintf("%s/%s/%s/issues/%d", f.URL, f.owner, f.repo, number),
+		CreatedAt: f.tick(),
+	}}
+	issue.UpdatedAt = issue.CreatedAt
+	f.issues = append(f.issues, issue)
+
+	writeJSON(w, http.StatusCreated, issue.Issue)
+}
+
+func (f *fakeGitea) handleEditIssue(w http.ResponseWriter, r *http.Request, user User) {
+	issue := f.issue(w, r)
+	if issue == nil {
+		return
+	}
+
+	var in map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&in)
+
+	if title, ok := in["title"]; ok && title != issue.Title {
+		f.addEvent(issue, TimelineEvent{Type: TimelineChangeTitle, User: user, OldTitle: issue.Title, NewTitle: title})
+		issue.Title = title
+	}
+	if body, ok := in["body"]; ok {
+		issue.Body = body
+		issue.UpdatedAt = f.tick()
+	}
+	if state, ok := in["state"]; ok && state != issue.State {
+		kind := TimelineReopen
+		if state == "closed" {
+			kind = TimelineClose
+		}
+		f.addEvent(issue, TimelineEvent{Type: kind, User: user})
+		issue.State = state
+	}
+
+	writeJSON(w, http.StatusCreated, issue.Issue)
+}
+
+func (f *fakeGitea) handleTimeline(w http.ResponseWriter, r *http.Request, _ User) {
+	issue := f.issue(w, r)
+	if issue == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, paginate(r, issue.timeline))
+}
+
+func (f *fakeGitea) handleCreateComment(w http.ResponseWriter, r *http.Request, user User) {
+	issue := f.issue(w, r)
+	if issue == nil {
+		return
+	}
+
+	var in struct {
+		Body string `json:"body"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&in)
+
+	f.addEvent(issue, TimelineEvent{Type: TimelineComment, User: user, Body: in.Body})
+	event := &issue.timeline[len(issue.timeline)-1]
+	event.HTMLURL = fmt.Sprintf("%s#issuecomment-%d", issue.HTMLURL, event.ID)
+
+	writeJSON(w, http.StatusCreated, Comment{
+		ID:        event.ID,
+		Body:      event.Body,
+		User:      user,
+		HTMLURL:   event.HTMLURL,
+		CreatedAt: event.CreatedAt,
+		UpdatedAt: event.UpdatedAt,
+	})
+}
+
+func (f *fakeGitea) handleEditComment(w http.ResponseWriter, r *http.Request, user User) {
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+
+	var in struct {
+		Body string `json:"body"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&in)
+
+	for _, issue := range f.issues {
+		for i := range issue.timeline {
+			event := &issue.timeline[i]
+			if event.ID != id || event.Type != TimelineComment {
+				continue
+			}
+			event.Body = in.Body
+			event.UpdatedAt = f.tick()
+			issue.UpdatedAt = event.UpdatedAt
+
+			writeJSON(w, http.StatusOK, Comment{
+				ID:        event.ID,
+				Body:      event.Body,
+				User:      event.User,
+				HTMLURL:   event.HTMLURL,
+				CreatedAt: event.CreatedAt,
+				UpdatedAt: event.UpdatedAt,
+			})
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "comment not found"})
+}
+
+func (f *fakeGitea) findLabel(id int64) (Label, bool) {
+	for _, label := range f.labels {
+		if label.ID == id {
+			return label, true
+		}
+	}
+	return Label{}, false
+}
+
+func (f *fakeGitea) handleAddLabels(w http.ResponseWriter, r *http.Request, user User) {
+	issue := f.issue(w, r)
+	if issue == nil {
+		return
+	}
+
+	var in struct {
+		Labels []int64 `json:"labels"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&in)
+
+	for _, id := range in.Labels {
+		label, ok := f.findLabel(id)
+		if !ok {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "unknown label"})
+			return
+		}
+		if issueHasLabel(issue, id) {
+			continue
+		}
+		issue.Labels = append(issue.Labels, label)
+		f.addEvent(issue, TimelineEvent{Type: TimelineLabel, User: user, Body: "1", Label: &label})
+	}
+
+	writeJSON(w, http.StatusOK, issue.Labels)
+}
+
+func (f *fakeGitea) handleRemoveLabel(w http.ResponseWriter, r *http.Request, user User) {
+	issue := f.issue(w, r)
+	if issue == nil {
+		return
+	}
+
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	label, ok := f.findLabel(id)
+	if !ok || !issueHasLabel(issue, id) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "label not found"})
+		return
+	}
+
+	for i, l := range issue.Labels {
+		if l.ID == id {
+			issue.Labels = append(issue.Labels[:i], issue.Labels[i+1:]...)
+			break
+		}
+	}
+	f.addEvent(issue, TimelineEvent{Type: TimelineLabel, User: user, Label: &label})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func issueHasLabel(issue *fakeIssue, id int64) bool {
+	for _, l := range issue.Labels {
+		if l.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeGitea) handleListLabels(w http.ResponseWriter, r *http.Request, _ User) {
+	writeJSON(w, http.StatusOK, paginate(r, f.labels))
+}
+
+func (f *fakeGitea) handleCreateLabel(w http.ResponseWriter, r *http.Request, _ User) {
+	var in struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&in)
+
+	label := Label{ID: f.nextId(), Name: in.Name, Color: strings.TrimPrefix(in.Color, "#")}
+	f.labels = append(f.labels, label)
+
+	writeJSON(w, http.StatusCreated, label)
+}
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// attachmentRegexp match the urls of the files uploaded to github through the
+// web UI and linked in the text of an issue or comment.
+func attachmentRegexp(owner, project string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(
+		`https://(?:user-images\.githubusercontent\.com/|github\.com/user-attachments/(?:assets|files)/|github\.com/%s/%s/files/)[^\s)"'>\]]+`,
+		regexp.QuoteMeta(owner), regexp.QuoteMeta(project),
+	))
+}
+
+// attachmentUrls return the urls of the files linked in a text, without
+// duplicates.
+func attachmentUrls(re *regexp.Regexp, body string) []string {
+	var result []string
+	seen := make(map[string]struct{})
+	for _, url := range re.FindAllString(body, -1) {
+		if _, ok := seen[url]; ok {
+			continue
+		}
+		seen[url] = struct{}{}
+		result = append(result, url)
+	}
+	return result
+}
+
+// importAttachments download the files linked in a text, to attach them to
+// the imported comment.
+func (gi *githubImporter) importAttachments(ctx context.Context, repo *cache.RepoCache, body string, entityId entity.Id) []bug.Attachment {
+	if gi.attachmentRegexp == nil {
+		return nil
+	}
+	urls := attachmentUrls(gi.attachmentRegexp, body)
+	if len(urls) == 0 {
+		return nil
+	}
+	return core.DownloadAttachments(ctx, repo, gi.httpClient, urls, nil, gi.out, entityId)
+}
+
+// warnAttachments report the attachments that can't be exported, as github
+// doesn't provide an API to upload files.
+func warnAttachments(files []repository.Hash, entityId entity.Id, out chan<- core.ExportResult) {
+	if len(files) == 0 {
+		return
+	}
+	err := fmt.Errorf("github doesn't support uploading files through its API, %d attachment(s) not exported", len(files))
+	out <- core.NewExportWarning(err, entityId)
+}
@@ -0,0 +1,23 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentUrls(t *testing.T) {
+	re := attachmentRegexp("git-bug", "git-bug")
+
+	body := `![image](https://user-images.githubusercontent.com/123/456-789.png)
+see [trace.txt](https://github.com/git-bug/git-bug/files/1234/trace.txt) and
+<img src="https://github.com/user-attachments/assets/0a1b2c3d-4e5f">
+but not https://github.com/other/project/files/1234/trace.txt
+nor [trace.txt](https://github.com/git-bug/git-bug/files/1234/trace.txt) twice`
+
+	require.Equal(t, []string{
+		"https://user-images.githubusercontent.com/123/456-789.png",
+		"https://github.com/git-bug/git-bug/files/1234/trace.txt",
+		"https://github.com/user-attachments/assets/0a1b2c3d-4e5f",
+	}, attachmentUrls(re, body))
+}
@@ -30,12 +30,13 @@ var (
 
 func (g *Github) ValidParams() map[string]interface{} {
 	return map[string]interface{}{
-		"URL":        nil,
-		"Login":      nil,
-		"CredPrefix": nil,
-		"TokenRaw":   nil,
-		"Owner":      nil,
-		"Project":    nil,
+		"URL":          nil,
+		"Login":        nil,
+		"CredPrefix":   nil,
+		"TokenRaw":     nil,
+		"Owner":        nil,
+		"Project":      nil,
+		"PullRequests": nil,
 	}
 }
 
@@ -144,6 +145,9 @@ func (g *Github) Configure(repo *cache.RepoCache, params core.BridgeParams, inte
 	conf[confKeyOwner] = owner
 	conf[confKeyProject] = project
 	conf[confKeyDefaultLogin] = login
+	if params.PullRequests {
+		conf[confKeyImportPullRequests] = "true"
+	}
 
 	err = g.ValidateConfig(conf)
 	if err != nil {
@@ -176,6 +180,9 @@ func (*Github) ValidateConfig(conf core.Configuration) error {
 	if _, ok := conf[confKeyDefaultLogin]; !ok {
 		return fmt.Errorf("missing %s key", confKeyDefaultLogin)
 	}
+	if v, ok := conf[confKeyImportPullRequests]; ok && v != "true" && v != "false" {
+		return fmt.Errorf("invalid %s value, expected true or false: %v", confKeyImportPullRequests, v)
+	}
 
 	return nil
 }
@@ -547,7 +554,7 @@ func getLoginFromToken(token *auth.Token) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
-	client := buildClient(token)
+	client := buildClient(http.DefaultTransport, token)
 
 	var q loginQuery
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/MichaelMure/git-bug/bridge/core"
 	"github.com/MichaelMure/git-bug/bridge/core/auth"
 )
 
@@ -99,6 +100,26 @@ func TestSplitURL(t *testing.T) {
 	}
 }
 
+func TestValidateConfigPullRequests(t *testing.T) {
+	conf := core.Configuration{
+		core.ConfigKeyTarget: target,
+		confKeyOwner:         "MichaelMure",
+		confKeyProject:       "git-bug",
+		confKeyDefaultLogin:  "MichaelMure",
+	}
+	g := &Github{}
+	assert.NoError(t, g.ValidateConfig(conf))
+
+	conf[confKeyImportPullRequests] = "true"
+	assert.NoError(t, g.ValidateConfig(conf))
+
+	conf[confKeyImportPullRequests] = "yes"
+	assert.Error(t, g.ValidateConfig(conf))
+
+	_, ok := g.ValidParams()["PullRequests"]
+	assert.True(t, ok)
+}
+
 func TestValidateUsername(t *testing.T) {
 	if env := os.Getenv("TRAVIS"); env == "true" {
 		t.Skip("Travis environment: avoiding non authenticated requests")
@@ -28,10 +28,23 @@ var (
 	ErrMissingIdentityToken = errors.New("missing identity token")
 )
 
+// dryRunID is the placeholder ID given to the Github entities that would have
+// been created, in dry-run mode
+const dryRunID = "dry-run"
+
 // githubExporter implement the Exporter interface
 type githubExporter struct {
 	conf core.Configuration
 
+	// label and status mapping
+	mapping *core.Mapping
+
+	// what to do when a local change conflicts with a remote one
+	conflictPolicy core.ConflictPolicy
+
+	// if true, don't call the mutating endpoints
+	dryRun bool
+
 	// cache identities clients
 	identityClient map[entity.Id]*rateLimitHandlerClient
 
@@ -55,19 +68,34 @@ type githubExporter struct {
 	// cache labels used to speed up exporting labels events
 	cachedLabels map[string]string
 
+	// transport of the HTTP requests
+	transport http.RoundTripper
+
 	// channel to send export results
 	out chan<- core.ExportResult
 }
 
 // Init .
-func (ge *githubExporter) Init(_ context.Context, repo *cache.RepoCache, conf core.Configuration) error {
+func (ge *githubExporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
 	ge.conf = conf
+	ge.transport = core.HTTPTransport(ctx)
 	ge.identityClient = make(map[entity.Id]*rateLimitHandlerClient)
 	ge.cachedOperationIDs = make(map[entity.Id]string)
 	ge.cachedLabels = make(map[string]string)
 
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	ge.mapping = mapping
+
+	ge.conflictPolicy, err = core.LoadConflictPolicy(conf)
+	if err != nil {
+		return err
+	}
+
 	// preload all clients
-	err := ge.cacheAllClient(repo)
+	err = ge.cacheAllClient(repo)
 	if err != nil {
 		return err
 	}
@@ -75,6 +103,11 @@ func (ge *githubExporter) Init(_ context.Context, repo *cache.RepoCache, conf co
 	return nil
 }
 
+// SetDryRun .
+func (ge *githubExporter) SetDryRun(dryRun bool) {
+	ge.dryRun = dryRun
+}
+
 func (ge *githubExporter) cacheAllClient(repo *cache.RepoCache) error {
 	creds, err := auth.List(repo, auth.WithTarget(target), auth.WithKind(auth.KindToken))
 	if err != nil {
@@ -100,7 +133,7 @@ func (ge *githubExporter) cacheAllClient(repo *cache.RepoCache) error {
 			continue
 		}
 
-		client := buildClient(creds[0].(*auth.Token))
+		client := buildClient(ge.transport, creds[0].(*auth.Token))
 		ge.identityClient[user.Id()] = client
 
 		// assign the default client and token as well
@@ -136,6 +169,7 @@ func (ge *githubExporter) ExportAll(ctx context.Context, repo *cache.RepoCache,
 	// get repository node id
 	ge.repositoryID, err = getRepositoryNodeID(
 		ctx,
+		ge.transport,
 		ge.defaultToken,
 		ge.conf[confKeyOwner],
 		ge.conf[confKeyProject],
@@ -264,6 +298,7 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 		}
 
 		out <- core.NewExportBug(b.Id())
+		warnAttachments(createOp.Files, b.Id(), out)
 
 		// mark bug creation operation as exported
 		if err := markOperationAsExported(b, createOp.Id(), id, url); err != nil {
@@ -306,6 +341,47 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			continue
 		}
 
+		// make sure we don't silently overwrite a change made on Github since
+		// the last synchronization
+		conflict, remoteID, remoteURL, err := ge.detectConflict(ctx, client, snapshot, op, bugGithubID)
+		if err != nil {
+			err := errors.Wrap(err, "checking for conflict")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		if conflict != "" {
+			out <- core.NewExportConflict(b.Id(), fmt.Sprintf("%s (%s)", conflict, ge.conflictPolicy))
+
+			if err := core.RecordConflict(b, op, target, ge.conflictPolicy, conflict); err != nil {
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			switch ge.conflictPolicy {
+			case core.ConflictPolicySkip:
+				continue
+
+			case core.ConflictPolicyFail:
+				out <- core.NewExportError(fmt.Errorf("conflict: %s", conflict), b.Id())
+				return
+
+			case core.ConflictPolicyRemoteWins:
+				// leave Github as is, the remote change will be imported later
+				if err := markOperationAsExported(b, op.Id(), remoteID, remoteURL); err != nil {
+					err := errors.Wrap(err, "marking operation as exported")
+					out <- core.NewExportError(err, b.Id())
+					return
+				}
+				if err := b.CommitAsNeeded(); err != nil {
+					err := errors.Wrap(err, "bug commit")
+					out <- core.NewExportError(err, b.Id())
+					return
+				}
+				continue
+			}
+		}
+
 		var id, url string
 		switch op := op.(type) {
 		case *bug.AddCommentOperation:
@@ -318,6 +394,7 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			}
 
 			out <- core.NewExportComment(b.Id())
+			warnAttachments(op.Files, b.Id(), out)
 
 			// cache comment id
 			ge.cachedOperationIDs[op.Id()] = id
@@ -385,7 +462,7 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			url = bugGithubURL
 
 		case *bug.LabelChangeOperation:
-			if err := ge.updateGithubIssueLabels(ctx, client, bugGithubID, op.Added, op.Removed); err != nil {
+			if err := ge.updateGithubIssueLabels(ctx, client, bugGithubID, ge.mapping.ExportLabels(op.Added), ge.mapping.ExportLabels(op.Removed)); err != nil {
 				err := errors.Wrap(err, "updating labels")
 				out <- core.NewExportError(err, b.Id())
 				return
@@ -422,10 +499,79 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 	}
 }
 
+// detectConflict check if the Github value that an operation is about to
+// overwrite changed since the last synchronization. If so, it returns a
+// description of the conflict along with the ID and URL of the Github entity.
+func (ge *githubExporter) detectConflict(ctx context.Context, gc *rateLimitHandlerClient, snapshot *bug.Snapshot, op dag.Operation, bugGithubID string) (string, string, string, error) {
+	// nothing to compare to for an issue that hasn't really been created
+	if bugGithubID == dryRunID {
+		return "", "", "", nil
+	}
+
+	switch op := op.(type) {
+	case *bug.SetTitleOperation:
+		content, err := ge.getGithubContent(ctx, gc, bugGithubID)
+		if err != nil {
+			return "", "", "", err
+		}
+		issue := content.Node.Issue
+		if !core.RemoteChanged(issue.Title, op.Was, op.Title) {
+			return "", "", "", nil
+		}
+		return fmt.Sprintf("title changed on Github to \"%s\"", issue.Title), issue.ID, issue.URL, nil
+
+	case *bug.EditCommentOperation:
+		expected := core.PreviousCommentMessage(snapshot, op)
+
+		// Since github doesn't consider the issue body as a comment
+		if op.Target == snapshot.Operations[0].Id() {
+			content, err := ge.getGithubContent(ctx, gc, bugGithubID)
+			if err != nil {
+				return "", "", "", err
+			}
+			issue := content.Node.Issue
+			if !core.RemoteChanged(issue.Body, expected, op.Message) {
+				return "", "", "", nil
+			}
+			return "issue description changed on Github", issue.ID, issue.URL, nil
+		}
+
+		commentID, ok := ge.cachedOperationIDs[op.Target]
+		if !ok || commentID == dryRunID {
+			return "", "", "", nil
+		}
+		content, err := ge.getGithubContent(ctx, gc, commentID)
+		if err != nil {
+			return "", "", "", err
+		}
+		comment := content.Node.IssueComment
+		if !core.RemoteChanged(comment.Body, expected, op.Message) {
+			return "", "", "", nil
+		}
+		return "comment changed on Github", comment.ID, comment.URL, nil
+	}
+
+	return "", "", "", nil
+}
+
+// getGithubContent fetch the current text of an issue or a comment
+func (ge *githubExporter) getGithubContent(ctx context.Context, gc *rateLimitHandlerClient, id string) (*contentQuery, error) {
+	q := &contentQuery{}
+	variables := map[string]interface{}{
+		"id": githubv4.ID(id),
+	}
+
+	if err := gc.queryExport(ctx, q, variables, ge.out); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
 // getRepositoryNodeID request github api v3 to get repository node id
-func getRepositoryNodeID(ctx context.Context, token *auth.Token, owner, project string) (string, error) {
+func getRepositoryNodeID(ctx context.Context, transport http.RoundTripper, token *auth.Token, owner, project string) (string, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s", githubV3Url, owner, project)
-	client := &http.Client{}
+	client := &http.Client{Transport: transport}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -520,8 +666,12 @@ func (ge *githubExporter) getLabelID(label string) (string, error) {
 // NOTE: since createLabel mutation is still in preview mode we use github api v3 to create labels
 // see https://developer.github.com/v4/mutation/createlabel/ and https://developer.github.com/v4/previews/#labels-preview
 func (ge *githubExporter) createGithubLabel(ctx context.Context, label, color string) (string, error) {
+	if ge.dryRun {
+		return dryRunID, nil
+	}
+
 	url := fmt.Sprintf("%s/repos/%s/%s/labels", githubV3Url, ge.conf[confKeyOwner], ge.conf[confKeyProject])
-	client := &http.Client{}
+	client := &http.Client{Transport: ge.transport}
 
 	params := struct {
 		Name        string `json:"name"`
@@ -643,6 +793,10 @@ func (ge *githubExporter) getLabelsIDs(ctx context.Context, gc *rateLimitHandler
 
 // create a github issue and return it ID
 func (ge *githubExporter) createGithubIssue(ctx context.Context, gc *rateLimitHandlerClient, repositoryID, title, body string) (string, string, error) {
+	if ge.dryRun {
+		return dryRunID, "", nil
+	}
+
 	m := &createIssueMutation{}
 	input := githubv4.CreateIssueInput{
 		RepositoryID: repositoryID,
@@ -660,6 +814,10 @@ func (ge *githubExporter) createGithubIssue(ctx context.Context, gc *rateLimitHa
 
 // add a comment to an issue and return its ID
 func (ge *githubExporter) addCommentGithubIssue(ctx context.Context, gc *rateLimitHandlerClient, subjectID string, body string) (string, string, error) {
+	if ge.dryRun {
+		return dryRunID, "", nil
+	}
+
 	m := &addCommentToIssueMutation{}
 	input := githubv4.AddCommentInput{
 		SubjectID: subjectID,
@@ -675,6 +833,10 @@ func (ge *githubExporter) addCommentGithubIssue(ctx context.Context, gc *rateLim
 }
 
 func (ge *githubExporter) editCommentGithubIssue(ctx context.Context, gc *rateLimitHandlerClient, commentID, body string) (string, string, error) {
+	if ge.dryRun {
+		return commentID, "", nil
+	}
+
 	m := &updateIssueCommentMutation{}
 	input := githubv4.UpdateIssueCommentInput{
 		ID:   commentID,
@@ -689,6 +851,10 @@ func (ge *githubExporter) editCommentGithubIssue(ctx context.Context, gc *rateLi
 }
 
 func (ge *githubExporter) updateGithubIssueStatus(ctx context.Context, gc *rateLimitHandlerClient, id string, status common.Status) error {
+	if ge.dryRun {
+		return nil
+	}
+
 	m := &updateIssueMutation{}
 
 	// set state
@@ -716,6 +882,10 @@ func (ge *githubExporter) updateGithubIssueStatus(ctx context.Context, gc *rateL
 }
 
 func (ge *githubExporter) updateGithubIssueBody(ctx context.Context, gc *rateLimitHandlerClient, id string, body string) error {
+	if ge.dryRun {
+		return nil
+	}
+
 	m := &updateIssueMutation{}
 	input := githubv4.UpdateIssueInput{
 		ID:   id,
@@ -730,6 +900,10 @@ func (ge *githubExporter) updateGithubIssueBody(ctx context.Context, gc *rateLim
 }
 
 func (ge *githubExporter) updateGithubIssueTitle(ctx context.Context, gc *rateLimitHandlerClient, id, title string) error {
+	if ge.dryRun {
+		return nil
+	}
+
 	m := &updateIssueMutation{}
 	input := githubv4.UpdateIssueInput{
 		ID:    id,
@@ -745,6 +919,9 @@ func (ge *githubExporter) updateGithubIssueTitle(ctx context.Context, gc *rateLi
 
 // update github issue labels
 func (ge *githubExporter) updateGithubIssueLabels(ctx context.Context, gc *rateLimitHandlerClient, labelableID string, added, removed []bug.Label) error {
+	if ge.dryRun {
+		return nil
+	}
 
 	wg, ctx := errgroup.WithContext(ctx)
 	if len(added) > 0 {
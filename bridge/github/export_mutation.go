@@ -1,5 +1,23 @@
 package github
 
+// contentQuery fetch the current text of an issue or a comment, to detect
+// conflicting changes before overwriting them
+type contentQuery struct {
+	Node struct {
+		Issue struct {
+			ID    string `graphql:"id"`
+			URL   string `graphql:"url"`
+			Title string `graphql:"title"`
+			Body  string `graphql:"body"`
+		} `graphql:"... on Issue"`
+		IssueComment struct {
+			ID   string `graphql:"id"`
+			URL  string `graphql:"url"`
+			Body string `graphql:"body"`
+		} `graphql:"... on IssueComment"`
+	} `graphql:"node(id: $id)"`
+}
+
 type createIssueMutation struct {
 	CreateIssue struct {
 		Issue struct {
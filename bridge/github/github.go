@@ -3,6 +3,7 @@ package github
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -21,6 +22,8 @@ const (
 	confKeyOwner        = "owner"
 	confKeyProject      = "project"
 	confKeyDefaultLogin = "default-login"
+	// confKeyImportPullRequests, if "true", enable the import of the pull requests
+	confKeyImportPullRequests = "import-pull-requests"
 
 	githubV3Url    = "https://api.github.com"
 	defaultTimeout = 60 * time.Second
@@ -46,10 +49,14 @@ func (*Github) NewExporter() core.Exporter {
 	return &githubExporter{}
 }
 
-func buildClient(token *auth.Token) *rateLimitHandlerClient {
+func buildClient(transport http.RoundTripper, token *auth.Token) *rateLimitHandlerClient {
+	return newRateLimitHandlerClient(buildHttpClient(transport, token))
+}
+
+func buildHttpClient(transport http.RoundTripper, token *auth.Token) *http.Client {
 	src := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token.Value},
 	)
-	httpClient := oauth2.NewClient(context.TODO(), src)
-	return newRateLimitHandlerClient(httpClient)
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: transport})
+	return oauth2.NewClient(ctx, src)
 }
@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/shurcooL/githubv4"
@@ -10,6 +12,8 @@ import (
 	"github.com/MichaelMure/git-bug/bridge/core"
 	"github.com/MichaelMure/git-bug/bridge/core/auth"
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/util/text"
 )
@@ -20,9 +24,16 @@ const EmptyTitlePlaceholder = "<empty string>"
 type githubImporter struct {
 	conf core.Configuration
 
+	// label and status mapping
+	mapping *core.Mapping
+
 	// default client
 	client *rateLimitHandlerClient
 
+	// client and pattern used to download the attachments
+	httpClient       *http.Client
+	attachmentRegexp *regexp.Regexp
+
 	// mediator to access the Github API
 	mediator *importMediator
 
@@ -30,8 +41,15 @@ type githubImporter struct {
 	out chan<- core.ImportResult
 }
 
-func (gi *githubImporter) Init(_ context.Context, repo *cache.RepoCache, conf core.Configuration) error {
+func (gi *githubImporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
 	gi.conf = conf
+
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	gi.mapping = mapping
+
 	creds, err := auth.List(repo,
 		auth.WithTarget(target),
 		auth.WithKind(auth.KindToken),
@@ -43,7 +61,10 @@ func (gi *githubImporter) Init(_ context.Context, repo *cache.RepoCache, conf co
 	if len(creds) <= 0 {
 		return ErrMissingIdentityToken
 	}
-	gi.client = buildClient(creds[0].(*auth.Token))
+	transport := core.HTTPTransport(ctx)
+	gi.client = buildClient(transport, creds[0].(*auth.Token))
+	gi.httpClient = buildHttpClient(transport, creds[0].(*auth.Token))
+	gi.attachmentRegexp = attachmentRegexp(conf[confKeyOwner], conf[confKeyProject])
 
 	return nil
 }
@@ -51,101 +72,121 @@ func (gi *githubImporter) Init(_ context.Context, repo *cache.RepoCache, conf co
 // ImportAll iterate over all the configured repository issues and ensure the creation of the
 // missing issues / timeline items / edits / label events ...
 func (gi *githubImporter) ImportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ImportResult, error) {
-	gi.mediator = NewImportMediator(ctx, gi.client, gi.conf[confKeyOwner], gi.conf[confKeyProject], since)
+	importPullRequests := gi.conf[confKeyImportPullRequests] == "true"
+	gi.mediator = NewImportMediator(ctx, gi.client, gi.conf[confKeyOwner], gi.conf[confKeyProject], since, importPullRequests)
 	out := make(chan core.ImportResult)
 	gi.out = out
 
 	go func() {
 		defer close(gi.out)
-		var currBug *cache.BugCache
-		var currEvent ImportEvent
-		var nextEvent ImportEvent
-		var err error
-		for {
-			// An IssueEvent contains the issue in its most recent state. If an issue
-			// has at least one issue edit, then the history of the issue edits is
-			// represented by IssueEditEvents. That is, the unedited (original) issue
-			// might be saved only in the IssueEditEvent following the IssueEvent.
-			// Since we replicate the edit history we need to either use the IssueEvent
-			// (if there are no edits) or the IssueEvent together with its first
-			// IssueEditEvent (if there are edits).
-			// Exactly the same is true for comments and comment edits.
-			// As a consequence we need to look at the current event and one look ahead
-			// event.
-
-			currEvent = nextEvent
-			if currEvent == nil {
-				currEvent = gi.getEventHandleMsgs()
-			}
-			if currEvent == nil {
-				break
+		gi.importMediatorEvents(ctx, repo, out)
+	}()
+
+	return out, nil
+}
+
+// importMediatorEvents consume all the events produced by the mediator and
+// ensure the creation of the corresponding bugs and operations
+func (gi *githubImporter) importMediatorEvents(ctx context.Context, repo *cache.RepoCache, out chan<- core.ImportResult) {
+	var currBug *cache.BugCache
+	var currEvent ImportEvent
+	var nextEvent ImportEvent
+	var err error
+	for {
+		// An IssueEvent contains the issue in its most recent state. If an issue
+		// has at least one issue edit, then the history of the issue edits is
+		// represented by IssueEditEvents. That is, the unedited (original) issue
+		// might be saved only in the IssueEditEvent following the IssueEvent.
+		// Since we replicate the edit history we need to either use the IssueEvent
+		// (if there are no edits) or the IssueEvent together with its first
+		// IssueEditEvent (if there are edits).
+		// Exactly the same is true for comments and comment edits.
+		// As a consequence we need to look at the current event and one look ahead
+		// event.
+
+		currEvent = nextEvent
+		if currEvent == nil {
+			currEvent = gi.getEventHandleMsgs()
+		}
+		if currEvent == nil {
+			break
+		}
+		nextEvent = gi.getEventHandleMsgs()
+
+		switch event := currEvent.(type) {
+		case RateLimitingEvent:
+			out <- core.NewImportRateLimiting(event.msg)
+		case IssueEvent:
+			// first: commit what is being held in currBug
+			if err = gi.commit(currBug, out); err != nil {
+				out <- core.NewImportError(err, "")
+				return
 			}
-			nextEvent = gi.getEventHandleMsgs()
-
-			switch event := currEvent.(type) {
-			case RateLimitingEvent:
-				out <- core.NewImportRateLimiting(event.msg)
-			case IssueEvent:
-				// first: commit what is being held in currBug
-				if err = gi.commit(currBug, out); err != nil {
-					out <- core.NewImportError(err, "")
-					return
-				}
-				// second: create new issue
-				switch next := nextEvent.(type) {
-				case IssueEditEvent:
-					// consuming and using next event
-					nextEvent = nil
-					currBug, err = gi.ensureIssue(ctx, repo, &event.issue, &next.userContentEdit)
-				default:
-					currBug, err = gi.ensureIssue(ctx, repo, &event.issue, nil)
-				}
-				if err != nil {
-					err := fmt.Errorf("issue creation: %v", err)
-					out <- core.NewImportError(err, "")
-					return
-				}
+			// second: create new issue
+			switch next := nextEvent.(type) {
 			case IssueEditEvent:
-				err = gi.ensureIssueEdit(ctx, repo, currBug, event.issueId, &event.userContentEdit)
-				if err != nil {
-					err = fmt.Errorf("issue edit: %v", err)
-					out <- core.NewImportError(err, "")
-					return
-				}
-			case TimelineEvent:
-				if next, ok := nextEvent.(CommentEditEvent); ok && event.Typename == "IssueComment" {
-					// consuming and using next event
-					nextEvent = nil
-					err = gi.ensureComment(ctx, repo, currBug, &event.timelineItem.IssueComment, &next.userContentEdit)
-				} else {
-					err = gi.ensureTimelineItem(ctx, repo, currBug, &event.timelineItem)
-				}
-				if err != nil {
-					err = fmt.Errorf("timeline item creation: %v", err)
-					out <- core.NewImportError(err, "")
-					return
-				}
-			case CommentEditEvent:
-				err = gi.ensureCommentEdit(ctx, repo, currBug, event.commentId, &event.userContentEdit)
-				if err != nil {
-					err = fmt.Errorf("comment edit: %v", err)
-					out <- core.NewImportError(err, "")
-					return
-				}
+				// consuming and using next event
+				nextEvent = nil
+				currBug, err = gi.ensureIssue(ctx, repo, &event.issue, &next.userContentEdit)
 			default:
-				panic("Unknown event type")
+				currBug, err = gi.ensureIssue(ctx, repo, &event.issue, nil)
 			}
+			if err != nil {
+				err := fmt.Errorf("issue creation: %v", err)
+				out <- core.NewImportError(err, "")
+				return
+			}
+		case IssueEditEvent:
+			err = gi.ensureIssueEdit(ctx, repo, currBug, event.issueId, &event.userContentEdit)
+			if err != nil {
+				err = fmt.Errorf("issue edit: %v", err)
+				out <- core.NewImportError(err, "")
+				return
+			}
+		case TimelineEvent:
+			if next, ok := nextEvent.(CommentEditEvent); ok && event.Typename == "IssueComment" {
+				// consuming and using next event
+				nextEvent = nil
+				err = gi.ensureComment(ctx, repo, currBug, &event.timelineItem.IssueComment, &next.userContentEdit)
+			} else {
+				err = gi.ensureTimelineItem(ctx, repo, currBug, &event.timelineItem)
+			}
+			if err != nil {
+				err = fmt.Errorf("timeline item creation: %v", err)
+				out <- core.NewImportError(err, "")
+				return
+			}
+		case CommentEditEvent:
+			err = gi.ensureCommentEdit(ctx, repo, currBug, event.commentId, &event.userContentEdit)
+			if err != nil {
+				err = fmt.Errorf("comment edit: %v", err)
+				out <- core.NewImportError(err, "")
+				return
+			}
+		case PullRequestEvent:
+			// pull requests come after all the issues
+			if err = gi.commit(currBug, out); err != nil {
+				out <- core.NewImportError(err, "")
+				return
+			}
+			currBug = nil
+			err = gi.ensurePullRequest(ctx, repo, &event.pullRequestNode)
+			if err != nil {
+				err = fmt.Errorf("pull request: %v", err)
+				out <- core.NewImportError(err, "")
+				return
+			}
+		default:
+			panic("Unknown event type")
 		}
-		// commit what is being held in currBug before returning
-		if err = gi.commit(currBug, out); err != nil {
-			out <- core.NewImportError(err, "")
-		}
-		if err = gi.mediator.Error(); err != nil {
-			gi.out <- core.NewImportError(err, "")
-		}
-	}()
-
-	return out, nil
+	}
+	// commit what is being held in currBug before returning
+	if err = gi.commit(currBug, out); err != nil {
+		out <- core.NewImportError(err, "")
+	}
+	if err = gi.mediator.Error(); err != nil {
+		gi.out <- core.NewImportError(err, "")
+	}
 }
 
 func (gi *githubImporter) getEventHandleMsgs() ImportEvent {
@@ -211,18 +252,20 @@ func (gi *githubImporter) ensureIssue(ctx context.Context, repo *cache.RepoCache
 		textInput = string(issue.Body)
 	}
 
+	attachments := gi.importAttachments(ctx, repo, textInput, "")
+
 	// create bug
 	b, _, err = repo.Bugs().NewRaw(
 		author,
 		issue.CreatedAt.Unix(),
 		text.CleanupOneLine(title), // TODO: this is the *current* title, not the original one
 		text.Cleanup(textInput),
-		nil,
-		map[string]string{
+		bug.AttachmentHashes(attachments),
+		core.WithAttachmentsMetadata(map[string]string{
 			core.MetaKeyOrigin: target,
 			metaKeyGithubId:    parseId(issue.Id),
 			metaKeyGithubUrl:   issue.Url.String(),
-		})
+		}, attachments))
 	if err != nil {
 		return nil, err
 	}
@@ -256,6 +299,10 @@ func (gi *githubImporter) ensureTimelineItem(ctx context.Context, repo *cache.Re
 		if err != cache.ErrNoMatchingOp {
 			return err
 		}
+		label, ok := gi.mapping.ImportLabel(text.CleanupOneLine(string(item.LabeledEvent.Label.Name)))
+		if !ok {
+			return nil
+		}
 		author, err := gi.ensurePerson(ctx, repo, item.LabeledEvent.Actor)
 		if err != nil {
 			return err
@@ -263,9 +310,7 @@ func (gi *githubImporter) ensureTimelineItem(ctx context.Context, repo *cache.Re
 		op, err := b.ForceChangeLabelsRaw(
 			author,
 			item.LabeledEvent.CreatedAt.Unix(),
-			[]string{
-				text.CleanupOneLine(string(item.LabeledEvent.Label.Name)),
-			},
+			[]string{label},
 			nil,
 			map[string]string{metaKeyGithubId: id},
 		)
@@ -285,6 +330,10 @@ func (gi *githubImporter) ensureTimelineItem(ctx context.Context, repo *cache.Re
 		if err != cache.ErrNoMatchingOp {
 			return err
 		}
+		label, ok := gi.mapping.ImportLabel(text.CleanupOneLine(string(item.UnlabeledEvent.Label.Name)))
+		if !ok {
+			return nil
+		}
 		author, err := gi.ensurePerson(ctx, repo, item.UnlabeledEvent.Actor)
 		if err != nil {
 			return err
@@ -294,9 +343,7 @@ func (gi *githubImporter) ensureTimelineItem(ctx context.Context, repo *cache.Re
 			author,
 			item.UnlabeledEvent.CreatedAt.Unix(),
 			nil,
-			[]string{
-				text.CleanupOneLine(string(item.UnlabeledEvent.Label.Name)),
-			},
+			[]string{label},
 			map[string]string{metaKeyGithubId: id},
 		)
 		if err != nil {
@@ -319,10 +366,14 @@ func (gi *githubImporter) ensureTimelineItem(ctx context.Context, repo *cache.Re
 		if err != nil {
 			return err
 		}
-		op, err := b.CloseRaw(
+		op, labelOp, err := gi.mapping.ApplyStatus(
+			b,
 			author,
 			item.ClosedEvent.CreatedAt.Unix(),
+			"closed",
+			common.ClosedStatus,
 			map[string]string{metaKeyGithubId: id},
+			map[string]string{metaKeyGithubId: core.StatusLabelsId(id)},
 		)
 
 		if err != nil {
@@ -330,6 +381,9 @@ func (gi *githubImporter) ensureTimelineItem(ctx context.Context, repo *cache.Re
 		}
 
 		gi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+		if labelOp != nil {
+			gi.out <- core.NewImportLabelChange(b.Id(), labelOp.Id())
+		}
 		return nil
 
 	case "ReopenedEvent":
@@ -345,10 +399,14 @@ func (gi *githubImporter) ensureTimelineItem(ctx context.Context, repo *cache.Re
 		if err != nil {
 			return err
 		}
-		op, err := b.OpenRaw(
+		op, labelOp, err := gi.mapping.ApplyStatus(
+			b,
 			author,
 			item.ReopenedEvent.CreatedAt.Unix(),
+			"open",
+			common.OpenStatus,
 			map[string]string{metaKeyGithubId: id},
+			map[string]string{metaKeyGithubId: core.StatusLabelsId(id)},
 		)
 
 		if err != nil {
@@ -356,6 +414,9 @@ func (gi *githubImporter) ensureTimelineItem(ctx context.Context, repo *cache.Re
 		}
 
 		gi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+		if labelOp != nil {
+			gi.out <- core.NewImportLabelChange(b.Id(), labelOp.Id())
+		}
 		return nil
 
 	case "RenamedTitleEvent":
@@ -469,16 +530,18 @@ func (gi *githubImporter) ensureComment(ctx context.Context, repo *cache.RepoCac
 		textInput = string(comment.Body)
 	}
 
+	attachments := gi.importAttachments(ctx, repo, textInput, b.Id())
+
 	// add comment operation
 	commentId, _, err := b.AddCommentRaw(
 		author,
 		comment.CreatedAt.Unix(),
 		text.Cleanup(textInput),
-		nil,
-		map[string]string{
+		bug.AttachmentHashes(attachments),
+		core.WithAttachmentsMetadata(map[string]string{
 			metaKeyGithubId:  parseId(comment.Id),
 			metaKeyGithubUrl: comment.Url.String(),
-		},
+		}, attachments),
 	)
 	if err != nil {
 		return err
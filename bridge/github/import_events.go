@@ -38,3 +38,9 @@ type CommentEditEvent struct {
 }
 
 func (CommentEditEvent) isImportEvent() {}
+
+type PullRequestEvent struct {
+	pullRequestNode
+}
+
+func (PullRequestEvent) isImportEvent() {}
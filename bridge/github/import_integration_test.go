@@ -11,6 +11,7 @@ import (
 	m "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/MichaelMure/git-bug/bridge/core"
 	"github.com/MichaelMure/git-bug/bridge/github/mocks"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/entities/bug"
@@ -31,6 +32,9 @@ func TestGithubImporterIntegration(t *testing.T) {
 	setupExpectations(t, clientMock)
 	importer := githubImporter{}
 	importer.client = &rateLimitHandlerClient{sc: clientMock}
+	mapping, err := core.LoadMapping(core.Configuration{})
+	require.NoError(t, err)
+	importer.mapping = mapping
 
 	// arrange
 	repo := repository.CreateGoGitTestRepo(t, false)
@@ -15,6 +15,12 @@ const (
 	NumTimelineItems = 100
 	NumCommentEdits  = 100
 
+	NumPullRequests        = 20
+	NumPullRequestCommits  = 100
+	NumPullRequestComments = 100
+	NumPullRequestReviews  = 50
+	NumReviewComments      = 50
+
 	ChanCapacity = 128
 )
 
@@ -33,6 +39,12 @@ type importMediator struct {
 	// given date should be imported.
 	since time.Time
 
+	// number, if not zero, restrict the import to that single issue
+	number int
+
+	// pullRequests, if true, also retrieve the pull requests after the issues
+	pullRequests bool
+
 	// importEvents holds events representing issues, comments, edits, ...
 	// In this channel issues are immediately followed by their issue edits and comments are
 	// immediately followed by their comment edits.
@@ -42,12 +54,29 @@ type importMediator struct {
 	err error
 }
 
-func NewImportMediator(ctx context.Context, client *rateLimitHandlerClient, owner, project string, since time.Time) *importMediator {
+func NewImportMediator(ctx context.Context, client *rateLimitHandlerClient, owner, project string, since time.Time, pullRequests bool) *importMediator {
 	mm := importMediator{
 		gh:           client,
 		owner:        owner,
 		project:      project,
 		since:        since,
+		pullRequests: pullRequests,
+		importEvents: make(chan ImportEvent, ChanCapacity),
+		err:          nil,
+	}
+
+	go mm.start(ctx)
+
+	return &mm
+}
+
+// NewIssueImportMediator create a mediator that retrieve a single issue, identified by its number.
+func NewIssueImportMediator(ctx context.Context, client *rateLimitHandlerClient, owner, project string, number int) *importMediator {
+	mm := importMediator{
+		gh:           client,
+		owner:        owner,
+		project:      project,
+		number:       number,
 		importEvents: make(chan ImportEvent, ChanCapacity),
 		err:          nil,
 	}
@@ -59,7 +88,11 @@ func NewImportMediator(ctx context.Context, client *rateLimitHandlerClient, owne
 
 func (mm *importMediator) start(ctx context.Context) {
 	ctx, cancel := context.WithCancel(ctx)
-	mm.fillImportEvents(ctx)
+	if mm.number != 0 {
+		mm.fillIssueImportEvents(ctx)
+	} else {
+		mm.fillImportEvents(ctx)
+	}
 	// Make sure we cancel everything when we are done, instead of relying on the parent context
 	// This should unblock pending send to the channel if the capacity was reached and avoid a panic/race when closing.
 	cancel()
@@ -105,6 +138,54 @@ func (mm *importMediator) fillImportEvents(ctx context.Context) {
 		}
 		issues, hasIssues = mm.queryIssue(ctx, issues.PageInfo.EndCursor)
 	}
+
+	if mm.pullRequests {
+		mm.fillPullRequestEvents(ctx)
+	}
+}
+
+// fillPullRequestEvents send the pull requests updated since the configured
+// date. Their commits, comments and reviews are retrieved along, in a single
+// query.
+func (mm *importMediator) fillPullRequestEvents(ctx context.Context) {
+	initialCursor := githubv4.String("")
+	prs, hasPrs := mm.queryPullRequest(ctx, initialCursor)
+	for hasPrs {
+		for _, node := range prs.Nodes {
+			// pull requests can't be filtered by update date in the query
+			if node.UpdatedAt.Before(mm.since) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case mm.importEvents <- PullRequestEvent{node}:
+			}
+		}
+		if !prs.PageInfo.HasNextPage {
+			break
+		}
+		prs, hasPrs = mm.queryPullRequest(ctx, prs.PageInfo.EndCursor)
+	}
+}
+
+func (mm *importMediator) fillIssueImportEvents(ctx context.Context) {
+	vars := newSingleIssueVars(mm.owner, mm.project, mm.number)
+	query := singleIssueQuery{}
+	if err := mm.gh.queryImport(ctx, &query, vars, mm.importEvents); err != nil {
+		mm.err = err
+		return
+	}
+
+	node := &query.Repository.Issue
+	select {
+	case <-ctx.Done():
+		return
+	case mm.importEvents <- IssueEvent{node.issue}:
+	}
+
+	mm.fillIssueEditEvents(ctx, node)
+	mm.fillTimelineEvents(ctx, node)
 }
 
 func (mm *importMediator) fillIssueEditEvents(ctx context.Context, issueNode *issueNode) {
@@ -264,6 +345,25 @@ func (mm *importMediator) queryIssue(ctx context.Context, cursor githubv4.String
 	return connection, true
 }
 
+func (mm *importMediator) queryPullRequest(ctx context.Context, cursor githubv4.String) (*pullRequestConnection, bool) {
+	vars := newPullRequestVars(mm.owner, mm.project)
+	if cursor == "" {
+		vars["prAfter"] = (*githubv4.String)(nil)
+	} else {
+		vars["prAfter"] = cursor
+	}
+	query := pullRequestQuery{}
+	if err := mm.gh.queryImport(ctx, &query, vars, mm.importEvents); err != nil {
+		mm.err = err
+		return nil, false
+	}
+	connection := &query.Repository.PullRequests
+	if len(connection.Nodes) <= 0 {
+		return nil, false
+	}
+	return connection, true
+}
+
 func reverse(eds []userContentEdit) chan userContentEdit {
 	ret := make(chan userContentEdit)
 	go func() {
@@ -293,6 +393,33 @@ func newIssueVars(owner, project string, since time.Time) varmap {
 	}
 }
 
+func newSingleIssueVars(owner, project string, number int) varmap {
+	return varmap{
+		"owner":             githubv4.String(owner),
+		"name":              githubv4.String(project),
+		"issueNumber":       githubv4.Int(number),
+		"issueEditLast":     githubv4.Int(NumIssueEdits),
+		"issueEditBefore":   (*githubv4.String)(nil),
+		"timelineFirst":     githubv4.Int(NumTimelineItems),
+		"timelineAfter":     (*githubv4.String)(nil),
+		"commentEditLast":   githubv4.Int(NumCommentEdits),
+		"commentEditBefore": (*githubv4.String)(nil),
+	}
+}
+
+func newPullRequestVars(owner, project string) varmap {
+	return varmap{
+		"owner":                githubv4.String(owner),
+		"name":                 githubv4.String(project),
+		"prFirst":              githubv4.Int(NumPullRequests),
+		"prAfter":              (*githubv4.String)(nil),
+		"prCommitFirst":        githubv4.Int(NumPullRequestCommits),
+		"prCommentFirst":       githubv4.Int(NumPullRequestComments),
+		"prReviewFirst":        githubv4.Int(NumPullRequestReviews),
+		"prReviewCommentFirst": githubv4.Int(NumReviewComments),
+	}
+}
+
 func newIssueEditVars() varmap {
 	return varmap{
 		"issueEditLast": githubv4.Int(NumIssueEdits),
@@ -0,0 +1,284 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// ensurePullRequest create or update a pull request from the Github data,
+// with its commits, comments, reviews and status.
+func (gi *githubImporter) ensurePullRequest(ctx context.Context, repo *cache.RepoCache, node *pullRequestNode) error {
+	pr, err := repo.PullRequests().ResolveMatcher(func(excerpt *cache.PullRequestExcerpt) bool {
+		return excerpt.CreateMetadata[metaKeyGithubUrl] == node.Url.String() &&
+			excerpt.CreateMetadata[metaKeyGithubId] == parseId(node.Id)
+	})
+	if err != nil && !entity.IsErrNotFound(err) {
+		return err
+	}
+
+	if entity.IsErrNotFound(err) {
+		author, err := gi.ensurePerson(ctx, repo, node.Author)
+		if err != nil {
+			return err
+		}
+
+		title := text.CleanupOneLine(string(node.Title))
+		if text.Empty(title) {
+			title = EmptyTitlePlaceholder
+		}
+
+		pr, _, err = repo.PullRequests().NewRaw(
+			author,
+			node.CreatedAt.Unix(),
+			title, // TODO: this is the *current* title, not the original one
+			text.Cleanup(string(node.Body)),
+			text.CleanupOneLine(string(node.HeadRefName)),
+			text.CleanupOneLine(string(node.BaseRefName)),
+			map[string]string{
+				core.MetaKeyOrigin: target,
+				metaKeyGithubId:    parseId(node.Id),
+				metaKeyGithubUrl:   node.Url.String(),
+			})
+		if err != nil {
+			return err
+		}
+
+		gi.out <- core.NewImportPullRequest(pr.Id())
+	}
+
+	if err := gi.ensurePullRequestCommits(ctx, repo, pr, node); err != nil {
+		return fmt.Errorf("commits: %v", err)
+	}
+	for i := range node.Comments.Nodes {
+		if err := gi.ensurePullRequestComment(ctx, repo, pr, &node.Comments.Nodes[i]); err != nil {
+			return fmt.Errorf("comment: %v", err)
+		}
+	}
+	for i := range node.Reviews.Nodes {
+		if err := gi.ensurePullRequestReview(ctx, repo, pr, &node.Reviews.Nodes[i]); err != nil {
+			return fmt.Errorf("review: %v", err)
+		}
+	}
+	if err := gi.ensurePullRequestStatus(ctx, repo, pr, node); err != nil {
+		return fmt.Errorf("status: %v", err)
+	}
+
+	gi.warnTruncated(pr.Id(), node)
+
+	if !pr.NeedCommit() {
+		gi.out <- core.NewImportNothing(pr.Id(), "no imported operation")
+		return nil
+	}
+	if err := pr.Commit(); err != nil {
+		return fmt.Errorf("pull request commit: %v", err)
+	}
+	return nil
+}
+
+func (gi *githubImporter) ensurePullRequestCommits(ctx context.Context, repo *cache.RepoCache, pr *cache.PullRequestCache, node *pullRequestNode) error {
+	if len(node.Commits.Nodes) == 0 {
+		return nil
+	}
+
+	commits := make([]pullrequest.Commit, len(node.Commits.Nodes))
+	for i, n := range node.Commits.Nodes {
+		commits[i] = pullrequest.Commit{
+			Hash:    string(n.Commit.Oid),
+			Message: text.CleanupOneLine(string(n.Commit.MessageHeadline)),
+		}
+	}
+
+	// Github doesn't tell who pushed the commits, the best guess is the author
+	// of the pull request.
+	author, err := gi.ensurePerson(ctx, repo, node.Author)
+	if err != nil {
+		return err
+	}
+
+	last := node.Commits.Nodes[len(node.Commits.Nodes)-1].Commit
+	_, err = pr.AddCommitsRaw(author, last.CommittedDate.Unix(), commits, nil)
+	return err
+}
+
+func (gi *githubImporter) ensurePullRequestComment(ctx context.Context, repo *cache.RepoCache, pr *cache.PullRequestCache, comment *pullRequestComment) error {
+	_, err := pr.ResolveOperationWithMetadata(metaKeyGithubId, parseId(comment.Id))
+	if err == nil {
+		return nil
+	}
+	if err != cache.ErrNoMatchingOp {
+		return err
+	}
+
+	author, err := gi.ensurePerson(ctx, repo, comment.Author)
+	if err != nil {
+		return err
+	}
+
+	commentId, _, err := pr.AddCommentRaw(
+		author,
+		comment.CreatedAt.Unix(),
+		text.Cleanup(string(comment.Body)),
+		map[string]string{
+			metaKeyGithubId:  parseId(comment.Id),
+			metaKeyGithubUrl: comment.Url.String(),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	gi.out <- core.NewImportComment(pr.Id(), commentId)
+	return nil
+}
+
+func (gi *githubImporter) ensurePullRequestReview(ctx context.Context, repo *cache.RepoCache, pr *cache.PullRequestCache, review *pullRequestReview) error {
+	state, ok := reviewState(review.State)
+	if !ok {
+		// pending reviews are not submitted yet
+		return nil
+	}
+
+	_, err := pr.ResolveOperationWithMetadata(metaKeyGithubId, parseId(review.Id))
+	if err == nil {
+		return nil
+	}
+	if err != cache.ErrNoMatchingOp {
+		return err
+	}
+
+	author, err := gi.ensurePerson(ctx, repo, review.Author)
+	if err != nil {
+		return err
+	}
+
+	comments := make([]pullrequest.ReviewComment, len(review.Comments.Nodes))
+	for i, c := range review.Comments.Nodes {
+		comments[i] = pullrequest.ReviewComment{
+			Path:     text.CleanupOneLine(string(c.Path)),
+			DiffHunk: text.Cleanup(string(c.DiffHunk)),
+			Message:  text.Cleanup(string(c.Body)),
+		}
+		// the line is missing when the comment is outdated
+		if c.Line != nil {
+			comments[i].Line = int(*c.Line)
+		}
+	}
+
+	_, _, err = pr.AddReviewRaw(
+		author,
+		review.CreatedAt.Unix(),
+		state,
+		text.Cleanup(string(review.Body)),
+		comments,
+		map[string]string{
+			metaKeyGithubId:  parseId(review.Id),
+			metaKeyGithubUrl: review.Url.String(),
+		},
+	)
+	return err
+}
+
+// ensurePullRequestStatus bring the status of the pull request up to date. As
+// only the current state is known, the intermediate transitions are lost.
+func (gi *githubImporter) ensurePullRequestStatus(ctx context.Context, repo *cache.RepoCache, pr *cache.PullRequestCache, node *pullRequestNode) error {
+	status, ok := pullRequestStatus(node.State)
+	if !ok || pr.Snapshot().Status == status {
+		return nil
+	}
+
+	// by default, the best guess is the author of the pull request
+	actor := node.Author
+	unixTime := node.UpdatedAt.Unix()
+	var mergeCommit string
+
+	switch status {
+	case pullrequest.MergedStatus:
+		actor = node.MergedBy
+		if node.MergedAt != nil {
+			unixTime = node.MergedAt.Unix()
+		}
+		if node.MergeCommit != nil {
+			mergeCommit = string(node.MergeCommit.Oid)
+		}
+	case pullrequest.ClosedStatus:
+		if len(node.ClosedEvents.Nodes) > 0 {
+			event := node.ClosedEvents.Nodes[0].ClosedEvent
+			actor = event.Actor
+			unixTime = event.CreatedAt.Unix()
+		}
+	}
+
+	author, err := gi.ensurePerson(ctx, repo, actor)
+	if err != nil {
+		return err
+	}
+
+	op, err := pr.SetStatusRaw(author, unixTime, status, mergeCommit, nil)
+	if err != nil {
+		return err
+	}
+
+	gi.out <- core.NewImportStatusChange(pr.Id(), op.Id())
+	return nil
+}
+
+// warnTruncated report the parts of a pull request that didn't fit in the
+// query and were not imported.
+func (gi *githubImporter) warnTruncated(prId entity.Id, node *pullRequestNode) {
+	truncated := func(what string, count int) {
+		err := fmt.Errorf("pull request #%d: only the first %d %s are imported", node.Number, count, what)
+		gi.out <- core.NewImportWarning(err, prId)
+	}
+
+	if node.Commits.PageInfo.HasNextPage {
+		truncated("commits", NumPullRequestCommits)
+	}
+	if node.Comments.PageInfo.HasNextPage {
+		truncated("comments", NumPullRequestComments)
+	}
+	if node.Reviews.PageInfo.HasNextPage {
+		truncated("reviews", NumPullRequestReviews)
+	}
+	for _, review := range node.Reviews.Nodes {
+		if review.Comments.PageInfo.HasNextPage {
+			truncated("comments of a review", NumReviewComments)
+			break
+		}
+	}
+}
+
+func pullRequestStatus(state githubv4.PullRequestState) (pullrequest.Status, bool) {
+	switch state {
+	case githubv4.PullRequestStateOpen:
+		return pullrequest.OpenStatus, true
+	case githubv4.PullRequestStateClosed:
+		return pullrequest.ClosedStatus, true
+	case githubv4.PullRequestStateMerged:
+		return pullrequest.MergedStatus, true
+	default:
+		return 0, false
+	}
+}
+
+func reviewState(state githubv4.PullRequestReviewState) (pullrequest.ReviewState, bool) {
+	switch state {
+	case githubv4.PullRequestReviewStateCommented:
+		return pullrequest.ReviewCommented, true
+	case githubv4.PullRequestReviewStateApproved:
+		return pullrequest.ReviewApproved, true
+	case githubv4.PullRequestReviewStateChangesRequested:
+		return pullrequest.ReviewChangesRequested, true
+	case githubv4.PullRequestReviewStateDismissed:
+		return pullrequest.ReviewDismissed, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,107 @@
+package github
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestEnsurePullRequest(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	out := make(chan core.ImportResult, 100)
+	importer := &githubImporter{out: out}
+
+	alice := &actor{Typename: "User", Login: "alice"}
+	bob := &actor{Typename: "User", Login: "bob"}
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	prUrl, _ := url.Parse("https://github.com/owner/project/pull/1")
+	line := githubv4.Int(12)
+
+	node := &pullRequestNode{
+		pullRequest: pullRequest{
+			authorEvent: authorEvent{Id: "PR_1", CreatedAt: githubv4.DateTime{Time: created}, Author: alice},
+			Title:       "add a feature",
+			Number:      1,
+			Body:        "description",
+			Url:         githubv4.URI{URL: prUrl},
+			State:       githubv4.PullRequestStateOpen,
+			UpdatedAt:   githubv4.DateTime{Time: created},
+			HeadRefName: "feature",
+			BaseRefName: "master",
+		},
+	}
+	node.Commits.Nodes = append(node.Commits.Nodes, struct{ Commit pullRequestCommit }{
+		Commit: pullRequestCommit{
+			Oid:             githubv4.GitObjectID(strings.Repeat("a", 40)),
+			MessageHeadline: "implement the feature",
+			CommittedDate:   githubv4.DateTime{Time: created},
+		},
+	})
+	node.Comments.Nodes = append(node.Comments.Nodes, pullRequestComment{
+		authorEvent: authorEvent{Id: "C_1", CreatedAt: githubv4.DateTime{Time: created.Add(time.Hour)}, Author: bob},
+		Body:        "looks interesting",
+		Url:         githubv4.URI{URL: prUrl},
+	})
+	review := pullRequestReview{
+		authorEvent: authorEvent{Id: "R_1", CreatedAt: githubv4.DateTime{Time: created.Add(2 * time.Hour)}, Author: bob},
+		State:       githubv4.PullRequestReviewStateChangesRequested,
+		Body:        "a few things",
+		Url:         githubv4.URI{URL: prUrl},
+	}
+	review.Comments.Nodes = append(review.Comments.Nodes, pullRequestReviewComment{
+		Path: "main.go", Line: &line, DiffHunk: "@@ -1 +1 @@", Body: "typo",
+	})
+	node.Reviews.Nodes = append(node.Reviews.Nodes, review)
+
+	err = importer.ensurePullRequest(context.Background(), backend, node)
+	require.NoError(t, err)
+
+	ids := backend.PullRequests().AllIds()
+	require.Len(t, ids, 1)
+	pr, err := backend.PullRequests().Resolve(ids[0])
+	require.NoError(t, err)
+
+	snap := pr.Snapshot()
+	require.Equal(t, "add a feature", snap.Title)
+	require.Equal(t, "feature", snap.SourceBranch)
+	require.Equal(t, "master", snap.TargetBranch)
+	require.Equal(t, pullrequest.OpenStatus, snap.Status)
+	require.Len(t, snap.Commits, 1)
+	require.Len(t, snap.Comments, 2)
+	require.Len(t, snap.Reviews, 1)
+	require.Equal(t, pullrequest.ReviewChangesRequested, snap.Reviews[0].State)
+	require.Equal(t, 12, snap.Reviews[0].Comments[0].Line)
+
+	// the pull request got merged in the meantime
+	mergedAt := githubv4.DateTime{Time: created.Add(3 * time.Hour)}
+	node.State = githubv4.PullRequestStateMerged
+	node.MergedAt = &mergedAt
+	node.MergedBy = alice
+	node.MergeCommit = &struct{ Oid githubv4.GitObjectID }{Oid: githubv4.GitObjectID(strings.Repeat("b", 40))}
+
+	err = importer.ensurePullRequest(context.Background(), backend, node)
+	require.NoError(t, err)
+
+	require.Len(t, backend.PullRequests().AllIds(), 1)
+	snap = pr.Snapshot()
+	require.Equal(t, pullrequest.MergedStatus, snap.Status)
+	require.Equal(t, strings.Repeat("b", 40), snap.MergeCommit)
+	require.Len(t, snap.Commits, 1)
+	require.Len(t, snap.Comments, 2)
+	require.Len(t, snap.Reviews, 1)
+}
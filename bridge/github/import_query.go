@@ -40,6 +40,12 @@ type issueQuery struct {
 	} `graphql:"repository(owner: $owner, name: $name)"`
 }
 
+type singleIssueQuery struct {
+	Repository struct {
+		Issue issueNode `graphql:"issue(number: $issueNumber)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
 type issueEditQuery struct {
 	Node struct {
 		Typename githubv4.String `graphql:"__typename"`
@@ -202,3 +208,87 @@ type pageInfo struct {
 	StartCursor     githubv4.String
 	HasPreviousPage bool
 }
+
+type pullRequestQuery struct {
+	Repository struct {
+		PullRequests pullRequestConnection `graphql:"pullRequests(first: $prFirst, after: $prAfter, orderBy: {field: CREATED_AT, direction: ASC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type pullRequestConnection struct {
+	Nodes    []pullRequestNode
+	PageInfo pageInfo
+}
+
+type pullRequestNode struct {
+	pullRequest
+	Commits struct {
+		Nodes []struct {
+			Commit pullRequestCommit
+		}
+		PageInfo pageInfo
+	} `graphql:"commits(first: $prCommitFirst)"`
+	Comments struct {
+		Nodes    []pullRequestComment
+		PageInfo pageInfo
+	} `graphql:"comments(first: $prCommentFirst)"`
+	Reviews struct {
+		Nodes    []pullRequestReview
+		PageInfo pageInfo
+	} `graphql:"reviews(first: $prReviewFirst)"`
+	// the last closing of the pull request, to know who closed it
+	ClosedEvents struct {
+		Nodes []struct {
+			ClosedEvent struct {
+				actorEvent
+			} `graphql:"... on ClosedEvent"`
+		}
+	} `graphql:"closedEvents: timelineItems(last: 1, itemTypes: [CLOSED_EVENT])"`
+}
+
+type pullRequest struct {
+	authorEvent
+	Title       githubv4.String
+	Number      githubv4.Int
+	Body        githubv4.String
+	Url         githubv4.URI
+	State       githubv4.PullRequestState
+	UpdatedAt   githubv4.DateTime
+	HeadRefName githubv4.String
+	BaseRefName githubv4.String
+	MergedAt    *githubv4.DateTime
+	MergedBy    *actor
+	MergeCommit *struct {
+		Oid githubv4.GitObjectID
+	}
+}
+
+type pullRequestCommit struct {
+	Oid             githubv4.GitObjectID
+	MessageHeadline githubv4.String
+	CommittedDate   githubv4.DateTime
+}
+
+type pullRequestComment struct {
+	authorEvent
+	Body githubv4.String
+	Url  githubv4.URI
+}
+
+type pullRequestReview struct {
+	authorEvent
+	State    githubv4.PullRequestReviewState
+	Body     githubv4.String
+	Url      githubv4.URI
+	Comments struct {
+		Nodes    []pullRequestReviewComment
+		PageInfo pageInfo
+	} `graphql:"comments(first: $prReviewCommentFirst)"`
+}
+
+type pullRequestReviewComment struct {
+	Path     githubv4.String
+	Line     *githubv4.Int
+	DiffHunk githubv4.String
+	Body     githubv4.String
+}
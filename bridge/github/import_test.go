@@ -21,9 +21,19 @@ import (
 )
 
 func TestGithubImporter(t *testing.T) {
+	// the API interactions recorded with GIT_BUG_HTTP_FIXTURES=record:testdata/http-fixtures
+	// are replayed without a token. No fixtures are committed yet: without a token, the test
+	// is skipped, and the replay of the bridge is not tested in the CI.
+	transport, replay, err := core.TestHTTPTransport("testdata/http-fixtures")
+	require.NoError(t, err)
+
 	envToken := os.Getenv("GITHUB_TOKEN_PRIVATE")
-	if envToken == "" {
-		t.Skip("Env var GITHUB_TOKEN_PRIVATE missing")
+	switch {
+	case replay:
+		// no real token needed
+		envToken = "replay"
+	case envToken == "":
+		t.Skip("Env var GITHUB_TOKEN_PRIVATE missing, and no HTTP fixtures to replay")
 	}
 
 	repo := repository.CreateGoGitTestRepo(t, false)
@@ -150,7 +160,7 @@ func TestGithubImporter(t *testing.T) {
 	err = auth.Store(repo, token)
 	require.NoError(t, err)
 
-	ctx := context.Background()
+	ctx := core.WithHTTPTransport(context.Background(), transport)
 
 	importer := &githubImporter{}
 	err = importer.Init(ctx, backend, core.Configuration{
@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+// webhookPayload is the subset of the "issues" and "issue_comment" webhook
+// payloads needed to find the affected issue.
+// See https://docs.github.com/en/webhooks/webhook-events-and-payloads
+type webhookPayload struct {
+	Issue struct {
+		Number      int              `json:"number"`
+		PullRequest *json.RawMessage `json:"pull_request"`
+	} `json:"issue"`
+	Repository struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+}
+
+// ImportWebhook import the issue affected by a Github webhook request
+func (gi *githubImporter) ImportWebhook(ctx context.Context, repo *cache.RepoCache, secret string, header http.Header, payload []byte) (<-chan core.ImportResult, error) {
+	number, reason, err := parseWebhook(secret, gi.conf[confKeyOwner], gi.conf[confKeyProject], header, payload)
+	if err != nil {
+		return nil, err
+	}
+	if number == 0 {
+		return core.NewWebhookIgnored(reason), nil
+	}
+
+	gi.mediator = NewIssueImportMediator(ctx, gi.client, gi.conf[confKeyOwner], gi.conf[confKeyProject], number)
+	out := make(chan core.ImportResult)
+	gi.out = out
+
+	go func() {
+		defer close(gi.out)
+		gi.importMediatorEvents(ctx, repo, out)
+	}()
+
+	return out, nil
+}
+
+// parseWebhook authenticate a webhook request and return the number of the
+// affected issue, or zero and a reason if there is nothing to import.
+func parseWebhook(secret, owner, project string, header http.Header, payload []byte) (int, string, error) {
+	if !validSignature(secret, header.Get("X-Hub-Signature-256"), payload) {
+		return 0, "", core.ErrWebhookSignature
+	}
+
+	event := header.Get("X-GitHub-Event")
+	switch event {
+	case "issues", "issue_comment":
+	default:
+		return 0, fmt.Sprintf("ignored webhook event \"%s\"", event), nil
+	}
+
+	var p webhookPayload
+	err := json.Unmarshal(payload, &p)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid webhook payload: %v", err)
+	}
+
+	if !strings.EqualFold(p.Repository.Owner.Login, owner) || !strings.EqualFold(p.Repository.Name, project) {
+		return 0, "", fmt.Errorf("webhook for another repository: %s/%s", p.Repository.Owner.Login, p.Repository.Name)
+	}
+
+	// comments on pull requests are delivered as issue comments
+	if p.Issue.PullRequest != nil {
+		return 0, "ignored pull request", nil
+	}
+
+	if p.Issue.Number <= 0 {
+		return 0, "", fmt.Errorf("invalid webhook payload: missing issue number")
+	}
+
+	return p.Issue.Number, "", nil
+}
+
+// validSignature check the HMAC of the payload, as sent by Github in the
+// X-Hub-Signature-256 header.
+func validSignature(secret, signature string, payload []byte) bool {
+	hexMac, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	received, err := hex.DecodeString(hexMac)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(received, mac.Sum(nil))
+}
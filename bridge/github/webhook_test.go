@@ -0,0 +1,58 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+)
+
+func signedHeader(secret, event string, payload []byte) http.Header {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	header := make(http.Header)
+	header.Set("X-GitHub-Event", event)
+	header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestParseWebhook(t *testing.T) {
+	issue := []byte(`{"action":"opened","issue":{"number":42},"repository":{"name":"git-bug","owner":{"login":"MichaelMure"}}}`)
+	pr := []byte(`{"action":"created","issue":{"number":43,"pull_request":{}},"repository":{"name":"git-bug","owner":{"login":"MichaelMure"}}}`)
+	other := []byte(`{"action":"opened","issue":{"number":42},"repository":{"name":"other","owner":{"login":"MichaelMure"}}}`)
+
+	number, _, err := parseWebhook("secret", "michaelmure", "git-bug", signedHeader("secret", "issues", issue), issue)
+	require.NoError(t, err)
+	require.Equal(t, 42, number)
+
+	number, _, err = parseWebhook("secret", "MichaelMure", "git-bug", signedHeader("secret", "issue_comment", issue), issue)
+	require.NoError(t, err)
+	require.Equal(t, 42, number)
+
+	_, _, err = parseWebhook("secret", "MichaelMure", "git-bug", signedHeader("wrong", "issues", issue), issue)
+	require.ErrorIs(t, err, core.ErrWebhookSignature)
+
+	header := signedHeader("secret", "issues", issue)
+	header.Del("X-Hub-Signature-256")
+	_, _, err = parseWebhook("secret", "MichaelMure", "git-bug", header, issue)
+	require.ErrorIs(t, err, core.ErrWebhookSignature)
+
+	number, reason, err := parseWebhook("secret", "MichaelMure", "git-bug", signedHeader("secret", "ping", issue), issue)
+	require.NoError(t, err)
+	require.Zero(t, number)
+	require.NotEmpty(t, reason)
+
+	number, reason, err = parseWebhook("secret", "MichaelMure", "git-bug", signedHeader("secret", "issue_comment", pr), pr)
+	require.NoError(t, err)
+	require.Zero(t, number)
+	require.NotEmpty(t, reason)
+
+	_, _, err = parseWebhook("secret", "MichaelMure", "git-bug", signedHeader("secret", "issues", other), other)
+	require.Error(t, err)
+}
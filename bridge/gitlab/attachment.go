@@ -0,0 +1,117 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// Files uploaded to gitlab are linked in the text with a path relative to the
+// project, like /uploads/<secret>/<filename>.
+var uploadRegexp = regexp.MustCompile(`/uploads/[0-9a-f]{32}/[^\s)"'>\]]+`)
+
+// Markdown links to uploaded files, as returned by the upload API.
+var uploadLinkRegexp = regexp.MustCompile(`!?\[[^\]]*\]\(/uploads/[0-9a-f]{32}/[^\s)]+\)`)
+
+// attachmentUrls return the absolute urls of the files linked in a text.
+// Those urls are relative to the project, found from the issue url.
+func attachmentUrls(issueWebUrl string, body string) []string {
+	projectUrl := issueWebUrl
+	if i := strings.LastIndex(projectUrl, "/-/issues/"); i >= 0 {
+		projectUrl = projectUrl[:i]
+	} else if i := strings.LastIndex(projectUrl, "/issues/"); i >= 0 {
+		projectUrl = projectUrl[:i]
+	}
+
+	var result []string
+	seen := make(map[string]struct{})
+	for _, upload := range uploadRegexp.FindAllString(body, -1) {
+		if _, ok := seen[upload]; ok {
+			continue
+		}
+		seen[upload] = struct{}{}
+		result = append(result, projectUrl+upload)
+	}
+	return result
+}
+
+// importAttachments download the files linked in a text, to attach them to
+// the imported comment.
+func (gi *gitlabImporter) importAttachments(ctx context.Context, repo *cache.RepoCache, issue *gitlab.Issue, body string, entityId entity.Id) []bug.Attachment {
+	urls := attachmentUrls(issue.WebURL, body)
+	if len(urls) == 0 {
+		return nil
+	}
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", gi.token)
+	return core.DownloadAttachments(ctx, repo, gi.httpClient, urls, header, gi.out, entityId)
+}
+
+// sameMessage compare a local message with the text of a gitlab issue or note,
+// ignoring the links to the uploaded attachments added when exporting.
+func sameMessage(local string, remote string) bool {
+	if local == remote {
+		return true
+	}
+	return local == text.Cleanup(uploadLinkRegexp.ReplaceAllString(remote, ""))
+}
+
+// uploadAttachments upload the attached files to gitlab and return the
+// markdown links to the uploaded files.
+func (ge *gitlabExporter) uploadAttachments(ctx context.Context, gc *gitlab.Client, repo *cache.RepoCache, repositoryID string, attachments []bug.Attachment) (string, error) {
+	if len(attachments) == 0 || ge.dryRun {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	links := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		data, err := repo.ReadData(attachment.Hash)
+		if err != nil {
+			return "", err
+		}
+		file, _, err := gc.Projects.UploadFile(
+			repositoryID,
+			bytes.NewReader(data),
+			attachment.DisplayName(),
+			gitlab.WithContext(ctx),
+		)
+		if err != nil {
+			return "", err
+		}
+		links = append(links, file.Markdown)
+	}
+
+	return strings.Join(links, "\n"), nil
+}
+
+// withAttachmentLinks complete a message with the links to its uploaded
+// attachments.
+func withAttachmentLinks(message string, links string) string {
+	if links == "" {
+		return message
+	}
+	return message + "\n\n" + links
+}
+
+// commentAttachments return the files attached to the comment created by the
+// given operation.
+func commentAttachments(snapshot *bug.Snapshot, opId entity.Id) []bug.Attachment {
+	comment, err := snapshot.SearchCommentByOpId(opId)
+	if err != nil {
+		return nil
+	}
+	return comment.Attachments
+}
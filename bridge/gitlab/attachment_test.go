@@ -0,0 +1,31 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentUrls(t *testing.T) {
+	body := `Here is the log: [build.log](/uploads/0123456789abcdef0123456789abcdef/build.log)
+
+![screenshot](/uploads/fedcba9876543210fedcba9876543210/screen.png)
+and again [build.log](/uploads/0123456789abcdef0123456789abcdef/build.log)`
+
+	urls := attachmentUrls("https://gitlab.com/group/project/-/issues/42", body)
+	require.Equal(t, []string{
+		"https://gitlab.com/group/project/uploads/0123456789abcdef0123456789abcdef/build.log",
+		"https://gitlab.com/group/project/uploads/fedcba9876543210fedcba9876543210/screen.png",
+	}, urls)
+
+	require.Empty(t, attachmentUrls("https://gitlab.com/group/project/-/issues/42", "no file here"))
+}
+
+func TestSameMessage(t *testing.T) {
+	links := "[build.log](/uploads/0123456789abcdef0123456789abcdef/build.log)\n" +
+		"![screen.png](/uploads/fedcba9876543210fedcba9876543210/screen.png)"
+
+	require.True(t, sameMessage("message", "message"))
+	require.True(t, sameMessage("message", withAttachmentLinks("message", links)))
+	require.False(t, sameMessage("message", withAttachmentLinks("edited", links)))
+}
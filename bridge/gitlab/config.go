@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -280,7 +281,7 @@ func validateProjectURL(baseUrl, url string, token *auth.Token) (int, error) {
 		return 0, err
 	}
 
-	client, err := buildClient(baseUrl, token)
+	client, err := buildClient(http.DefaultTransport, baseUrl, token)
 	if err != nil {
 		return 0, err
 	}
@@ -294,7 +295,7 @@ func validateProjectURL(baseUrl, url string, token *auth.Token) (int, error) {
 }
 
 func getLoginFromToken(baseUrl string, token *auth.Token) (string, error) {
-	client, err := buildClient(baseUrl, token)
+	client, err := buildClient(http.DefaultTransport, baseUrl, token)
 	if err != nil {
 		return "", err
 	}
@@ -3,6 +3,7 @@ package gitlab
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"time"
@@ -27,28 +28,57 @@ var (
 type gitlabExporter struct {
 	conf core.Configuration
 
+	// label and status mapping
+	mapping *core.Mapping
+
+	// what to do when a local change conflicts with a remote one
+	conflictPolicy core.ConflictPolicy
+
+	// if true, don't call the mutating endpoints, and use 0 as the ID of the
+	// created entities
+	dryRun bool
+
 	// cache identities clients
 	identityClient map[entity.Id]*gitlab.Client
 
+	// transport of the HTTP requests
+	transport http.RoundTripper
+
 	// gitlab repository ID
 	repositoryID string
 
 	// cache identifiers used to speed up exporting operations
 	// cleared for each bug
 	cachedOperationIDs map[string]string
+
+	// cache the links to the uploaded attachments of the exported comments
+	cachedAttachmentLinks map[string]string
 }
 
 // Init .
-func (ge *gitlabExporter) Init(_ context.Context, repo *cache.RepoCache, conf core.Configuration) error {
+func (ge *gitlabExporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
 	ge.conf = conf
+	ge.transport = core.HTTPTransport(ctx)
 	ge.identityClient = make(map[entity.Id]*gitlab.Client)
 	ge.cachedOperationIDs = make(map[string]string)
+	ge.cachedAttachmentLinks = make(map[string]string)
+
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	ge.mapping = mapping
+
+	ge.conflictPolicy, err = core.LoadConflictPolicy(conf)
+	if err != nil {
+		return err
+	}
 
 	// get repository node id
 	ge.repositoryID = ge.conf[confKeyProjectID]
 
 	// preload all clients
-	err := ge.cacheAllClient(repo, ge.conf[confKeyGitlabBaseUrl])
+	err = ge.cacheAllClient(repo, ge.conf[confKeyGitlabBaseUrl])
 	if err != nil {
 		return err
 	}
@@ -56,6 +86,11 @@ func (ge *gitlabExporter) Init(_ context.Context, repo *cache.RepoCache, conf co
 	return nil
 }
 
+// SetDryRun .
+func (ge *gitlabExporter) SetDryRun(dryRun bool) {
+	ge.dryRun = dryRun
+}
+
 func (ge *gitlabExporter) cacheAllClient(repo *cache.RepoCache, baseURL string) error {
 	creds, err := auth.List(repo,
 		auth.WithTarget(target),
@@ -82,7 +117,7 @@ func (ge *gitlabExporter) cacheAllClient(repo *cache.RepoCache, baseURL string)
 		}
 
 		if _, ok := ge.identityClient[user.Id()]; !ok {
-			client, err := buildClient(ge.conf[confKeyGitlabBaseUrl], creds[0].(*auth.Token))
+			client, err := buildClient(ge.transport, ge.conf[confKeyGitlabBaseUrl], creds[0].(*auth.Token))
 			if err != nil {
 				return err
 			}
@@ -139,7 +174,7 @@ func (ge *gitlabExporter) ExportAll(ctx context.Context, repo *cache.RepoCache,
 
 				if snapshot.HasAnyActor(allIdentitiesIds...) {
 					// try to export the bug and it associated events
-					ge.exportBug(ctx, b, out)
+					ge.exportBug(ctx, repo, b, out)
 				}
 			}
 		}
@@ -149,7 +184,7 @@ func (ge *gitlabExporter) ExportAll(ctx context.Context, repo *cache.RepoCache,
 }
 
 // exportBug publish bugs and related events
-func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out chan<- core.ExportResult) {
+func (ge *gitlabExporter) exportBug(ctx context.Context, repo *cache.RepoCache, b *cache.BugCache, out chan<- core.ExportResult) {
 	snapshot := b.Snapshot()
 
 	var bugUpdated bool
@@ -212,8 +247,16 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			return
 		}
 
+		links, err := ge.uploadAttachments(ctx, client, repo, ge.repositoryID, snapshot.Comments[0].Attachments)
+		if err != nil {
+			err := errors.Wrap(err, "uploading attachments")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
 		// create bug
-		_, id, url, err := createGitlabIssue(ctx, client, ge.repositoryID, createOp.Title, createOp.Message)
+		body := withAttachmentLinks(createOp.Message, links)
+		_, id, url, err := ge.createGitlabIssue(ctx, client, ge.repositoryID, createOp.Title, body)
 		if err != nil {
 			err := errors.Wrap(err, "exporting gitlab issue")
 			out <- core.NewExportError(err, b.Id())
@@ -223,15 +266,18 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 		idString := strconv.Itoa(id)
 		out <- core.NewExportBug(b.Id())
 
-		_, err = b.SetMetadata(
-			createOp.Id(),
-			map[string]string{
-				metaKeyGitlabId:      idString,
-				metaKeyGitlabUrl:     url,
-				metaKeyGitlabProject: ge.repositoryID,
-				metaKeyGitlabBaseUrl: GitlabBaseUrl,
-			},
-		)
+		metadata := map[string]string{
+			metaKeyGitlabId:      idString,
+			metaKeyGitlabUrl:     url,
+			metaKeyGitlabProject: ge.repositoryID,
+			metaKeyGitlabBaseUrl: GitlabBaseUrl,
+		}
+		if links != "" {
+			metadata[metaKeyGitlabAttachments] = links
+			ge.cachedAttachmentLinks[createOp.Id().String()] = links
+		}
+
+		_, err = b.SetMetadata(createOp.Id(), metadata)
 		if err != nil {
 			err := errors.Wrap(err, "marking operation as exported")
 			out <- core.NewExportError(err, b.Id())
@@ -253,6 +299,9 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 	bugCreationId = createOp.Id().String()
 	// cache operation gitlab id
 	ge.cachedOperationIDs[bugCreationId] = bugGitlabIDString
+	if links, ok := createOp.GetMetadata(metaKeyGitlabAttachments); ok {
+		ge.cachedAttachmentLinks[bugCreationId] = links
+	}
 
 	labelSet := make(map[string]struct{})
 	for _, op := range snapshot.Operations[1:] {
@@ -265,6 +314,9 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 		// cache the ID of already exported or imported issues and events from Gitlab
 		if id, ok := op.GetMetadata(metaKeyGitlabId); ok {
 			ge.cachedOperationIDs[op.Id().String()] = id
+			if links, ok := op.GetMetadata(metaKeyGitlabAttachments); ok {
+				ge.cachedAttachmentLinks[op.Id().String()] = links
+			}
 			continue
 		}
 
@@ -274,13 +326,61 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			continue
 		}
 
+		// make sure we don't silently overwrite a change made on Gitlab since
+		// the last synchronization
+		conflict, remoteID, err := ge.detectConflict(ctx, client, snapshot, op, bugGitlabID)
+		if err != nil {
+			err := errors.Wrap(err, "checking for conflict")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		if conflict != "" {
+			out <- core.NewExportConflict(b.Id(), fmt.Sprintf("%s (%s)", conflict, ge.conflictPolicy))
+
+			if err := core.RecordConflict(b, op, target, ge.conflictPolicy, conflict); err != nil {
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			switch ge.conflictPolicy {
+			case core.ConflictPolicySkip:
+				continue
+
+			case core.ConflictPolicyFail:
+				out <- core.NewExportError(fmt.Errorf("conflict: %s", conflict), b.Id())
+				return
+
+			case core.ConflictPolicyRemoteWins:
+				// leave Gitlab as is, the remote change will be imported later
+				if err := markOperationAsExported(b, op.Id(), strconv.Itoa(remoteID), "", ""); err != nil {
+					err := errors.Wrap(err, "marking operation as exported")
+					out <- core.NewExportError(err, b.Id())
+					return
+				}
+				if err := b.CommitAsNeeded(); err != nil {
+					err := errors.Wrap(err, "bug commit")
+					out <- core.NewExportError(err, b.Id())
+					return
+				}
+				continue
+			}
+		}
+
 		var id int
-		var idString, url string
+		var idString, url, exportedLinks string
 		switch op := op.(type) {
 		case *bug.AddCommentOperation:
+			links, err := ge.uploadAttachments(ctx, client, repo, ge.repositoryID, commentAttachments(snapshot, op.Id()))
+			if err != nil {
+				err := errors.Wrap(err, "uploading attachments")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
 
 			// send operation to gitlab
-			id, err = addCommentGitlabIssue(ctx, client, ge.repositoryID, bugGitlabID, op.Message)
+			body := withAttachmentLinks(op.Message, links)
+			id, err = ge.addCommentGitlabIssue(ctx, client, ge.repositoryID, bugGitlabID, body)
 			if err != nil {
 				err := errors.Wrap(err, "adding comment")
 				out <- core.NewExportError(err, b.Id())
@@ -292,6 +392,8 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			idString = strconv.Itoa(id)
 			// cache comment id
 			ge.cachedOperationIDs[op.Id().String()] = idString
+			ge.cachedAttachmentLinks[op.Id().String()] = links
+			exportedLinks = links
 
 		case *bug.EditCommentOperation:
 			targetId := op.Target.String()
@@ -300,7 +402,8 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			if targetId == bugCreationId {
 
 				// case bug creation operation: we need to edit the Gitlab issue
-				if err := updateGitlabIssueBody(ctx, client, ge.repositoryID, bugGitlabID, op.Message); err != nil {
+				body := withAttachmentLinks(op.Message, ge.cachedAttachmentLinks[targetId])
+				if err := ge.updateGitlabIssueBody(ctx, client, ge.repositoryID, bugGitlabID, body); err != nil {
 					err := errors.Wrap(err, "editing issue")
 					out <- core.NewExportError(err, b.Id())
 					return
@@ -324,7 +427,8 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 					return
 				}
 
-				if err := editCommentGitlabIssue(ctx, client, ge.repositoryID, bugGitlabID, commentIDint, op.Message); err != nil {
+				body := withAttachmentLinks(op.Message, ge.cachedAttachmentLinks[targetId])
+				if err := ge.editCommentGitlabIssue(ctx, client, ge.repositoryID, bugGitlabID, commentIDint, body); err != nil {
 					err := errors.Wrap(err, "editing comment")
 					out <- core.NewExportError(err, b.Id())
 					return
@@ -335,7 +439,7 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			}
 
 		case *bug.SetStatusOperation:
-			if err := updateGitlabIssueStatus(ctx, client, ge.repositoryID, bugGitlabID, op.Status); err != nil {
+			if err := ge.updateGitlabIssueStatus(ctx, client, ge.repositoryID, bugGitlabID, op.Status); err != nil {
 				err := errors.Wrap(err, "editing status")
 				out <- core.NewExportError(err, b.Id())
 				return
@@ -345,7 +449,7 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			id = bugGitlabID
 
 		case *bug.SetTitleOperation:
-			if err := updateGitlabIssueTitle(ctx, client, ge.repositoryID, bugGitlabID, op.Title); err != nil {
+			if err := ge.updateGitlabIssueTitle(ctx, client, ge.repositoryID, bugGitlabID, op.Title); err != nil {
 				err := errors.Wrap(err, "editing title")
 				out <- core.NewExportError(err, b.Id())
 				return
@@ -358,11 +462,11 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			// we need to set the actual list of labels at each label change operation
 			// because gitlab update issue requests need directly the latest list of the version
 
-			for _, label := range op.Added {
+			for _, label := range ge.mapping.ExportLabels(op.Added) {
 				labelSet[label.String()] = struct{}{}
 			}
 
-			for _, label := range op.Removed {
+			for _, label := range ge.mapping.ExportLabels(op.Removed) {
 				delete(labelSet, label.String())
 			}
 
@@ -371,7 +475,7 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 				labels = append(labels, key)
 			}
 
-			if err := updateGitlabIssueLabels(ctx, client, ge.repositoryID, bugGitlabID, labels); err != nil {
+			if err := ge.updateGitlabIssueLabels(ctx, client, ge.repositoryID, bugGitlabID, labels); err != nil {
 				err := errors.Wrap(err, "updating labels")
 				out <- core.NewExportError(err, b.Id())
 				return
@@ -385,7 +489,7 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 
 		idString = strconv.Itoa(id)
 		// mark operation as exported
-		if err := markOperationAsExported(b, op.Id(), idString, url); err != nil {
+		if err := markOperationAsExported(b, op.Id(), idString, url, exportedLinks); err != nil {
 			err := errors.Wrap(err, "marking operation as exported")
 			out <- core.NewExportError(err, b.Id())
 			return
@@ -406,20 +510,80 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 	}
 }
 
-func markOperationAsExported(b *cache.BugCache, target entity.Id, gitlabID, gitlabURL string) error {
-	_, err := b.SetMetadata(
-		target,
-		map[string]string{
-			metaKeyGitlabId:  gitlabID,
-			metaKeyGitlabUrl: gitlabURL,
-		},
-	)
+// detectConflict check if the Gitlab value that an operation is about to
+// overwrite changed since the last synchronization. If so, it returns a
+// description of the conflict along with the ID of the Gitlab entity.
+func (ge *gitlabExporter) detectConflict(ctx context.Context, gc *gitlab.Client, snapshot *bug.Snapshot, op dag.Operation, issueID int) (string, int, error) {
+	// nothing to compare to for an issue that hasn't really been created
+	if issueID == 0 {
+		return "", 0, nil
+	}
+
+	switch op := op.(type) {
+	case *bug.SetTitleOperation:
+		issue, err := getGitlabIssue(ctx, gc, ge.repositoryID, issueID)
+		if err != nil {
+			return "", 0, err
+		}
+		if !core.RemoteChanged(issue.Title, op.Was, op.Title) {
+			return "", 0, nil
+		}
+		return fmt.Sprintf("title changed on Gitlab to \"%s\"", issue.Title), issueID, nil
+
+	case *bug.EditCommentOperation:
+		targetId := op.Target.String()
+		links := ge.cachedAttachmentLinks[targetId]
+		expected := withAttachmentLinks(core.PreviousCommentMessage(snapshot, op), links)
+		local := withAttachmentLinks(op.Message, links)
+
+		// Since gitlab doesn't consider the issue body as a comment
+		if op.Target == snapshot.Operations[0].Id() {
+			issue, err := getGitlabIssue(ctx, gc, ge.repositoryID, issueID)
+			if err != nil {
+				return "", 0, err
+			}
+			if !core.RemoteChanged(issue.Description, expected, local) {
+				return "", 0, nil
+			}
+			return "issue description changed on Gitlab", issueID, nil
+		}
+
+		commentID, err := strconv.Atoi(ge.cachedOperationIDs[targetId])
+		if err != nil || commentID == 0 {
+			return "", 0, nil
+		}
+		note, err := getGitlabIssueNote(ctx, gc, ge.repositoryID, issueID, commentID)
+		if err != nil {
+			return "", 0, err
+		}
+		if !core.RemoteChanged(note.Body, expected, local) {
+			return "", 0, nil
+		}
+		return "comment changed on Gitlab", commentID, nil
+	}
+
+	return "", 0, nil
+}
+
+func markOperationAsExported(b *cache.BugCache, target entity.Id, gitlabID, gitlabURL, attachmentLinks string) error {
+	metadata := map[string]string{
+		metaKeyGitlabId:  gitlabID,
+		metaKeyGitlabUrl: gitlabURL,
+	}
+	if attachmentLinks != "" {
+		metadata[metaKeyGitlabAttachments] = attachmentLinks
+	}
+	_, err := b.SetMetadata(target, metadata)
 
 	return err
 }
 
 // create a gitlab. issue and return it ID
-func createGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID, title, body string) (int, int, string, error) {
+func (ge *gitlabExporter) createGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID, title, body string) (int, int, string, error) {
+	if ge.dryRun {
+		return 0, 0, "", nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	issue, _, err := gc.Issues.CreateIssue(
@@ -437,8 +601,28 @@ func createGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID, tit
 	return issue.ID, issue.IID, issue.WebURL, nil
 }
 
+// fetch the current state of an issue
+func getGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int) (*gitlab.Issue, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	issue, _, err := gc.Issues.GetIssue(repositoryID, issueID, gitlab.WithContext(ctx))
+	return issue, err
+}
+
+// fetch the current state of an issue comment
+func getGitlabIssueNote(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID, noteID int) (*gitlab.Note, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	note, _, err := gc.Notes.GetIssueNote(repositoryID, issueID, noteID, gitlab.WithContext(ctx))
+	return note, err
+}
+
 // add a comment to an issue and return it ID
-func addCommentGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, body string) (int, error) {
+func (ge *gitlabExporter) addCommentGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, body string) (int, error) {
+	if ge.dryRun {
+		return 0, nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	note, _, err := gc.Notes.CreateIssueNote(
@@ -455,7 +639,11 @@ func addCommentGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID
 	return note.ID, nil
 }
 
-func editCommentGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID, noteID int, body string) error {
+func (ge *gitlabExporter) editCommentGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID, noteID int, body string) error {
+	if ge.dryRun {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	_, _, err := gc.Notes.UpdateIssueNote(
@@ -469,7 +657,11 @@ func editCommentGitlabIssue(ctx context.Context, gc *gitlab.Client, repositoryID
 	return err
 }
 
-func updateGitlabIssueStatus(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, status common.Status) error {
+func (ge *gitlabExporter) updateGitlabIssueStatus(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, status common.Status) error {
+	if ge.dryRun {
+		return nil
+	}
+
 	var state string
 
 	switch status {
@@ -494,7 +686,11 @@ func updateGitlabIssueStatus(ctx context.Context, gc *gitlab.Client, repositoryI
 	return err
 }
 
-func updateGitlabIssueBody(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, body string) error {
+func (ge *gitlabExporter) updateGitlabIssueBody(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, body string) error {
+	if ge.dryRun {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	_, _, err := gc.Issues.UpdateIssue(
@@ -508,7 +704,11 @@ func updateGitlabIssueBody(ctx context.Context, gc *gitlab.Client, repositoryID
 	return err
 }
 
-func updateGitlabIssueTitle(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, title string) error {
+func (ge *gitlabExporter) updateGitlabIssueTitle(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, title string) error {
+	if ge.dryRun {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	_, _, err := gc.Issues.UpdateIssue(
@@ -523,7 +723,11 @@ func updateGitlabIssueTitle(ctx context.Context, gc *gitlab.Client, repositoryID
 }
 
 // update gitlab. issue labels
-func updateGitlabIssueLabels(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, labels []string) error {
+func (ge *gitlabExporter) updateGitlabIssueLabels(ctx context.Context, gc *gitlab.Client, repositoryID string, issueID int, labels []string) error {
+	if ge.dryRun {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	gitlabLabels := gitlab.LabelOptions(labels)
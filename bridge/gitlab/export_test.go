@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"os"
 	"strconv"
 	"testing"
@@ -292,7 +293,7 @@ func generateRepoName() string {
 
 // create repository need a token with scope 'repo'
 func createRepository(ctx context.Context, name string, token *auth.Token) (int, error) {
-	client, err := buildClient(defaultBaseURL, token)
+	client, err := buildClient(http.DefaultTransport, defaultBaseURL, token)
 	if err != nil {
 		return 0, err
 	}
@@ -314,7 +315,7 @@ func createRepository(ctx context.Context, name string, token *auth.Token) (int,
 
 // delete repository need a token with scope 'delete_repo'
 func deleteRepository(ctx context.Context, project int, token *auth.Token) error {
-	client, err := buildClient(defaultBaseURL, token)
+	client, err := buildClient(http.DefaultTransport, defaultBaseURL, token)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package gitlab
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/xanzy/go-gitlab"
@@ -18,6 +19,10 @@ const (
 	metaKeyGitlabProject = "gitlab-project-id"
 	metaKeyGitlabBaseUrl = "gitlab-base-url"
 
+	// links to the attachments uploaded when exporting a comment, kept to
+	// export the edits of that comment
+	metaKeyGitlabAttachments = "gitlab-attachments"
+
 	confKeyProjectID     = "project-id"
 	confKeyGitlabBaseUrl = "base-url"
 	confKeyDefaultLogin  = "default-login"
@@ -46,9 +51,10 @@ func (Gitlab) NewExporter() core.Exporter {
 	return &gitlabExporter{}
 }
 
-func buildClient(baseURL string, token *auth.Token) (*gitlab.Client, error) {
+func buildClient(transport http.RoundTripper, baseURL string, token *auth.Token) (*gitlab.Client, error) {
 	gitlabClient, err := gitlab.NewClient(token.Value,
 		gitlab.WithBaseURL(baseURL),
+		gitlab.WithHTTPClient(&http.Client{Transport: transport}),
 	)
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package gitlab
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -11,6 +12,8 @@ import (
 	"github.com/MichaelMure/git-bug/bridge/core"
 	"github.com/MichaelMure/git-bug/bridge/core/auth"
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/util/text"
 )
@@ -19,16 +22,29 @@ import (
 type gitlabImporter struct {
 	conf core.Configuration
 
+	// label and status mapping
+	mapping *core.Mapping
+
 	// default client
 	client *gitlab.Client
 
+	// token and client used to download the attachments
+	token      string
+	httpClient *http.Client
+
 	// send only channel
 	out chan<- core.ImportResult
 }
 
-func (gi *gitlabImporter) Init(_ context.Context, repo *cache.RepoCache, conf core.Configuration) error {
+func (gi *gitlabImporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
 	gi.conf = conf
 
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	gi.mapping = mapping
+
 	creds, err := auth.List(repo,
 		auth.WithTarget(target),
 		auth.WithKind(auth.KindToken),
@@ -43,10 +59,14 @@ func (gi *gitlabImporter) Init(_ context.Context, repo *cache.RepoCache, conf co
 		return ErrMissingIdentityToken
 	}
 
-	gi.client, err = buildClient(conf[confKeyGitlabBaseUrl], creds[0].(*auth.Token))
+	token := creds[0].(*auth.Token)
+	transport := core.HTTPTransport(ctx)
+	gi.client, err = buildClient(transport, conf[confKeyGitlabBaseUrl], token)
 	if err != nil {
 		return err
 	}
+	gi.token = token.Value
+	gi.httpClient = &http.Client{Transport: transport, Timeout: defaultTimeout}
 
 	return nil
 }
@@ -61,46 +81,53 @@ func (gi *gitlabImporter) ImportAll(ctx context.Context, repo *cache.RepoCache,
 		defer close(out)
 
 		for issue := range Issues(ctx, gi.client, gi.conf[confKeyProjectID], since) {
-
-			b, err := gi.ensureIssue(repo, issue)
-			if err != nil {
-				err := fmt.Errorf("issue creation: %v", err)
+			if err := gi.importIssue(ctx, repo, issue, out); err != nil {
 				out <- core.NewImportError(err, "")
 				return
 			}
+		}
+	}()
 
-			issueEvents := SortedEvents(
-				Notes(ctx, gi.client, issue),
-				LabelEvents(ctx, gi.client, issue),
-				StateEvents(ctx, gi.client, issue),
-			)
+	return out, nil
+}
 
-			for e := range issueEvents {
-				if e, ok := e.(ErrorEvent); ok {
-					out <- core.NewImportError(e.Err, "")
-					continue
-				}
-				if err := gi.ensureIssueEvent(repo, b, issue, e); err != nil {
-					err := fmt.Errorf("issue event creation: %v", err)
-					out <- core.NewImportError(err, entity.Id(e.ID()))
-				}
-			}
+// importIssue ensure the creation of an issue and of all its events. Only
+// the errors preventing to go further are returned, the others are sent as
+// import events.
+func (gi *gitlabImporter) importIssue(ctx context.Context, repo *cache.RepoCache, issue *gitlab.Issue, out chan<- core.ImportResult) error {
+	b, err := gi.ensureIssue(ctx, repo, issue)
+	if err != nil {
+		return fmt.Errorf("issue creation: %v", err)
+	}
 
-			if !b.NeedCommit() {
-				out <- core.NewImportNothing(b.Id(), "no imported operation")
-			} else if err := b.Commit(); err != nil {
-				// commit bug state
-				err := fmt.Errorf("bug commit: %v", err)
-				out <- core.NewImportError(err, "")
-				return
-			}
+	issueEvents := SortedEvents(
+		Notes(ctx, gi.client, issue),
+		LabelEvents(ctx, gi.client, issue),
+		StateEvents(ctx, gi.client, issue),
+	)
+
+	for e := range issueEvents {
+		if e, ok := e.(ErrorEvent); ok {
+			out <- core.NewImportError(e.Err, "")
+			continue
 		}
-	}()
+		if err := gi.ensureIssueEvent(ctx, repo, b, issue, e); err != nil {
+			err := fmt.Errorf("issue event creation: %v", err)
+			out <- core.NewImportError(err, entity.Id(e.ID()))
+		}
+	}
 
-	return out, nil
+	if !b.NeedCommit() {
+		out <- core.NewImportNothing(b.Id(), "no imported operation")
+	} else if err := b.Commit(); err != nil {
+		// commit bug state
+		return fmt.Errorf("bug commit: %v", err)
+	}
+
+	return nil
 }
 
-func (gi *gitlabImporter) ensureIssue(repo *cache.RepoCache, issue *gitlab.Issue) (*cache.BugCache, error) {
+func (gi *gitlabImporter) ensureIssue(ctx context.Context, repo *cache.RepoCache, issue *gitlab.Issue) (*cache.BugCache, error) {
 	// ensure issue author
 	author, err := gi.ensurePerson(repo, issue.Author.ID)
 	if err != nil {
@@ -121,20 +148,22 @@ func (gi *gitlabImporter) ensureIssue(repo *cache.RepoCache, issue *gitlab.Issue
 		return nil, err
 	}
 
+	attachments := gi.importAttachments(ctx, repo, issue, issue.Description, "")
+
 	// if bug was never imported, create bug
 	b, _, err = repo.Bugs().NewRaw(
 		author,
 		issue.CreatedAt.Unix(),
 		text.CleanupOneLine(issue.Title),
 		text.Cleanup(issue.Description),
-		nil,
-		map[string]string{
+		bug.AttachmentHashes(attachments),
+		core.WithAttachmentsMetadata(map[string]string{
 			core.MetaKeyOrigin:   target,
 			metaKeyGitlabId:      fmt.Sprintf("%d", issue.IID),
 			metaKeyGitlabUrl:     issue.WebURL,
 			metaKeyGitlabProject: gi.conf[confKeyProjectID],
 			metaKeyGitlabBaseUrl: gi.conf[confKeyGitlabBaseUrl],
-		},
+		}, attachments),
 	)
 
 	if err != nil {
@@ -147,7 +176,7 @@ func (gi *gitlabImporter) ensureIssue(repo *cache.RepoCache, issue *gitlab.Issue
 	return b, nil
 }
 
-func (gi *gitlabImporter) ensureIssueEvent(repo *cache.RepoCache, b *cache.BugCache, issue *gitlab.Issue, event Event) error {
+func (gi *gitlabImporter) ensureIssueEvent(ctx context.Context, repo *cache.RepoCache, b *cache.BugCache, issue *gitlab.Issue, event Event) error {
 	id, errResolve := b.ResolveOperationWithMetadata(metaKeyGitlabId, event.ID())
 	if errResolve != nil && errResolve != cache.ErrNoMatchingOp {
 		return errResolve
@@ -165,37 +194,54 @@ func (gi *gitlabImporter) ensureIssueEvent(repo *cache.RepoCache, b *cache.BugCa
 			return nil
 		}
 
-		op, err := b.CloseRaw(
+		op, labelOp, err := gi.mapping.ApplyStatus(
+			b,
 			author,
 			event.CreatedAt().Unix(),
+			"closed",
+			common.ClosedStatus,
 			map[string]string{
 				metaKeyGitlabId: event.ID(),
 			},
+			map[string]string{
+				metaKeyGitlabId: core.StatusLabelsId(event.ID()),
+			},
 		)
-
 		if err != nil {
 			return err
 		}
 
 		gi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+		if labelOp != nil {
+			gi.out <- core.NewImportLabelChange(b.Id(), labelOp.Id())
+		}
 
 	case EventReopened:
 		if errResolve == nil {
 			return nil
 		}
 
-		op, err := b.OpenRaw(
+		op, labelOp, err := gi.mapping.ApplyStatus(
+			b,
 			author,
 			event.CreatedAt().Unix(),
+			"open",
+			common.OpenStatus,
 			map[string]string{
 				metaKeyGitlabId: event.ID(),
 			},
+			map[string]string{
+				metaKeyGitlabId: core.StatusLabelsId(event.ID()),
+			},
 		)
 		if err != nil {
 			return err
 		}
 
 		gi.out <- core.NewImportStatusChange(b.Id(), op.Id())
+		if labelOp != nil {
+			gi.out <- core.NewImportLabelChange(b.Id(), labelOp.Id())
+		}
 
 	case EventDescriptionChanged:
 		firstComment := b.Snapshot().Comments[0]
@@ -203,7 +249,7 @@ func (gi *gitlabImporter) ensureIssueEvent(repo *cache.RepoCache, b *cache.BugCa
 		// we should check for "changed the description" notes and compare issue texts
 		// TODO: Check only one time and ignore next 'description change' within one issue
 		cleanedDesc := text.Cleanup(issue.Description)
-		if errResolve == cache.ErrNoMatchingOp && cleanedDesc != firstComment.Message {
+		if errResolve == cache.ErrNoMatchingOp && !sameMessage(firstComment.Message, cleanedDesc) {
 			// comment edition
 			op, err := b.EditCommentRaw(
 				author,
@@ -226,16 +272,17 @@ func (gi *gitlabImporter) ensureIssueEvent(repo *cache.RepoCache, b *cache.BugCa
 
 		// if we didn't import the comment
 		if errResolve == cache.ErrNoMatchingOp {
+			attachments := gi.importAttachments(ctx, repo, issue, cleanText, b.Id())
 
 			// add comment operation
 			commentId, _, err := b.AddCommentRaw(
 				author,
 				event.CreatedAt().Unix(),
 				cleanText,
-				nil,
-				map[string]string{
+				bug.AttachmentHashes(attachments),
+				core.WithAttachmentsMetadata(map[string]string{
 					metaKeyGitlabId: event.ID(),
-				},
+				}, attachments),
 			)
 			if err != nil {
 				return err
@@ -253,7 +300,7 @@ func (gi *gitlabImporter) ensureIssueEvent(repo *cache.RepoCache, b *cache.BugCa
 		}
 
 		// compare local bug comment with the new event body
-		if comment.Message != cleanText {
+		if !sameMessage(comment.Message, cleanText) {
 			// comment edition
 			_, err := b.EditCommentRaw(
 				author,
@@ -292,10 +339,15 @@ func (gi *gitlabImporter) ensureIssueEvent(repo *cache.RepoCache, b *cache.BugCa
 		gi.out <- core.NewImportTitleEdition(b.Id(), op.Id())
 
 	case EventAddLabel:
+		label, ok := gi.mapping.ImportLabel(event.(LabelEvent).Label.Name)
+		if !ok {
+			return nil
+		}
+
 		_, err = b.ForceChangeLabelsRaw(
 			author,
 			event.CreatedAt().Unix(),
-			[]string{event.(LabelEvent).Label.Name},
+			[]string{label},
 			nil,
 			map[string]string{
 				metaKeyGitlabId: event.ID(),
@@ -304,11 +356,16 @@ func (gi *gitlabImporter) ensureIssueEvent(repo *cache.RepoCache, b *cache.BugCa
 		return err
 
 	case EventRemoveLabel:
+		label, ok := gi.mapping.ImportLabel(event.(LabelEvent).Label.Name)
+		if !ok {
+			return nil
+		}
+
 		_, err = b.ForceChangeLabelsRaw(
 			author,
 			event.CreatedAt().Unix(),
 			nil,
-			[]string{event.(LabelEvent).Label.Name},
+			[]string{label},
 			map[string]string{
 				metaKeyGitlabId: event.ID(),
 			},
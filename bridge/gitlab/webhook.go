@@ -0,0 +1,101 @@
+package gitlab
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+// webhookPayload is the subset of the issue and comment webhook payloads
+// needed to find the affected issue.
+// See https://docs.gitlab.com/ee/user/project/integrations/webhook_events.html
+type webhookPayload struct {
+	Project struct {
+		ID int `json:"id"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID          int    `json:"iid"`
+		NoteableType string `json:"noteable_type"`
+	} `json:"object_attributes"`
+	Issue struct {
+		IID int `json:"iid"`
+	} `json:"issue"`
+}
+
+// ImportWebhook import the issue affected by a Gitlab webhook request
+func (gi *gitlabImporter) ImportWebhook(ctx context.Context, repo *cache.RepoCache, secret string, header http.Header, payload []byte) (<-chan core.ImportResult, error) {
+	iid, reason, err := parseWebhook(secret, gi.conf[confKeyProjectID], header, payload)
+	if err != nil {
+		return nil, err
+	}
+	if iid == 0 {
+		return core.NewWebhookIgnored(reason), nil
+	}
+
+	issue, _, err := gi.client.Issues.GetIssue(gi.conf[confKeyProjectID], iid, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan core.ImportResult)
+	gi.out = out
+
+	go func() {
+		defer close(out)
+
+		if err := gi.importIssue(ctx, repo, issue, out); err != nil {
+			out <- core.NewImportError(err, "")
+		}
+	}()
+
+	return out, nil
+}
+
+// parseWebhook authenticate a webhook request and return the IID of the
+// affected issue, or zero and a reason if there is nothing to import.
+func parseWebhook(secret, projectID string, header http.Header, payload []byte) (int, string, error) {
+	token := header.Get("X-Gitlab-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return 0, "", core.ErrWebhookSignature
+	}
+
+	event := header.Get("X-Gitlab-Event")
+	switch event {
+	case "Issue Hook", "Confidential Issue Hook", "Note Hook", "Confidential Note Hook":
+	default:
+		return 0, fmt.Sprintf("ignored webhook event \"%s\"", event), nil
+	}
+
+	var p webhookPayload
+	err := json.Unmarshal(payload, &p)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid webhook payload: %v", err)
+	}
+
+	if strconv.Itoa(p.Project.ID) != projectID {
+		return 0, "", fmt.Errorf("webhook for another project: %d", p.Project.ID)
+	}
+
+	iid := p.ObjectAttributes.IID
+	if event == "Note Hook" || event == "Confidential Note Hook" {
+		// comments can be on merge requests, commits or snippets as well
+		if p.ObjectAttributes.NoteableType != "Issue" {
+			return 0, fmt.Sprintf("ignored comment on %s", p.ObjectAttributes.NoteableType), nil
+		}
+		iid = p.Issue.IID
+	}
+
+	if iid <= 0 {
+		return 0, "", fmt.Errorf("invalid webhook payload: missing issue iid")
+	}
+
+	return iid, "", nil
+}
@@ -0,0 +1,47 @@
+package gitlab
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+)
+
+func TestParseWebhook(t *testing.T) {
+	header := func(token, event string) http.Header {
+		h := make(http.Header)
+		h.Set("X-Gitlab-Token", token)
+		h.Set("X-Gitlab-Event", event)
+		return h
+	}
+
+	issue := []byte(`{"object_kind":"issue","project":{"id":1234},"object_attributes":{"iid":7}}`)
+	note := []byte(`{"object_kind":"note","project":{"id":1234},"object_attributes":{"noteable_type":"Issue"},"issue":{"iid":8}}`)
+	mrNote := []byte(`{"object_kind":"note","project":{"id":1234},"object_attributes":{"noteable_type":"MergeRequest"}}`)
+
+	iid, _, err := parseWebhook("secret", "1234", header("secret", "Issue Hook"), issue)
+	require.NoError(t, err)
+	require.Equal(t, 7, iid)
+
+	iid, _, err = parseWebhook("secret", "1234", header("secret", "Note Hook"), note)
+	require.NoError(t, err)
+	require.Equal(t, 8, iid)
+
+	iid, reason, err := parseWebhook("secret", "1234", header("secret", "Note Hook"), mrNote)
+	require.NoError(t, err)
+	require.Zero(t, iid)
+	require.NotEmpty(t, reason)
+
+	iid, reason, err = parseWebhook("secret", "1234", header("secret", "Push Hook"), issue)
+	require.NoError(t, err)
+	require.Zero(t, iid)
+	require.NotEmpty(t, reason)
+
+	_, _, err = parseWebhook("secret", "1234", header("wrong", "Issue Hook"), issue)
+	require.ErrorIs(t, err, core.ErrWebhookSignature)
+
+	_, _, err = parseWebhook("secret", "4321", header("secret", "Issue Hook"), issue)
+	require.Error(t, err)
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/MichaelMure/git-bug/bridge/core"
 	"github.com/MichaelMure/git-bug/entities/bug"
 )
 
@@ -134,6 +135,24 @@ type IssueFields struct {
 	Summary     string      `json:"summary"`
 	Comments    CommentPage `json:"comment"`
 	Labels      []string    `json:"labels"`
+
+	// Raw holds all the fields as returned by JIRA, to read the fields that
+	// don't have a dedicated member, like the custom ones
+	Raw map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the known fields, and keeps all of them in Raw
+func (fields *IssueFields) UnmarshalJSON(data []byte) error {
+	type plain IssueFields
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &decoded.Raw); err != nil {
+		return err
+	}
+	*fields = IssueFields(decoded)
+	return nil
 }
 
 // ChangeLogItem "field-change" data within a changelog entry. A single
@@ -143,6 +162,7 @@ type IssueFields struct {
 type ChangeLogItem struct {
 	Field      string `json:"field"`
 	FieldType  string `json:"fieldtype"`
+	FieldID    string `json:"fieldId"` // Cloud-only
 	From       string `json:"from"`
 	FromString string `json:"fromString"`
 	To         string `json:"to"`
@@ -245,6 +265,12 @@ type IssueCreate struct {
 	Fields IssueCreateFields `json:"fields"`
 }
 
+// IssueUpdate the JSON object that is PUT to the /issue endpoint to apply a
+// list of operations ("add", "remove" or "set") on some fields of an issue
+type IssueUpdate struct {
+	Update map[string][]map[string]interface{} `json:"update"`
+}
+
 // IssueCreateResult the JSON object returned after issue creation.
 type IssueCreateResult struct {
 	ID  string `json:"id"`
@@ -335,18 +361,28 @@ type Client struct {
 	*http.Client
 	serverURL string
 	ctx       context.Context
+
+	// additional fields requested when searching issues
+	searchFields []string
 }
 
 // NewClient Construct a new client connected to the provided server and
-// utilizing the given context for asynchronous events
+// utilizing the given context for asynchronous events, and for the HTTP
+// transport to use (see core.WithHTTPTransport)
 func NewClient(ctx context.Context, serverURL string) *Client {
 	cookiJar, _ := cookiejar.New(nil)
 	client := &http.Client{
-		Transport: &ClientTransport{underlyingTransport: http.DefaultTransport},
+		Transport: &ClientTransport{underlyingTransport: core.HTTPTransport(ctx)},
 		Jar:       cookiJar,
 	}
 
-	return &Client{client, serverURL, ctx}
+	return &Client{Client: client, serverURL: serverURL, ctx: ctx}
+}
+
+// AddSearchFields request some additional fields when searching issues, like
+// the custom fields
+func (client *Client) AddSearchFields(fields ...string) {
+	client.searchFields = append(client.searchFields, fields...)
 }
 
 // Login POST credentials to the /session endpoint and get a session cookie
@@ -457,14 +493,14 @@ func (client *Client) Search(jql string, maxResults int, startAt int) (*SearchRe
 		JQL:        jql,
 		StartAt:    startAt,
 		MaxResults: maxResults,
-		Fields: []string{
+		Fields: append([]string{
 			"comment",
 			"created",
 			"creator",
 			"description",
 			"labels",
 			"status",
-			"summary"}})
+			"summary"}, client.searchFields...)})
 	if err != nil {
 		return nil, err
 	}
@@ -1298,6 +1334,58 @@ func (client *Client) UpdateLabels(issueKeyOrID string, added, removed []bug.Lab
 	return responseTime, nil
 }
 
+// UpdateFields applies some operations on the fields of an issue
+func (client *Client) UpdateFields(issueKeyOrID string, update map[string][]map[string]interface{}) (time.Time, error) {
+	url := fmt.Sprintf(
+		"%s/rest/api/2/issue/%s/", client.serverURL, issueKeyOrID)
+	var responseTime time.Time
+
+	data, err := json.Marshal(IssueUpdate{Update: update})
+	if err != nil {
+		return responseTime, err
+	}
+
+	request, err := http.NewRequest("PUT", url, bytes.NewBuffer(data))
+	if err != nil {
+		return responseTime, err
+	}
+
+	if client.ctx != nil {
+		ctx, cancel := context.WithTimeout(client.ctx, defaultTimeout)
+		defer cancel()
+		request = request.WithContext(ctx)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		err := fmt.Errorf("Performing request %v", err)
+		return responseTime, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		content, _ := io.ReadAll(response.Body)
+		err := fmt.Errorf(
+			"HTTP response %d, query was %s\n  data: %s\n  response: %s",
+			response.StatusCode, request.URL.String(), data, content)
+		return responseTime, err
+	}
+
+	dateHeader, ok := response.Header["Date"]
+	if !ok || len(dateHeader) != 1 {
+		// No "Date" header, or empty, or multiple of them. Regardless, we don't
+		// have a date we can return
+		return responseTime, nil
+	}
+
+	responseTime, err = http.ParseTime(dateHeader[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return responseTime, nil
+}
+
 // GetTransitions returns a list of available transitions for an issue
 func (client *Client) GetTransitions(issueKeyOrID string) (*TransitionList, error) {
 
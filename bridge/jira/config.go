@@ -176,6 +176,9 @@ func (*Jira) ValidateConfig(conf core.Configuration) error {
 	if _, ok := conf[confKeyDefaultLogin]; !ok {
 		return fmt.Errorf("missing %s key", confKeyDefaultLogin)
 	}
+	if _, err := loadFieldMappings(conf); err != nil {
+		return err
+	}
 
 	return nil
 }
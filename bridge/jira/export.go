@@ -22,6 +22,10 @@ var (
 	ErrMissingCredentials = errors.New("missing credentials")
 )
 
+// dryRunID is the placeholder ID given to the JIRA entities that would have
+// been created, in dry-run mode
+const dryRunID = "dry-run"
+
 // jiraExporter implement the Exporter interface
 type jiraExporter struct {
 	conf core.Configuration
@@ -32,6 +36,15 @@ type jiraExporter struct {
 	// the mapping from git-bug "status" to JIRA "status" id
 	statusMap map[string]string
 
+	// label and status mapping
+	mapping *core.Mapping
+
+	// if true, don't call the mutating endpoints
+	dryRun bool
+
+	// mapping of the priority, components, versions or custom fields
+	fields fieldMappings
+
 	// cache identifiers used to speed up exporting operations
 	// cleared for each bug
 	cachedOperationIDs map[entity.Id]string
@@ -56,6 +69,18 @@ func (je *jiraExporter) Init(ctx context.Context, repo *cache.RepoCache, conf co
 	}
 	je.statusMap = statusMap
 
+	mapping, err := core.LoadMapping(je.conf)
+	if err != nil {
+		return err
+	}
+	je.mapping = mapping
+
+	fields, err := loadFieldMappings(je.conf)
+	if err != nil {
+		return err
+	}
+	je.fields = fields
+
 	// preload all clients
 	err = je.cacheAllClient(ctx, repo)
 	if err != nil {
@@ -84,6 +109,11 @@ func (je *jiraExporter) Init(ctx context.Context, repo *cache.RepoCache, conf co
 	return nil
 }
 
+// SetDryRun .
+func (je *jiraExporter) SetDryRun(dryRun bool) {
+	je.dryRun = dryRun
+}
+
 func (je *jiraExporter) cacheAllClient(ctx context.Context, repo *cache.RepoCache) error {
 	creds, err := auth.List(repo,
 		auth.WithTarget(target),
@@ -260,16 +290,24 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 			fields[bugIDField] = b.Id().String()
 		}
 
+		// the fields kept as bug metadata are only sent when creating the issue
+		for field, m := range je.fields {
+			if m.Metadata == "" {
+				continue
+			}
+			if value, ok := snapshot.GetCreateMetadata(m.Metadata); ok && value != "" {
+				fields[field] = m.createValue(value)
+			}
+		}
+
 		// create bug
-		result, err := client.CreateIssue(
-			je.project.ID, createOp.Title, createOp.Message, fields)
+		id, err := je.createJiraIssue(client, createOp.Title, createOp.Message, fields)
 		if err != nil {
 			err := errors.Wrap(err, "exporting jira issue")
 			out <- core.NewExportError(err, b.Id())
 			return err
 		}
 
-		id := result.ID
 		out <- core.NewExportBug(b.Id())
 		// mark bug creation operation as exported
 		err = markOperationAsExported(
@@ -322,13 +360,12 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 		var exportTime time.Time
 		switch opr := op.(type) {
 		case *bug.AddCommentOperation:
-			comment, err := client.AddComment(bugJiraID, opr.Message)
+			id, err = je.addJiraComment(client, bugJiraID, opr.Message)
 			if err != nil {
 				err := errors.Wrap(err, "adding comment")
 				out <- core.NewExportError(err, b.Id())
 				return err
 			}
-			id = comment.ID
 			out <- core.NewExportComment(b.Id())
 
 			// cache comment id
@@ -338,7 +375,7 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 			if opr.Target == createOp.Id() {
 				// An EditCommentOpreation with the Target set to the create operation
 				// encodes a modification to the long-description/summary.
-				exportTime, err = client.UpdateIssueBody(bugJiraID, opr.Message)
+				exportTime, err = je.updateJiraIssueBody(client, bugJiraID, opr.Message)
 				if err != nil {
 					err := errors.Wrap(err, "editing issue")
 					out <- core.NewExportError(err, b.Id())
@@ -356,25 +393,23 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 					// have cached the creation id.
 					panic("unexpected error: comment id not found")
 				}
-				comment, err := client.UpdateComment(bugJiraID, commentID, opr.Message)
+				id, err = je.updateJiraComment(client, bugJiraID, commentID, opr.Message)
 				if err != nil {
 					err := errors.Wrap(err, "editing comment")
 					out <- core.NewExportError(err, b.Id())
 					return err
 				}
 				out <- core.NewExportCommentEdition(b.Id())
-				// JIRA doesn't track all comment edits, they will only tell us about
-				// the most recent one. We must invent a consistent id for the operation
-				// so we use the comment ID plus the timestamp of the update, as
-				// reported by JIRA. Note that this must be consistent with the importer
-				// during ensureComment()
-				id = getTimeDerivedID(comment.ID, comment.Updated)
 			}
 
 		case *bug.SetStatusOperation:
-			jiraStatus, hasStatus := je.statusMap[opr.Status.String()]
+			// the status mapping takes precedence over the status id map
+			jiraStatus, hasStatus := je.mapping.ExportStatus(opr.Status, core.LabelsAt(snapshot, opr))
+			if !hasStatus {
+				jiraStatus, hasStatus = je.statusMap[opr.Status.String()]
+			}
 			if hasStatus {
-				exportTime, err = UpdateIssueStatus(client, bugJiraID, jiraStatus)
+				exportTime, err = je.updateJiraIssueStatus(client, bugJiraID, jiraStatus)
 				if err != nil {
 					err := errors.Wrap(err, "editing status")
 					out <- core.NewExportWarning(err, b.Id())
@@ -391,7 +426,7 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 			}
 
 		case *bug.SetTitleOperation:
-			exportTime, err = client.UpdateIssueTitle(bugJiraID, opr.Title)
+			exportTime, err = je.updateJiraIssueTitle(client, bugJiraID, opr.Title)
 			if err != nil {
 				err := errors.Wrap(err, "editing title")
 				out <- core.NewExportError(err, b.Id())
@@ -401,8 +436,7 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 			id = bugJiraID
 
 		case *bug.LabelChangeOperation:
-			exportTime, err = client.UpdateLabels(
-				bugJiraID, opr.Added, opr.Removed)
+			exportTime, err = je.exportLabelChange(client, bugJiraID, opr)
 			if err != nil {
 				err := errors.Wrap(err, "updating labels")
 				out <- core.NewExportError(err, b.Id())
@@ -437,6 +471,120 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 	return nil
 }
 
+// exportLabelChange update the labels of an issue, along with the fields
+// whose values are kept as labels, in a single changelog entry
+func (je *jiraExporter) exportLabelChange(client *Client, bugJiraID string, opr *bug.LabelChangeOperation) (time.Time, error) {
+	update := make(map[string][]map[string]interface{})
+
+	for _, label := range je.mapping.ExportLabels(je.fields.plainLabels(opr.Added)) {
+		update["labels"] = append(update["labels"], map[string]interface{}{"add": label})
+	}
+	for _, label := range je.mapping.ExportLabels(je.fields.plainLabels(opr.Removed)) {
+		update["labels"] = append(update["labels"], map[string]interface{}{"remove": label})
+	}
+
+	// a single valued field is replaced by the added value, or cleared
+	for _, label := range opr.Removed {
+		field, m, value, ok := je.fields.forLabel(label)
+		if !ok {
+			continue
+		}
+		if m.Multiple {
+			update[field] = append(update[field], map[string]interface{}{"remove": m.encode(value)})
+		} else if _, isSet := update[field]; !isSet {
+			update[field] = []map[string]interface{}{{"set": nil}}
+		}
+	}
+	for _, label := range opr.Added {
+		field, m, value, ok := je.fields.forLabel(label)
+		if !ok {
+			continue
+		}
+		if m.Multiple {
+			update[field] = append(update[field], map[string]interface{}{"add": m.encode(value)})
+		} else {
+			update[field] = []map[string]interface{}{{"set": m.encode(value)}}
+		}
+	}
+
+	if len(update) == 0 || je.dryRun {
+		return time.Time{}, nil
+	}
+
+	return client.UpdateFields(bugJiraID, update)
+}
+
+// create a JIRA issue and return its ID
+func (je *jiraExporter) createJiraIssue(client *Client, title, body string, fields map[string]interface{}) (string, error) {
+	if je.dryRun {
+		return dryRunID, nil
+	}
+
+	result, err := client.CreateIssue(je.project.ID, title, body, fields)
+	if err != nil {
+		return "", err
+	}
+
+	return result.ID, nil
+}
+
+// add a comment to a JIRA issue and return its ID
+func (je *jiraExporter) addJiraComment(client *Client, bugJiraID, body string) (string, error) {
+	if je.dryRun {
+		return dryRunID, nil
+	}
+
+	comment, err := client.AddComment(bugJiraID, body)
+	if err != nil {
+		return "", err
+	}
+
+	return comment.ID, nil
+}
+
+// edit a JIRA comment and return the ID of the edition
+func (je *jiraExporter) updateJiraComment(client *Client, bugJiraID, commentID, body string) (string, error) {
+	if je.dryRun {
+		return dryRunID, nil
+	}
+
+	comment, err := client.UpdateComment(bugJiraID, commentID, body)
+	if err != nil {
+		return "", err
+	}
+
+	// JIRA doesn't track all comment edits, they will only tell us about
+	// the most recent one. We must invent a consistent id for the operation
+	// so we use the comment ID plus the timestamp of the update, as
+	// reported by JIRA. Note that this must be consistent with the importer
+	// during ensureComment()
+	return getTimeDerivedID(comment.ID, comment.Updated), nil
+}
+
+func (je *jiraExporter) updateJiraIssueBody(client *Client, bugJiraID, body string) (time.Time, error) {
+	if je.dryRun {
+		return time.Time{}, nil
+	}
+
+	return client.UpdateIssueBody(bugJiraID, body)
+}
+
+func (je *jiraExporter) updateJiraIssueTitle(client *Client, bugJiraID, title string) (time.Time, error) {
+	if je.dryRun {
+		return time.Time{}, nil
+	}
+
+	return client.UpdateIssueTitle(bugJiraID, title)
+}
+
+func (je *jiraExporter) updateJiraIssueStatus(client *Client, bugJiraID, status string) (time.Time, error) {
+	if je.dryRun {
+		return time.Time{}, nil
+	}
+
+	return UpdateIssueStatus(client, bugJiraID, status)
+}
+
 func markOperationAsExported(b *cache.BugCache, target entity.Id, jiraID, jiraProject string, exportTime time.Time) error {
 	newMetadata := map[string]string{
 		metaKeyJiraId:      jiraID,
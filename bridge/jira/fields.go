@@ -0,0 +1,297 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// the value types of a field, as expected by the JIRA API on update
+const (
+	fieldTypeName   = "name"   // an object with a name, like a priority or a component
+	fieldTypeValue  = "value"  // an object with a value, like a custom select list
+	fieldTypeString = "string" // a plain string, like a custom text field
+)
+
+// the fields the bridge already handle on its own
+var reservedFields = map[string]bool{
+	"summary":     true,
+	"description": true,
+	"labels":      true,
+	"status":      true,
+	"comment":     true,
+}
+
+// the standard fields holding a list of values
+var multipleFields = map[string]bool{
+	"components":  true,
+	"fixVersions": true,
+	"versions":    true,
+}
+
+// the name of the standard fields in the changelog, when it doesn't give the
+// field id
+var changelogFieldNames = map[string]string{
+	"components":  "Component",
+	"fixVersions": "Fix Version",
+	"versions":    "Version",
+}
+
+// fieldMapping describes where the values of a JIRA field are kept in git-bug:
+// either as labels with a prefix, which follow the changes of the field in
+// both directions, or as a bug metadata. As the bug metadata are immutable,
+// the latter is only set once and sent when creating an issue.
+type fieldMapping struct {
+	// Label is the prefix of the labels holding the values, like "priority:"
+	Label string `json:"label,omitempty"`
+	// Metadata is the key of the bug metadata holding the value
+	Metadata string `json:"metadata,omitempty"`
+	// Multiple is true if the field holds a list of values. It's already known
+	// for the standard fields.
+	Multiple bool `json:"multiple,omitempty"`
+	// Type is the value type of the field, to export it: "name", "value" or
+	// "string". The default is "name" for the standard fields and "value"
+	// for the custom fields.
+	Type string `json:"type,omitempty"`
+}
+
+// fieldMappings map a JIRA field id (priority, components, customfield_10010
+// ...) to its mapping
+type fieldMappings map[string]fieldMapping
+
+// loadFieldMappings read the field mapping from a bridge configuration
+func loadFieldMappings(conf core.Configuration) (fieldMappings, error) {
+	result := make(fieldMappings)
+
+	raw, ok := conf[confKeyFieldMapping]
+	if !ok || raw == "" {
+		return result, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", confKeyFieldMapping, err)
+	}
+
+	for field, m := range result {
+		if field == "" {
+			return nil, fmt.Errorf("empty field in the field mapping")
+		}
+		if reservedFields[field] {
+			return nil, fmt.Errorf("field \"%s\" is already handled by the bridge", field)
+		}
+		switch {
+		case m.Label == "" && m.Metadata == "":
+			return nil, fmt.Errorf("field \"%s\": a label prefix or a metadata key is required", field)
+		case m.Label != "" && m.Metadata != "":
+			return nil, fmt.Errorf("field \"%s\": only one of label and metadata can be set", field)
+		case m.Label != "" && !text.SafeOneLine(m.Label):
+			return nil, fmt.Errorf("field \"%s\": invalid label prefix", field)
+		case m.Metadata != "" && !text.SafeOneLine(m.Metadata):
+			return nil, fmt.Errorf("field \"%s\": invalid metadata key", field)
+		case m.Metadata != "" && strings.HasPrefix(m.Metadata, "jira-"):
+			return nil, fmt.Errorf("field \"%s\": the metadata keys starting with \"jira-\" are reserved", field)
+		}
+
+		switch m.Type {
+		case "":
+			m.Type = fieldTypeName
+			if strings.HasPrefix(field, "customfield_") {
+				m.Type = fieldTypeValue
+			}
+		case fieldTypeName, fieldTypeValue, fieldTypeString:
+		default:
+			return nil, fmt.Errorf("field \"%s\": unknown type \"%s\"", field, m.Type)
+		}
+		m.Multiple = m.Multiple || multipleFields[field]
+
+		result[field] = m
+	}
+
+	return result, nil
+}
+
+// ids return the ids of the mapped fields, to request them from JIRA
+func (fm fieldMappings) ids() []string {
+	result := make([]string, 0, len(fm))
+	for field := range fm {
+		result = append(result, field)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// forChangelog return the mapped field changed by a changelog item, if any
+func (fm fieldMappings) forChangelog(item ChangeLogItem) (string, fieldMapping, bool) {
+	for field, m := range fm {
+		if item.FieldID == field || item.Field == field ||
+			(item.FieldID == "" && changelogFieldNames[field] == item.Field) {
+			return field, m, true
+		}
+	}
+	return "", fieldMapping{}, false
+}
+
+// forLabel return the field holding a label, if any, along with the value
+func (fm fieldMappings) forLabel(label bug.Label) (string, fieldMapping, string, bool) {
+	// the longest matching prefix wins
+	var best string
+	for field, m := range fm {
+		if m.Label == "" || !strings.HasPrefix(string(label), m.Label) {
+			continue
+		}
+		if best == "" || len(m.Label) > len(fm[best].Label) {
+			best = field
+		}
+	}
+	if best == "" {
+		return "", fieldMapping{}, "", false
+	}
+	m := fm[best]
+	return best, m, strings.TrimPrefix(string(label), m.Label), true
+}
+
+// plainLabels return the labels not holding the value of a field
+func (fm fieldMappings) plainLabels(labels []bug.Label) []bug.Label {
+	result := make([]bug.Label, 0, len(labels))
+	for _, label := range labels {
+		if _, _, _, ok := fm.forLabel(label); !ok {
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// labels return the labels holding the given values of a field
+func (m fieldMapping) labels(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = text.CleanupOneLine(value)
+		if value != "" {
+			result = append(result, m.Label+value)
+		}
+	}
+	return result
+}
+
+// metadata return the metadata value holding the given values of a field
+func (m fieldMapping) metadata(values []string) string {
+	return text.CleanupOneLine(strings.Join(values, ", "))
+}
+
+// encode return the JSON representation of a value of the field, as
+// expected by the JIRA API
+func (m fieldMapping) encode(value string) interface{} {
+	switch m.Type {
+	case fieldTypeString:
+		return value
+	case fieldTypeValue:
+		return map[string]interface{}{"value": value}
+	default:
+		return map[string]interface{}{"name": value}
+	}
+}
+
+// createValue return the value of a field when creating an issue, from the
+// content of the bug metadata
+func (m fieldMapping) createValue(metadata string) interface{} {
+	if !m.Multiple {
+		return m.encode(metadata)
+	}
+	var values []interface{}
+	for _, value := range removeEmpty(strings.Split(metadata, ",")) {
+		values = append(values, m.encode(value))
+	}
+	return values
+}
+
+// fieldValues extract the values of a field from the JSON returned by JIRA.
+// A value is either a plain string or number, or an object with a name, a
+// value or a display name, like a priority, a version or a user.
+func fieldValues(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		var result []string
+		for _, item := range list {
+			result = append(result, fieldValues(item)...)
+		}
+		return result
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		if obj == nil {
+			return nil
+		}
+		for _, key := range []string{"name", "value", "displayName", "key"} {
+			if value, ok := obj[key].(string); ok && value != "" {
+				return []string{value}
+			}
+		}
+		return nil
+	}
+
+	var scalar interface{}
+	if err := json.Unmarshal(raw, &scalar); err != nil || scalar == nil {
+		return nil
+	}
+	value := strings.TrimSpace(fmt.Sprint(scalar))
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// originalFieldValues walk back a changelog to find the values of the mapped
+// fields when the issue was created
+func (fm fieldMappings) originalFieldValues(issue Issue, changelog []ChangeLogEntry) map[string][]string {
+	result := make(map[string][]string, len(fm))
+
+	for field, m := range fm {
+		current := fieldValues(issue.Fields.Raw[field])
+		if !m.Multiple {
+			for i := len(changelog) - 1; i >= 0; i-- {
+				for _, item := range changelog[i].Items {
+					if f, _, ok := fm.forChangelog(item); ok && f == field {
+						current = removeEmpty([]string{item.FromString})
+					}
+				}
+			}
+			result[field] = current
+			continue
+		}
+
+		values := make(map[string]bool)
+		for _, value := range current {
+			values[value] = true
+		}
+		for i := len(changelog) - 1; i >= 0; i-- {
+			for _, item := range changelog[i].Items {
+				if f, _, ok := fm.forChangelog(item); ok && f == field {
+					if item.ToString != "" {
+						delete(values, item.ToString)
+					}
+					if item.FromString != "" {
+						values[item.FromString] = true
+					}
+				}
+			}
+		}
+		current = make([]string, 0, len(values))
+		for value := range values {
+			current = append(current, value)
+		}
+		sort.Strings(current)
+		result[field] = current
+	}
+
+	return result
+}
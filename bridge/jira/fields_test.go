@@ -0,0 +1,155 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const testFieldMapping = `{
+	"priority": {"label": "priority:"},
+	"components": {"label": "component:"},
+	"customfield_10010": {"metadata": "team"},
+	"customfield_10020": {"label": "env:", "multiple": true, "type": "string"}
+}`
+
+func TestLoadFieldMappings(t *testing.T) {
+	fields, err := loadFieldMappings(core.Configuration{confKeyFieldMapping: testFieldMapping})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"components", "customfield_10010", "customfield_10020", "priority"}, fields.ids())
+	require.Equal(t, fieldMapping{Label: "priority:", Type: fieldTypeName}, fields["priority"])
+	require.Equal(t, fieldMapping{Label: "component:", Multiple: true, Type: fieldTypeName}, fields["components"])
+	require.Equal(t, fieldMapping{Metadata: "team", Type: fieldTypeValue}, fields["customfield_10010"])
+	require.Equal(t, fieldMapping{Label: "env:", Multiple: true, Type: fieldTypeString}, fields["customfield_10020"])
+
+	for _, invalid := range []string{
+		`{"priority": {}}`,
+		`{"priority": {"label": "p:", "metadata": "p"}}`,
+		`{"status": {"label": "status:"}}`,
+		`{"priority": {"metadata": "jira-priority"}}`,
+		`{"priority": {"label": "p:", "type": "number"}}`,
+	} {
+		_, err := loadFieldMappings(core.Configuration{confKeyFieldMapping: invalid})
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestFieldValues(t *testing.T) {
+	var issue Issue
+	err := json.Unmarshal([]byte(`{
+		"id": "10000",
+		"fields": {
+			"summary": "title",
+			"priority": {"id": "2", "name": "High"},
+			"components": [{"id": "1", "name": "UI"}, {"id": "2", "name": "Backend"}],
+			"customfield_10010": {"id": "3", "value": "Platform"},
+			"customfield_10020": ["staging", "production"],
+			"customfield_10030": 3.5,
+			"customfield_10040": null
+		}
+	}`), &issue)
+	require.NoError(t, err)
+
+	require.Equal(t, "title", issue.Fields.Summary)
+	require.Equal(t, []string{"High"}, fieldValues(issue.Fields.Raw["priority"]))
+	require.Equal(t, []string{"UI", "Backend"}, fieldValues(issue.Fields.Raw["components"]))
+	require.Equal(t, []string{"Platform"}, fieldValues(issue.Fields.Raw["customfield_10010"]))
+	require.Equal(t, []string{"staging", "production"}, fieldValues(issue.Fields.Raw["customfield_10020"]))
+	require.Equal(t, []string{"3.5"}, fieldValues(issue.Fields.Raw["customfield_10030"]))
+	require.Empty(t, fieldValues(issue.Fields.Raw["customfield_10040"]))
+	require.Empty(t, fieldValues(issue.Fields.Raw["missing"]))
+}
+
+func TestOriginalFieldValues(t *testing.T) {
+	fields, err := loadFieldMappings(core.Configuration{confKeyFieldMapping: testFieldMapping})
+	require.NoError(t, err)
+
+	var issue Issue
+	err = json.Unmarshal([]byte(`{
+		"fields": {
+			"priority": {"name": "High"},
+			"components": [{"name": "UI"}, {"name": "Backend"}]
+		}
+	}`), &issue)
+	require.NoError(t, err)
+
+	changelog := []ChangeLogEntry{
+		{Items: []ChangeLogItem{
+			{Field: "priority", FromString: "Low", ToString: "Medium"},
+			{Field: "Component", ToString: "Backend"},
+		}},
+		{Items: []ChangeLogItem{
+			{Field: "priority", FieldID: "priority", FromString: "Medium", ToString: "High"},
+			{Field: "Component", FieldID: "components", FromString: "Docs"},
+		}},
+	}
+
+	original := fields.originalFieldValues(issue, changelog)
+	require.Equal(t, []string{"Low"}, original["priority"])
+	require.Equal(t, []string{"Docs", "UI"}, original["components"])
+}
+
+func TestExportLabelChange(t *testing.T) {
+	var update IssueUpdate
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.Equal(t, "/rest/api/2/issue/10000/", r.URL.Path)
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &update))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	mapping, err := core.LoadMapping(core.Configuration{})
+	require.NoError(t, err)
+	fields, err := loadFieldMappings(core.Configuration{confKeyFieldMapping: testFieldMapping})
+	require.NoError(t, err)
+
+	je := &jiraExporter{mapping: mapping, fields: fields}
+	client := NewClient(context.Background(), server.URL)
+	author, err := identity.NewIdentity(repository.NewMockRepo(), "Alice", "alice@example.com")
+	require.NoError(t, err)
+
+	op := bug.NewLabelChangeOperation(author, 0,
+		[]bug.Label{"bug", "priority:High", "component:UI", "env:staging"},
+		[]bug.Label{"priority:Low", "component:Docs"},
+	)
+	_, err = je.exportLabelChange(client, "10000", op)
+	require.NoError(t, err)
+
+	expected := IssueUpdate{Update: map[string][]map[string]interface{}{
+		"labels": {{"add": "bug"}},
+		"priority": {
+			{"set": map[string]interface{}{"name": "High"}},
+		},
+		"components": {
+			{"remove": map[string]interface{}{"name": "Docs"}},
+			{"add": map[string]interface{}{"name": "UI"}},
+		},
+		"customfield_10020": {
+			{"add": "staging"},
+		},
+	}}
+	require.Equal(t, expected, update)
+
+	// removing the only value of a single valued field clears it
+	update = IssueUpdate{}
+	op = bug.NewLabelChangeOperation(author, 0, nil, []bug.Label{"priority:High"})
+	_, err = je.exportLabelChange(client, "10000", op)
+	require.NoError(t, err)
+	require.Equal(t, IssueUpdate{Update: map[string][]map[string]interface{}{
+		"priority": {{"set": nil}},
+	}}, update)
+}
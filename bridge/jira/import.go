@@ -27,6 +27,12 @@ const (
 type jiraImporter struct {
 	conf core.Configuration
 
+	// label and status mapping
+	mapping *core.Mapping
+
+	// mapping of the priority, components, versions or custom fields
+	fields fieldMappings
+
 	client *Client
 
 	// send only channel
@@ -37,6 +43,18 @@ type jiraImporter struct {
 func (ji *jiraImporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
 	ji.conf = conf
 
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	ji.mapping = mapping
+
+	fields, err := loadFieldMappings(conf)
+	if err != nil {
+		return err
+	}
+	ji.fields = fields
+
 	var cred auth.Credential
 
 	// Prioritize LoginPassword credentials to avoid a prompt
@@ -75,7 +93,12 @@ end:
 	// TODO(josh)[da52062]: Validate token and if it is expired then prompt for
 	// credentials and generate a new one
 	ji.client, err = buildClient(ctx, conf[confKeyBaseUrl], conf[confKeyCredentialType], cred)
-	return err
+	if err != nil {
+		return err
+	}
+	ji.client.AddSearchFields(ji.fields.ids()...)
+
+	return nil
 }
 
 // ImportAll iterate over all the configured repository issues and ensure the
@@ -165,6 +188,11 @@ func (ji *jiraImporter) ImportAll(ctx context.Context, repo *cache.RepoCache, si
 				out <- core.NewImportError(changelogIter.Err, "")
 			}
 
+			err = ji.ensureFieldsMetadata(repo, b, *issue)
+			if err != nil {
+				out <- core.NewImportError(err, "")
+			}
+
 			if !b.NeedCommit() {
 				out <- core.NewImportNothing(b.Id(), "no imported operation")
 			} else if err := b.Commit(); err != nil {
@@ -234,29 +262,134 @@ func (ji *jiraImporter) ensureIssue(repo *cache.RepoCache, issue Issue) (*cache.
 	}
 
 	if entity.IsErrNotFound(err) {
+		metadata := map[string]string{
+			core.MetaKeyOrigin: target,
+			metaKeyJiraId:      issue.ID,
+			metaKeyJiraKey:     issue.Key,
+			metaKeyJiraProject: ji.conf[confKeyProject],
+			metaKeyJiraBaseUrl: ji.conf[confKeyBaseUrl],
+		}
+
+		// the bug metadata are immutable, so they hold the current value of
+		// the fields instead of the original one
+		for field, m := range ji.fields {
+			if m.Metadata == "" {
+				continue
+			}
+			if value := m.metadata(fieldValues(issue.Fields.Raw[field])); value != "" {
+				metadata[m.Metadata] = value
+			}
+		}
+
 		b, _, err = repo.Bugs().NewRaw(
 			author,
 			issue.Fields.Created.Unix(),
 			text.CleanupOneLine(issue.Fields.Summary),
 			text.Cleanup(issue.Fields.Description),
 			nil,
-			map[string]string{
-				core.MetaKeyOrigin: target,
-				metaKeyJiraId:      issue.ID,
-				metaKeyJiraKey:     issue.Key,
-				metaKeyJiraProject: ji.conf[confKeyProject],
-				metaKeyJiraBaseUrl: ji.conf[confKeyBaseUrl],
-			})
+			metadata,
+		)
 		if err != nil {
 			return nil, err
 		}
 
 		ji.out <- core.NewImportBug(b.Id())
+
+		err = ji.ensureOriginalFieldLabels(b, author, issue)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return b, nil
 }
 
+// ensureOriginalFieldLabels add the labels holding the values of the fields
+// when the issue was created. The later changes are found in the changelog.
+func (ji *jiraImporter) ensureOriginalFieldLabels(b *cache.BugCache, author *cache.IdentityCache, issue Issue) error {
+	var labels []string
+	hasLabels := false
+	for _, m := range ji.fields {
+		hasLabels = hasLabels || m.Label != ""
+	}
+	if !hasLabels {
+		return nil
+	}
+
+	var changelog []ChangeLogEntry
+	changelogIter := ji.client.IterChangeLog(issue.ID, defaultPageSize)
+	for changelogIter.HasNext() {
+		changelog = append(changelog, *changelogIter.Next())
+	}
+	if changelogIter.HasError() {
+		return changelogIter.Err
+	}
+
+	for field, values := range ji.fields.originalFieldValues(issue, changelog) {
+		if m := ji.fields[field]; m.Label != "" {
+			labels = append(labels, m.labels(values)...)
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	sort.Strings(labels)
+
+	op, err := b.ForceChangeLabelsRaw(
+		author,
+		issue.Fields.Created.Unix(),
+		labels,
+		nil,
+		map[string]string{
+			metaKeyJiraId:        issue.ID,
+			metaKeyJiraDerivedId: issue.ID + "-fields",
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	ji.out <- core.NewImportLabelChange(b.Id(), op.Id())
+	return nil
+}
+
+// ensureFieldsMetadata set the bug metadata of the mapped fields that were
+// not set yet, like for a bug imported before configuring the field mapping.
+// As the bug metadata are immutable, a changed value can't be updated.
+func (ji *jiraImporter) ensureFieldsMetadata(repo *cache.RepoCache, b *cache.BugCache, issue Issue) error {
+	snapshot := b.Snapshot()
+	newMetadata := make(map[string]string)
+
+	for _, field := range ji.fields.ids() {
+		m := ji.fields[field]
+		if m.Metadata == "" {
+			continue
+		}
+		value := m.metadata(fieldValues(issue.Fields.Raw[field]))
+		current, ok := snapshot.GetCreateMetadata(m.Metadata)
+		switch {
+		case !ok && value != "":
+			newMetadata[m.Metadata] = value
+		case ok && current != value:
+			ji.out <- core.NewImportWarning(fmt.Errorf(
+				"field %s changed to \"%s\", but the bug metadata %s can't be updated",
+				field, value, m.Metadata), b.Id())
+		}
+	}
+
+	if len(newMetadata) == 0 {
+		return nil
+	}
+
+	author, err := ji.ensurePerson(repo, issue.Fields.Creator)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.SetMetadataRaw(author, time.Now().Unix(), snapshot.Operations[0].Id(), newMetadata)
+	return err
+}
+
 // Return a unique string derived from a unique jira id and a timestamp
 func getTimeDerivedID(jiraID string, timestamp Time) string {
 	return fmt.Sprintf("%s-%d", jiraID, timestamp.Unix())
@@ -357,6 +490,30 @@ func getIndexDerivedID(jiraID string, idx int) string {
 	return fmt.Sprintf("%s-%d", jiraID, idx)
 }
 
+// fieldChangeMatch return true if a label change holds the change of a field
+func fieldChangeMatch(m fieldMapping, item ChangeLogItem, opr *bug.LabelChangeOperation) bool {
+	if item.ToString != "" {
+		return labelsContain(opr.Added, m.labels([]string{item.ToString})...)
+	}
+	return labelsContain(opr.Removed, m.labels([]string{item.FromString})...)
+}
+
+func labelsContain(labels []bug.Label, values ...string) bool {
+	for _, value := range values {
+		found := false
+		for _, label := range labels {
+			if label.String() == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func labelSetsMatch(jiraSet []string, gitbugSet []bug.Label) bool {
 	if len(jiraSet) != len(gitbugSet) {
 		return false
@@ -418,6 +575,20 @@ func (ji *jiraImporter) ensureChange(repo *cache.RepoCache, b *cache.BugCache, e
 	// So we associate the operation with the entire changelog, and not a specific
 	// entry.
 	for _, item := range entry.Items {
+		if _, m, ok := ji.fields.forChangelog(item); ok {
+			opr, isRightType := potentialOp.(*bug.LabelChangeOperation)
+			if isRightType && m.Label != "" && fieldChangeMatch(m, item, opr) {
+				_, err := b.SetMetadata(opr.Id(), map[string]string{
+					metaKeyJiraDerivedId: entry.ID,
+				})
+				if err != nil {
+					return err
+				}
+				return nil
+			}
+			continue
+		}
+
 		switch item.Field {
 		case "labels":
 			fromLabels := removeEmpty(strings.Split(item.FromString, " "))
@@ -425,7 +596,9 @@ func (ji *jiraImporter) ensureChange(repo *cache.RepoCache, b *cache.BugCache, e
 			removedLabels, addedLabels, _ := setSymmetricDifference(fromLabels, toLabels)
 
 			opr, isRightType := potentialOp.(*bug.LabelChangeOperation)
-			if isRightType && labelSetsMatch(addedLabels, opr.Added) && labelSetsMatch(removedLabels, opr.Removed) {
+			if isRightType &&
+				labelSetsMatch(addedLabels, ji.mapping.ExportLabels(ji.fields.plainLabels(opr.Added))) &&
+				labelSetsMatch(removedLabels, ji.mapping.ExportLabels(ji.fields.plainLabels(opr.Removed))) {
 				_, err := b.SetMetadata(opr.Id(), map[string]string{
 					metaKeyJiraDerivedId: entry.ID,
 				})
@@ -437,7 +610,12 @@ func (ji *jiraImporter) ensureChange(repo *cache.RepoCache, b *cache.BugCache, e
 
 		case "status":
 			opr, isRightType := potentialOp.(*bug.SetStatusOperation)
-			if isRightType && statusMap[opr.Status.String()] == item.To {
+			if !isRightType {
+				break
+			}
+			remote, mapped := ji.mapping.ExportStatus(opr.Status, b.Snapshot().Labels)
+			if statusMap[opr.Status.String()] == item.To ||
+				(mapped && (remote == item.To || remote == item.ToString)) {
 				_, err := b.SetMetadata(opr.Id(), map[string]string{
 					metaKeyJiraDerivedId: entry.ID,
 				})
@@ -492,17 +670,53 @@ func (ji *jiraImporter) ensureChange(repo *cache.RepoCache, b *cache.BugCache, e
 			return err
 		}
 
+		if _, m, ok := ji.fields.forChangelog(item); ok {
+			// the metadata can't follow the changes, see ensureFieldsMetadata
+			if m.Label == "" {
+				continue
+			}
+
+			added := m.labels(removeEmpty([]string{item.ToString}))
+			removed := m.labels(removeEmpty([]string{item.FromString}))
+			if len(added) == 0 && len(removed) == 0 {
+				continue
+			}
+
+			op, err := b.ForceChangeLabelsRaw(
+				author,
+				entry.Created.Unix(),
+				added,
+				removed,
+				map[string]string{
+					metaKeyJiraId:        entry.ID,
+					metaKeyJiraDerivedId: derivedID,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			ji.out <- core.NewImportLabelChange(b.Id(), op.Id())
+			continue
+		}
+
 		switch item.Field {
 		case "labels":
 			fromLabels := removeEmpty(strings.Split(item.FromString, " "))
 			toLabels := removeEmpty(strings.Split(item.ToString, " "))
 			removedLabels, addedLabels, _ := setSymmetricDifference(fromLabels, toLabels)
 
+			addedLabels = ji.mapping.ImportLabels(text.CleanupOneLineArray(addedLabels))
+			removedLabels = ji.mapping.ImportLabels(text.CleanupOneLineArray(removedLabels))
+			if len(addedLabels) == 0 && len(removedLabels) == 0 {
+				continue
+			}
+
 			op, err := b.ForceChangeLabelsRaw(
 				author,
 				entry.Created.Unix(),
-				text.CleanupOneLineArray(addedLabels),
-				text.CleanupOneLineArray(removedLabels),
+				addedLabels,
+				removedLabels,
 				map[string]string{
 					metaKeyJiraId:        entry.ID,
 					metaKeyJiraDerivedId: derivedID,
@@ -515,36 +729,43 @@ func (ji *jiraImporter) ensureChange(repo *cache.RepoCache, b *cache.BugCache, e
 			ji.out <- core.NewImportLabelChange(b.Id(), op.Id())
 
 		case "status":
+			// the status mapping takes precedence, with either the name or
+			// the id of the Jira status
+			_, mapped := ji.mapping.ImportStatus(item.ToString)
+			remoteState := item.ToString
+			if !mapped {
+				_, mapped = ji.mapping.ImportStatus(item.To)
+				remoteState = item.To
+			}
+
 			statusStr, hasMap := statusMap[item.To]
-			if hasMap {
-				switch statusStr {
-				case common.OpenStatus.String():
-					op, err := b.OpenRaw(
-						author,
-						entry.Created.Unix(),
-						map[string]string{
-							metaKeyJiraId:        entry.ID,
-							metaKeyJiraDerivedId: derivedID,
-						},
-					)
-					if err != nil {
-						return err
-					}
-					ji.out <- core.NewImportStatusChange(b.Id(), op.Id())
-
-				case common.ClosedStatus.String():
-					op, err := b.CloseRaw(
-						author,
-						entry.Created.Unix(),
-						map[string]string{
-							metaKeyJiraId:        entry.ID,
-							metaKeyJiraDerivedId: derivedID,
-						},
-					)
-					if err != nil {
-						return err
-					}
-					ji.out <- core.NewImportStatusChange(b.Id(), op.Id())
+			if mapped || hasMap {
+				fallback := common.OpenStatus
+				if statusStr == common.ClosedStatus.String() {
+					fallback = common.ClosedStatus
+				}
+
+				op, labelOp, err := ji.mapping.ApplyStatus(
+					b,
+					author,
+					entry.Created.Unix(),
+					remoteState,
+					fallback,
+					map[string]string{
+						metaKeyJiraId:        entry.ID,
+						metaKeyJiraDerivedId: derivedID,
+					},
+					map[string]string{
+						metaKeyJiraId:        entry.ID,
+						metaKeyJiraDerivedId: core.StatusLabelsId(derivedID),
+					},
+				)
+				if err != nil {
+					return err
+				}
+				ji.out <- core.NewImportStatusChange(b.Id(), op.Id())
+				if labelOp != nil {
+					ji.out <- core.NewImportLabelChange(b.Id(), labelOp.Id())
 				}
 			} else {
 				ji.out <- core.NewImportError(
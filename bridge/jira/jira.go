@@ -34,6 +34,9 @@ const (
 	confKeyCreateDefaults = "create-issue-defaults"
 	// if set, the bridge fill this JIRA field with the `git-bug` id when exporting
 	confKeyCreateGitBug = "create-issue-gitbug-id"
+	// how the priority, components, versions or custom fields are kept in
+	// git-bug, as a JSON object
+	confKeyFieldMapping = "field-mapping"
 
 	defaultTimeout = 60 * time.Second
 )
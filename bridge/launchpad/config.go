@@ -1,22 +1,29 @@
 package launchpad
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"regexp"
 
 	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/commands/input"
+	"github.com/MichaelMure/git-bug/repository"
 )
 
 var ErrBadProjectURL = errors.New("bad Launchpad project URL")
 
 func (Launchpad) ValidParams() map[string]interface{} {
 	return map[string]interface{}{
-		"URL":     nil,
-		"Project": nil,
+		"URL":        nil,
+		"Project":    nil,
+		"CredPrefix": nil,
+		"TokenRaw":   nil,
 	}
 }
 
@@ -51,16 +58,71 @@ func (l *Launchpad) Configure(repo *cache.RepoCache, params core.BridgeParams, i
 		return nil, fmt.Errorf("project doesn't exist")
 	}
 
+	// Credentials are only needed to export, a bridge without them can
+	// still import anonymously.
+	var login string
+	var cred auth.Credential
+
+	switch {
+	case params.CredPrefix != "":
+		cred, err = auth.LoadWithPrefix(repo, params.CredPrefix)
+		if err != nil {
+			return nil, err
+		}
+		credLogin, ok := cred.GetMetadata(auth.MetaKeyLogin)
+		if !ok {
+			return nil, fmt.Errorf("credential doesn't have a login")
+		}
+		login = credLogin
+	case params.TokenRaw != "":
+		token := auth.NewToken(target, params.TokenRaw)
+		login, err = getLoginFromToken(token)
+		if err != nil {
+			return nil, err
+		}
+		token.SetMetadata(auth.MetaKeyLogin, login)
+		cred = token
+	case interactive:
+		cred, err = promptTokenOptions(repo)
+		if err != nil {
+			return nil, err
+		}
+		if cred != nil {
+			login, _ = cred.GetMetadata(auth.MetaKeyLogin)
+		}
+	}
+
+	if cred != nil {
+		if _, ok := cred.(*auth.Token); !ok {
+			return nil, fmt.Errorf("the Launchpad bridge only handle token credentials")
+		}
+	}
+
 	conf := make(core.Configuration)
 	conf[core.ConfigKeyTarget] = target
 	conf[confKeyProject] = project
+	if login != "" {
+		conf[confKeyDefaultLogin] = login
+	}
 
 	err = l.ValidateConfig(conf)
 	if err != nil {
 		return nil, err
 	}
 
-	return conf, nil
+	if cred == nil {
+		return conf, nil
+	}
+
+	// don't forget to store the now known valid token
+	if !auth.IdExist(repo, cred.ID()) {
+		err = auth.Store(repo, cred)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return conf, core.FinishConfig(repo, metaKeyLaunchpadLogin, login)
 }
 
 func (*Launchpad) ValidateConfig(conf core.Configuration) error {
@@ -76,6 +138,87 @@ func (*Launchpad) ValidateConfig(conf core.Configuration) error {
 	return nil
 }
 
+func promptTokenOptions(repo repository.RepoKeyring) (auth.Credential, error) {
+	creds, err := auth.List(repo,
+		auth.WithTarget(target),
+		auth.WithKind(auth.KindToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, index, err := input.PromptCredential(target, "token", creds, []string{
+		"authorize git-bug on Launchpad",
+		"don't authenticate (import only)",
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case cred != nil:
+		return cred, nil
+	case index == 0:
+		return promptAuthorization()
+	case index == 1:
+		return nil, nil
+	default:
+		panic("missed case")
+	}
+}
+
+// promptAuthorization go through the OAuth authorization with the user to
+// get a new access token
+func promptAuthorization() (*auth.Token, error) {
+	ctx := context.Background()
+
+	reqToken, err := requestToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("To allow git-bug to modify bugs on your behalf, visit the following page")
+	fmt.Println("and authorize the access:")
+	fmt.Println()
+	fmt.Println(authorizeURL(reqToken))
+	fmt.Println()
+	fmt.Print("Press enter once done.")
+
+	_, err = bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	accToken, err := accessToken(ctx, reqToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token := auth.NewToken(target, accToken.String())
+	login, err := getLoginFromToken(token)
+	if err != nil {
+		return nil, err
+	}
+	token.SetMetadata(auth.MetaKeyLogin, login)
+
+	return token, nil
+}
+
+func getLoginFromToken(token *auth.Token) (string, error) {
+	client, err := buildClient(http.DefaultTransport, token)
+	if err != nil {
+		return "", err
+	}
+
+	login, err := client.Me(context.Background())
+	if err != nil {
+		return "", err
+	}
+	if login == "" {
+		return "", fmt.Errorf("launchpad doesn't seem to know this token")
+	}
+
+	return login, nil
+}
+
 func validateProject(project string) (bool, error) {
 	url := fmt.Sprintf("%s/%s", apiRoot, project)
 
@@ -0,0 +1,392 @@
+package launchpad
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+)
+
+var (
+	ErrMissingIdentityToken = errors.New("missing identity token")
+)
+
+// launchpadExporter implement the Exporter interface
+type launchpadExporter struct {
+	conf core.Configuration
+
+	// label and status mapping
+	mapping *core.Mapping
+
+	// if true, don't call the mutating endpoints
+	dryRun bool
+
+	// cache identities clients
+	identityClient map[entity.Id]*launchpadAPI
+
+	// transport of the HTTP requests
+	transport http.RoundTripper
+}
+
+// Init .
+func (le *launchpadExporter) Init(ctx context.Context, repo *cache.RepoCache, conf core.Configuration) error {
+	le.conf = conf
+	le.transport = core.HTTPTransport(ctx)
+	le.identityClient = make(map[entity.Id]*launchpadAPI)
+
+	mapping, err := core.LoadMapping(conf)
+	if err != nil {
+		return err
+	}
+	le.mapping = mapping
+
+	// preload all clients
+	err = le.cacheAllClient(repo)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetDryRun .
+func (le *launchpadExporter) SetDryRun(dryRun bool) {
+	le.dryRun = dryRun
+}
+
+func (le *launchpadExporter) cacheAllClient(repo *cache.RepoCache) error {
+	creds, err := auth.List(repo,
+		auth.WithTarget(target),
+		auth.WithKind(auth.KindToken),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, cred := range creds {
+		login, ok := cred.GetMetadata(auth.MetaKeyLogin)
+		if !ok {
+			_, _ = fmt.Fprintf(os.Stderr, "credential %s is not tagged with a Launchpad login\n", cred.ID().Human())
+			continue
+		}
+
+		user, err := repo.Identities().ResolveIdentityImmutableMetadata(metaKeyLaunchpadLogin, login)
+		if entity.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, ok := le.identityClient[user.Id()]; !ok {
+			client, err := buildClient(le.transport, cred.(*auth.Token))
+			if err != nil {
+				return err
+			}
+			le.identityClient[user.Id()] = client
+		}
+	}
+
+	return nil
+}
+
+// getIdentityClient return a Launchpad API client configured with the OAuth token of the given identity.
+func (le *launchpadExporter) getIdentityClient(userId entity.Id) (*launchpadAPI, error) {
+	client, ok := le.identityClient[userId]
+	if ok {
+		return client, nil
+	}
+
+	return nil, ErrMissingIdentityToken
+}
+
+// ExportAll export all event made by the current user to Launchpad
+func (le *launchpadExporter) ExportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ExportResult, error) {
+	out := make(chan core.ExportResult)
+
+	go func() {
+		defer close(out)
+
+		allIdentitiesIds := make([]entity.Id, 0, len(le.identityClient))
+		for id := range le.identityClient {
+			allIdentitiesIds = append(allIdentitiesIds, id)
+		}
+
+		allBugsIds := repo.Bugs().AllIds()
+
+		for _, id := range allBugsIds {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				b, err := repo.Bugs().Resolve(id)
+				if err != nil {
+					out <- core.NewExportError(err, id)
+					return
+				}
+
+				snapshot := b.Snapshot()
+
+				// ignore issues created before since date
+				// TODO: compare the Lamport time instead of using the unix time
+				if snapshot.CreateTime.Before(since) {
+					out <- core.NewExportNothing(b.Id(), "bug created before the since date")
+					continue
+				}
+
+				if snapshot.HasAnyActor(allIdentitiesIds...) {
+					// try to export the bug and it associated events
+					le.exportBug(ctx, b, out)
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// exportBug publish bugs and related events
+func (le *launchpadExporter) exportBug(ctx context.Context, b *cache.BugCache, out chan<- core.ExportResult) {
+	snapshot := b.Snapshot()
+
+	var bugUpdated bool
+	var err error
+	var bugLaunchpadID int
+
+	project := le.conf[confKeyProject]
+
+	// skip bug if origin is not allowed
+	origin, ok := snapshot.GetCreateMetadata(core.MetaKeyOrigin)
+	if ok && origin != target {
+		out <- core.NewExportNothing(b.Id(), fmt.Sprintf("issue tagged with origin: %s", origin))
+		return
+	}
+
+	// first operation is always createOp
+	createOp := snapshot.Operations[0].(*bug.CreateOperation)
+	author := snapshot.Author
+
+	// get launchpad bug ID
+	launchpadID, ok := snapshot.GetCreateMetadata(metaKeyLaunchpadID)
+	if ok {
+		// bugs imported before the project was recorded are assumed to
+		// belong to the configured project
+		launchpadProject, ok := snapshot.GetCreateMetadata(metaKeyLaunchpadProject)
+		if ok && launchpadProject != project {
+			out <- core.NewExportNothing(b.Id(), "skipping bug imported from another project")
+			return
+		}
+
+		bugLaunchpadID, err = strconv.Atoi(launchpadID)
+		if err != nil {
+			out <- core.NewExportError(fmt.Errorf("unexpected launchpad id format: %s", launchpadID), b.Id())
+			return
+		}
+
+	} else {
+		// check that we have a token for operation author
+		client, err := le.getIdentityClient(author.Id())
+		if err != nil {
+			// if bug is still not exported and we do not have the author stop the execution
+			out <- core.NewExportNothing(b.Id(), "missing author token")
+			return
+		}
+
+		// create bug
+		id, err := le.createLaunchpadBug(ctx, client, project, createOp.Title, createOp.Message)
+		if err != nil {
+			err := errors.Wrap(err, "exporting launchpad bug")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		out <- core.NewExportBug(b.Id())
+
+		_, err = b.SetMetadata(
+			createOp.Id(),
+			map[string]string{
+				core.MetaKeyOrigin:      target,
+				metaKeyLaunchpadID:      strconv.Itoa(id),
+				metaKeyLaunchpadProject: project,
+			},
+		)
+		if err != nil {
+			err := errors.Wrap(err, "marking operation as exported")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		// commit operation to avoid creating multiple bugs with multiple pushes
+		if err := b.CommitAsNeeded(); err != nil {
+			err := errors.Wrap(err, "bug commit")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		bugLaunchpadID = id
+	}
+
+	for _, op := range snapshot.Operations[1:] {
+		// ignore SetMetadata operations
+		if _, ok := op.(dag.OperationDoesntChangeSnapshot); ok {
+			continue
+		}
+
+		// ignore operations already existing in launchpad (due to import or export)
+		if _, ok := op.GetMetadata(metaKeyLaunchpadID); ok {
+			continue
+		}
+
+		opAuthor := op.Author()
+		client, err := le.getIdentityClient(opAuthor.Id())
+		if err != nil {
+			continue
+		}
+
+		// Only comments have an identity on Launchpad, other operations are
+		// marked as exported with an empty id.
+		var id string
+
+		switch op := op.(type) {
+		case *bug.AddCommentOperation:
+			id, err = le.addLaunchpadMessage(ctx, client, bugLaunchpadID, op.Message)
+			if err != nil {
+				err := errors.Wrap(err, "adding comment")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportComment(b.Id())
+
+		case *bug.EditCommentOperation:
+			// Launchpad comments can't be edited, only the description can
+			if op.Target != createOp.Id() {
+				out <- core.NewExportNothing(b.Id(), "launchpad doesn't support editing comments")
+				continue
+			}
+
+			err := le.updateLaunchpadBug(ctx, client, bugLaunchpadID, map[string]string{
+				"description": op.Message,
+			})
+			if err != nil {
+				err := errors.Wrap(err, "editing description")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportCommentEdition(b.Id())
+
+		case *bug.SetStatusOperation:
+			err := le.updateLaunchpadBugStatus(ctx, client, project, bugLaunchpadID, le.launchpadStatus(op.Status, core.LabelsAt(snapshot, op)))
+			if err != nil {
+				err := errors.Wrap(err, "editing status")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportStatusChange(b.Id())
+
+		case *bug.SetTitleOperation:
+			err := le.updateLaunchpadBug(ctx, client, bugLaunchpadID, map[string]string{
+				"title": op.Title,
+			})
+			if err != nil {
+				err := errors.Wrap(err, "editing title")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportTitleEdition(b.Id())
+
+		case *bug.LabelChangeOperation:
+			out <- core.NewExportNothing(b.Id(), "launchpad bridge doesn't support labels")
+			continue
+
+		default:
+			panic("unhandled operation type case")
+		}
+
+		// mark operation as exported
+		if _, err := b.SetMetadata(op.Id(), map[string]string{metaKeyLaunchpadID: id}); err != nil {
+			err := errors.Wrap(err, "marking operation as exported")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		// commit at each operation export to avoid exporting same events multiple times
+		if err := b.CommitAsNeeded(); err != nil {
+			err := errors.Wrap(err, "bug commit")
+			out <- core.NewExportError(err, b.Id())
+			return
+		}
+
+		bugUpdated = true
+	}
+
+	if !bugUpdated {
+		out <- core.NewExportNothing(b.Id(), "nothing has been exported")
+	}
+}
+
+// create a launchpad bug and return its ID
+func (le *launchpadExporter) createLaunchpadBug(ctx context.Context, client *launchpadAPI, project, title, description string) (int, error) {
+	if le.dryRun {
+		return 0, nil
+	}
+
+	return client.CreateBug(ctx, project, title, description)
+}
+
+// add a message to a launchpad bug and return its ID
+func (le *launchpadExporter) addLaunchpadMessage(ctx context.Context, client *launchpadAPI, bugID int, content string) (string, error) {
+	if le.dryRun {
+		return "", nil
+	}
+
+	return client.AddMessage(ctx, bugID, content)
+}
+
+func (le *launchpadExporter) updateLaunchpadBug(ctx context.Context, client *launchpadAPI, bugID int, fields map[string]string) error {
+	if le.dryRun {
+		return nil
+	}
+
+	return client.UpdateBug(ctx, bugID, fields)
+}
+
+func (le *launchpadExporter) updateLaunchpadBugStatus(ctx context.Context, client *launchpadAPI, project string, bugID int, status string) error {
+	if le.dryRun {
+		return nil
+	}
+
+	return client.UpdateBugTaskStatus(ctx, project, bugID, status)
+}
+
+// launchpadStatus map a git-bug status to a Launchpad one, using the status
+// mapping if it defines one
+func (le *launchpadExporter) launchpadStatus(status common.Status, labels []bug.Label) string {
+	if remote, ok := le.mapping.ExportStatus(status, labels); ok {
+		return remote
+	}
+
+	switch status {
+	case common.OpenStatus:
+		return statusOpen
+	case common.ClosedStatus:
+		return statusClosed
+	default:
+		panic("unknown bug state")
+	}
+}
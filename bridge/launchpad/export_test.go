@@ -0,0 +1,389 @@
+package launchpad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+type fakeBug struct {
+	title       string
+	description string
+	status      string
+	messages    []string
+}
+
+// fakeLaunchpad is a minimal stand-in for the write side of the Launchpad API.
+type fakeLaunchpad struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	project string
+	bugs    map[int]*fakeBug
+	nextID  int
+}
+
+func newFakeLaunchpad(t *testing.T, project, token string) *fakeLaunchpad {
+	f := &fakeLaunchpad{
+		project: project,
+		bugs:    make(map[int]*fakeBug),
+		nextID:  1,
+	}
+
+	key, secret, _ := strings.Cut(token, ":")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /people/+me", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"name": "test-identity"})
+	})
+	mux.HandleFunc("POST /bugs", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("ws.op") != "createBug" || r.FormValue("target") != apiRoot+"/"+f.project {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		id := f.nextID
+		f.nextID++
+		f.bugs[id] = &fakeBug{
+			title:       r.FormValue("title"),
+			description: r.FormValue("description"),
+			status:      "New",
+		}
+		w.Header().Set("Location", fmt.Sprintf("%s/bugs/%d", apiRoot, id))
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("POST /bugs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		b, id := f.bug(r)
+		if b == nil || r.FormValue("ws.op") != "newMessage" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		b.messages = append(b.messages, r.FormValue("content"))
+		w.Header().Set("Location", fmt.Sprintf("%s/%s/+bug/%d/comments/%d", apiRoot, f.project, id, len(b.messages)))
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("PATCH /bugs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := f.bug(r)
+		var fields map[string]string
+		if b == nil || json.NewDecoder(r.Body).Decode(&fields) != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if title, ok := fields["title"]; ok {
+			b.title = title
+		}
+		if description, ok := fields["description"]; ok {
+			b.description = description
+		}
+	})
+	mux.HandleFunc("PATCH /{project}/+bug/{id}", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := f.bug(r)
+		var fields map[string]string
+		if b == nil || r.PathValue("project") != f.project || json.NewDecoder(r.Body).Decode(&fields) != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		b.status = fields["status"]
+	})
+
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if !strings.Contains(authorization, fmt.Sprintf(`oauth_token="%s"`, key)) ||
+			!strings.Contains(authorization, fmt.Sprintf(`oauth_signature="%%26%s"`, secret)) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(f.Close)
+
+	// redirect the API to the fake server
+	previousRoot := apiRoot
+	apiRoot = f.URL
+	t.Cleanup(func() { apiRoot = previousRoot })
+
+	return f
+}
+
+func (f *fakeLaunchpad) bug(r *http.Request) (*fakeBug, int) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return nil, 0
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.bugs[id], id
+}
+
+func TestLaunchpadExport(t *testing.T) {
+	server := newFakeLaunchpad(t, "git-bug", "key:secret")
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	// set author identity
+	login := "test-identity"
+	author, err := backend.Identities().New("test identity", "test@test.org")
+	require.NoError(t, err)
+	author.SetMetadata(metaKeyLaunchpadLogin, login)
+	err = author.Commit()
+	require.NoError(t, err)
+
+	err = backend.SetUserIdentity(author)
+	require.NoError(t, err)
+
+	token := auth.NewToken(target, "key:secret")
+	token.SetMetadata(auth.MetaKeyLogin, login)
+	err = auth.Store(repo, token)
+	require.NoError(t, err)
+
+	// the login is resolved from the token
+	resolved, err := getLoginFromToken(token)
+	require.NoError(t, err)
+	require.Equal(t, login, resolved)
+
+	simpleBug, _, err := backend.Bugs().New("simple bug", "new bug")
+	require.NoError(t, err)
+
+	complexBug, createOp, err := backend.Bugs().New("complex bug", "new bug")
+	require.NoError(t, err)
+	_, _, err = complexBug.AddComment("first comment")
+	require.NoError(t, err)
+	_, err = complexBug.EditComment(entity.CombineIds(complexBug.Id(), createOp.Id()), "new bug edited")
+	require.NoError(t, err)
+	_, err = complexBug.SetTitle("complex bug edited")
+	require.NoError(t, err)
+	_, err = complexBug.Close()
+	require.NoError(t, err)
+	_, _, err = complexBug.ChangeLabels([]string{"bug"}, nil)
+	require.NoError(t, err)
+
+	conf := core.Configuration{
+		confKeyProject:      "git-bug",
+		confKeyDefaultLogin: login,
+	}
+
+	ctx := context.Background()
+
+	runExport := func() []core.ExportResult {
+		exporter := &launchpadExporter{}
+		err := exporter.Init(ctx, backend, conf)
+		require.NoError(t, err)
+
+		events, err := exporter.ExportAll(ctx, backend, time.Time{})
+		require.NoError(t, err)
+
+		var results []core.ExportResult
+		for result := range events {
+			require.NoError(t, result.Err)
+			results = append(results, result)
+		}
+		return results
+	}
+
+	var events []core.ExportEvent
+	for _, result := range runExport() {
+		events = append(events, result.Event)
+	}
+	require.ElementsMatch(t, []core.ExportEvent{
+		core.ExportEventBug,
+		core.ExportEventBug,
+		core.ExportEventNothing, // simple bug, nothing beside the creation
+		core.ExportEventComment,
+		core.ExportEventCommentEdition,
+		core.ExportEventTitleEdition,
+		core.ExportEventStatusChange,
+		core.ExportEventNothing, // labels are not supported
+	}, events)
+
+	require.Len(t, server.bugs, 2)
+
+	simpleID, ok := simpleBug.Snapshot().GetCreateMetadata(metaKeyLaunchpadID)
+	require.True(t, ok)
+	complexID, ok := complexBug.Snapshot().GetCreateMetadata(metaKeyLaunchpadID)
+	require.True(t, ok)
+
+	id, err := strconv.Atoi(simpleID)
+	require.NoError(t, err)
+	require.Equal(t, &fakeBug{title: "simple bug", description: "new bug", status: "New"}, server.bugs[id])
+
+	id, err = strconv.Atoi(complexID)
+	require.NoError(t, err)
+	require.Equal(t, &fakeBug{
+		title:       "complex bug edited",
+		description: "new bug edited",
+		status:      statusClosed,
+		messages:    []string{"first comment"},
+	}, server.bugs[id])
+
+	// exported operations are tagged, beside the unsupported label change
+	for _, op := range complexBug.Snapshot().Operations {
+		if _, ok := op.(dag.OperationDoesntChangeSnapshot); ok {
+			continue
+		}
+		_, ok := op.GetMetadata(metaKeyLaunchpadID)
+		require.Equal(t, op.Type() != bug.LabelChangeOp, ok)
+	}
+
+	// the bugs are bound to the launchpad bugs, origin is set for the import
+	origin, ok := complexBug.Snapshot().GetCreateMetadata(core.MetaKeyOrigin)
+	require.True(t, ok)
+	require.Equal(t, target, origin)
+
+	// a second export doesn't do anything
+	for _, result := range runExport() {
+		require.Equal(t, core.ExportEventNothing, result.Event)
+	}
+	require.Len(t, server.bugs, 2)
+}
+
+func TestLaunchpadExportStatusMapping(t *testing.T) {
+	server := newFakeLaunchpad(t, "git-bug", "key:secret")
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	login := "test-identity"
+	author, err := backend.Identities().New("test identity", "test@test.org")
+	require.NoError(t, err)
+	author.SetMetadata(metaKeyLaunchpadLogin, login)
+	err = author.Commit()
+	require.NoError(t, err)
+
+	err = backend.SetUserIdentity(author)
+	require.NoError(t, err)
+
+	token := auth.NewToken(target, "key:secret")
+	token.SetMetadata(auth.MetaKeyLogin, login)
+	err = auth.Store(repo, token)
+	require.NoError(t, err)
+
+	// the bug is closed as won't fix, the label is removed afterward
+	b, _, err := backend.Bugs().New("status bug", "new bug")
+	require.NoError(t, err)
+	_, _, err = b.ChangeLabels([]string{"wontfix"}, nil)
+	require.NoError(t, err)
+	_, err = b.Close()
+	require.NoError(t, err)
+	_, _, err = b.ChangeLabels(nil, []string{"wontfix"})
+	require.NoError(t, err)
+
+	conf := core.Configuration{
+		confKeyProject:              "git-bug",
+		confKeyDefaultLogin:         login,
+		core.ConfigKeyStatusMapping: `{"Won't Fix": {"status": "closed", "labels": ["wontfix"]}, "Fix Released": {"status": "closed"}}`,
+	}
+
+	ctx := context.Background()
+
+	exporter := &launchpadExporter{}
+	err = exporter.Init(ctx, backend, conf)
+	require.NoError(t, err)
+
+	events, err := exporter.ExportAll(ctx, backend, time.Time{})
+	require.NoError(t, err)
+	for result := range events {
+		require.NoError(t, result.Err)
+	}
+
+	// the status is mapped with the labels the bug had when it was closed
+	remoteID, ok := b.Snapshot().GetCreateMetadata(metaKeyLaunchpadID)
+	require.True(t, ok)
+	id, err := strconv.Atoi(remoteID)
+	require.NoError(t, err)
+	require.Equal(t, "Won't Fix", server.bugs[id].status)
+}
+
+func TestLaunchpadExportDryRun(t *testing.T) {
+	server := newFakeLaunchpad(t, "git-bug", "key:secret")
+
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	backend, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	login := "test-identity"
+	author, err := backend.Identities().New("test identity", "test@test.org")
+	require.NoError(t, err)
+	author.SetMetadata(metaKeyLaunchpadLogin, login)
+	err = author.Commit()
+	require.NoError(t, err)
+
+	err = backend.SetUserIdentity(author)
+	require.NoError(t, err)
+
+	token := auth.NewToken(target, "key:secret")
+	token.SetMetadata(auth.MetaKeyLogin, login)
+	err = auth.Store(repo, token)
+	require.NoError(t, err)
+
+	b, _, err := backend.Bugs().New("a bug", "new bug")
+	require.NoError(t, err)
+	_, _, err = b.AddComment("first comment")
+	require.NoError(t, err)
+	_, err = b.SetTitle("a bug edited")
+	require.NoError(t, err)
+	_, err = b.Close()
+	require.NoError(t, err)
+
+	conf := core.Configuration{
+		confKeyProject:      "git-bug",
+		confKeyDefaultLogin: login,
+	}
+
+	ctx := context.Background()
+
+	exporter := &launchpadExporter{}
+	exporter.SetDryRun(true)
+	err = exporter.Init(ctx, backend, conf)
+	require.NoError(t, err)
+
+	events, err := exporter.ExportAll(ctx, backend, time.Time{})
+	require.NoError(t, err)
+
+	var exported []core.ExportEvent
+	for result := range events {
+		require.NoError(t, result.Err)
+		exported = append(exported, result.Event)
+	}
+
+	// the export went through the bug without changing anything on Launchpad
+	require.Equal(t, []core.ExportEvent{
+		core.ExportEventBug,
+		core.ExportEventComment,
+		core.ExportEventTitleEdition,
+		core.ExportEventStatusChange,
+	}, exported)
+	require.Empty(t, server.bugs)
+}
@@ -46,7 +46,7 @@ func (li *launchpadImporter) ImportAll(ctx context.Context, repo *cache.RepoCach
 	out := make(chan core.ImportResult)
 	lpAPI := new(launchpadAPI)
 
-	err := lpAPI.Init()
+	err := lpAPI.Init(core.HTTPTransport(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -87,8 +87,9 @@ func (li *launchpadImporter) ImportAll(ctx context.Context, repo *cache.RepoCach
 						text.Cleanup(lpBug.Description),
 						nil,
 						map[string]string{
-							core.MetaKeyOrigin: target,
-							metaKeyLaunchpadID: lpBugID,
+							core.MetaKeyOrigin:      target,
+							metaKeyLaunchpadID:      lpBugID,
+							metaKeyLaunchpadProject: li.conf[confKeyProject],
 						},
 					)
 					if err != nil {
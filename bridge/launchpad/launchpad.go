@@ -2,18 +2,27 @@
 package launchpad
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
 )
 
 const (
 	target = "launchpad-preview"
 
-	metaKeyLaunchpadID    = "launchpad-id"
-	metaKeyLaunchpadLogin = "launchpad-login"
+	metaKeyLaunchpadID      = "launchpad-id"
+	metaKeyLaunchpadLogin   = "launchpad-login"
+	metaKeyLaunchpadProject = "launchpad-project"
 
-	confKeyProject = "project"
+	confKeyProject      = "project"
+	confKeyDefaultLogin = "default-login"
+
+	// statuses used when exporting a status change, as Launchpad has a
+	// much richer set of statuses than git-bug
+	statusOpen   = "Confirmed"
+	statusClosed = "Fix Released"
 
 	defaultTimeout = 60 * time.Second
 )
@@ -35,5 +44,22 @@ func (*Launchpad) NewImporter() core.Importer {
 }
 
 func (*Launchpad) NewExporter() core.Exporter {
-	return nil
+	return &launchpadExporter{}
+}
+
+// buildClient create a Launchpad API client signing its requests with the
+// OAuth token
+func buildClient(transport http.RoundTripper, token *auth.Token) (*launchpadAPI, error) {
+	oauth, err := parseOAuthToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &launchpadAPI{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   defaultTimeout,
+		},
+		token: &oauth,
+	}, nil
 }
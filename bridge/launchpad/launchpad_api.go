@@ -10,18 +10,24 @@ package launchpad
  * - SearchTasks should yield bugs one by one
  *
  * TODO (maybe):
- * - Authentication (this might help retrieving email addresses)
+ * - Use the authentication for reading (this might help retrieving email addresses)
  */
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"path"
+	"strconv"
+	"strings"
 )
 
-const apiRoot = "https://api.launchpad.net/devel"
+// apiRoot is the root of the Launchpad API, overridden in tests.
+var apiRoot = "https://api.launchpad.net/devel"
 
 // Person describes a person on Launchpad (a bug owner, a message author, ...).
 type LPPerson struct {
@@ -70,11 +76,15 @@ type launchpadMessageAnswer struct {
 
 type launchpadAPI struct {
 	client *http.Client
+
+	// token is used to sign the requests modifying data, if set
+	token *oauthToken
 }
 
-func (lapi *launchpadAPI) Init() error {
+func (lapi *launchpadAPI) Init(transport http.RoundTripper) error {
 	lapi.client = &http.Client{
-		Timeout: defaultTimeout,
+		Transport: transport,
+		Timeout:   defaultTimeout,
 	}
 	return nil
 }
@@ -202,3 +212,124 @@ func (lapi *launchpadAPI) queryMessages(ctx context.Context, messagesURL string)
 	}
 	return messages, nil
 }
+
+// Me return the login of the authenticated user.
+func (lapi *launchpadAPI) Me(ctx context.Context) (string, error) {
+	resp, err := lapi.do(ctx, http.MethodGet, apiRoot+"/people/+me", "", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var person struct {
+		Login string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&person); err != nil {
+		return "", err
+	}
+
+	return person.Login, nil
+}
+
+// CreateBug file a new bug against a project and return its ID.
+func (lapi *launchpadAPI) CreateBug(ctx context.Context, project, title, description string) (int, error) {
+	form := url.Values{
+		"ws.op":       {"createBug"},
+		"target":      {fmt.Sprintf("%s/%s", apiRoot, project)},
+		"title":       {title},
+		"description": {description},
+	}
+
+	resp, err := lapi.postForm(ctx, apiRoot+"/bugs", form)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+
+	// The new bug is only given as a link, like https://api.launchpad.net/devel/bugs/42
+	location := resp.Header.Get("Location")
+	id, err := strconv.Atoi(path.Base(location))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected bug location: %s", location)
+	}
+
+	return id, nil
+}
+
+// AddMessage post a new comment on a bug and return its link, which is also
+// the ID found when querying the messages.
+func (lapi *launchpadAPI) AddMessage(ctx context.Context, bugID int, content string) (string, error) {
+	form := url.Values{
+		"ws.op":   {"newMessage"},
+		"content": {content},
+	}
+
+	resp, err := lapi.postForm(ctx, fmt.Sprintf("%s/bugs/%d", apiRoot, bugID), form)
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("missing message location")
+	}
+
+	return location, nil
+}
+
+// UpdateBug change some fields of a bug, like its title or description.
+func (lapi *launchpadAPI) UpdateBug(ctx context.Context, bugID int, fields map[string]string) error {
+	return lapi.patch(ctx, fmt.Sprintf("%s/bugs/%d", apiRoot, bugID), fields)
+}
+
+// UpdateBugTaskStatus change the status of a bug in the given project.
+func (lapi *launchpadAPI) UpdateBugTaskStatus(ctx context.Context, project string, bugID int, status string) error {
+	return lapi.patch(ctx, fmt.Sprintf("%s/%s/+bug/%d", apiRoot, project, bugID), map[string]string{
+		"status": status,
+	})
+}
+
+func (lapi *launchpadAPI) postForm(ctx context.Context, endpoint string, form url.Values) (*http.Response, error) {
+	return lapi.do(ctx, http.MethodPost, endpoint, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+}
+
+func (lapi *launchpadAPI) patch(ctx context.Context, endpoint string, fields map[string]string) error {
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	resp, err := lapi.do(ctx, http.MethodPatch, endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do send a request signed with the OAuth token and check the response status.
+func (lapi *launchpadAPI) do(ctx context.Context, method, endpoint, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if lapi.token != nil {
+		req.Header.Set("Authorization", lapi.token.authorizationHeader())
+	}
+
+	resp, err := lapi.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("launchpad API: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return resp, nil
+}
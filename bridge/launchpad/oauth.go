@@ -0,0 +1,145 @@
+package launchpad
+
+/*
+ * Launchpad authenticates API clients with OAuth 1.0, using the PLAINTEXT
+ * signature method. The process is documented at:
+ * https://help.launchpad.net/API/SigningRequests
+ *
+ * Once authorized, an access token is made of a key and a secret. As the
+ * auth package only knows about opaque tokens, both are stored together in
+ * a single auth.Token, as "key:secret".
+ */
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/bridge/core/auth"
+)
+
+// consumerKey identify git-bug as an application to Launchpad.
+const consumerKey = "git-bug"
+
+var ErrBadOAuthToken = errors.New("bad Launchpad OAuth token, expected \"key:secret\"")
+
+// webRoot is where the OAuth tokens are requested and authorized.
+var webRoot = "https://launchpad.net"
+
+type oauthToken struct {
+	Key    string
+	Secret string
+}
+
+// parseOAuthToken decode an OAuth access token stored as "key:secret"
+func parseOAuthToken(token *auth.Token) (oauthToken, error) {
+	key, secret, found := strings.Cut(token.Value, ":")
+	if !found || key == "" || secret == "" {
+		return oauthToken{}, ErrBadOAuthToken
+	}
+	return oauthToken{Key: key, Secret: secret}, nil
+}
+
+func (t oauthToken) String() string {
+	return t.Key + ":" + t.Secret
+}
+
+// authorizationHeader return the value of the Authorization header used to sign
+// a request to the Launchpad API.
+func (t oauthToken) authorizationHeader() string {
+	params := []struct{ key, value string }{
+		{"oauth_consumer_key", consumerKey},
+		{"oauth_token", t.Key},
+		{"oauth_signature_method", "PLAINTEXT"},
+		{"oauth_signature", "&" + t.Secret},
+		{"oauth_timestamp", strconv.FormatInt(time.Now().Unix(), 10)},
+		{"oauth_nonce", strconv.FormatInt(time.Now().UnixNano(), 36)},
+		{"oauth_version", "1.0"},
+	}
+
+	parts := make([]string, 0, len(params)+1)
+	parts = append(parts, `realm="https://api.launchpad.net/"`)
+	for _, p := range params {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, p.key, url.QueryEscape(p.value)))
+	}
+
+	return "OAuth " + strings.Join(parts, ", ")
+}
+
+// requestToken start the OAuth dance by requesting a token that the user
+// will then need to authorize.
+func requestToken(ctx context.Context) (oauthToken, error) {
+	return postOAuthForm(ctx, webRoot+"/+request-token", url.Values{
+		"oauth_consumer_key":     {consumerKey},
+		"oauth_signature_method": {"PLAINTEXT"},
+		"oauth_signature":        {"&"},
+	})
+}
+
+// authorizeURL return the page where the user can authorize a request token.
+func authorizeURL(requestToken oauthToken) string {
+	return fmt.Sprintf("%s/+authorize-token?oauth_token=%s&allow_permission=WRITE_PUBLIC",
+		webRoot, url.QueryEscape(requestToken.Key))
+}
+
+// accessToken exchange an authorized request token for an access token.
+func accessToken(ctx context.Context, requestToken oauthToken) (oauthToken, error) {
+	return postOAuthForm(ctx, webRoot+"/+access-token", url.Values{
+		"oauth_consumer_key":     {consumerKey},
+		"oauth_token":            {requestToken.Key},
+		"oauth_signature_method": {"PLAINTEXT"},
+		"oauth_signature":        {"&" + requestToken.Secret},
+	})
+}
+
+func postOAuthForm(ctx context.Context, endpoint string, form url.Values) (oauthToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauthToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{
+		Timeout: defaultTimeout,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return oauthToken{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return oauthToken{}, fmt.Errorf("launchpad OAuth: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	values, err := url.ParseQuery(strings.TrimSpace(string(body)))
+	if err != nil {
+		return oauthToken{}, err
+	}
+
+	token := oauthToken{
+		Key:    values.Get("oauth_token"),
+		Secret: values.Get("oauth_token_secret"),
+	}
+	if token.Key == "" || token.Secret == "" {
+		return oauthToken{}, fmt.Errorf("launchpad OAuth: unexpected response")
+	}
+
+	return token, nil
+}
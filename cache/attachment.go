@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// attachmentMaxSizeConfigKey is the git config key holding the maximum size of
+// an attachment, in bytes or with a unit (ex: "10MB").
+const attachmentMaxSizeConfigKey = "git-bug.attachment.max-size"
+
+// DefaultAttachmentMaxSize is the maximum size of an attachment when not
+// configured otherwise (100MB, same as github)
+const DefaultAttachmentMaxSize = 100 * 1000 * 1000
+
+var ErrAttachmentTooBig = errors.New("attachment is too big")
+
+// AttachmentMaxSize return the maximum size of an attachment, as configured in
+// the git config.
+func (c *RepoCache) AttachmentMaxSize() (int64, error) {
+	raw, err := c.AnyConfig().ReadString(attachmentMaxSizeConfigKey)
+	if errors.Is(err, repository.ErrNoConfigEntry) {
+		return DefaultAttachmentMaxSize, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := humanize.ParseBytes(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", attachmentMaxSizeConfigKey, err)
+	}
+	return int64(size), nil
+}
+
+// StoreAttachment store the content of a file in git and return the
+// corresponding attachment, ready to be attached to a comment. The MIME type
+// is guessed from the file name, or else from the content.
+func (c *RepoCache) StoreAttachment(name string, data []byte) (bug.Attachment, error) {
+	maxSize, err := c.AttachmentMaxSize()
+	if err != nil {
+		return bug.Attachment{}, err
+	}
+	if int64(len(data)) > maxSize {
+		return bug.Attachment{}, fmt.Errorf("%w: %s is %s, the limit is %s", ErrAttachmentTooBig,
+			name, humanize.Bytes(uint64(len(data))), humanize.Bytes(uint64(maxSize)))
+	}
+
+	hash, err := c.StoreData(data)
+	if err != nil {
+		return bug.Attachment{}, err
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(name))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	if name != "" {
+		name = filepath.Base(name)
+	}
+
+	return bug.Attachment{
+		Hash:     hash,
+		Name:     name,
+		MimeType: mimeType,
+		Size:     int64(len(data)),
+	}, nil
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestAttachments(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	cache, err := NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	iden, err := cache.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(iden))
+
+	maxSize, err := cache.AttachmentMaxSize()
+	require.NoError(t, err)
+	require.Equal(t, int64(DefaultAttachmentMaxSize), maxSize)
+
+	img, err := cache.StoreAttachment("/tmp/screenshot.png", []byte("\x89PNG\r\n\x1a\n"))
+	require.NoError(t, err)
+	require.Equal(t, "screenshot.png", img.Name)
+	require.Equal(t, "image/png", img.MimeType)
+	require.Equal(t, int64(8), img.Size)
+
+	data, err := cache.ReadData(img.Hash)
+	require.NoError(t, err)
+	require.Equal(t, []byte("\x89PNG\r\n\x1a\n"), data)
+
+	// unknown extension, sniffed from the content
+	dump, err := cache.StoreAttachment("dump", []byte{0x1f, 0x8b, 0x08, 0x00})
+	require.NoError(t, err)
+	require.Equal(t, "application/x-gzip", dump.MimeType)
+
+	require.NoError(t, repo.LocalConfig().StoreString(attachmentMaxSizeConfigKey, "4B"))
+	_, err = cache.StoreAttachment("screenshot.png", []byte("\x89PNG\r\n\x1a\n"))
+	require.ErrorIs(t, err, ErrAttachmentTooBig)
+
+	b, _, err := cache.Bugs().NewWithAttachments("title", "message", []bug.Attachment{img})
+	require.NoError(t, err)
+	require.Equal(t, []bug.Attachment{img}, b.Snapshot().Comments[0].Attachments)
+
+	commentId, _, err := b.AddCommentWithAttachments("comment", []bug.Attachment{img, dump})
+	require.NoError(t, err)
+
+	comment, err := b.Snapshot().SearchComment(commentId)
+	require.NoError(t, err)
+	require.Equal(t, []bug.Attachment{img, dump}, comment.Attachments)
+	require.Equal(t, []repository.Hash{img.Hash, dump.Hash}, comment.Files)
+
+	// editing the message keep the attachments
+	_, err = b.EditComment(commentId, "edited")
+	require.NoError(t, err)
+	_, _, err = b.EditCreateComment("edited")
+	require.NoError(t, err)
+
+	snap := b.Snapshot()
+	require.Equal(t, []bug.Attachment{img}, snap.Comments[0].Attachments)
+	require.Equal(t, []bug.Attachment{img, dump}, snap.Comments[1].Attachments)
+	require.NoError(t, b.Commit())
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// BulkEdit describe the changes to apply on many bugs at once
+type BulkEdit struct {
+	// Status is the status to set, or 0 to leave it unchanged
+	Status       common.Status
+	AddLabels    []string
+	RemoveLabels []string
+	Comment      string
+}
+
+// Validate check that the BulkEdit does something, and does it correctly
+func (e BulkEdit) Validate() error {
+	if e.Status == 0 && len(e.AddLabels) == 0 && len(e.RemoveLabels) == 0 && e.Comment == "" {
+		return fmt.Errorf("nothing to change")
+	}
+	if e.Status != 0 {
+		if err := e.Status.Validate(); err != nil {
+			return fmt.Errorf("status: %w", err)
+		}
+	}
+	for _, label := range append(slices.Clone(e.AddLabels), e.RemoveLabels...) {
+		if err := bug.Label(label).Validate(); err != nil {
+			return fmt.Errorf("label %q: %w", label, err)
+		}
+	}
+	for _, label := range e.AddLabels {
+		if slices.Contains(e.RemoveLabels, label) {
+			return fmt.Errorf("label %q is both added and removed", label)
+		}
+	}
+	return nil
+}
+
+// BulkEditResult is the outcome of a BulkEdit on one bug
+type BulkEditResult struct {
+	Id    entity.Id
+	Title string
+
+	// the effective changes: the labels already set are not added again, and
+	// so on
+	AddedLabels   []bug.Label
+	RemovedLabels []bug.Label
+	// Status is the new status, or 0 if unchanged
+	Status    common.Status
+	Commented bool
+
+	Err error
+}
+
+// Changed tell if the bug was (or would be, in a dry-run) changed
+func (r BulkEditResult) Changed() bool {
+	return len(r.AddedLabels) > 0 || len(r.RemovedLabels) > 0 || r.Status != 0 || r.Commented
+}
+
+// BulkEdit apply the same changes to a set of bugs, in a single commit for
+// each bug. Only the changes having an effect are applied, so a bug already
+// in the wanted state is left untouched. If dryRun is true, the changes are
+// only computed.
+func (c *RepoCacheBug) BulkEdit(author identity.Interface, unixTime int64, ids []entity.Id, edit BulkEdit, dryRun bool) ([]BulkEditResult, error) {
+	if err := edit.Validate(); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkEditResult, len(ids))
+	for i, id := range ids {
+		results[i] = c.bulkEditOne(author, unixTime, id, edit, dryRun)
+	}
+	return results, nil
+}
+
+func (c *RepoCacheBug) bulkEditOne(author identity.Interface, unixTime int64, id entity.Id, edit BulkEdit, dryRun bool) BulkEditResult {
+	result := BulkEditResult{Id: id}
+
+	b, err := c.Resolve(id)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	snap := b.Snapshot()
+	result.Title = snap.Title
+
+	var added, removed []string
+	for _, label := range edit.AddLabels {
+		if !slices.Contains(snap.Labels, bug.Label(label)) && !slices.Contains(added, label) {
+			added = append(added, label)
+			result.AddedLabels = append(result.AddedLabels, bug.Label(label))
+		}
+	}
+	for _, label := range edit.RemoveLabels {
+		if slices.Contains(snap.Labels, bug.Label(label)) && !slices.Contains(removed, label) {
+			removed = append(removed, label)
+			result.RemovedLabels = append(result.RemovedLabels, bug.Label(label))
+		}
+	}
+	if edit.Status != 0 && edit.Status != snap.Status {
+		result.Status = edit.Status
+	}
+	result.Commented = edit.Comment != ""
+
+	if dryRun || !result.Changed() {
+		return result
+	}
+
+	result.Err = func() error {
+		// commit what was applied, even if something failed
+		defer func() {
+			_ = b.CommitAsNeeded()
+		}()
+
+		if edit.Comment != "" {
+			if _, _, err := b.AddCommentRaw(author, unixTime, edit.Comment, nil, nil); err != nil {
+				return err
+			}
+		}
+		if len(added) > 0 || len(removed) > 0 {
+			if _, _, err := b.ChangeLabelsRaw(author, unixTime, added, removed, nil); err != nil {
+				return err
+			}
+		}
+		switch result.Status {
+		case common.OpenStatus:
+			if _, err := b.OpenRaw(author, unixTime, nil); err != nil {
+				return err
+			}
+		case common.ClosedStatus:
+			if _, err := b.CloseRaw(author, unixTime, nil); err != nil {
+				return err
+			}
+		}
+		return b.Commit()
+	}()
+
+	return result
+}
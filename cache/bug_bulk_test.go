@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBulkEdit(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	cache, err := NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	author, err := cache.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(author))
+
+	bug1, _, err := cache.Bugs().New("first", "message")
+	require.NoError(t, err)
+	_, _, err = bug1.ChangeLabels([]string{"foo"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	bug2, _, err := cache.Bugs().New("second", "message")
+	require.NoError(t, err)
+	_, err = bug2.Close()
+	require.NoError(t, err)
+	_, _, err = bug2.ChangeLabels([]string{"bar"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug2.Commit())
+
+	_, err = cache.Bugs().BulkEdit(author, time.Now().Unix(), nil, BulkEdit{}, false)
+	require.Error(t, err)
+	_, err = cache.Bugs().BulkEdit(author, time.Now().Unix(), nil, BulkEdit{AddLabels: []string{"a"}, RemoveLabels: []string{"a"}}, false)
+	require.Error(t, err)
+
+	edit := BulkEdit{
+		Status:       common.ClosedStatus,
+		AddLabels:    []string{"bar"},
+		RemoveLabels: []string{"foo"},
+	}
+	ids := []entity.Id{bug1.Id(), bug2.Id(), entity.Id("unknown")}
+
+	// dry-run only compute the changes
+	results, err := cache.Bugs().BulkEdit(author, time.Now().Unix(), ids, edit, true)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.NoError(t, results[0].Err)
+	require.Equal(t, "first", results[0].Title)
+	require.True(t, results[0].Changed())
+	require.Equal(t, []bug.Label{"bar"}, results[0].AddedLabels)
+	require.Equal(t, []bug.Label{"foo"}, results[0].RemovedLabels)
+	require.Equal(t, common.ClosedStatus, results[0].Status)
+
+	require.NoError(t, results[1].Err)
+	require.False(t, results[1].Changed())
+
+	require.Error(t, results[2].Err)
+
+	require.Equal(t, common.OpenStatus, bug1.Snapshot().Status)
+	require.Len(t, bug1.Snapshot().Operations, 2)
+
+	// apply
+	edit.Comment = "bulk comment"
+	results, err = cache.Bugs().BulkEdit(author, time.Now().Unix(), ids[:2], edit, false)
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.NoError(t, results[1].Err)
+	require.True(t, results[1].Commented)
+
+	snap1 := bug1.Snapshot()
+	require.Equal(t, common.ClosedStatus, snap1.Status)
+	require.Equal(t, []bug.Label{"bar"}, snap1.Labels)
+	require.Equal(t, "bulk comment", snap1.Comments[1].Message)
+	require.False(t, bug1.NeedCommit())
+
+	snap2 := bug2.Snapshot()
+	require.Equal(t, []bug.Label{"bar"}, snap2.Labels)
+	// the status and labels were already set, only the comment is added
+	require.Len(t, snap2.Operations, 4)
+	require.False(t, bug2.NeedCommit())
+}
@@ -46,6 +46,18 @@ func (c *BugCache) AddCommentWithFiles(message string, files []repository.Hash)
 	return c.AddCommentRaw(author, time.Now().Unix(), message, files, nil)
 }
 
+// AddCommentWithAttachments add a comment with attached files, stored
+// beforehand with RepoCache.StoreAttachment
+func (c *BugCache) AddCommentWithAttachments(message string, attachments []bug.Attachment) (entity.CombinedId, *bug.AddCommentOperation, error) {
+	author, err := c.getUserIdentity()
+	if err != nil {
+		return entity.UnsetCombinedId, nil, err
+	}
+
+	return c.AddCommentRaw(author, time.Now().Unix(), message,
+		bug.AttachmentHashes(attachments), bug.AttachmentsMetadata(attachments))
+}
+
 func (c *BugCache) AddCommentRaw(author identity.Interface, unixTime int64, message string, files []repository.Hash, metadata map[string]string) (entity.CombinedId, *bug.AddCommentOperation, error) {
 	c.mu.Lock()
 	commentId, op, err := bug.AddComment(c.entity, author, unixTime, message, files, metadata)
@@ -163,8 +175,12 @@ func (c *BugCache) EditCreateComment(body string) (entity.CombinedId, *bug.EditC
 
 // EditCreateCommentRaw is a convenience function to edit the body of a bug (the first comment)
 func (c *BugCache) EditCreateCommentRaw(author identity.Interface, unixTime int64, body string, metadata map[string]string) (entity.CombinedId, *bug.EditCommentOperation, error) {
+	// keep the attached files
+	attachments := c.Snapshot().Comments[0].Attachments
+	files, metadata := keepAttachments(attachments, metadata)
+
 	c.mu.Lock()
-	commentId, op, err := bug.EditCreateComment(c.entity, author, unixTime, body, nil, metadata)
+	commentId, op, err := bug.EditCreateComment(c.entity, author, unixTime, body, files, metadata)
 	c.mu.Unlock()
 	if err != nil {
 		return entity.UnsetCombinedId, nil, err
@@ -187,8 +203,11 @@ func (c *BugCache) EditCommentRaw(author identity.Interface, unixTime int64, tar
 		return nil, err
 	}
 
+	// keep the attached files
+	files, metadata := keepAttachments(comment.Attachments, metadata)
+
 	c.mu.Lock()
-	commentId, op, err := bug.EditComment(c.entity, author, unixTime, comment.TargetId(), message, nil, metadata)
+	commentId, op, err := bug.EditComment(c.entity, author, unixTime, comment.TargetId(), message, files, metadata)
 	c.mu.Unlock()
 	if err != nil {
 		return nil, err
@@ -217,3 +236,27 @@ func (c *BugCache) SetMetadataRaw(author identity.Interface, unixTime int64, tar
 	}
 	return op, c.notifyUpdated()
 }
+
+// keepAttachments return the files and metadata to use for an edition of a
+// comment, so that the attached files are not lost.
+func keepAttachments(attachments []bug.Attachment, metadata map[string]string) ([]repository.Hash, map[string]string) {
+	if len(attachments) == 0 {
+		return nil, metadata
+	}
+
+	merged := make(map[string]string, len(metadata)+len(attachments))
+	for _, attachment := range attachments {
+		// only the attachments that were described in the first place
+		if attachment.Name == "" && attachment.MimeType == "" {
+			continue
+		}
+		for key, value := range bug.AttachmentsMetadata([]bug.Attachment{attachment}) {
+			merged[key] = value
+		}
+	}
+	for key, value := range metadata {
+		merged[key] = value
+	}
+
+	return bug.AttachmentHashes(attachments), merged
+}
@@ -14,11 +14,14 @@ import (
 
 type RepoCacheBug struct {
 	*SubCache[*bug.Bug, *BugExcerpt, *BugCache]
+
+	watches *RepoCacheWatch
 }
 
 func NewRepoCacheBug(repo repository.ClockedRepo,
 	resolvers func() entity.Resolvers,
-	getUserIdentity getUserIdentityFunc) *RepoCacheBug {
+	getUserIdentity getUserIdentityFunc,
+	watches *RepoCacheWatch) *RepoCacheBug {
 
 	makeCached := func(b *bug.Bug, entityUpdated func(id entity.Id) error) *BugCache {
 		return NewBugCache(b, repo, getUserIdentity, entityUpdated)
@@ -49,7 +52,7 @@ func NewRepoCacheBug(repo repository.ClockedRepo,
 		formatVersion, defaultMaxLoadedBugs,
 	)
 
-	return &RepoCacheBug{SubCache: sc}
+	return &RepoCacheBug{SubCache: sc, watches: watches}
 }
 
 // ResolveBugCreateMetadata retrieve a bug that has the exact given metadata on
@@ -108,16 +111,35 @@ func (c *RepoCacheBug) ResolveComment(prefix string) (*BugCache, entity.Combined
 	return matchingBug, matchingCommentId, nil
 }
 
-// Query return the id of all Bug matching the given Query
+// Query return the id of all Bug matching the given Query. The "me" value of
+// the query designate the user identity of the repository.
 func (c *RepoCacheBug) Query(q *query.Query) ([]entity.Id, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	return c.query(q, c.getUserIdentity)
+}
+
+// QueryAs return the id of all Bug matching the given Query, with "me"
+// designating the identity returned by the me function. This function is
+// only called if the query use "me".
+func (c *RepoCacheBug) QueryAs(q *query.Query, me func() (*IdentityCache, error)) ([]entity.Id, error) {
+	return c.query(q, me)
+}
 
+func (c *RepoCacheBug) query(q *query.Query, me getUserIdentityFunc) ([]entity.Id, error) {
 	if q == nil {
 		return c.AllIds(), nil
 	}
 
-	matcher := compileMatcher(q.Filters)
+	matcher, err := compileMatcher(q.Filters, matcherEnv{
+		resolvers: c.resolvers(),
+		me:        me,
+		watches:   c.watches,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	var filtered []*BugExcerpt
 	var foundBySearch map[entity.Id]*BugExcerpt
@@ -232,6 +254,19 @@ func (c *RepoCacheBug) NewWithFiles(title string, message string, files []reposi
 	return c.NewRaw(author, time.Now().Unix(), title, message, files, nil)
 }
 
+// NewWithAttachments create a new bug with attached files for the message,
+// stored beforehand with RepoCache.StoreAttachment
+// The new bug is written in the repository (commit)
+func (c *RepoCacheBug) NewWithAttachments(title string, message string, attachments []bug.Attachment) (*BugCache, *bug.CreateOperation, error) {
+	author, err := c.getUserIdentity()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.NewRaw(author, time.Now().Unix(), title, message,
+		bug.AttachmentHashes(attachments), bug.AttachmentsMetadata(attachments))
+}
+
 // NewRaw create a new bug with attached files for the message, as
 // well as metadata for the Create operation.
 // The new bug is written in the repository (commit)
@@ -106,6 +106,12 @@ func (e *CachedEntityBase[SnapT, OpT]) EditLamportTime() lamport.Time {
 	return e.entity.EditLamportTime()
 }
 
+func (e *CachedEntityBase[SnapT, OpT]) OperationEditLamportTime(id entity.Id) (lamport.Time, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.entity.OperationEditLamportTime(id)
+}
+
 func (e *CachedEntityBase[SnapT, OpT]) FirstOp() OpT {
 	return e.entity.FirstOp()
 }
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/entities/common"
@@ -32,6 +33,13 @@ func AuthorFilter(query string) Filter {
 	}
 }
 
+// AuthorIdFilter return a Filter that match the bugs opened by an identity
+func AuthorIdFilter(id entity.Id) Filter {
+	return func(excerpt *BugExcerpt, resolvers entity.Resolvers) bool {
+		return excerpt.AuthorId == id
+	}
+}
+
 // MetadataFilter return a Filter that match a bug metadata at creation time
 func MetadataFilter(pair query.StringPair) Filter {
 	return func(excerpt *BugExcerpt, resolvers entity.Resolvers) bool {
@@ -73,6 +81,18 @@ func ActorFilter(query string) Filter {
 	}
 }
 
+// ActorIdFilter return a Filter that match the bugs an identity interacted with
+func ActorIdFilter(id entity.Id) Filter {
+	return func(excerpt *BugExcerpt, resolvers entity.Resolvers) bool {
+		for _, actorId := range excerpt.Actors {
+			if actorId == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // ParticipantFilter return a Filter that match a bug participant
 func ParticipantFilter(query string) Filter {
 	return func(excerpt *BugExcerpt, resolvers entity.Resolvers) bool {
@@ -92,6 +112,19 @@ func ParticipantFilter(query string) Filter {
 	}
 }
 
+// ParticipantIdFilter return a Filter that match the bugs an identity opened
+// or commented
+func ParticipantIdFilter(id entity.Id) Filter {
+	return func(excerpt *BugExcerpt, resolvers entity.Resolvers) bool {
+		for _, participantId := range excerpt.Participants {
+			if participantId == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // TitleFilter return a Filter that match if the title contains the given query
 func TitleFilter(query string) Filter {
 	return func(excerpt *BugExcerpt, resolvers entity.Resolvers) bool {
@@ -109,6 +142,15 @@ func NoLabelFilter() Filter {
 	}
 }
 
+// WatchingFilter return a Filter that match the bugs watched by a set of
+// identities
+func WatchingFilter(watched map[entity.Id]struct{}) Filter {
+	return func(excerpt *BugExcerpt, resolvers entity.Resolvers) bool {
+		_, ok := watched[excerpt.Id()]
+		return ok
+	}
+}
+
 // Matcher is a collection of Filter that implement a complex filter
 type Matcher struct {
 	Status      []Filter
@@ -118,28 +160,53 @@ type Matcher struct {
 	Participant []Filter
 	Label       []Filter
 	Title       []Filter
+	Watching    []Filter
 	NoFilters   []Filter
 }
 
 // compileMatcher transform a query.Filters into a specialized matcher
 // for the cache.
-func compileMatcher(filters query.Filters) *Matcher {
+func compileMatcher(filters query.Filters, env matcherEnv) (*Matcher, error) {
 	result := &Matcher{}
 
+	// identityFilter resolve "me" to the current user, or use the query
+	identityFilter := func(value string, byQuery func(string) Filter, byId func(entity.Id) Filter) (Filter, error) {
+		if !query.IsMe(value) {
+			return byQuery(value), nil
+		}
+		me, err := env.me()
+		if err != nil {
+			return nil, fmt.Errorf("resolving \"%s\": %w", query.Me, err)
+		}
+		return byId(me.Id()), nil
+	}
+
 	for _, value := range filters.Status {
 		result.Status = append(result.Status, StatusFilter(value))
 	}
 	for _, value := range filters.Author {
-		result.Author = append(result.Author, AuthorFilter(value))
+		f, err := identityFilter(value, AuthorFilter, AuthorIdFilter)
+		if err != nil {
+			return nil, err
+		}
+		result.Author = append(result.Author, f)
 	}
 	for _, value := range filters.Metadata {
 		result.Metadata = append(result.Metadata, MetadataFilter(value))
 	}
 	for _, value := range filters.Actor {
-		result.Actor = append(result.Actor, ActorFilter(value))
+		f, err := identityFilter(value, ActorFilter, ActorIdFilter)
+		if err != nil {
+			return nil, err
+		}
+		result.Actor = append(result.Actor, f)
 	}
 	for _, value := range filters.Participant {
-		result.Participant = append(result.Participant, ParticipantFilter(value))
+		f, err := identityFilter(value, ParticipantFilter, ParticipantIdFilter)
+		if err != nil {
+			return nil, err
+		}
+		result.Participant = append(result.Participant, f)
 	}
 	for _, value := range filters.Label {
 		result.Label = append(result.Label, LabelFilter(value))
@@ -147,11 +214,26 @@ func compileMatcher(filters query.Filters) *Matcher {
 	for _, value := range filters.Title {
 		result.Title = append(result.Title, TitleFilter(value))
 	}
+	for _, value := range filters.Watching {
+		watched, err := env.watches.watchedBy(value, env.resolvers, env.me)
+		if err != nil {
+			return nil, err
+		}
+		result.Watching = append(result.Watching, WatchingFilter(watched))
+	}
 	if filters.NoLabel {
 		result.NoFilters = append(result.NoFilters, NoLabelFilter())
 	}
 
-	return result
+	return result, nil
+}
+
+// matcherEnv is what the filters need from the cache to be compiled
+type matcherEnv struct {
+	resolvers entity.Resolvers
+	// me return the identity designated by "me" in the query
+	me      getUserIdentityFunc
+	watches *RepoCacheWatch
 }
 
 // Match check if a bug match the set of filters
@@ -176,6 +258,10 @@ func (f *Matcher) Match(excerpt *BugExcerpt, resolvers entity.Resolvers) bool {
 		return false
 	}
 
+	if match := f.orMatch(f.Watching, excerpt, resolvers); !match {
+		return false
+	}
+
 	if match := f.andMatch(f.Label, excerpt, resolvers); !match {
 		return false
 	}
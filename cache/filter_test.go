@@ -2,8 +2,14 @@ package cache
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/repository"
 )
 
 func TestTitleFilter(t *testing.T) {
@@ -32,3 +38,65 @@ func TestTitleFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryMe(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	c, err := NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+
+	rene, err := c.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	isaac, err := c.Identities().New("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+
+	bug1, _, err := c.Bugs().NewRaw(rene, time.Now().Unix(), "first", "message", nil, nil)
+	require.NoError(t, err)
+	bug2, _, err := c.Bugs().NewRaw(isaac, time.Now().Unix(), "second", "message", nil, nil)
+	require.NoError(t, err)
+	_, _, err = bug2.AddCommentRaw(rene, time.Now().Unix(), "comment", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug2.Commit())
+
+	parse := func(q string) *query.Query {
+		parsed, err := query.Parse(q + " sort:creation-asc")
+		require.NoError(t, err)
+		return parsed
+	}
+
+	// without user identity, "me" can't be resolved
+	_, err = c.Bugs().Query(parse("author:me"))
+	require.Error(t, err)
+
+	// but it's only needed if the query use it
+	ids, err := c.Bugs().Query(parse("author:descartes"))
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{bug1.Id()}, ids)
+
+	require.NoError(t, c.SetUserIdentity(rene))
+
+	queryIds := func(q string) []entity.Id {
+		ids, err := c.Bugs().Query(parse(q))
+		require.NoError(t, err)
+		return ids
+	}
+
+	require.Equal(t, []entity.Id{bug1.Id()}, queryIds("author:me"))
+	require.Equal(t, []entity.Id{bug1.Id()}, queryIds("author:Me"))
+	require.Equal(t, []entity.Id{bug1.Id(), bug2.Id()}, queryIds("actor:me"))
+	require.Equal(t, []entity.Id{bug1.Id(), bug2.Id()}, queryIds("participant:me"))
+	require.Equal(t, []entity.Id{bug2.Id()}, queryIds("participant:me author:newton"))
+	require.Empty(t, queryIds("author:me title:second"))
+
+	asIsaac := func(q string) []entity.Id {
+		ids, err := c.Bugs().QueryAs(parse(q), func() (*IdentityCache, error) {
+			return isaac, nil
+		})
+		require.NoError(t, err)
+		return ids
+	}
+
+	require.Equal(t, []entity.Id{bug2.Id()}, asIsaac("author:me"))
+	require.Equal(t, []entity.Id{bug2.Id()}, asIsaac("participant:me"))
+	require.Equal(t, []entity.Id{bug1.Id()}, asIsaac("author:descartes"))
+}
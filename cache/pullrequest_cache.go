@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// PullRequestCache is a wrapper around a PullRequest. It provides multiple functions:
+//
+// 1. Provide a higher level API to use than the raw API from PullRequest.
+// 2. Maintain an up-to-date Snapshot available.
+// 3. Deal with concurrency.
+type PullRequestCache struct {
+	CachedEntityBase[*pullrequest.Snapshot, pullrequest.Operation]
+}
+
+func NewPullRequestCache(pr *pullrequest.PullRequest, repo repository.ClockedRepo, getUserIdentity getUserIdentityFunc, entityUpdated func(id entity.Id) error) *PullRequestCache {
+	return &PullRequestCache{
+		CachedEntityBase: CachedEntityBase[*pullrequest.Snapshot, pullrequest.Operation]{
+			repo:            repo,
+			entityUpdated:   entityUpdated,
+			getUserIdentity: getUserIdentity,
+			entity:          &withSnapshot[*pullrequest.Snapshot, pullrequest.Operation]{Interface: pr},
+		},
+	}
+}
+
+func (c *PullRequestCache) AddCommentRaw(author identity.Interface, unixTime int64, message string, metadata map[string]string) (entity.CombinedId, *pullrequest.AddCommentOperation, error) {
+	c.mu.Lock()
+	commentId, op, err := pullrequest.AddComment(c.entity, author, unixTime, message, metadata)
+	c.mu.Unlock()
+	if err != nil {
+		return entity.UnsetCombinedId, nil, err
+	}
+	return commentId, op, c.notifyUpdated()
+}
+
+// AddCommitsRaw reference new commits in the pull request. The commits already
+// referenced are ignored, and a nil operation is returned if there is nothing
+// new.
+func (c *PullRequestCache) AddCommitsRaw(author identity.Interface, unixTime int64, commits []pullrequest.Commit, metadata map[string]string) (*pullrequest.AddCommitsOperation, error) {
+	c.mu.Lock()
+	op, err := pullrequest.AddCommits(c.entity, author, unixTime, commits, metadata)
+	c.mu.Unlock()
+	if err != nil || op == nil {
+		return nil, err
+	}
+	return op, c.notifyUpdated()
+}
+
+func (c *PullRequestCache) AddReviewRaw(author identity.Interface, unixTime int64, state pullrequest.ReviewState, message string, comments []pullrequest.ReviewComment, metadata map[string]string) (entity.CombinedId, *pullrequest.AddReviewOperation, error) {
+	c.mu.Lock()
+	reviewId, op, err := pullrequest.AddReview(c.entity, author, unixTime, state, message, comments, metadata)
+	c.mu.Unlock()
+	if err != nil {
+		return entity.UnsetCombinedId, nil, err
+	}
+	return reviewId, op, c.notifyUpdated()
+}
+
+func (c *PullRequestCache) SetStatusRaw(author identity.Interface, unixTime int64, status pullrequest.Status, mergeCommit string, metadata map[string]string) (*pullrequest.SetStatusOperation, error) {
+	c.mu.Lock()
+	op, err := pullrequest.SetStatus(c.entity, author, unixTime, status, mergeCommit, metadata)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return op, c.notifyUpdated()
+}
+
+func (c *PullRequestCache) SetTitleRaw(author identity.Interface, unixTime int64, title string, metadata map[string]string) (*pullrequest.SetTitleOperation, error) {
+	c.mu.Lock()
+	op, err := pullrequest.SetTitle(c.entity, author, unixTime, title, metadata)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return op, c.notifyUpdated()
+}
+
+func (c *PullRequestCache) SetMetadata(target entity.Id, newMetadata map[string]string) (*dag.SetMetadataOperation[*pullrequest.Snapshot], error) {
+	author, err := c.getUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SetMetadataRaw(author, time.Now().Unix(), target, newMetadata)
+}
+
+func (c *PullRequestCache) SetMetadataRaw(author identity.Interface, unixTime int64, target entity.Id, newMetadata map[string]string) (*dag.SetMetadataOperation[*pullrequest.Snapshot], error) {
+	c.mu.Lock()
+	op, err := pullrequest.SetMetadata(c.entity, author, unixTime, target, newMetadata)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return op, c.notifyUpdated()
+}
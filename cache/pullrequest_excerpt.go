@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"encoding/gob"
+	"time"
+
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// Package initialisation used to register the type for (de)serialization
+func init() {
+	gob.Register(PullRequestExcerpt{})
+}
+
+var _ Excerpt = &PullRequestExcerpt{}
+
+// PullRequestExcerpt hold a subset of the pull request values to be able to
+// sort and filter them efficiently without having to read and compile each
+// raw pull requests.
+type PullRequestExcerpt struct {
+	id entity.Id
+
+	CreateLamportTime lamport.Time
+	EditLamportTime   lamport.Time
+	CreateUnixTime    int64
+	EditUnixTime      int64
+
+	AuthorId     entity.Id
+	Status       pullrequest.Status
+	Title        string
+	SourceBranch string
+	TargetBranch string
+	LenComments  int
+	LenReviews   int
+	LenCommits   int
+
+	CreateMetadata map[string]string
+}
+
+func NewPullRequestExcerpt(pr *PullRequestCache) *PullRequestExcerpt {
+	snap := pr.Snapshot()
+
+	return &PullRequestExcerpt{
+		id:                pr.Id(),
+		CreateLamportTime: pr.CreateLamportTime(),
+		EditLamportTime:   pr.EditLamportTime(),
+		CreateUnixTime:    pr.FirstOp().Time().Unix(),
+		EditUnixTime:      snap.EditTime().Unix(),
+		AuthorId:          snap.Author.Id(),
+		Status:            snap.Status,
+		Title:             snap.Title,
+		SourceBranch:      snap.SourceBranch,
+		TargetBranch:      snap.TargetBranch,
+		LenComments:       len(snap.Comments),
+		LenReviews:        len(snap.Reviews),
+		LenCommits:        len(snap.Commits),
+		CreateMetadata:    pr.FirstOp().AllMetadata(),
+	}
+}
+
+func (e *PullRequestExcerpt) setId(id entity.Id) {
+	e.id = id
+}
+
+func (e *PullRequestExcerpt) Id() entity.Id {
+	return e.id
+}
+
+func (e *PullRequestExcerpt) CreateTime() time.Time {
+	return time.Unix(e.CreateUnixTime, 0)
+}
+
+func (e *PullRequestExcerpt) EditTime() time.Time {
+	return time.Unix(e.EditUnixTime, 0)
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"sort"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+type RepoCachePullRequest struct {
+	*SubCache[*pullrequest.PullRequest, *PullRequestExcerpt, *PullRequestCache]
+}
+
+func NewRepoCachePullRequest(repo repository.ClockedRepo,
+	resolvers func() entity.Resolvers,
+	getUserIdentity getUserIdentityFunc) *RepoCachePullRequest {
+
+	makeCached := func(pr *pullrequest.PullRequest, entityUpdated func(id entity.Id) error) *PullRequestCache {
+		return NewPullRequestCache(pr, repo, getUserIdentity, entityUpdated)
+	}
+
+	makeIndexData := func(pr *PullRequestCache) []string {
+		snap := pr.Snapshot()
+		var res []string
+		for _, comment := range snap.Comments {
+			res = append(res, comment.Message)
+		}
+		for _, review := range snap.Reviews {
+			res = append(res, review.Message)
+			for _, comment := range review.Comments {
+				res = append(res, comment.Message)
+			}
+		}
+		res = append(res, snap.Title)
+		return res
+	}
+
+	actions := Actions[*pullrequest.PullRequest]{
+		ReadWithResolver:    pullrequest.ReadWithResolver,
+		ReadAllWithResolver: pullrequest.ReadAllWithResolver,
+		Remove:              pullrequest.Remove,
+		RemoveAll:           pullrequest.RemoveAll,
+		MergeAll:            pullrequest.MergeAll,
+	}
+
+	sc := NewSubCache[*pullrequest.PullRequest, *PullRequestExcerpt, *PullRequestCache](
+		repo, resolvers, getUserIdentity,
+		makeCached, NewPullRequestExcerpt, makeIndexData, actions,
+		pullrequest.Typename, pullrequest.Namespace,
+		formatVersion, defaultMaxLoadedBugs,
+	)
+
+	return &RepoCachePullRequest{SubCache: sc}
+}
+
+// ResolvePullRequestCreateMetadata retrieve a pull request that has the exact
+// given metadata on its Create operation, that is, the first operation. It
+// fails if multiple pull requests match.
+func (c *RepoCachePullRequest) ResolvePullRequestCreateMetadata(key string, value string) (*PullRequestCache, error) {
+	return c.ResolveMatcher(func(excerpt *PullRequestExcerpt) bool {
+		return excerpt.CreateMetadata[key] == value
+	})
+}
+
+// QueryStatus return the ids of the pull requests with one of the given
+// statuses, or of all of them if no status is given, the most recent first.
+func (c *RepoCachePullRequest) QueryStatus(statuses ...pullrequest.Status) []entity.Id {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var filtered []*PullRequestExcerpt
+	for _, excerpt := range c.excerpts {
+		if len(statuses) == 0 {
+			filtered = append(filtered, excerpt)
+			continue
+		}
+		for _, status := range statuses {
+			if excerpt.Status == status {
+				filtered = append(filtered, excerpt)
+				break
+			}
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if filtered[i].CreateLamportTime != filtered[j].CreateLamportTime {
+			return filtered[i].CreateLamportTime > filtered[j].CreateLamportTime
+		}
+		return filtered[i].CreateUnixTime > filtered[j].CreateUnixTime
+	})
+
+	result := make([]entity.Id, len(filtered))
+	for i, excerpt := range filtered {
+		result[i] = excerpt.Id()
+	}
+	return result
+}
+
+// NewRaw create a new pull request, as well as metadata for the Create
+// operation.
+// The new pull request is written in the repository (commit)
+func (c *RepoCachePullRequest) NewRaw(author identity.Interface, unixTime int64, title, message, sourceBranch, targetBranch string, metadata map[string]string) (*PullRequestCache, *pullrequest.CreateOperation, error) {
+	pr, op, err := pullrequest.Create(author, unixTime, title, message, sourceBranch, targetBranch, metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = pr.Commit(c.repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cached, err := c.add(pr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cached, op, nil
+}
@@ -62,8 +62,10 @@ type RepoCache struct {
 	// resolvers for all known entities and excerpts
 	resolvers entity.Resolvers
 
-	bugs       *RepoCacheBug
-	identities *RepoCacheIdentity
+	bugs         *RepoCacheBug
+	identities   *RepoCacheIdentity
+	pullRequests *RepoCachePullRequest
+	watches      *RepoCacheWatch
 
 	subcaches []cacheMgmt
 
@@ -91,14 +93,24 @@ func NewNamedRepoCache(r repository.ClockedRepo, name string) (*RepoCache, chan
 	c.identities = NewRepoCacheIdentity(r, c.getResolvers, c.GetUserIdentity)
 	c.subcaches = append(c.subcaches, c.identities)
 
-	c.bugs = NewRepoCacheBug(r, c.getResolvers, c.GetUserIdentity)
+	c.watches = NewRepoCacheWatch(r, func(id entity.Id) (*BugExcerpt, error) {
+		return c.bugs.ResolveExcerpt(id)
+	})
+
+	c.bugs = NewRepoCacheBug(r, c.getResolvers, c.GetUserIdentity, c.watches)
 	c.subcaches = append(c.subcaches, c.bugs)
 
+	c.pullRequests = NewRepoCachePullRequest(r, c.getResolvers, c.GetUserIdentity)
+	c.subcaches = append(c.subcaches, c.pullRequests)
+
 	c.resolvers = entity.Resolvers{
 		&IdentityCache{}:   entity.ResolverFunc[*IdentityCache](c.identities.Resolve),
 		&IdentityExcerpt{}: entity.ResolverFunc[*IdentityExcerpt](c.identities.ResolveExcerpt),
 		&BugCache{}:        entity.ResolverFunc[*BugCache](c.bugs.Resolve),
 		&BugExcerpt{}:      entity.ResolverFunc[*BugExcerpt](c.bugs.ResolveExcerpt),
+
+		&PullRequestCache{}:   entity.ResolverFunc[*PullRequestCache](c.pullRequests.Resolve),
+		&PullRequestExcerpt{}: entity.ResolverFunc[*PullRequestExcerpt](c.pullRequests.ResolveExcerpt),
 	}
 
 	// small buffer so that below functions can emit an event without blocking
@@ -137,11 +149,32 @@ func NewRepoCacheNoEvents(r repository.ClockedRepo) (*RepoCache, error) {
 	return cache, nil
 }
 
+// Sandbox create a new RepoCache on top of a sandbox of the repository, where
+// everything written is kept in memory and discarded on Close. Neither this
+// cache nor the repository are affected.
+func (c *RepoCache) Sandbox() (*RepoCache, error) {
+	sandbox, err := repository.NewSandboxRepo(c.repo)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepoCacheNoEvents(sandbox)
+}
+
 // Bugs gives access to the Bug entities
 func (c *RepoCache) Bugs() *RepoCacheBug {
 	return c.bugs
 }
 
+// PullRequests gives access to the PullRequest entities
+func (c *RepoCache) PullRequests() *RepoCachePullRequest {
+	return c.pullRequests
+}
+
+// Watches gives access to the bugs watched by the local identities
+func (c *RepoCache) Watches() *RepoCacheWatch {
+	return c.watches
+}
+
 // Identities gives access to the Identity entities
 func (c *RepoCache) Identities() *RepoCacheIdentity {
 	return c.identities
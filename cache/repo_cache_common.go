@@ -97,7 +97,7 @@ func (c *RepoCache) MergeAll(remote string) <-chan entity.MergeResult {
 
 	dependency := [][]cacheMgmt{
 		{c.identities},
-		{c.bugs},
+		{c.bugs, c.pullRequests},
 	}
 
 	// run MergeAll according to entities dependencies and merge the results
@@ -0,0 +1,271 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// watchFile is where the watched bugs are stored, in the local storage of the
+// repository. As such, they are not pushed or pulled with the bugs.
+const watchFile = "watch.json"
+
+// RepoCacheWatch maintain, for each local identity, the list of the bugs it
+// watch and the edit time of each of these bugs when it last saw them.
+type RepoCacheWatch struct {
+	repo repository.RepoStorage
+	// resolveExcerpt give the current edit time of a bug
+	resolveExcerpt func(id entity.Id) (*BugExcerpt, error)
+
+	mu sync.Mutex
+	// watched bugs of each identity, loaded when needed
+	identities map[entity.Id]map[entity.Id]lamport.Time
+}
+
+func NewRepoCacheWatch(repo repository.RepoStorage, resolveExcerpt func(id entity.Id) (*BugExcerpt, error)) *RepoCacheWatch {
+	return &RepoCacheWatch{
+		repo:           repo,
+		resolveExcerpt: resolveExcerpt,
+	}
+}
+
+// Watch add a bug to the watch list of an identity. The bug is considered
+// seen as of now.
+func (c *RepoCacheWatch) Watch(identityId entity.Id, bugId entity.Id) error {
+	excerpt, err := c.resolveExcerpt(bugId)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	bugs, ok := c.identities[identityId]
+	if !ok {
+		bugs = make(map[entity.Id]lamport.Time)
+		c.identities[identityId] = bugs
+	}
+	bugs[bugId] = excerpt.EditLamportTime
+
+	return c.save()
+}
+
+// Unwatch remove a bug from the watch list of an identity
+func (c *RepoCacheWatch) Unwatch(identityId entity.Id, bugId entity.Id) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	if _, ok := c.identities[identityId][bugId]; !ok {
+		return nil
+	}
+	delete(c.identities[identityId], bugId)
+	if len(c.identities[identityId]) == 0 {
+		delete(c.identities, identityId)
+	}
+
+	return c.save()
+}
+
+// IsWatching return true if an identity watch a bug
+func (c *RepoCacheWatch) IsWatching(identityId entity.Id, bugId entity.Id) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return false, err
+	}
+
+	_, ok := c.identities[identityId][bugId]
+	return ok, nil
+}
+
+// Watched return the ids of the bugs watched by an identity
+func (c *RepoCacheWatch) Watched(identityId entity.Id) ([]entity.Id, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.Id, 0, len(c.identities[identityId]))
+	for id := range c.identities[identityId] {
+		result = append(result, id)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result, nil
+}
+
+// Watchers return the ids of the identities having a watch list
+func (c *RepoCacheWatch) Watchers() ([]entity.Id, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.Id, 0, len(c.identities))
+	for id := range c.identities {
+		result = append(result, id)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result, nil
+}
+
+// MarkSeen record that an identity saw the current state of a bug. It does
+// nothing if the bug is not watched.
+func (c *RepoCacheWatch) MarkSeen(identityId entity.Id, bugId entity.Id) error {
+	excerpt, err := c.resolveExcerpt(bugId)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	seen, ok := c.identities[identityId][bugId]
+	if !ok || seen == excerpt.EditLamportTime {
+		return nil
+	}
+	c.identities[identityId][bugId] = excerpt.EditLamportTime
+
+	return c.save()
+}
+
+// HasUnread return true if a bug watched by an identity changed since it last
+// saw it. A bug not watched has no unread changes.
+func (c *RepoCacheWatch) HasUnread(identityId entity.Id, bugId entity.Id) (bool, error) {
+	c.mu.Lock()
+	err := c.load()
+	seen, ok := c.identities[identityId][bugId]
+	c.mu.Unlock()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	excerpt, err := c.resolveExcerpt(bugId)
+	if err != nil {
+		return false, err
+	}
+
+	return excerpt.EditLamportTime > seen, nil
+}
+
+// load read the watch lists, if not done already
+func (c *RepoCacheWatch) load() error {
+	if c.identities != nil {
+		return nil
+	}
+
+	identities := make(map[entity.Id]map[entity.Id]lamport.Time)
+
+	f, err := c.repo.LocalStorage().Open(watchFile)
+	if os.IsNotExist(err) {
+		c.identities = identities
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &identities); err != nil {
+		return fmt.Errorf("reading the watched bugs: %w", err)
+	}
+
+	c.identities = identities
+	return nil
+}
+
+func (c *RepoCacheWatch) save() error {
+	data, err := json.Marshal(c.identities)
+	if err != nil {
+		return err
+	}
+
+	f, err := c.repo.LocalStorage().OpenFile(watchFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// watchedBy return the bugs watched by the identities matching a query, or by
+// the current user for "me"
+func (c *RepoCacheWatch) watchedBy(value string, resolvers entity.Resolvers, me getUserIdentityFunc) (map[entity.Id]struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[entity.Id]struct{})
+
+	if query.IsMe(value) {
+		user, err := me()
+		if err != nil {
+			return nil, fmt.Errorf("resolving \"%s\": %w", query.Me, err)
+		}
+		for id := range c.identities[user.Id()] {
+			result[id] = struct{}{}
+		}
+		return result, nil
+	}
+
+	value = strings.ToLower(value)
+	for identityId, bugs := range c.identities {
+		excerpt, err := entity.Resolve[*IdentityExcerpt](resolvers, identityId)
+		if entity.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !excerpt.Match(value) {
+			continue
+		}
+		for id := range bugs {
+			result[id] = struct{}{}
+		}
+	}
+
+	return result, nil
+}
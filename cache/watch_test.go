@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestWatch(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	c, err := NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+
+	rene, err := c.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, c.SetUserIdentity(rene))
+	isaac, err := c.Identities().New("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+
+	bug1, _, err := c.Bugs().New("first", "message")
+	require.NoError(t, err)
+	bug2, _, err := c.Bugs().New("second", "message")
+	require.NoError(t, err)
+
+	queryIds := func(q string) []entity.Id {
+		parsed, err := query.Parse(q + " sort:creation-asc")
+		require.NoError(t, err)
+		ids, err := c.Bugs().Query(parsed)
+		require.NoError(t, err)
+		return ids
+	}
+
+	require.Empty(t, queryIds("watching:me"))
+
+	require.NoError(t, c.Watches().Watch(rene.Id(), bug1.Id()))
+	require.NoError(t, c.Watches().Watch(rene.Id(), bug2.Id()))
+	require.NoError(t, c.Watches().Watch(isaac.Id(), bug2.Id()))
+
+	require.Equal(t, []entity.Id{bug1.Id(), bug2.Id()}, queryIds("watching:me"))
+	require.Equal(t, []entity.Id{bug2.Id()}, queryIds("watching:newton"))
+	require.Equal(t, []entity.Id{bug2.Id()}, queryIds("watching:me watching:newton title:second"))
+
+	watching, err := c.Watches().IsWatching(isaac.Id(), bug1.Id())
+	require.NoError(t, err)
+	require.False(t, watching)
+
+	// a change by anyone is unread until seen
+	unread, err := c.Watches().HasUnread(rene.Id(), bug1.Id())
+	require.NoError(t, err)
+	require.False(t, unread)
+
+	_, _, err = bug1.AddComment("a change")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	unread, err = c.Watches().HasUnread(rene.Id(), bug1.Id())
+	require.NoError(t, err)
+	require.True(t, unread)
+	unread, err = c.Watches().HasUnread(isaac.Id(), bug1.Id())
+	require.NoError(t, err)
+	require.False(t, unread)
+
+	require.NoError(t, c.Watches().MarkSeen(rene.Id(), bug1.Id()))
+	require.NoError(t, c.Watches().MarkSeen(isaac.Id(), bug1.Id()))
+	unread, err = c.Watches().HasUnread(rene.Id(), bug1.Id())
+	require.NoError(t, err)
+	require.False(t, unread)
+
+	// seeing a bug doesn't watch it
+	watched, err := c.Watches().Watched(isaac.Id())
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{bug2.Id()}, watched)
+
+	require.NoError(t, c.Watches().Unwatch(rene.Id(), bug1.Id()))
+	require.Equal(t, []entity.Id{bug2.Id()}, queryIds("watching:me"))
+
+	// the watch lists are persisted
+	require.NoError(t, c.Close())
+	c, err = NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer c.Close()
+
+	watchers, err := c.Watches().Watchers()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []entity.Id{rene.Id(), isaac.Id()}, watchers)
+	require.Equal(t, []entity.Id{bug2.Id()}, queryIds("watching:me"))
+}
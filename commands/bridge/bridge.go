@@ -19,10 +19,13 @@ func NewBridgeCommand(env *execenv.Env) *cobra.Command {
 	}
 
 	cmd.AddCommand(newBridgeAuthCommand(env))
+	cmd.AddCommand(newBridgeConfigureCommand(env))
+	cmd.AddCommand(newBridgeConflictsCommand(env))
 	cmd.AddCommand(newBridgeNewCommand(env))
 	cmd.AddCommand(newBridgePullCommand(env))
 	cmd.AddCommand(newBridgePushCommand(env))
 	cmd.AddCommand(newBridgeRm(env))
+	cmd.AddCommand(newBridgeSyncCommand(env))
 
 	return cmd
 }
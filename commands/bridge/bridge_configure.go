@@ -0,0 +1,266 @@
+package bridgecmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/common"
+)
+
+type bridgeConfigureOptions struct {
+	mapLabels   []string
+	dropLabels  []string
+	unmapLabels []string
+	mapStatuses []string
+	unmapStatus []string
+	clear       bool
+
+	webhookSecret    string
+	setWebhookSecret bool
+
+	conflictPolicy string
+}
+
+func newBridgeConfigureCommand(env *execenv.Env) *cobra.Command {
+	options := bridgeConfigureOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "configure NAME",
+		Short: "Configure the label and status mapping, the webhooks and the conflict policy of a bridge",
+		Long: `Configure how labels and statuses are translated by a bridge, for both import and export.
+
+Without flags, the current mapping and conflict policy are displayed.
+
+A label mapping renames a remote label into a git-bug label, or drops it when no git-bug label is given. A remote label ending with "*" matches all the labels with that prefix, and a "*" in the git-bug label is replaced with the rest of the remote label.
+
+A status mapping translates a remote state (or the state a transition leads to) into a git-bug status, optionally adding some labels along.
+
+With a webhook secret, the bridges supporting it (Github and Gitlab) can import an issue as soon as it changes, when the remote tracker is configured to send its webhooks to the "/webhook/NAME" endpoint of the web UI.
+
+The conflict policy tells what to do when pushing a change that would overwrite a title or a comment edited on the remote tracker since the last synchronization:
+- "skip" (the default) leaves the change unpushed and reports it in "git bug bridge conflicts"
+- "local-wins" overwrites the remote change
+- "remote-wins" keeps the remote change, which will be imported by the next pull
+- "fail" stops the push of the bug with an error`,
+		Example: `# Display the mapping of the "default" bridge
+git bug bridge configure default
+
+# Rename the Gitlab scoped labels and drop an unwanted label
+git bug bridge configure default \
+    --map-label 'priority::*=priority:*' \
+    --map-label 'type::bug=bug' \
+    --drop-label 'triage'
+
+# Map Jira statuses to git-bug
+git bug bridge configure default \
+    --map-status 'In Progress=open,in-progress' \
+    --map-status "Won't Do=closed,wontfix" \
+    --map-status 'Done=closed'
+
+# Accept the webhooks signed with a secret
+git bug bridge configure default --webhook-secret "$(openssl rand -hex 20)"
+
+# Overwrite the remote changes on conflict
+git bug bridge configure default --conflict-policy local-wins`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			options.setWebhookSecret = cmd.Flags().Changed("webhook-secret")
+			return runBridgeConfigure(env, options, args)
+		}),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.Bridge(env),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringArrayVar(&options.mapLabels, "map-label", nil,
+		"Map a remote label to a git-bug label, as REMOTE=LOCAL")
+	flags.StringArrayVar(&options.dropLabels, "drop-label", nil,
+		"Drop a remote label")
+	flags.StringArrayVar(&options.unmapLabels, "unmap-label", nil,
+		"Remove the mapping of a remote label")
+	flags.StringArrayVar(&options.mapStatuses, "map-status", nil,
+		"Map a remote state to a git-bug status and optional labels, as STATE=STATUS[,LABEL...]")
+	flags.StringArrayVar(&options.unmapStatus, "unmap-status", nil,
+		"Remove the mapping of a remote state")
+	flags.BoolVar(&options.clear, "clear", false,
+		"Remove all the mapping before applying the other flags")
+	flags.StringVar(&options.webhookSecret, "webhook-secret", "",
+		"Secret shared with the remote tracker to authenticate its webhooks, or empty to disable them")
+	flags.StringVar(&options.conflictPolicy, "conflict-policy", "",
+		"What to do when a pushed change conflicts with a remote one [skip,local-wins,remote-wins,fail]")
+
+	return cmd
+}
+
+func runBridgeConfigure(env *execenv.Env, opts bridgeConfigureOptions, args []string) error {
+	b, err := bridge.LoadBridge(env.Backend, args[0])
+	if err != nil {
+		return err
+	}
+
+	var policy core.ConflictPolicy
+	if opts.conflictPolicy != "" {
+		policy, err = core.ConflictPolicyFromString(opts.conflictPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	mapping, err := b.Mapping()
+	if err != nil {
+		return err
+	}
+
+	changed := opts.clear ||
+		len(opts.mapLabels) > 0 || len(opts.dropLabels) > 0 || len(opts.unmapLabels) > 0 ||
+		len(opts.mapStatuses) > 0 || len(opts.unmapStatus) > 0
+
+	if opts.clear {
+		mapping.Labels = make(map[string]string)
+		mapping.Statuses = make(map[string]core.StatusMapping)
+	}
+
+	for _, raw := range opts.mapLabels {
+		remote, local, found := strings.Cut(raw, "=")
+		if !found || remote == "" {
+			return fmt.Errorf("invalid label mapping \"%s\", expected REMOTE=LOCAL", raw)
+		}
+		mapping.Labels[remote] = local
+	}
+	for _, remote := range opts.dropLabels {
+		mapping.Labels[remote] = ""
+	}
+	for _, remote := range opts.unmapLabels {
+		delete(mapping.Labels, remote)
+	}
+
+	for _, raw := range opts.mapStatuses {
+		state, sm, err := parseStatusMapping(raw)
+		if err != nil {
+			return err
+		}
+		mapping.Statuses[state] = sm
+	}
+	for _, state := range opts.unmapStatus {
+		delete(mapping.Statuses, state)
+	}
+
+	if changed {
+		err = b.SetMapping(mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.setWebhookSecret {
+		err = b.SetWebhookSecret(opts.webhookSecret)
+		if err != nil {
+			return err
+		}
+		if opts.webhookSecret == "" {
+			env.Out.Println("Webhooks disabled.")
+		} else {
+			env.Out.Println("Webhook secret updated.")
+		}
+		if !changed && policy == "" {
+			return nil
+		}
+	}
+
+	if policy != "" {
+		err = b.SetConflictPolicy(policy)
+		if err != nil {
+			return err
+		}
+		env.Out.Printf("Conflict policy set to %s.\n", policy)
+		if !changed {
+			return nil
+		}
+	}
+
+	printMapping(env, mapping)
+
+	if !changed {
+		current, err := b.ConflictPolicy()
+		if err != nil {
+			return err
+		}
+		env.Out.Printf("Conflict policy: %s\n", current)
+	}
+
+	return nil
+}
+
+func parseStatusMapping(raw string) (string, core.StatusMapping, error) {
+	state, value, found := strings.Cut(raw, "=")
+	if !found || state == "" || value == "" {
+		return "", core.StatusMapping{}, fmt.Errorf("invalid status mapping \"%s\", expected STATE=STATUS[,LABEL...]", raw)
+	}
+
+	parts := strings.Split(value, ",")
+
+	status, err := common.StatusFromString(parts[0])
+	if err != nil {
+		return "", core.StatusMapping{}, fmt.Errorf("invalid status mapping \"%s\": unknown status \"%s\"", raw, parts[0])
+	}
+
+	var labels []string
+	for _, label := range parts[1:] {
+		label = strings.TrimSpace(label)
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return state, core.StatusMapping{Status: status, Labels: labels}, nil
+}
+
+func printMapping(env *execenv.Env, mapping *core.Mapping) {
+	if len(mapping.Labels) == 0 && len(mapping.Statuses) == 0 {
+		env.Out.Println("No mapping configured, labels and statuses are kept as is.")
+		return
+	}
+
+	if len(mapping.Labels) > 0 {
+		env.Out.Println("Labels:")
+		keys := make([]string, 0, len(mapping.Labels))
+		for key := range mapping.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, remote := range keys {
+			local := mapping.Labels[remote]
+			if local == "" {
+				env.Out.Printf("  %s => (dropped)\n", remote)
+			} else {
+				env.Out.Printf("  %s => %s\n", remote, local)
+			}
+		}
+	}
+
+	if len(mapping.Statuses) > 0 {
+		env.Out.Println("Statuses:")
+		keys := make([]string, 0, len(mapping.Statuses))
+		for key := range mapping.Statuses {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, state := range keys {
+			sm := mapping.Statuses[state]
+			if len(sm.Labels) == 0 {
+				env.Out.Printf("  %s => %s\n", state, sm.Status)
+			} else {
+				env.Out.Printf("  %s => %s, with labels %s\n", state, sm.Status, strings.Join(sm.Labels, ", "))
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package bridgecmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+func newBridgeConflictsCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conflicts [NAME]",
+		Short: "List the changes that couldn't be pushed because of a conflicting remote change",
+		Long: `List the local changes that couldn't be pushed to the remote bug tracker, because the remote value they would overwrite changed since the last synchronization.
+
+What the export does on a conflict is configured with "git bug bridge configure --conflict-policy". A conflict stays listed until it's resolved by pushing with the "local-wins" or "remote-wins" policy.`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runBridgeConflicts(env, args)
+		}),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.Bridge(env),
+	}
+
+	return cmd
+}
+
+func runBridgeConflicts(env *execenv.Env, args []string) error {
+	var b *core.Bridge
+	var err error
+
+	if len(args) == 0 {
+		b, err = bridge.DefaultBridge(env.Backend)
+	} else {
+		b, err = bridge.LoadBridge(env.Backend, args[0])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := b.Conflicts()
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) == 0 {
+		env.Out.Println("No conflict.")
+		return nil
+	}
+
+	for _, conflict := range conflicts {
+		env.Out.Printf("%s %s %s\n",
+			colors.Cyan(conflict.BugId.Human()),
+			colors.Yellow(conflict.Time.Format("Jan 2 2006 15:04")),
+			conflict.Reason,
+		)
+	}
+
+	return nil
+}
@@ -34,12 +34,15 @@ func newBridgeNewCommand(env *execenv.Env) *cobra.Command {
 		Short: "Configure a new bridge",
 		Long:  "Configure a new bridge by passing flags or/and using interactive terminal prompts. You can avoid all the terminal prompts by passing all the necessary flags to configure your bridge.",
 		Example: `# Interactive example
-[1]: github
-[2]: gitlab
-[3]: jira
-[4]: launchpad-preview
-
-target: 1
+[1]: bugzilla
+[2]: file
+[3]: gitea
+[4]: github
+[5]: gitlab
+[6]: jira
+[7]: launchpad-preview
+
+target: 4
 name [default]: default
 
 Detected projects:
@@ -74,12 +77,50 @@ git bug bridge new \
     --project=$(PROJECT) \
     --token=$(TOKEN)
 
+# For GitHub, also importing the pull requests
+git bug bridge new \
+    --name=default \
+    --target=github \
+    --owner=$(OWNER) \
+    --project=$(PROJECT) \
+    --pull-requests \
+    --token=$(TOKEN)
+
+# For Gitea or Forgejo
+git bug bridge new \
+    --name=default \
+    --target=gitea \
+    --base-url=https://codeberg.org/ \
+    --url=https://codeberg.org/forgejo/forgejo \
+    --token=$(TOKEN)
+
 # For Launchpad
 git bug bridge new \
     --name=default \
     --target=launchpad-preview \
     --url=https://bugs.launchpad.net/ubuntu/
 
+# For Launchpad, with an OAuth token to export ("key:secret")
+git bug bridge new \
+    --name=default \
+    --target=launchpad-preview \
+    --url=https://bugs.launchpad.net/ubuntu/ \
+    --token=$(TOKEN)
+
+# For Bugzilla, with an optional API key to see the restricted bugs
+git bug bridge new \
+    --name=default \
+    --target=bugzilla \
+    --base-url=https://bugzilla.mozilla.org/ \
+    --project=Firefox \
+    --token=$(API_KEY)
+
+# For a JSON Lines or CSV file
+git bug bridge new \
+    --name=backup \
+    --target=file \
+    --url=bugs.jsonl
+
 # For Gitlab
 git bug bridge new \
     --name=default \
@@ -99,7 +140,7 @@ git bug bridge new \
 	flags.StringVarP(&options.target, "target", "t", "",
 		fmt.Sprintf("The target of the bridge. Valid values are [%s]", strings.Join(bridge.Targets(), ",")))
 	cmd.RegisterFlagCompletionFunc("target", completion.From(bridge.Targets()))
-	flags.StringVarP(&options.params.URL, "url", "u", "", "The URL of the remote repository")
+	flags.StringVarP(&options.params.URL, "url", "u", "", "The URL of the remote repository, or the path of the file")
 	flags.StringVarP(&options.params.BaseURL, "base-url", "b", "", "The base URL of your remote issue tracker")
 	flags.StringVarP(&options.params.Login, "login", "l", "", "The login on your remote issue tracker")
 	flags.StringVarP(&options.params.CredPrefix, "credential", "c", "", "The identifier or prefix of an already known credential for your remote issue tracker (see \"git-bug bridge auth\")")
@@ -107,6 +148,7 @@ git bug bridge new \
 	flags.BoolVar(&options.tokenStdin, "token-stdin", false, "Will read the token from stdin and ignore --token")
 	flags.StringVarP(&options.params.Owner, "owner", "o", "", "The owner of the remote repository")
 	flags.StringVarP(&options.params.Project, "project", "p", "", "The name of the remote repository")
+	flags.BoolVar(&options.params.PullRequests, "pull-requests", false, "Also import the pull requests, for a Github bridge")
 	flags.BoolVar(&options.nonInteractive, "non-interactive", false, "Do not ask for user input")
 
 	return cmd
@@ -15,12 +15,14 @@ import (
 	"github.com/MichaelMure/git-bug/bridge/core"
 	"github.com/MichaelMure/git-bug/commands/completion"
 	"github.com/MichaelMure/git-bug/commands/execenv"
+	notifycmd "github.com/MichaelMure/git-bug/commands/notify"
 	"github.com/MichaelMure/git-bug/util/interrupt"
 )
 
 type bridgePullOptions struct {
 	importSince string
 	noResume    bool
+	dryRun      bool
 }
 
 func newBridgePullCommand(env *execenv.Env) *cobra.Command {
@@ -42,6 +44,7 @@ func newBridgePullCommand(env *execenv.Env) *cobra.Command {
 
 	flags.BoolVarP(&options.noResume, "no-resume", "n", false, "force importing all bugs")
 	flags.StringVarP(&options.importSince, "since", "s", "", "import only bugs updated after the given date (ex: \"200h\" or \"june 2 2019\")")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "show what would be imported, without changing anything")
 
 	return cmd
 }
@@ -64,6 +67,14 @@ func runBridgePull(env *execenv.Env, opts bridgePullOptions, args []string) erro
 		return err
 	}
 
+	if opts.dryRun {
+		b, err = b.DryRun()
+		if err != nil {
+			return err
+		}
+		defer b.Close()
+	}
+
 	parentCtx := context.Background()
 	ctx, cancel := context.WithCancel(parentCtx)
 	defer cancel()
@@ -112,6 +123,7 @@ func runBridgePull(env *execenv.Env, opts bridgePullOptions, args []string) erro
 	}
 
 	importedIssues := 0
+	importedPullRequests := 0
 	importedIdentities := 0
 	for result := range events {
 		switch result.Event {
@@ -122,6 +134,10 @@ func runBridgePull(env *execenv.Env, opts bridgePullOptions, args []string) erro
 			importedIssues++
 			env.Out.Println(result.String())
 
+		case core.ImportEventPullRequest:
+			importedPullRequests++
+			env.Out.Println(result.String())
+
 		case core.ImportEventIdentity:
 			importedIdentities++
 			env.Out.Println(result.String())
@@ -136,7 +152,16 @@ func runBridgePull(env *execenv.Env, opts bridgePullOptions, args []string) erro
 		}
 	}
 
-	env.Out.Printf("imported %d issues and %d identities with %s bridge\n", importedIssues, importedIdentities, b.Name)
+	if importedPullRequests > 0 {
+		env.Out.Printf("imported %d issues, %d pull requests and %d identities with %s bridge\n", importedIssues, importedPullRequests, importedIdentities, b.Name)
+	} else {
+		env.Out.Printf("imported %d issues and %d identities with %s bridge\n", importedIssues, importedIdentities, b.Name)
+	}
+	if opts.dryRun {
+		env.Out.Println("dry-run: nothing has been written in the repository")
+	} else {
+		notifycmd.AfterSync(env)
+	}
 
 	// send done signal
 	close(done)
@@ -15,22 +15,33 @@ import (
 	"github.com/MichaelMure/git-bug/util/interrupt"
 )
 
+type bridgePushOptions struct {
+	dryRun bool
+}
+
 func newBridgePushCommand(env *execenv.Env) *cobra.Command {
+	options := bridgePushOptions{}
+
 	cmd := &cobra.Command{
 		Use:     "push [NAME]",
 		Short:   "Push updates to remote bug tracker",
 		PreRunE: execenv.LoadBackendEnsureUser(env),
 		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
-			return runBridgePush(env, args)
+			return runBridgePush(env, options, args)
 		}),
 		Args:              cobra.MaximumNArgs(1),
 		ValidArgsFunction: completion.Bridge(env),
 	}
 
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.BoolVar(&options.dryRun, "dry-run", false, "show what would be exported, without changing anything")
+
 	return cmd
 }
 
-func runBridgePush(env *execenv.Env, args []string) error {
+func runBridgePush(env *execenv.Env, opts bridgePushOptions, args []string) error {
 	var b *core.Bridge
 	var err error
 
@@ -44,6 +55,14 @@ func runBridgePush(env *execenv.Env, args []string) error {
 		return err
 	}
 
+	if opts.dryRun {
+		b, err = b.DryRun()
+		if err != nil {
+			return err
+		}
+		defer b.Close()
+	}
+
 	parentCtx := context.Background()
 	ctx, cancel := context.WithCancel(parentCtx)
 	defer cancel()
@@ -78,6 +97,7 @@ func runBridgePush(env *execenv.Env, args []string) error {
 	}
 
 	exportedIssues := 0
+	conflicts := 0
 	for result := range events {
 		if result.Event != core.ExportEventNothing {
 			env.Out.Println(result.String())
@@ -86,10 +106,17 @@ func runBridgePush(env *execenv.Env, args []string) error {
 		switch result.Event {
 		case core.ExportEventBug:
 			exportedIssues++
+		case core.ExportEventConflict:
+			conflicts++
 		}
 	}
 
 	env.Out.Printf("exported %d issues with %s bridge\n", exportedIssues, b.Name)
+	if opts.dryRun {
+		env.Out.Println("dry-run: nothing has been changed in the repository or on the remote")
+	} else if conflicts > 0 {
+		env.Out.Printf("%d conflicts with remote changes, see \"git bug bridge conflicts %s\"\n", conflicts, b.Name)
+	}
 
 	// send done signal
 	close(done)
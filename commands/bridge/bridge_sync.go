@@ -0,0 +1,214 @@
+package bridgecmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	notifycmd "github.com/MichaelMure/git-bug/commands/notify"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+// maxBackoff is the maximum factor applied to the interval when backing off
+const maxBackoff = 16
+
+type bridgeSyncOptions struct {
+	watch    bool
+	interval time.Duration
+	verbose  bool
+}
+
+func newBridgeSyncCommand(env *execenv.Env) *cobra.Command {
+	options := bridgeSyncOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sync [NAME...]",
+		Short: "Pull and push updates for all the configured bridges",
+		Long: `Pull and push updates for the given bridges, or all the configured bridges if none is given.
+
+Each import resume from the last complete import of that bridge. With --watch, the synchronization runs continuously on an interval. When a remote tracker rate limits or fails, the interval for that bridge is doubled until a cycle succeeds again.`,
+		Example: `# Synchronize all the bridges once
+git bug bridge sync
+
+# Synchronize continuously, every 10 minutes
+git bug bridge sync --watch --interval 10m`,
+		PreRunE: execenv.LoadBackendEnsureUser(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runBridgeSync(env, options, args)
+		}),
+		ValidArgsFunction: completion.Bridge(env),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.BoolVarP(&options.watch, "watch", "w", false, "keep synchronizing on an interval")
+	flags.DurationVarP(&options.interval, "interval", "i", 5*time.Minute, "time between two synchronizations with --watch")
+	flags.BoolVarP(&options.verbose, "verbose", "v", false, "display every imported and exported change")
+
+	return cmd
+}
+
+// syncState track when a bridge should be synchronized next
+type syncState struct {
+	bridge  *core.Bridge
+	backoff int
+	next    time.Time
+}
+
+func runBridgeSync(env *execenv.Env, opts bridgeSyncOptions, args []string) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("the interval must be positive")
+	}
+
+	names := args
+	if len(names) == 0 {
+		var err error
+		names, err = bridge.ConfiguredBridges(env.Backend)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no configured bridge")
+		}
+	}
+
+	states := make([]*syncState, 0, len(names))
+	for _, name := range names {
+		b, err := bridge.LoadBridge(env.Backend, name)
+		if err != nil {
+			return err
+		}
+		states = append(states, &syncState{bridge: b, backoff: 1})
+	}
+
+	parentCtx := context.Background()
+	ctx, cancel := context.WithCancel(parentCtx)
+	defer cancel()
+
+	// buffered channel to avoid send block at the end
+	done := make(chan struct{}, 1)
+
+	var mu sync.Mutex
+	interruptCount := 0
+	interrupt.RegisterCleaner(func() error {
+		mu.Lock()
+		if interruptCount > 0 {
+			env.Err.Println("Received another interrupt before graceful stop, terminating...")
+			os.Exit(0)
+		}
+
+		interruptCount++
+		mu.Unlock()
+
+		env.Err.Println("Received interrupt signal, stopping the synchronization...\n(Hit ctrl-c again to kill the process.)")
+
+		// send signal to stop the bridges
+		cancel()
+
+		// block until the bridges gracefully shutdown
+		<-done
+		return nil
+	})
+	defer close(done)
+
+	onImport := func(result core.ImportResult) {
+		if displayImportResult(result, opts.verbose) {
+			env.Out.Println(result.String())
+		}
+	}
+	onExport := func(result core.ExportResult) {
+		if displayExportResult(result, opts.verbose) {
+			env.Out.Println(result.String())
+		}
+	}
+
+	for {
+		synced := false
+		for _, state := range states {
+			if time.Now().Before(state.next) {
+				continue
+			}
+			synced = true
+
+			summary, err := state.bridge.Sync(ctx, onImport, onExport)
+			if ctx.Err() != nil {
+				// interrupted, the next run resume from the last complete import
+				return nil
+			}
+			if err != nil {
+				env.Err.Printf("%s: %v\n", state.bridge.Name, err)
+			} else {
+				env.Out.Println(summary.String())
+			}
+
+			if !opts.watch {
+				continue
+			}
+
+			if err != nil || summary.Errors > 0 || summary.RateLimited {
+				state.backoff = min(state.backoff*2, maxBackoff)
+				env.Out.Printf("%s: backing off, next synchronization in %s\n",
+					state.bridge.Name, opts.interval*time.Duration(state.backoff))
+			} else {
+				state.backoff = 1
+			}
+			state.next = time.Now().Add(opts.interval * time.Duration(state.backoff))
+		}
+
+		if synced {
+			notifycmd.AfterSync(env)
+		}
+
+		if !opts.watch {
+			return nil
+		}
+
+		next := states[0].next
+		for _, state := range states[1:] {
+			if state.next.Before(next) {
+				next = state.next
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+	}
+}
+
+func displayImportResult(result core.ImportResult, verbose bool) bool {
+	switch result.Event {
+	case core.ImportEventNothing:
+		return false
+	case core.ImportEventError:
+		return result.Err != context.Canceled
+	case core.ImportEventBug, core.ImportEventPullRequest, core.ImportEventIdentity, core.ImportEventWarning, core.ImportEventRateLimiting:
+		return true
+	default:
+		return verbose
+	}
+}
+
+func displayExportResult(result core.ExportResult, verbose bool) bool {
+	switch result.Event {
+	case core.ExportEventNothing:
+		return false
+	case core.ExportEventError:
+		return result.Err != context.Canceled
+	case core.ExportEventBug, core.ExportEventConflict, core.ExportEventWarning, core.ExportEventRateLimiting:
+		return true
+	default:
+		return verbose
+	}
+}
@@ -1,9 +1,12 @@
 package bugcmd
 
 import (
+	"encoding/csv"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	text "github.com/MichaelMure/go-term-text"
@@ -28,6 +31,7 @@ type bugOptions struct {
 	actorQuery          []string
 	labelQuery          []string
 	titleQuery          []string
+	watchingQuery       []string
 	noQuery             []string
 	sortBy              string
 	sortDirection       string
@@ -55,6 +59,15 @@ git bug "foo bar" baz
 
 Use queries, flags, and full text search:
 git bug status:open --by creation "foo bar" baz
+
+Format the output with a Go template:
+git bug --format 'template={{.HumanId}} {{.Title}} {{range .Labels}}{{label .}} {{end}}({{ago .EditTime}})'
+
+Define a named format, then use it:
+git config git-bug.format.bug.short '{{.HumanId}} {{.Title}}'
+git bug --format short
+
+The templates are given the same data as the json format, and can use the functions human, bold, cyan, yellow, magenta, green, red, blue, label, labelColor, ago, date, join, upper, lower, trim, truncate and json.
 `,
 		PreRunE: execenv.LoadBackend(env),
 		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
@@ -71,21 +84,24 @@ git bug status:open --by creation "foo bar" baz
 		"Filter by status. Valid values are [open,closed]")
 	cmd.RegisterFlagCompletionFunc("status", completion.From([]string{"open", "closed"}))
 	flags.StringSliceVarP(&options.authorQuery, "author", "a", nil,
-		"Filter by author")
+		"Filter by author, \"me\" for yourself")
 	flags.StringSliceVarP(&options.metadataQuery, "metadata", "m", nil,
 		"Filter by metadata. Example: github-url=URL")
 	cmd.RegisterFlagCompletionFunc("author", completion.UserForQuery(env))
 	flags.StringSliceVarP(&options.participantQuery, "participant", "p", nil,
-		"Filter by participant")
+		"Filter by participant, \"me\" for yourself")
 	cmd.RegisterFlagCompletionFunc("participant", completion.UserForQuery(env))
 	flags.StringSliceVarP(&options.actorQuery, "actor", "A", nil,
-		"Filter by actor")
+		"Filter by actor, \"me\" for yourself")
 	cmd.RegisterFlagCompletionFunc("actor", completion.UserForQuery(env))
 	flags.StringSliceVarP(&options.labelQuery, "label", "l", nil,
 		"Filter by label")
 	cmd.RegisterFlagCompletionFunc("label", completion.Label(env))
 	flags.StringSliceVarP(&options.titleQuery, "title", "t", nil,
 		"Filter by title")
+	flags.StringSliceVarP(&options.watchingQuery, "watching", "w", nil,
+		"Filter by watcher, \"me\" for the bugs you watch")
+	cmd.RegisterFlagCompletionFunc("watching", completion.UserForQuery(env))
 	flags.StringSliceVarP(&options.noQuery, "no", "n", nil,
 		"Filter by absence of something. Valid values are [label]")
 	cmd.RegisterFlagCompletionFunc("no", completion.Label(env))
@@ -96,9 +112,10 @@ git bug status:open --by creation "foo bar" baz
 		"Select the sorting direction. Valid values are [asc,desc]")
 	cmd.RegisterFlagCompletionFunc("direction", completion.From([]string{"asc", "desc"}))
 	flags.StringVarP(&options.outputFormat, "format", "f", "default",
-		"Select the output formatting style. Valid values are [default,plain,id,json,org-mode]")
+		"Select the output formatting style. Valid values are [default,plain,id,json,org-mode,markdown,csv,html], "+
+			fmt.Sprintf(formatHelp, "bug"))
 	cmd.RegisterFlagCompletionFunc("format",
-		completion.From([]string{"default", "plain", "id", "json", "org-mode"}))
+		completion.From([]string{"default", "plain", "id", "json", "org-mode", "markdown", "csv", "html"}))
 
 	const selectGroup = "select"
 	cmd.AddGroup(&cobra.Group{ID: selectGroup, Title: "Implicit selection"})
@@ -111,6 +128,7 @@ git bug status:open --by creation "foo bar" baz
 	addCmdWithGroup(newBugDeselectCommand(env), selectGroup)
 	addCmdWithGroup(newBugSelectCommand(env), selectGroup)
 
+	cmd.AddCommand(newBugAttachmentCommand(env))
 	cmd.AddCommand(newBugCommentCommand(env))
 	cmd.AddCommand(newBugLabelCommand(env))
 	cmd.AddCommand(newBugNewCommand(env))
@@ -175,8 +193,21 @@ func runBug(env *execenv.Env, opts bugOptions, args []string) error {
 		return bugsJsonFormatter(env, excerpts)
 	case "org-mode":
 		return bugsOrgmodeFormatter(env, excerpts)
+	case "markdown":
+		return bugsMarkdownFormatter(env, excerpts)
+	case "csv":
+		return bugsCsvFormatter(env, excerpts)
+	case "html":
+		return bugsHtmlFormatter(env, excerpts)
 	default:
-		return fmt.Errorf("unknown format %s", opts.outputFormat)
+		tmpl, err := loadTemplate(env, "bug", opts.outputFormat)
+		if err != nil {
+			return err
+		}
+		if tmpl == nil {
+			return fmt.Errorf("unknown format %s", opts.outputFormat)
+		}
+		return bugsTemplateFormatter(env, tmpl, excerpts)
 	}
 }
 
@@ -193,18 +224,105 @@ func repairQuery(args []string) string {
 	return strings.Join(args, " ")
 }
 
-func bugsJsonFormatter(env *execenv.Env, excerpts []*cache.BugExcerpt) error {
+func newJsonBugExcerpts(env *execenv.Env, excerpts []*cache.BugExcerpt) ([]cmdjson.BugExcerpt, error) {
 	jsonBugs := make([]cmdjson.BugExcerpt, len(excerpts))
 	for i, b := range excerpts {
 		jsonBug, err := cmdjson.NewBugExcerpt(env.Backend, b)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		jsonBugs[i] = jsonBug
 	}
+	return jsonBugs, nil
+}
+
+func bugsJsonFormatter(env *execenv.Env, excerpts []*cache.BugExcerpt) error {
+	jsonBugs, err := newJsonBugExcerpts(env, excerpts)
+	if err != nil {
+		return err
+	}
 	return env.Out.PrintJSON(jsonBugs)
 }
 
+func bugsMarkdownFormatter(env *execenv.Env, excerpts []*cache.BugExcerpt) error {
+	jsonBugs, err := newJsonBugExcerpts(env, excerpts)
+	if err != nil {
+		return err
+	}
+
+	env.Out.Println("| ID | Status | Title | Labels | Author | Comments | Last edit |")
+	env.Out.Println("|----|--------|-------|--------|--------|----------|-----------|")
+
+	for _, b := range jsonBugs {
+		labels := make([]string, len(b.Labels))
+		for i, l := range b.Labels {
+			labels[i] = "`" + markdownCell(l.String()) + "`"
+		}
+
+		env.Out.Printf("| %s | %s | %s | %s | %s | %d | %s |\n",
+			b.HumanId,
+			b.Status,
+			markdownCell(b.Title),
+			strings.Join(labels, " "),
+			markdownCell(b.Author.Name),
+			b.Comments,
+			b.EditTime.Time.Format("2006-01-02"),
+		)
+	}
+	return nil
+}
+
+// markdownCell escape a text to be used in a markdown table cell
+func markdownCell(str string) string {
+	str = strings.ReplaceAll(str, "|", "\\|")
+	return strings.Join(strings.Fields(str), " ")
+}
+
+func bugsCsvFormatter(env *execenv.Env, excerpts []*cache.BugExcerpt) error {
+	jsonBugs, err := newJsonBugExcerpts(env, excerpts)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(env.Out)
+	_ = w.Write([]string{"id", "human_id", "status", "title", "labels", "author", "create_time", "edit_time", "comments"})
+
+	for _, b := range jsonBugs {
+		labels := make([]string, len(b.Labels))
+		for i, l := range b.Labels {
+			labels[i] = l.String()
+		}
+
+		_ = w.Write([]string{
+			b.Id,
+			b.HumanId,
+			b.Status,
+			b.Title,
+			strings.Join(labels, ","),
+			b.Author.Name,
+			b.CreateTime.Time.Format(time.RFC3339),
+			b.EditTime.Time.Format(time.RFC3339),
+			strconv.Itoa(b.Comments),
+		})
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func bugsTemplateFormatter(env *execenv.Env, tmpl *template.Template, excerpts []*cache.BugExcerpt) error {
+	for _, b := range excerpts {
+		jsonBug, err := cmdjson.NewBugExcerpt(env.Backend, b)
+		if err != nil {
+			return err
+		}
+		if err := executeTemplate(env, tmpl, jsonBug); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func bugsIDFormatter(env *execenv.Env, excerpts []*cache.BugExcerpt) error {
 	for _, b := range excerpts {
 		env.Out.Println(b.Id().String())
@@ -381,6 +499,7 @@ func completeQuery(q *query.Query, opts bugOptions) error {
 	q.Actor = append(q.Actor, opts.actorQuery...)
 	q.Label = append(q.Label, opts.labelQuery...)
 	q.Title = append(q.Title, opts.titleQuery...)
+	q.Watching = append(q.Watching, opts.watchingQuery...)
 
 	for _, no := range opts.noQuery {
 		switch no {
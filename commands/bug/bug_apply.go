@@ -0,0 +1,481 @@
+package bugcmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+type applyOptions struct {
+	dryRun bool
+}
+
+func NewApplyCommand(env *execenv.Env) *cobra.Command {
+	options := applyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "apply [FILE]",
+		Short: "Apply a batch of operations on bugs",
+		Long: `Apply a batch of operations on bugs, read as JSON Lines from a file or the standard input.
+
+Each line is an object with an "op" field, one of:
+  create    create a bug, with "title" and "message"
+  comment   add a comment to a bug, with "message"
+  label     change the labels of a bug, with "added" and "removed"
+  status    change the status of a bug, with "status" (open or closed)
+  title     change the title of a bug, with "title"
+  metadata  set "metadata" on the creation of a bug
+
+Except for create, "bug" is the id (or id prefix) of the bug to change, or the "ref" given to a bug created earlier in the batch. All operations accept:
+  author    the id (or id prefix) of the identity doing the change, instead of the user identity
+  time      the time of the change, as RFC 3339 or unix seconds, instead of now
+  metadata  metadata to attach to the operation
+
+All the operations are validated and applied in memory before any change is written, so that a failing line leaves the repository untouched. On success, a JSON line is written for each created bug, with its line number, ref and id.`,
+		Example: `echo '{"op":"create","ref":"a","title":"crash on start","message":"..."}
+{"op":"label","bug":"a","added":["crash"]}
+{"op":"comment","bug":"1a2b3c","message":"same as the new crash"}' | git bug apply`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: execenv.LoadBackendEnsureUser(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runApply(env, options, args)
+		}),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Validate the operations without applying them")
+
+	return cmd
+}
+
+type applyOpType string
+
+const (
+	applyCreate   applyOpType = "create"
+	applyComment  applyOpType = "comment"
+	applyLabel    applyOpType = "label"
+	applyStatus   applyOpType = "status"
+	applyTitle    applyOpType = "title"
+	applyMetadata applyOpType = "metadata"
+)
+
+// applyRecord is a line of the input
+type applyRecord struct {
+	Op applyOpType `json:"op"`
+
+	// Ref name a created bug, to reference it in the following operations
+	Ref string `json:"ref,omitempty"`
+	// Bug is the id prefix of the bug to change, or the ref of a created bug
+	Bug string `json:"bug,omitempty"`
+
+	Author string    `json:"author,omitempty"`
+	Time   applyTime `json:"time,omitempty"`
+
+	Title    string            `json:"title,omitempty"`
+	Message  string            `json:"message,omitempty"`
+	Status   string            `json:"status,omitempty"`
+	Added    []string          `json:"added,omitempty"`
+	Removed  []string          `json:"removed,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// applyTime is a time given either as RFC 3339 or as unix seconds
+type applyTime int64
+
+func (t *applyTime) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch raw := raw.(type) {
+	case float64:
+		*t = applyTime(raw)
+	case string:
+		if unix, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			*t = applyTime(unix)
+			return nil
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return fmt.Errorf("invalid time \"%s\"", raw)
+		}
+		*t = applyTime(parsed.Unix())
+	case nil:
+	default:
+		return fmt.Errorf("invalid time %s", string(data))
+	}
+	return nil
+}
+
+// applyStep is a validated operation, ready to be applied
+type applyStep struct {
+	line   int
+	record applyRecord
+	author identity.Interface
+	time   int64
+	status common.Status
+	// the bug to change, either an existing one or the index of the step
+	// creating it
+	bug    *cache.BugCache
+	create int
+}
+
+// applyCreated is the report of a created bug
+type applyCreated struct {
+	Line int       `json:"line"`
+	Ref  string    `json:"ref,omitempty"`
+	Id   entity.Id `json:"id"`
+}
+
+func runApply(env *execenv.Env, opts applyOptions, args []string) error {
+	var in io.Reader = env.In
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	steps, err := readApplySteps(env, in)
+	if err != nil {
+		return err
+	}
+
+	if err := simulateApplySteps(env.Repo, steps); err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		env.Err.Printf("%d operation(s) are valid\n", len(steps))
+		return nil
+	}
+
+	created, err := applySteps(env.Backend, steps)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range created {
+		if err := json.NewEncoder(env.Out).Encode(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readApplySteps parse and validate all the operations, without changing
+// anything
+func readApplySteps(env *execenv.Env, in io.Reader) ([]applyStep, error) {
+	user, err := env.Backend.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	v := applyValidator{
+		backend: env.Backend,
+		user:    user,
+		now:     time.Now().Unix(),
+		refs:    make(map[string]int),
+	}
+
+	var steps []applyStep
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var record applyRecord
+		decoder := json.NewDecoder(strings.NewReader(scanner.Text()))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		step, err := v.validate(line, record, len(steps))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		steps = append(steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+type applyValidator struct {
+	backend *cache.RepoCache
+	user    identity.Interface
+	now     int64
+
+	// the index of the step creating a bug, by ref
+	refs map[string]int
+}
+
+func (v *applyValidator) validate(line int, record applyRecord, index int) (applyStep, error) {
+	step := applyStep{
+		line:   line,
+		record: record,
+		author: v.user,
+		time:   v.now,
+		create: -1,
+	}
+
+	switch record.Op {
+	case applyCreate, applyComment, applyLabel, applyStatus, applyTitle, applyMetadata:
+	case "":
+		return step, fmt.Errorf("missing \"op\"")
+	default:
+		return step, fmt.Errorf("unknown op \"%s\"", record.Op)
+	}
+
+	if record.Author != "" {
+		author, err := v.backend.Identities().ResolvePrefix(record.Author)
+		if err != nil {
+			return step, fmt.Errorf("author: %w", err)
+		}
+		step.author = author
+	}
+	if record.Time != 0 {
+		step.time = int64(record.Time)
+	}
+
+	if record.Op == applyCreate {
+		if record.Bug != "" {
+			return step, fmt.Errorf("\"bug\" is not allowed for %s", record.Op)
+		}
+		if record.Ref != "" {
+			if _, ok := v.refs[record.Ref]; ok {
+				return step, fmt.Errorf("duplicate ref \"%s\"", record.Ref)
+			}
+			v.refs[record.Ref] = index
+		}
+		op := bug.NewCreateOp(step.author, step.time, record.Title, record.Message, nil)
+		return step, v.validateOp(op, record.Metadata)
+	}
+
+	if record.Ref != "" {
+		return step, fmt.Errorf("\"ref\" is only allowed for %s", applyCreate)
+	}
+	if record.Bug == "" {
+		return step, fmt.Errorf("missing \"bug\"")
+	}
+
+	if i, ok := v.refs[record.Bug]; ok {
+		step.create = i
+	} else {
+		b, err := v.backend.Bugs().ResolvePrefix(record.Bug)
+		if err != nil {
+			return step, fmt.Errorf("bug: %w", err)
+		}
+		step.bug = b
+	}
+
+	switch record.Op {
+	case applyComment:
+		op := bug.NewAddCommentOp(step.author, step.time, record.Message, nil)
+		return step, v.validateOp(op, record.Metadata)
+
+	case applyLabel:
+		op := bug.NewLabelChangeOperation(step.author, step.time, toLabels(record.Added), toLabels(record.Removed))
+		return step, v.validateOp(op, record.Metadata)
+
+	case applyStatus:
+		status, err := common.StatusFromString(record.Status)
+		if err != nil {
+			return step, err
+		}
+		step.status = status
+		op := bug.NewSetStatusOp(step.author, step.time, status)
+		return step, v.validateOp(op, record.Metadata)
+
+	case applyTitle:
+		op := bug.NewSetTitleOp(step.author, step.time, record.Title, "")
+		return step, v.validateOp(op, record.Metadata)
+
+	case applyMetadata:
+		if len(record.Metadata) == 0 {
+			return step, fmt.Errorf("no metadata to set")
+		}
+		return step, nil
+
+	default:
+		return step, fmt.Errorf("unknown op \"%s\"", record.Op)
+	}
+}
+
+func (v *applyValidator) validateOp(op bug.Operation, metadata map[string]string) error {
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+	return op.Validate()
+}
+
+func toLabels(labels []string) []bug.Label {
+	result := make([]bug.Label, len(labels))
+	for i, label := range labels {
+		result[i] = bug.Label(label)
+	}
+	return result
+}
+
+// simulateApplySteps apply the operations on in-memory bugs: the new ones are
+// built with bug.Create, and the existing ones are read again from the
+// repository, out of the cache. Nothing is written, and the cache is left
+// untouched, so that a failing operation doesn't leave a partially applied
+// batch behind.
+func simulateApplySteps(repo repository.ClockedRepo, steps []applyStep) error {
+	created := make(map[int]*bug.Bug)
+	existing := make(map[entity.Id]*bug.Bug)
+
+	for i, step := range steps {
+		r := step.record
+
+		if r.Op == applyCreate {
+			b, _, err := bug.Create(step.author, step.time, r.Title, r.Message, nil, r.Metadata)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", step.line, err)
+			}
+			created[i] = b
+			continue
+		}
+
+		var b *bug.Bug
+		if step.create >= 0 {
+			b = created[step.create]
+		} else {
+			b = existing[step.bug.Id()]
+			if b == nil {
+				var err error
+				b, err = bug.Read(repo, step.bug.Id())
+				if err != nil {
+					return fmt.Errorf("line %d: %w", step.line, err)
+				}
+				existing[b.Id()] = b
+			}
+		}
+
+		var err error
+		switch r.Op {
+		case applyComment:
+			_, _, err = bug.AddComment(b, step.author, step.time, r.Message, nil, r.Metadata)
+		case applyLabel:
+			_, _, err = bug.ChangeLabels(b, step.author, step.time, r.Added, r.Removed, r.Metadata)
+		case applyStatus:
+			switch step.status {
+			case common.OpenStatus:
+				_, err = bug.Open(b, step.author, step.time, r.Metadata)
+			case common.ClosedStatus:
+				_, err = bug.Close(b, step.author, step.time, r.Metadata)
+			}
+		case applyTitle:
+			_, err = bug.SetTitle(b, step.author, step.time, r.Title, r.Metadata)
+		case applyMetadata:
+			_, err = bug.SetMetadata(b, step.author, step.time, b.FirstOp().Id(), r.Metadata)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", step.line, err)
+		}
+	}
+
+	return nil
+}
+
+// applySteps apply the validated operations, and commit the changed bugs. As
+// simulateApplySteps already went through the same operations, only a
+// failure to write is expected here, in which case the bugs created by the
+// batch are removed.
+func applySteps(backend *cache.RepoCache, steps []applyStep) ([]applyCreated, error) {
+	var created []applyCreated
+	createdBugs := make(map[int]*cache.BugCache)
+
+	var changed []*cache.BugCache
+
+	rollback := func(err error) ([]applyCreated, error) {
+		for _, c := range created {
+			_ = backend.Bugs().Remove(c.Id.String())
+		}
+		return nil, err
+	}
+
+	for i, step := range steps {
+		r := step.record
+
+		if r.Op == applyCreate {
+			b, _, err := backend.Bugs().NewRaw(step.author, step.time, r.Title, r.Message, nil, r.Metadata)
+			if err != nil {
+				return rollback(fmt.Errorf("line %d: %w", step.line, err))
+			}
+			createdBugs[i] = b
+			created = append(created, applyCreated{Line: step.line, Ref: r.Ref, Id: b.Id()})
+			continue
+		}
+
+		b := step.bug
+		if step.create >= 0 {
+			b = createdBugs[step.create]
+		}
+		if !slices.Contains(changed, b) {
+			changed = append(changed, b)
+		}
+
+		var err error
+		switch r.Op {
+		case applyComment:
+			_, _, err = b.AddCommentRaw(step.author, step.time, r.Message, nil, r.Metadata)
+		case applyLabel:
+			_, _, err = b.ChangeLabelsRaw(step.author, step.time, r.Added, r.Removed, r.Metadata)
+		case applyStatus:
+			switch step.status {
+			case common.OpenStatus:
+				_, err = b.OpenRaw(step.author, step.time, r.Metadata)
+			case common.ClosedStatus:
+				_, err = b.CloseRaw(step.author, step.time, r.Metadata)
+			}
+		case applyTitle:
+			_, err = b.SetTitleRaw(step.author, step.time, r.Title, r.Metadata)
+		case applyMetadata:
+			target := b.Snapshot().Operations[0].Id()
+			_, err = b.SetMetadataRaw(step.author, step.time, target, r.Metadata)
+		}
+		if err != nil {
+			return rollback(fmt.Errorf("line %d: %w", step.line, err))
+		}
+	}
+
+	for _, b := range changed {
+		if err := b.CommitAsNeeded(); err != nil {
+			return rollback(err)
+		}
+	}
+
+	return created, nil
+}
@@ -0,0 +1,127 @@
+package bugcmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+func TestApply(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	other, err := env.Backend.Identities().New("Jane Doe", "jane@example.com")
+	require.NoError(t, err)
+
+	input := `{"op":"create","ref":"a","title":"new bug","message":"new message","time":"2023-01-02T03:04:05Z"}
+{"op":"label","bug":"a","added":["foo","bar"]}
+{"op":"label","bug":"a","removed":["foo"],"author":"` + other.Id().Human() + `"}
+
+{"op":"comment","bug":"` + bugID.Human() + `","message":"a comment","time":1700000000}
+{"op":"status","bug":"` + bugID.Human() + `","status":"closed","metadata":{"origin":"tool"}}
+{"op":"title","bug":"` + bugID.Human() + `","title":"new title"}
+{"op":"metadata","bug":"a","metadata":{"tracker":"T-1"}}
+`
+	_, err = env.In.(interface{ WriteString(string) (int, error) }).WriteString(input)
+	require.NoError(t, err)
+
+	require.NoError(t, runApply(env, applyOptions{}, nil))
+
+	var created applyCreated
+	require.NoError(t, json.Unmarshal([]byte(env.Out.String()), &created))
+	require.Equal(t, 1, created.Line)
+	require.Equal(t, "a", created.Ref)
+
+	newBug, err := env.Backend.Bugs().Resolve(created.Id)
+	require.NoError(t, err)
+	snap := newBug.Snapshot()
+	require.Equal(t, "new bug", snap.Title)
+	require.Equal(t, int64(1672628645), snap.CreateTime.Unix())
+	require.Equal(t, []bug.Label{"bar"}, snap.Labels)
+	require.Equal(t, other.Id(), snap.Operations[2].Author().Id())
+	value, ok := snap.Operations[0].GetMetadata("tracker")
+	require.True(t, ok)
+	require.Equal(t, "T-1", value)
+	require.False(t, newBug.NeedCommit())
+
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	snap = b.Snapshot()
+	require.Equal(t, common.ClosedStatus, snap.Status)
+	require.Equal(t, "new title", snap.Title)
+	require.Equal(t, "a comment", snap.Comments[1].Message)
+	require.Equal(t, int64(1700000000), snap.Operations[1].Time().Unix())
+	require.False(t, b.NeedCommit())
+}
+
+func TestApplyInvalid(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	for name, tc := range map[string]struct {
+		input string
+		err   string
+	}{
+		"unknown op":     {`{"op":"delete","bug":"` + bugID.Human() + `"}`, `line 1: unknown op "delete"`},
+		"unknown field":  {`{"op":"comment","bugs":"x"}`, `unknown field "bugs"`},
+		"unknown bug":    {`{"op":"comment","bug":"a","message":"m"}`, "line 1: bug:"},
+		"missing bug":    {`{"op":"comment","message":"m"}`, `line 1: missing "bug"`},
+		"empty title":    {`{"op":"create","title":"","message":"m"}`, "line 1: title is empty"},
+		"invalid status": {`{"op":"status","bug":"` + bugID.Human() + `","status":"gone"}`, "line 1: unknown status"},
+		"invalid time":   {`{"op":"create","title":"t","time":"yesterday"}`, `line 1: invalid time "yesterday"`},
+		"no label change": {`{"op":"create","ref":"a","title":"t"}
+{"op":"label","bug":"a","added":["x"]}
+{"op":"label","bug":"a","added":["x"]}`, "line 3: no label added or removed"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := env.In.(interface{ WriteString(string) (int, error) }).WriteString(tc.input)
+			require.NoError(t, err)
+
+			err = runApply(env, applyOptions{}, nil)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	// nothing was applied
+	ids := env.Backend.Bugs().AllIds()
+	require.Equal(t, []entity.Id{bugID}, ids)
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	require.Len(t, b.Snapshot().Operations, 1)
+	require.True(t, strings.HasPrefix(b.Snapshot().Title, "this is"))
+}
+
+func TestApplyFailingLine(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	// the last line is valid, but fails when applied as the label is not set
+	input := `{"op":"create","ref":"a","title":"new bug","message":"new message"}
+{"op":"comment","bug":"a","message":"a comment"}
+{"op":"comment","bug":"` + bugID.Human() + `","message":"a comment"}
+{"op":"label","bug":"` + bugID.Human() + `","removed":["missing"]}
+`
+	for _, dryRun := range []bool{true, false} {
+		_, err := env.In.(interface{ WriteString(string) (int, error) }).WriteString(input)
+		require.NoError(t, err)
+
+		err = runApply(env, applyOptions{dryRun: dryRun}, nil)
+		require.EqualError(t, err, "line 4: no label added or removed")
+	}
+
+	// nothing was written, or changed in the cache
+	require.Empty(t, env.Out.String())
+	require.Equal(t, []entity.Id{bugID}, env.Backend.Bugs().AllIds())
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	require.Len(t, b.Snapshot().Operations, 1)
+	require.False(t, b.NeedCommit())
+	stored, err := bug.Read(env.Repo, bugID)
+	require.NoError(t, err)
+	require.Len(t, stored.Operations(), 1)
+}
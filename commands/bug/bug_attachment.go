@@ -0,0 +1,69 @@
+package bugcmd
+
+import (
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+func newBugAttachmentCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "attachment [BUG_ID]",
+		Short:   "List the files attached to a bug",
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runBugAttachment(env, args)
+		}),
+		ValidArgsFunction: BugCompletion(env),
+	}
+
+	cmd.AddCommand(newBugAttachmentGetCommand(env))
+
+	return cmd
+}
+
+func runBugAttachment(env *execenv.Env, args []string) error {
+	b, _, err := ResolveSelected(env.Backend, args)
+	if err != nil {
+		return err
+	}
+
+	snap := b.Snapshot()
+
+	for _, comment := range snap.Comments {
+		for _, attachment := range comment.Attachments {
+			env.Out.Printf("%s %s\t%s\t%s\t%s\n",
+				colors.Cyan(comment.CombinedId().Human()),
+				colors.Yellow(attachment.Hash),
+				attachment.DisplayName(),
+				attachment.MimeType,
+				humanize.Bytes(uint64(attachment.Size)),
+			)
+		}
+	}
+
+	return nil
+}
+
+// storeAttachments read the given files and store them as attachments
+func storeAttachments(backend *cache.RepoCache, paths []string) ([]bug.Attachment, error) {
+	var attachments []bug.Attachment
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		attachment, err := backend.StoreAttachment(path, data)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
@@ -0,0 +1,114 @@
+package bugcmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+type bugAttachmentGetOptions struct {
+	output string
+	force  bool
+}
+
+func newBugAttachmentGetCommand(env *execenv.Env) *cobra.Command {
+	options := bugAttachmentGetOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "get HASH",
+		Short: "Extract an attached file",
+		Long: `Extract an attached file, given its hash.
+
+By default, the file is written in the current directory with its original name, or its hash if the name is unsafe. An existing file is not overwritten, unless --force is given. Use --output to choose the path, or --output - to write it on the standard output.`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runBugAttachmentGet(env, options, args)
+		}),
+		Args: cobra.ExactArgs(1),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&options.output, "output", "o", "",
+		"Write the file at the given path, overwriting it. Use - to write on the standard output")
+	flags.BoolVarP(&options.force, "force", "f", false,
+		"Overwrite the file in the current directory if it exists")
+
+	return cmd
+}
+
+func runBugAttachmentGet(env *execenv.Env, opts bugAttachmentGetOptions, args []string) error {
+	hash := repository.Hash(args[0])
+	if !hash.IsValid() {
+		return fmt.Errorf("invalid hash %s", hash)
+	}
+
+	data, err := env.Backend.ReadData(hash)
+	if err != nil {
+		return err
+	}
+
+	if opts.output == "-" {
+		_, err = env.Out.Write(data)
+		return err
+	}
+
+	output := opts.output
+	// the name comes from metadata anyone can push, never replace a file
+	// the user didn't designate
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if output == "" {
+		output, err = attachmentName(env.Backend, hash)
+		if err != nil {
+			return err
+		}
+		if !opts.force {
+			flag = os.O_WRONLY | os.O_CREATE | os.O_EXCL
+		}
+	}
+
+	f, err := os.OpenFile(output, flag, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it or --output to choose another path", output)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return err
+	}
+
+	env.Err.Printf("%s written\n", output)
+	return nil
+}
+
+// attachmentName look for the original name of an attached file, falling back
+// to its hash if the file was attached without a name.
+func attachmentName(backend *cache.RepoCache, hash repository.Hash) (string, error) {
+	for _, id := range backend.Bugs().AllIds() {
+		b, err := backend.Bugs().Resolve(id)
+		if err != nil {
+			return "", err
+		}
+		for _, comment := range b.Snapshot().Comments {
+			for _, attachment := range comment.Attachments {
+				if attachment.Hash == hash && attachment.Name != "" {
+					return attachment.Name, nil
+				}
+			}
+		}
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,111 @@
+package bugcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+	"github.com/MichaelMure/git-bug/entities/bug"
+)
+
+func TestBugAttachment(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("some notes"), 0644))
+
+	err := runBugCommentNew(env, bugCommentNewOptions{
+		nonInteractive: true,
+		message:        "see the attached notes",
+		attachments:    []string{path},
+	}, []string{bugID.String()})
+	require.NoError(t, err)
+
+	env.Out.Reset()
+	require.NoError(t, runBugAttachment(env, []string{bugID.String()}))
+	require.Regexp(t, "^[0-9a-f]{7} [0-9a-f]{40}\tnotes.txt\ttext/plain; charset=utf-8\t10 B\n$", env.Out.String())
+
+	env.Out.Reset()
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	hash := b.Snapshot().Comments[1].Files[0]
+	require.NoError(t, runBugAttachmentGet(env, bugAttachmentGetOptions{output: "-"}, []string{hash.String()}))
+	require.Equal(t, "some notes", env.Out.String())
+
+	output := filepath.Join(dir, "extracted.txt")
+	require.NoError(t, runBugAttachmentGet(env, bugAttachmentGetOptions{output: output}, []string{hash.String()}))
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	require.Equal(t, "some notes", string(data))
+
+	// unreadable files are rejected before creating anything
+	err = runBugCommentNew(env, bugCommentNewOptions{
+		nonInteractive: true,
+		message:        "missing",
+		attachments:    []string{filepath.Join(dir, "missing")},
+	}, []string{bugID.String()})
+	require.Error(t, err)
+}
+
+func TestBugAttachmentGetMaliciousName(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	author, err := env.Backend.GetUserIdentity()
+	require.NoError(t, err)
+
+	// the metadata of an operation come unchecked from a pull or a bridge
+	hash, err := env.Backend.StoreData([]byte("evil"))
+	require.NoError(t, err)
+	attachments := []bug.Attachment{{Hash: hash, Name: "../../.bashrc"}}
+	_, _, err = b.AddCommentRaw(author, time.Now().Unix(), "malicious", bug.AttachmentHashes(attachments), bug.AttachmentsMetadata(attachments))
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	name, err := attachmentName(env.Backend, hash)
+	require.NoError(t, err)
+	require.Equal(t, hash.String(), name)
+}
+
+func TestBugAttachmentGetNoOverwrite(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	author, err := env.Backend.GetUserIdentity()
+	require.NoError(t, err)
+
+	hash, err := env.Backend.StoreData([]byte("evil"))
+	require.NoError(t, err)
+	attachments := []bug.Attachment{{Hash: hash, Name: "Makefile"}}
+	_, _, err = b.AddCommentRaw(author, time.Now().Unix(), "malicious", bug.AttachmentHashes(attachments), bug.AttachmentsMetadata(attachments))
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() {
+		require.NoError(t, os.Chdir(wd))
+	}()
+
+	require.NoError(t, os.WriteFile("Makefile", []byte("all:"), 0644))
+
+	err = runBugAttachmentGet(env, bugAttachmentGetOptions{}, []string{hash.String()})
+	require.ErrorContains(t, err, "Makefile already exists")
+	data, err := os.ReadFile("Makefile")
+	require.NoError(t, err)
+	require.Equal(t, "all:", string(data))
+
+	require.NoError(t, runBugAttachmentGet(env, bugAttachmentGetOptions{force: true}, []string{hash.String()}))
+	data, err = os.ReadFile("Makefile")
+	require.NoError(t, err)
+	require.Equal(t, "evil", string(data))
+}
@@ -0,0 +1,197 @@
+package bugcmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+type bulkOptions struct {
+	close     bool
+	open      bool
+	addLabels []string
+	rmLabels  []string
+	comment   string
+	dryRun    bool
+	assumeYes bool
+}
+
+func NewBulkCommand(env *execenv.Env) *cobra.Command {
+	options := bulkOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bulk QUERY",
+		Short: "Edit all the bugs matching a query",
+		Long: `Apply the same changes to all the bugs matching a query.
+
+The query use the same language as "git bug bug". Only the changes having an effect are applied: a label already set is not added again, and a bug already closed is not closed again.
+
+A summary of the changes is displayed and must be confirmed, unless --yes is given.`,
+		Example: `Close all the bugs labeled "wontfix":
+git bug bulk label:wontfix --close
+
+Triage the open bugs mentioning "crash":
+git bug bulk status:open crash --label-add crash --label-rm needs-triage
+
+Preview the changes without applying them:
+git bug bulk label:old --close --comment "Closing old bugs" --dry-run
+`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: execenv.LoadBackendEnsureUser(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runBulk(env, options, args)
+		}),
+		ValidArgsFunction: completion.Ls(env),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.BoolVar(&options.close, "close", false, "Close the bugs")
+	flags.BoolVar(&options.open, "open", false, "Open the bugs")
+	flags.StringSliceVar(&options.addLabels, "label-add", nil, "Add a label to the bugs")
+	cmd.RegisterFlagCompletionFunc("label-add", completion.Label(env))
+	flags.StringSliceVar(&options.rmLabels, "label-rm", nil, "Remove a label from the bugs")
+	cmd.RegisterFlagCompletionFunc("label-rm", completion.Label(env))
+	flags.StringVarP(&options.comment, "comment", "m", "", "Add a comment to the bugs")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show the changes without applying them")
+	flags.BoolVarP(&options.assumeYes, "yes", "y", false, "Apply the changes without asking for a confirmation")
+
+	cmd.MarkFlagsMutuallyExclusive("close", "open")
+
+	return cmd
+}
+
+func runBulk(env *execenv.Env, opts bulkOptions, args []string) error {
+	edit := cache.BulkEdit{
+		AddLabels:    text.CleanupOneLineArray(opts.addLabels),
+		RemoveLabels: text.CleanupOneLineArray(opts.rmLabels),
+		Comment:      opts.comment,
+	}
+	switch {
+	case opts.close:
+		edit.Status = common.ClosedStatus
+	case opts.open:
+		edit.Status = common.OpenStatus
+	}
+
+	if err := edit.Validate(); err != nil {
+		return err
+	}
+
+	// either the shell or cobra remove the quotes, we need them back for the query parsing
+	q, err := query.Parse(repairQuery(args))
+	if err != nil {
+		return err
+	}
+
+	ids, err := env.Backend.Bugs().Query(q)
+	if err != nil {
+		return err
+	}
+
+	author, err := env.Backend.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	// compute the changes first, to show and confirm them
+	results, err := env.Backend.Bugs().BulkEdit(author, time.Now().Unix(), ids, edit, true)
+	if err != nil {
+		return err
+	}
+
+	changed := bulkPrintResults(env, results)
+	if changed == 0 {
+		env.Out.Printf("nothing to change in the %d matching bug(s)\n", len(ids))
+		return nil
+	}
+	if opts.dryRun {
+		env.Out.Printf("%d bug(s) would be changed out of %d matching\n", changed, len(ids))
+		return nil
+	}
+
+	if !opts.assumeYes {
+		if !env.In.IsTerminal() {
+			return errors.New("not running in a terminal, use --yes to apply the changes")
+		}
+		env.Out.Printf("Apply the changes to %d bug(s)? [y/N] ", changed)
+		answer, err := bufio.NewReader(env.In).ReadString('\n')
+		if err != nil && answer == "" {
+			return err
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			return errors.New("aborted")
+		}
+	}
+
+	results, err = env.Backend.Bugs().BulkEdit(author, time.Now().Unix(), ids, edit, false)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			env.Err.Printf("%s: %v\n", result.Id.Human(), result.Err)
+		}
+	}
+
+	env.Out.Printf("%d bug(s) changed\n", changed-failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d bug(s) failed to be changed", failed)
+	}
+	return nil
+}
+
+// bulkPrintResults display the changes of each bug, and return the number of
+// bugs changed
+func bulkPrintResults(env *execenv.Env, results []cache.BulkEditResult) int {
+	var changed int
+	for _, result := range results {
+		if result.Err != nil {
+			env.Err.Printf("%s: %v\n", result.Id.Human(), result.Err)
+			continue
+		}
+		if !result.Changed() {
+			continue
+		}
+		changed++
+
+		var changes []string
+		if result.Status != 0 {
+			changes = append(changes, result.Status.Action())
+		}
+		for _, label := range result.AddedLabels {
+			changes = append(changes, "+"+label.String())
+		}
+		for _, label := range result.RemovedLabels {
+			changes = append(changes, "-"+label.String())
+		}
+		if result.Commented {
+			changes = append(changes, "comment")
+		}
+
+		env.Out.Printf("%s\t%s\t%s\n",
+			colors.Cyan(result.Id.Human()),
+			result.Title,
+			strings.Join(changes, ", "),
+		)
+	}
+	return changed
+}
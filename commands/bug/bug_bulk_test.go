@@ -0,0 +1,59 @@
+package bugcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+)
+
+func TestBulk(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	opts := bulkOptions{
+		close:     true,
+		addLabels: []string{"wontfix"},
+	}
+
+	// dry-run
+	dryOpts := opts
+	dryOpts.dryRun = true
+	require.NoError(t, runBulk(env, dryOpts, []string{"status:open"}))
+	require.Equal(t, bugID.Human()+"\tthis is a bug title\tclosed, +wontfix\n"+
+		"1 bug(s) would be changed out of 1 matching\n", env.Out.String())
+	env.Out.Reset()
+
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	require.Equal(t, common.OpenStatus, b.Snapshot().Status)
+
+	// no terminal to confirm
+	require.Error(t, runBulk(env, opts, []string{"status:open"}))
+	env.Out.Reset()
+
+	// refused confirmation
+	env.In.(*execenv.TestIn).ForceIsTerminal(true)
+	env.In.(*execenv.TestIn).WriteString("n\n")
+	require.Error(t, runBulk(env, opts, []string{"status:open"}))
+	require.Equal(t, common.OpenStatus, b.Snapshot().Status)
+	env.Out.Reset()
+
+	// confirmed
+	env.In.(*execenv.TestIn).WriteString("y\n")
+	require.NoError(t, runBulk(env, opts, []string{"status:open"}))
+	require.Contains(t, env.Out.String(), "1 bug(s) changed\n")
+	env.Out.Reset()
+
+	snap := b.Snapshot()
+	require.Equal(t, common.ClosedStatus, snap.Status)
+	require.Equal(t, []bug.Label{"wontfix"}, snap.Labels)
+
+	// nothing left to do
+	opts.assumeYes = true
+	require.NoError(t, runBulk(env, opts, []string{"label:wontfix"}))
+	require.Equal(t, "nothing to change in the 1 matching bug(s)\n", env.Out.String())
+}
@@ -11,6 +11,7 @@ import (
 type bugCommentNewOptions struct {
 	messageFile    string
 	message        string
+	attachments    []string
 	nonInteractive bool
 }
 
@@ -35,6 +36,8 @@ func newBugCommentNewCommand(env *execenv.Env) *cobra.Command {
 
 	flags.StringVarP(&options.message, "message", "m", "",
 		"Provide the new message from the command line")
+	flags.StringArrayVar(&options.attachments, "attach", nil,
+		"Attach the given file to the comment. Can be repeated")
 	flags.BoolVar(&options.nonInteractive, "non-interactive", false, "Do not ask for user input")
 
 	return cmd
@@ -46,6 +49,12 @@ func runBugCommentNew(env *execenv.Env, opts bugCommentNewOptions, args []string
 		return err
 	}
 
+	// fail early if the files can't be read
+	attachments, err := storeAttachments(env.Backend, opts.attachments)
+	if err != nil {
+		return err
+	}
+
 	if opts.messageFile != "" && opts.message == "" {
 		opts.message, err = buginput.BugCommentFileInput(opts.messageFile)
 		if err != nil {
@@ -68,7 +77,7 @@ func runBugCommentNew(env *execenv.Env, opts bugCommentNewOptions, args []string
 		}
 	}
 
-	_, _, err = b.AddComment(text.Cleanup(opts.message))
+	_, _, err = b.AddCommentWithAttachments(text.Cleanup(opts.message), attachments)
 	if err != nil {
 		return err
 	}
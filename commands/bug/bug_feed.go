@@ -0,0 +1,86 @@
+package bugcmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/api/feed"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/query"
+)
+
+type feedOptions struct {
+	bug     string
+	baseURL string
+	limit   int
+}
+
+func NewFeedCommand(env *execenv.Env) *cobra.Command {
+	options := feedOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "feed [QUERY]",
+		Short: "Output an Atom feed of the bug events",
+		Long: `Output an Atom feed of the comments and changes of the bugs matching a query, most recent first.
+
+The query use the same language as "git bug bug". With --bug, the feed only has the events of a single bug.
+
+The same feeds are served by "git bug webui" at /feed?q=QUERY and /feed?bug=BUG_ID.`,
+		Example: `Follow the open bugs labeled "security":
+git bug feed status:open label:security > security.xml
+
+Follow a single bug:
+git bug feed --bug 2f15 --base-url https://bugs.example.com
+`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runFeed(env, options, args)
+		}),
+		ValidArgsFunction: completion.Ls(env),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&options.bug, "bug", "b", "", "Output the feed of a single bug")
+	cmd.RegisterFlagCompletionFunc("bug", BugCompletion(env))
+	flags.StringVar(&options.baseURL, "base-url", "", "URL of the web UI, for the links of the entries")
+	flags.IntVarP(&options.limit, "limit", "n", feed.DefaultLimit, "Maximum number of entries")
+
+	return cmd
+}
+
+func runFeed(env *execenv.Env, opts feedOptions, args []string) error {
+	feedOpts := feed.Options{
+		BaseURL: opts.baseURL,
+		Limit:   opts.limit,
+	}
+
+	var f *feed.Feed
+
+	if opts.bug != "" {
+		if len(args) > 0 {
+			return errors.New("a query can't be used with --bug")
+		}
+		b, err := env.Backend.Bugs().ResolvePrefix(opts.bug)
+		if err != nil {
+			return err
+		}
+		f = feed.BugFeed(b, feedOpts)
+	} else {
+		// either the shell or cobra remove the quotes, we need them back for the query parsing
+		raw := repairQuery(args)
+		q, err := query.Parse(raw)
+		if err != nil {
+			return err
+		}
+		f, err = feed.QueryFeed(env.Backend, q, raw, env.Backend.GetUserIdentity, feedOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.Write(env.Out)
+}
@@ -0,0 +1,32 @@
+package bugcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+)
+
+func TestFeed(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	require.NoError(t, runFeed(env, feedOptions{limit: 10}, []string{"status:open"}))
+	require.Contains(t, env.Out.String(), "<title>Bugs matching status:open</title>")
+	require.Contains(t, env.Out.String(), "<title>this is a bug title: John Doe opened the bug</title>")
+	require.NotContains(t, env.Out.String(), "<link")
+	env.Out.Reset()
+
+	require.NoError(t, runFeed(env, feedOptions{limit: 10}, []string{"status:closed"}))
+	require.NotContains(t, env.Out.String(), "<entry>")
+	env.Out.Reset()
+
+	opts := feedOptions{bug: bugID.Human(), baseURL: "https://bugs.example.com", limit: 10}
+	require.NoError(t, runFeed(env, opts, nil))
+	require.Contains(t, env.Out.String(), "<title>this is a bug title</title>")
+	require.Contains(t, env.Out.String(), `href="https://bugs.example.com/bug/`+bugID.Human()+`"`)
+	env.Out.Reset()
+
+	require.Error(t, runFeed(env, opts, []string{"status:open"}))
+	require.Error(t, runFeed(env, feedOptions{bug: "unknown"}, nil))
+}
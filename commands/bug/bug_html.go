@@ -0,0 +1,191 @@
+package bugcmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/cmdjson"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// htmlMaxImageSize is the maximum size of an image to embed in an HTML page,
+// to keep the page reasonable
+const htmlMaxImageSize = 5 * 1024 * 1024
+
+var htmlFuncs = template.FuncMap{
+	"labelColor": func(l bug.Label) template.CSS {
+		c := l.Color().RGBA()
+		return template.CSS(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+	},
+	// labelTextColor return a text color readable on the label color
+	"labelTextColor": func(l bug.Label) template.CSS {
+		c := l.Color().RGBA()
+		luminance := 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+		if luminance > 150 {
+			return "#000"
+		}
+		return "#fff"
+	},
+	"date": func(t cmdjson.Time) string {
+		return t.Time.Format("2006-01-02 15:04")
+	},
+}
+
+const htmlStyle = `
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #24292f; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 6px 10px; border-bottom: 1px solid #d0d7de; }
+.id { font-family: monospace; color: #57606a; }
+.status { display: inline-block; padding: 2px 8px; border-radius: 1em; color: #fff; font-size: 0.9em; }
+.status-open { background: #2da44e; }
+.status-closed { background: #8250df; }
+.label { display: inline-block; padding: 0 7px; margin-right: 4px; border-radius: 1em; font-size: 0.85em; }
+.meta { color: #57606a; }
+.comment { border: 1px solid #d0d7de; border-radius: 6px; margin: 1em 0; }
+.comment-header { background: #f6f8fa; padding: 8px 12px; border-bottom: 1px solid #d0d7de; }
+.comment-body { padding: 12px; white-space: pre-wrap; }
+.comment-body img { max-width: 100%; display: block; margin-top: 8px; }
+.event { color: #57606a; margin: 0.5em 12px; }
+`
+
+var htmlListTemplate = template.Must(template.New("list").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Bugs</title>
+<style>` + htmlStyle + `</style>
+</head>
+<body>
+<h1>Bugs</h1>
+<p class="meta">{{len .Bugs}} bug(s), generated on {{.Generated.Format "2006-01-02 15:04"}}</p>
+<table>
+<tr><th>ID</th><th>Status</th><th>Title</th><th>Author</th><th>Comments</th><th>Last edit</th></tr>
+{{- range .Bugs}}
+<tr>
+<td class="id">{{.HumanId}}</td>
+<td><span class="status status-{{.Status}}">{{.Status}}</span></td>
+<td>{{.Title}} {{range .Labels}}<span class="label" style="background: {{labelColor .}}; color: {{labelTextColor .}}">{{.}}</span>{{end}}</td>
+<td>{{.Author.Name}}</td>
+<td>{{.Comments}}</td>
+<td>{{date .EditTime}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+var htmlShowTemplate = template.Must(template.New("show").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Bug.Title}}</title>
+<style>` + htmlStyle + `</style>
+</head>
+<body>
+<h1>{{.Bug.Title}} <span class="id">{{.Bug.HumanId}}</span></h1>
+<p>
+<span class="status status-{{.Bug.Status}}">{{.Bug.Status}}</span>
+<span class="meta">{{.Bug.Author.Name}} opened this bug on {{date .Bug.CreateTime}}, last edited on {{date .Bug.EditTime}}</span>
+</p>
+<p>{{range .Bug.Labels}}<span class="label" style="background: {{labelColor .}}; color: {{labelTextColor .}}">{{.}}</span>{{end}}</p>
+<p class="meta">Participants: {{range $i, $p := .Bug.Participants}}{{if $i}}, {{end}}{{$p.Name}}{{end}}</p>
+{{- $images := .Images}}
+{{- range .Timeline}}
+{{- if or (eq .Type "create") (eq .Type "comment")}}
+<div class="comment" id="{{.HumanId}}">
+<div class="comment-header"><strong>{{.Author.Name}}</strong> <span class="meta">{{if eq .Type "create"}}opened{{else}}commented{{end}} on {{date .Time}}{{if .Edited}} (edited){{end}}</span></div>
+<div class="comment-body">{{if .Message}}{{.Message}}{{else}}<em>No description provided.</em>{{end}}
+{{- range .Attachments}}
+{{- with index $images .Hash}}<img src="{{.}}" alt="">{{else}}<div class="meta">Attachment: {{.Name}}</div>{{end}}
+{{- end}}</div>
+</div>
+{{- else if eq .Type "label"}}
+<div class="event"><strong>{{.Author.Name}}</strong>{{if .Added}} added {{range .Added}}<span class="label" style="background: {{labelColor .}}; color: {{labelTextColor .}}">{{.}}</span>{{end}}{{end}}{{if .Removed}}{{if .Added}} and{{end}} removed {{range .Removed}}<span class="label" style="background: {{labelColor .}}; color: {{labelTextColor .}}">{{.}}</span>{{end}}{{end}} on {{date .Time}}</div>
+{{- else if eq .Type "status"}}
+<div class="event"><strong>{{.Author.Name}}</strong> {{if eq .Status "open"}}reopened{{else}}closed{{end}} this bug on {{date .Time}}</div>
+{{- else if eq .Type "title"}}
+<div class="event"><strong>{{.Author.Name}}</strong> changed the title from <em>{{.Was}}</em> to <em>{{.Title}}</em> on {{date .Time}}</div>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+func bugsHtmlFormatter(env *execenv.Env, excerpts []*cache.BugExcerpt) error {
+	jsonBugs, err := newJsonBugExcerpts(env, excerpts)
+	if err != nil {
+		return err
+	}
+
+	return htmlListTemplate.Execute(env.Out, struct {
+		Generated time.Time
+		Bugs      []cmdjson.BugExcerpt
+	}{
+		Generated: time.Now(),
+		Bugs:      jsonBugs,
+	})
+}
+
+func showHtmlFormatter(env *execenv.Env, snap *bug.Snapshot) error {
+	timeline := cmdjson.NewBugTimeline(snap)
+
+	images, err := htmlImages(env, timeline)
+	if err != nil {
+		return err
+	}
+
+	return htmlShowTemplate.Execute(env.Out, struct {
+		Bug      cmdjson.BugSnapshot
+		Timeline []cmdjson.BugTimelineItem
+		Images   map[string]template.URL
+	}{
+		Bug:      cmdjson.NewBugSnapshot(snap),
+		Timeline: timeline,
+		Images:   images,
+	})
+}
+
+// htmlImages read the images attached to the comments, and encode them as
+// data URLs to embed them in a standalone page
+func htmlImages(env *execenv.Env, timeline []cmdjson.BugTimelineItem) (map[string]template.URL, error) {
+	images := make(map[string]template.URL)
+
+	for _, item := range timeline {
+		for _, attachment := range item.Attachments {
+			if attachment.Mime != "" && !strings.HasPrefix(attachment.Mime, "image/") {
+				continue
+			}
+			if attachment.Size > htmlMaxImageSize {
+				continue
+			}
+
+			data, err := env.Backend.ReadData(repository.Hash(attachment.Hash))
+			if err != nil {
+				return nil, err
+			}
+			if len(data) > htmlMaxImageSize {
+				continue
+			}
+
+			mime := attachment.Mime
+			if mime == "" {
+				mime = http.DetectContentType(data)
+			}
+			if !strings.HasPrefix(mime, "image/") {
+				continue
+			}
+
+			images[attachment.Hash] = template.URL("data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data))
+		}
+	}
+
+	return images, nil
+}
@@ -12,6 +12,7 @@ type bugNewOptions struct {
 	title          string
 	message        string
 	messageFile    string
+	attachments    []string
 	nonInteractive bool
 }
 
@@ -36,13 +37,20 @@ func newBugNewCommand(env *execenv.Env) *cobra.Command {
 		"Provide a message to describe the issue")
 	flags.StringVarP(&options.messageFile, "file", "F", "",
 		"Take the message from the given file. Use - to read the message from the standard input")
+	flags.StringArrayVar(&options.attachments, "attach", nil,
+		"Attach the given file to the bug description. Can be repeated")
 	flags.BoolVar(&options.nonInteractive, "non-interactive", false, "Do not ask for user input")
 
 	return cmd
 }
 
 func runBugNew(env *execenv.Env, opts bugNewOptions) error {
-	var err error
+	// fail early if the files can't be read
+	attachments, err := storeAttachments(env.Backend, opts.attachments)
+	if err != nil {
+		return err
+	}
+
 	if opts.messageFile != "" && opts.message == "" {
 		opts.title, opts.message, err = buginput.BugCreateFileInput(opts.messageFile)
 		if err != nil {
@@ -62,9 +70,10 @@ func runBugNew(env *execenv.Env, opts bugNewOptions) error {
 		}
 	}
 
-	b, _, err := env.Backend.Bugs().New(
+	b, _, err := env.Backend.Bugs().NewWithAttachments(
 		text.CleanupOneLine(opts.title),
 		text.Cleanup(opts.message),
+		attachments,
 	)
 	if err != nil {
 		return err
@@ -1,9 +1,11 @@
 package bugcmd
 
 import (
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/MichaelMure/git-bug/commands/completion"
 	"github.com/MichaelMure/git-bug/commands/execenv"
 	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
 	"github.com/MichaelMure/git-bug/util/colors"
 )
 
@@ -41,7 +44,8 @@ func newBugShowCommand(env *execenv.Env) *cobra.Command {
 		"Select field to display. Valid values are ["+strings.Join(fields, ",")+"]")
 	cmd.RegisterFlagCompletionFunc("by", completion.From(fields))
 	flags.StringVarP(&options.format, "format", "f", "default",
-		"Select the output formatting style. Valid values are [default,json,org-mode]")
+		"Select the output formatting style. Valid values are [default,json,org-mode,markdown,csv,html], "+
+			fmt.Sprintf(formatHelp, "show"))
 
 	return cmd
 }
@@ -58,6 +62,10 @@ func runBugShow(env *execenv.Env, opts bugShowOptions, args []string) error {
 		return errors.New("invalid bug: no comment")
 	}
 
+	if err := markSeen(env, b.Id()); err != nil {
+		return err
+	}
+
 	if opts.fields != "" {
 		switch opts.fields {
 		case "author":
@@ -104,8 +112,21 @@ func runBugShow(env *execenv.Env, opts bugShowOptions, args []string) error {
 		return showJsonFormatter(env, snap)
 	case "default":
 		return showDefaultFormatter(env, snap)
+	case "markdown":
+		return showMarkdownFormatter(env, snap)
+	case "csv":
+		return showCsvFormatter(env, snap)
+	case "html":
+		return showHtmlFormatter(env, snap)
 	default:
-		return fmt.Errorf("unknown format %s", opts.format)
+		tmpl, err := loadTemplate(env, "show", opts.format)
+		if err != nil {
+			return err
+		}
+		if tmpl == nil {
+			return fmt.Errorf("unknown format %s", opts.format)
+		}
+		return executeTemplate(env, tmpl, cmdjson.NewBugSnapshot(snap))
 	}
 }
 
@@ -184,6 +205,112 @@ func showDefaultFormatter(env *execenv.Env, snapshot *bug.Snapshot) error {
 	return nil
 }
 
+func showMarkdownFormatter(env *execenv.Env, snap *bug.Snapshot) error {
+	jsonBug := cmdjson.NewBugSnapshot(snap)
+
+	formatTime := func(t cmdjson.Time) string {
+		return t.Time.Format("2006-01-02 15:04")
+	}
+	formatLabels := func(labels []bug.Label) string {
+		result := make([]string, len(labels))
+		for i, l := range labels {
+			result[i] = "`" + l.String() + "`"
+		}
+		return strings.Join(result, ", ")
+	}
+
+	env.Out.Printf("# %s (%s)\n\n", jsonBug.Title, jsonBug.HumanId)
+
+	env.Out.Printf("- **Status:** %s\n", jsonBug.Status)
+	env.Out.Printf("- **Author:** %s\n", jsonBug.Author.Name)
+	env.Out.Printf("- **Created:** %s\n", formatTime(jsonBug.CreateTime))
+	env.Out.Printf("- **Last edit:** %s\n", formatTime(jsonBug.EditTime))
+	if len(jsonBug.Labels) > 0 {
+		env.Out.Printf("- **Labels:** %s\n", formatLabels(jsonBug.Labels))
+	}
+
+	participants := make([]string, len(jsonBug.Participants))
+	for i, p := range jsonBug.Participants {
+		participants[i] = p.Name
+	}
+	env.Out.Printf("- **Participants:** %s\n", strings.Join(participants, ", "))
+
+	env.Out.Printf("\n## Timeline\n")
+
+	for _, item := range cmdjson.NewBugTimeline(snap) {
+		switch item.Type {
+		case "create", "comment":
+			action := "commented"
+			if item.Type == "create" {
+				action = "opened this bug"
+			}
+			env.Out.Printf("\n### %s %s on %s\n\n", item.Author.Name, action, formatTime(item.Time))
+
+			if item.Message == "" {
+				env.Out.Printf("_No description provided._\n")
+			} else {
+				env.Out.Printf("%s\n", item.Message)
+			}
+			for _, attachment := range item.Attachments {
+				env.Out.Printf("\n- Attachment: `%s` (%s)\n", attachment.Name, attachment.Hash)
+			}
+
+		case "label":
+			var changes []string
+			if len(item.Added) > 0 {
+				changes = append(changes, "added "+formatLabels(item.Added))
+			}
+			if len(item.Removed) > 0 {
+				changes = append(changes, "removed "+formatLabels(item.Removed))
+			}
+			env.Out.Printf("\n_%s %s on %s_\n", item.Author.Name, strings.Join(changes, " and "), formatTime(item.Time))
+
+		case "status":
+			action := "closed"
+			if item.Status == common.OpenStatus.String() {
+				action = "reopened"
+			}
+			env.Out.Printf("\n_%s %s this bug on %s_\n", item.Author.Name, action, formatTime(item.Time))
+
+		case "title":
+			env.Out.Printf("\n_%s changed the title from \"%s\" to \"%s\" on %s_\n", item.Author.Name, item.Was, item.Title, formatTime(item.Time))
+		}
+	}
+
+	return nil
+}
+
+func showCsvFormatter(env *execenv.Env, snap *bug.Snapshot) error {
+	w := csv.NewWriter(env.Out)
+	_ = w.Write([]string{"type", "id", "human_id", "author", "time", "message", "added", "removed", "status", "title"})
+
+	joinLabels := func(labels []bug.Label) string {
+		result := make([]string, len(labels))
+		for i, l := range labels {
+			result[i] = l.String()
+		}
+		return strings.Join(result, ",")
+	}
+
+	for _, item := range cmdjson.NewBugTimeline(snap) {
+		_ = w.Write([]string{
+			item.Type,
+			item.Id,
+			item.HumanId,
+			item.Author.Name,
+			item.Time.Time.Format(time.RFC3339),
+			item.Message,
+			joinLabels(item.Added),
+			joinLabels(item.Removed),
+			item.Status,
+			item.Title,
+		})
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
 func showJsonFormatter(env *execenv.Env, snap *bug.Snapshot) error {
 	jsonBug := cmdjson.NewBugSnapshot(snap)
 	return env.Out.PrintJSON(jsonBug)
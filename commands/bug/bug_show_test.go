@@ -0,0 +1,103 @@
+package bugcmd
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+	. "github.com/MichaelMure/git-bug/commands/cmdtest"
+)
+
+// a 1x1 transparent PNG
+const testPNG = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+
+func TestBugShow_Format(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	png, err := base64.StdEncoding.DecodeString(testPNG)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "screenshot.png")
+	require.NoError(t, os.WriteFile(path, png, 0644))
+
+	require.NoError(t, runBugCommentNew(env, bugCommentNewOptions{
+		nonInteractive: true,
+		message:        "see <this> screenshot",
+		attachments:    []string{path},
+	}, []string{bugID.String()}))
+
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	_, _, err = b.ChangeLabels([]string{"ui"}, nil)
+	require.NoError(t, err)
+	_, err = b.Close()
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	t.Run("markdown", func(t *testing.T) {
+		env.Out.Reset()
+		require.NoError(t, runBugShow(env, bugShowOptions{format: "markdown"}, []string{bugID.Human()}))
+
+		out := regexp.MustCompile(`\d{4}-\d\d-\d\d \d\d:\d\d`).ReplaceAllString(env.Out.String(), "DATE")
+		out = regexp.MustCompile(`[0-9a-f]{40,64}`).ReplaceAllString(out, "HASH")
+		out = strings.ReplaceAll(out, bugID.Human(), "ID")
+
+		require.Equal(t, "# this is a bug title (ID)\n\n"+
+			"- **Status:** closed\n"+
+			"- **Author:** John Doe\n"+
+			"- **Created:** DATE\n"+
+			"- **Last edit:** DATE\n"+
+			"- **Labels:** `ui`\n"+
+			"- **Participants:** John Doe\n"+
+			"\n## Timeline\n"+
+			"\n### John Doe opened this bug on DATE\n\n"+
+			"this is a bug message\n"+
+			"\n### John Doe commented on DATE\n\n"+
+			"see <this> screenshot\n"+
+			"\n- Attachment: `screenshot.png` (HASH)\n"+
+			"\n_John Doe added `ui` on DATE_\n"+
+			"\n_John Doe closed this bug on DATE_\n", out)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		env.Out.Reset()
+		require.NoError(t, runBugShow(env, bugShowOptions{format: "csv"}, []string{bugID.Human()}))
+
+		require.Regexp(t, MakeExpectedRegex("type,id,human_id,author,time,message,added,removed,status,title\n"+
+			"create,"+ExpId+","+ExpHumanId+",John Doe,"+ExpISO8601+",this is a bug message,,,,\n"+
+			"comment,"+ExpId+","+ExpHumanId+",John Doe,"+ExpISO8601+",see <this> screenshot,,,,\n"+
+			"label,"+ExpId+","+ExpHumanId+",John Doe,"+ExpISO8601+",,ui,,,\n"+
+			"status,"+ExpId+","+ExpHumanId+",John Doe,"+ExpISO8601+",,,,closed,\n"), env.Out.String())
+	})
+
+	t.Run("html", func(t *testing.T) {
+		env.Out.Reset()
+		require.NoError(t, runBugShow(env, bugShowOptions{format: "html"}, []string{bugID.Human()}))
+
+		out := env.Out.String()
+		require.Contains(t, out, "<title>this is a bug title</title>")
+		require.Contains(t, out, "see &lt;this&gt; screenshot")
+		require.Contains(t, out, `<img src="data:image/png;base64,`+testPNG+`"`)
+		require.Contains(t, out, `<span class="label" style="background: #4caf50; color: #fff">ui</span>`)
+		require.Contains(t, out, "closed this bug on")
+	})
+}
+
+func TestBug_FormatHtml(t *testing.T) {
+	env, _ := testenv.NewTestEnvAndBug(t)
+
+	opts := bugOptions{
+		sortDirection: "asc",
+		sortBy:        "creation",
+		outputFormat:  "html",
+	}
+
+	require.NoError(t, runBug(env, opts, []string{}))
+	require.Contains(t, env.Out.String(), "<p class=\"meta\">1 bug(s), generated on")
+	require.Contains(t, env.Out.String(), "<td>this is a bug title </td>")
+}
@@ -0,0 +1,165 @@
+package bugcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	text "github.com/MichaelMure/go-term-text"
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+
+	"github.com/MichaelMure/git-bug/commands/cmdjson"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+// formatConfigKeyPrefix is the prefix of the git config keys defining named
+// output formats, as git-bug.format.<command>.<name> = <template>
+const formatConfigKeyPrefix = "git-bug.format"
+
+const (
+	formatTemplatePrefix     = "template="
+	formatTemplateFilePrefix = "template-file="
+)
+
+// formatHelp describe the template formats, for the --format flags
+const formatHelp = "template=TEMPLATE, template-file=FILE, or a name defined in the git config as " +
+	formatConfigKeyPrefix + ".%s.NAME"
+
+// loadTemplate return the output template selected with --format, or nil if
+// the format is not a template. command select the named formats defined in
+// the git config.
+func loadTemplate(env *execenv.Env, command string, format string) (*template.Template, error) {
+	var raw string
+
+	switch {
+	case strings.HasPrefix(format, formatTemplatePrefix):
+		raw = strings.TrimPrefix(format, formatTemplatePrefix)
+
+	case strings.HasPrefix(format, formatTemplateFilePrefix):
+		data, err := os.ReadFile(strings.TrimPrefix(format, formatTemplateFilePrefix))
+		if err != nil {
+			return nil, err
+		}
+		raw = string(data)
+
+	default:
+		value, err := env.Backend.AnyConfig().ReadString(formatConfigKeyPrefix + "." + command + "." + format)
+		if errors.Is(err, repository.ErrNoConfigEntry) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		raw = value
+	}
+
+	tmpl, err := template.New(format).Funcs(templateFuncs).Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// executeTemplate render the template and print it, terminated by a new line
+func executeTemplate(env *execenv.Env, tmpl *template.Template, data interface{}) error {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	out := buf.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	env.Out.Print(out)
+	return nil
+}
+
+var templateFuncs = template.FuncMap{
+	// human shorten an id
+	"human": func(id string) string {
+		return entity.Id(id).Human()
+	},
+
+	"bold":    colors.Bold,
+	"cyan":    colors.Cyan,
+	"yellow":  colors.Yellow,
+	"magenta": colors.Magenta,
+	"green":   colors.Green,
+	"red":     colors.Red,
+	"blue":    colors.Blue,
+
+	// label render a label with its color
+	"label": func(l bug.Label) string {
+		if color.NoColor {
+			return l.String()
+		}
+		c := l.Color().Term256()
+		return c.Escape() + l.String() + c.Unescape()
+	},
+	// labelColor return the color of a label, as #rrggbb
+	"labelColor": func(l bug.Label) string {
+		c := l.Color().RGBA()
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	},
+
+	// ago render a time relative to now, like "3 days ago"
+	"ago": func(t interface{}) (string, error) {
+		tt, err := templateTime(t)
+		if err != nil {
+			return "", err
+		}
+		return humanize.Time(tt), nil
+	},
+	// date format a time with a Go layout, like "2006-01-02"
+	"date": func(layout string, t interface{}) (string, error) {
+		tt, err := templateTime(t)
+		if err != nil {
+			return "", err
+		}
+		return tt.Format(layout), nil
+	},
+
+	"join": func(sep string, list interface{}) (string, error) {
+		v := reflect.ValueOf(list)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return "", fmt.Errorf("join: expected a list, got %T", list)
+		}
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(elems, sep), nil
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	// truncate shorten a text to a maximum width
+	"truncate": func(width int, s string) string {
+		return text.TruncateMax(s, width)
+	},
+	"json": func(v interface{}) (string, error) {
+		raw, err := json.Marshal(v)
+		return string(raw), err
+	},
+}
+
+func templateTime(t interface{}) (time.Time, error) {
+	switch t := t.(type) {
+	case cmdjson.Time:
+		return t.Time, nil
+	case time.Time:
+		return t, nil
+	default:
+		return time.Time{}, fmt.Errorf("expected a time, got %T", t)
+	}
+}
@@ -0,0 +1,69 @@
+package bugcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+	. "github.com/MichaelMure/git-bug/commands/cmdtest"
+)
+
+func TestBug_Template(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	b, err := env.Backend.Bugs().Resolve(bugID)
+	require.NoError(t, err)
+	_, _, err = b.ChangeLabels([]string{"bug", "ui"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	file := filepath.Join(t.TempDir(), "report.tmpl")
+	require.NoError(t, os.WriteFile(file, []byte("- {{.Title | upper}} ({{.Author.Name}})\n"), 0644))
+
+	require.NoError(t, env.Backend.LocalConfig().StoreString("git-bug.format.bug.short", "{{.HumanId}} {{.Status}}"))
+	require.NoError(t, env.Backend.LocalConfig().StoreString("git-bug.format.show.comments", "{{range .Comments}}{{.Author.Name}}: {{.Message}}{{end}}"))
+
+	listCases := []struct {
+		format string
+		exp    string
+	}{
+		{"template={{human .Id}}\t{{join \",\" .Labels}}\t{{date \"2006\" .CreateTime}}", ExpHumanId + "\tbug,ui\t" + ExpISO8601 + "\n"},
+		{"template={{range .Labels}}{{label .}}={{labelColor .}} {{end}}", "bug=#9e9e9e ui=#4caf50 \n"},
+		{"template={{ago .EditTime}}", "now\n"},
+		{"template-file=" + file, "- THIS IS A BUG TITLE (John Doe)\n"},
+		{"short", ExpHumanId + " open\n"},
+	}
+
+	for _, testcase := range listCases {
+		t.Run(testcase.format, func(t *testing.T) {
+			opts := bugOptions{
+				sortDirection:       "asc",
+				sortBy:              "creation",
+				outputFormat:        testcase.format,
+				outputFormatChanged: true,
+			}
+
+			env.Out.Reset()
+			require.NoError(t, runBug(env, opts, []string{}))
+			require.Regexp(t, MakeExpectedRegex(testcase.exp), env.Out.String())
+		})
+	}
+
+	env.Out.Reset()
+	require.NoError(t, runBugShow(env, bugShowOptions{format: "template={{.Title}} [{{.Status}}]"}, []string{bugID.Human()}))
+	require.Equal(t, "this is a bug title [open]\n", env.Out.String())
+	env.Out.Reset()
+
+	require.NoError(t, runBugShow(env, bugShowOptions{format: "comments"}, []string{bugID.Human()}))
+	require.Equal(t, "John Doe: this is a bug message\n", env.Out.String())
+	env.Out.Reset()
+
+	// the named formats are per command
+	require.ErrorContains(t, runBugShow(env, bugShowOptions{format: "short"}, []string{bugID.Human()}), "unknown format short")
+
+	require.ErrorContains(t, runBug(env, bugOptions{sortBy: "creation", sortDirection: "asc", outputFormat: "template={{.Nope"}, nil), "invalid template")
+	require.Error(t, runBug(env, bugOptions{sortBy: "creation", sortDirection: "asc", outputFormat: "template={{ago .Title}}"}, nil))
+}
@@ -109,6 +109,11 @@ func TestBug_Format(t *testing.T) {
 		{"id", ExpId + "\n"},
 		{"org-mode", expOrgMode},
 		{"json", expJson},
+		{"markdown", "| ID | Status | Title | Labels | Author | Comments | Last edit |\n" +
+			"|----|--------|-------|--------|--------|----------|-----------|\n" +
+			"| " + ExpHumanId + " | open | this is a bug title |  | John Doe | 1 | " + ExpISO8601 + " |\n"},
+		{"csv", "id,human_id,status,title,labels,author,create_time,edit_time,comments\n" +
+			ExpId + "," + ExpHumanId + ",open,this is a bug title,,John Doe," + ExpISO8601 + "," + ExpISO8601 + ",1\n"},
 	}
 
 	for _, testcase := range cases {
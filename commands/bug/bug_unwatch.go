@@ -0,0 +1,41 @@
+package bugcmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/execenv"
+)
+
+func NewUnwatchCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "unwatch [BUG_ID]",
+		Short:   "Stop watching a bug",
+		PreRunE: execenv.LoadBackendEnsureUser(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runUnwatch(env, args)
+		}),
+		ValidArgsFunction: BugCompletion(env),
+	}
+
+	return cmd
+}
+
+func runUnwatch(env *execenv.Env, args []string) error {
+	b, _, err := ResolveSelected(env.Backend, args)
+	if err != nil {
+		return err
+	}
+
+	user, err := env.Backend.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	err = env.Backend.Watches().Unwatch(user.Id(), b.Id())
+	if err != nil {
+		return err
+	}
+
+	env.Out.Printf("stopped watching bug %s\n", b.Id().Human())
+	return nil
+}
@@ -0,0 +1,65 @@
+package bugcmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+func NewWatchCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch [BUG_ID]",
+		Short: "Watch a bug",
+		Long: `Add a bug to the list of the bugs you watch.
+
+The watched bugs can be listed with the "watching:me" query, and the ones that changed since you last
+saw them are marked as unread in the terminal UI. Showing a bug mark it as seen.
+
+The list is stored in the repository, but is not pushed or pulled.`,
+		Example: `git bug watch 2f15
+git bug bug watching:me`,
+		PreRunE: execenv.LoadBackendEnsureUser(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runWatch(env, args)
+		}),
+		ValidArgsFunction: BugCompletion(env),
+	}
+
+	return cmd
+}
+
+func runWatch(env *execenv.Env, args []string) error {
+	b, _, err := ResolveSelected(env.Backend, args)
+	if err != nil {
+		return err
+	}
+
+	user, err := env.Backend.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	err = env.Backend.Watches().Watch(user.Id(), b.Id())
+	if err != nil {
+		return err
+	}
+
+	env.Out.Printf("watching bug %s\n", b.Id().Human())
+	return nil
+}
+
+// markSeen record that the user saw the current state of a bug, if watched
+func markSeen(env *execenv.Env, bugId entity.Id) error {
+	set, err := env.Backend.IsUserIdentitySet()
+	if err != nil || !set {
+		return err
+	}
+
+	user, err := env.Backend.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	return env.Backend.Watches().MarkSeen(user.Id(), bugId)
+}
@@ -0,0 +1,39 @@
+package bugcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+)
+
+func TestWatch(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	opts := bugOptions{sortBy: "creation", sortDirection: "asc", outputFormat: "id"}
+
+	require.NoError(t, runBug(env, opts, []string{"watching:me"}))
+	require.Empty(t, env.Out.String())
+
+	require.NoError(t, runWatch(env, []string{bugID.Human()}))
+	require.Equal(t, "watching bug "+bugID.Human()+"\n", env.Out.String())
+	env.Out.Reset()
+
+	require.NoError(t, runBug(env, opts, []string{"watching:me"}))
+	require.Equal(t, bugID.String()+"\n", env.Out.String())
+	env.Out.Reset()
+
+	opts.watchingQuery = []string{"doe"}
+	require.NoError(t, runBug(env, opts, nil))
+	require.Equal(t, bugID.String()+"\n", env.Out.String())
+	env.Out.Reset()
+	opts.watchingQuery = nil
+
+	require.NoError(t, runUnwatch(env, []string{bugID.Human()}))
+	require.Equal(t, "stopped watching bug "+bugID.Human()+"\n", env.Out.String())
+	env.Out.Reset()
+
+	require.NoError(t, runBug(env, opts, []string{"watching:me"}))
+	require.Empty(t, env.Out.String())
+}
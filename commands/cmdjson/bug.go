@@ -121,3 +121,101 @@ func NewBugExcerpt(backend *cache.RepoCache, excerpt *cache.BugExcerpt) (BugExce
 
 	return jsonBug, nil
 }
+
+type BugAttachment struct {
+	Hash string `json:"hash"`
+	Name string `json:"name"`
+	Mime string `json:"mime,omitempty"`
+	Size int64  `json:"size,omitempty"`
+}
+
+// BugTimelineItem is an event of the timeline of a bug. Depending on the
+// Type (create, comment, label, status or title), only some fields are set.
+type BugTimelineItem struct {
+	Type    string   `json:"type"`
+	Id      string   `json:"id"`
+	HumanId string   `json:"human_id"`
+	Author  Identity `json:"author"`
+	Time    Time     `json:"time"`
+
+	// create and comment
+	Message     string          `json:"message,omitempty"`
+	Edited      bool            `json:"edited,omitempty"`
+	Attachments []BugAttachment `json:"attachments,omitempty"`
+
+	// label
+	Added   []bug.Label `json:"added,omitempty"`
+	Removed []bug.Label `json:"removed,omitempty"`
+
+	// status
+	Status string `json:"status,omitempty"`
+
+	// title
+	Title string `json:"title,omitempty"`
+	Was   string `json:"was,omitempty"`
+}
+
+func NewBugTimeline(snap *bug.Snapshot) []BugTimelineItem {
+	result := make([]BugTimelineItem, 0, len(snap.Timeline))
+
+	for _, item := range snap.Timeline {
+		switch item := item.(type) {
+		case *bug.CreateTimelineItem:
+			result = append(result, newBugCommentItem("create", item.CommentTimelineItem))
+		case *bug.AddCommentTimelineItem:
+			result = append(result, newBugCommentItem("comment", item.CommentTimelineItem))
+		case *bug.LabelChangeTimelineItem:
+			result = append(result, BugTimelineItem{
+				Type:    "label",
+				Id:      item.CombinedId().String(),
+				HumanId: item.CombinedId().Human(),
+				Author:  NewIdentity(item.Author),
+				Time:    NewTime(item.UnixTime.Time(), 0),
+				Added:   item.Added,
+				Removed: item.Removed,
+			})
+		case *bug.SetStatusTimelineItem:
+			result = append(result, BugTimelineItem{
+				Type:    "status",
+				Id:      item.CombinedId().String(),
+				HumanId: item.CombinedId().Human(),
+				Author:  NewIdentity(item.Author),
+				Time:    NewTime(item.UnixTime.Time(), 0),
+				Status:  item.Status.String(),
+			})
+		case *bug.SetTitleTimelineItem:
+			result = append(result, BugTimelineItem{
+				Type:    "title",
+				Id:      item.CombinedId().String(),
+				HumanId: item.CombinedId().Human(),
+				Author:  NewIdentity(item.Author),
+				Time:    NewTime(item.UnixTime.Time(), 0),
+				Title:   item.Title,
+				Was:     item.Was,
+			})
+		}
+	}
+
+	return result
+}
+
+func newBugCommentItem(typ string, item bug.CommentTimelineItem) BugTimelineItem {
+	result := BugTimelineItem{
+		Type:    typ,
+		Id:      item.CombinedId().String(),
+		HumanId: item.CombinedId().Human(),
+		Author:  NewIdentity(item.Author),
+		Time:    NewTime(item.CreatedAt.Time(), 0),
+		Message: item.Message,
+		Edited:  item.Edited(),
+	}
+	for _, attachment := range item.Attachments {
+		result.Attachments = append(result.Attachments, BugAttachment{
+			Hash: attachment.Hash.String(),
+			Name: attachment.DisplayName(),
+			Mime: attachment.MimeType,
+			Size: attachment.Size,
+		})
+	}
+	return result
+}
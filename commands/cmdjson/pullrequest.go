@@ -0,0 +1,133 @@
+package cmdjson
+
+import (
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+)
+
+type PullRequestSnapshot struct {
+	Id           string               `json:"id"`
+	HumanId      string               `json:"human_id"`
+	CreateTime   Time                 `json:"create_time"`
+	EditTime     Time                 `json:"edit_time"`
+	Status       string               `json:"status"`
+	Title        string               `json:"title"`
+	SourceBranch string               `json:"source_branch"`
+	TargetBranch string               `json:"target_branch"`
+	MergeCommit  string               `json:"merge_commit,omitempty"`
+	Author       Identity             `json:"author"`
+	Actors       []Identity           `json:"actors"`
+	Participants []Identity           `json:"participants"`
+	Commits      []pullrequest.Commit `json:"commits"`
+	Comments     []PullRequestComment `json:"comments"`
+	Reviews      []PullRequestReview  `json:"reviews"`
+}
+
+func NewPullRequestSnapshot(snap *pullrequest.Snapshot) PullRequestSnapshot {
+	jsonPr := PullRequestSnapshot{
+		Id:           snap.Id().String(),
+		HumanId:      snap.Id().Human(),
+		CreateTime:   NewTime(snap.CreateTime, 0),
+		EditTime:     NewTime(snap.EditTime(), 0),
+		Status:       snap.Status.String(),
+		Title:        snap.Title,
+		SourceBranch: snap.SourceBranch,
+		TargetBranch: snap.TargetBranch,
+		MergeCommit:  snap.MergeCommit,
+		Author:       NewIdentity(snap.Author),
+		Commits:      snap.Commits,
+	}
+
+	jsonPr.Actors = make([]Identity, len(snap.Actors))
+	for i, element := range snap.Actors {
+		jsonPr.Actors[i] = NewIdentity(element)
+	}
+
+	jsonPr.Participants = make([]Identity, len(snap.Participants))
+	for i, element := range snap.Participants {
+		jsonPr.Participants[i] = NewIdentity(element)
+	}
+
+	jsonPr.Comments = make([]PullRequestComment, len(snap.Comments))
+	for i, comment := range snap.Comments {
+		jsonPr.Comments[i] = PullRequestComment{
+			Id:      comment.CombinedId().String(),
+			HumanId: comment.CombinedId().Human(),
+			Author:  NewIdentity(comment.Author),
+			Message: comment.Message,
+		}
+	}
+
+	jsonPr.Reviews = make([]PullRequestReview, len(snap.Reviews))
+	for i, review := range snap.Reviews {
+		jsonPr.Reviews[i] = PullRequestReview{
+			Id:       review.CombinedId().String(),
+			HumanId:  review.CombinedId().Human(),
+			Author:   NewIdentity(review.Author),
+			State:    review.State.String(),
+			Message:  review.Message,
+			Comments: review.Comments,
+		}
+	}
+
+	return jsonPr
+}
+
+type PullRequestComment struct {
+	Id      string   `json:"id"`
+	HumanId string   `json:"human_id"`
+	Author  Identity `json:"author"`
+	Message string   `json:"message"`
+}
+
+type PullRequestReview struct {
+	Id       string                      `json:"id"`
+	HumanId  string                      `json:"human_id"`
+	Author   Identity                    `json:"author"`
+	State    string                      `json:"state"`
+	Message  string                      `json:"message"`
+	Comments []pullrequest.ReviewComment `json:"comments"`
+}
+
+type PullRequestExcerpt struct {
+	Id         string `json:"id"`
+	HumanId    string `json:"human_id"`
+	CreateTime Time   `json:"create_time"`
+	EditTime   Time   `json:"edit_time"`
+
+	Status       string   `json:"status"`
+	Title        string   `json:"title"`
+	SourceBranch string   `json:"source_branch"`
+	TargetBranch string   `json:"target_branch"`
+	Author       Identity `json:"author"`
+
+	Comments int               `json:"comments"`
+	Reviews  int               `json:"reviews"`
+	Commits  int               `json:"commits"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func NewPullRequestExcerpt(backend *cache.RepoCache, excerpt *cache.PullRequestExcerpt) (PullRequestExcerpt, error) {
+	jsonPr := PullRequestExcerpt{
+		Id:           excerpt.Id().String(),
+		HumanId:      excerpt.Id().Human(),
+		CreateTime:   NewTime(excerpt.CreateTime(), excerpt.CreateLamportTime),
+		EditTime:     NewTime(excerpt.EditTime(), excerpt.EditLamportTime),
+		Status:       excerpt.Status.String(),
+		Title:        excerpt.Title,
+		SourceBranch: excerpt.SourceBranch,
+		TargetBranch: excerpt.TargetBranch,
+		Comments:     excerpt.LenComments,
+		Reviews:      excerpt.LenReviews,
+		Commits:      excerpt.LenCommits,
+		Metadata:     excerpt.CreateMetadata,
+	}
+
+	author, err := backend.Identities().ResolveExcerpt(excerpt.AuthorId)
+	if err != nil {
+		return PullRequestExcerpt{}, err
+	}
+	jsonPr.Author = NewIdentityFromExcerpt(author)
+
+	return jsonPr, nil
+}
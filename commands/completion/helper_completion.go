@@ -10,6 +10,7 @@ import (
 	"github.com/MichaelMure/git-bug/bridge"
 	"github.com/MichaelMure/git-bug/bridge/core/auth"
 	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/query"
 )
 
 type ValidArgsFunction func(cmd *cobra.Command, args []string, toComplete string) (completions []string, directives cobra.ShellCompDirective)
@@ -231,7 +232,7 @@ func UserForQuery(env *execenv.Env) ValidArgsFunction {
 		}()
 
 		ids := env.Backend.Identities().AllIds()
-		completions = make([]string, len(ids))
+		completions = make([]string, len(ids), len(ids)+1)
 		for i, id := range ids {
 			user, err := env.Backend.Identities().ResolveExcerpt(id)
 			if err != nil {
@@ -246,6 +247,7 @@ func UserForQuery(env *execenv.Env) ValidArgsFunction {
 			}
 			completions[i] = handle + "\t" + user.DisplayName()
 		}
+		completions = append(completions, query.Me+"\tYourself")
 		return completions, cobra.ShellCompDirectiveNoFileComp
 	}
 }
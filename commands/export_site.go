@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/site"
+)
+
+type exportSiteOptions struct {
+	title   string
+	baseURL string
+	force   bool
+}
+
+func newExportSiteCommand(env *execenv.Env) *cobra.Command {
+	options := exportSiteOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export-site DIR",
+		Short: "Generate a static website of the bugs",
+		Long: `Generate a static, read-only website of the bugs in a directory.
+
+The site has lists of bugs by status and label, a page per bug with its timeline and
+attachments, a page per identity, a client-side search and an Atom feed.
+
+When run again on the same directory, only the pages of the bugs edited since the
+previous generation are written again.`,
+		Example: `git bug export-site public --title "My project bugs" --base-url https://bugs.example.com/`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runExportSite(env, options, args[0])
+		}),
+		Args: cobra.ExactArgs(1),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(&options.title, "title", "Bugs", "Title of the site")
+	flags.StringVar(&options.baseURL, "base-url", "", "Public URL of the site, for the absolute links of the Atom feed")
+	flags.BoolVarP(&options.force, "force", "f", false, "Regenerate all the pages, even those of the bugs that didn't change")
+
+	return cmd
+}
+
+func runExportSite(env *execenv.Env, opts exportSiteOptions, dir string) error {
+	stats, err := site.Generate(env.Backend, dir, site.Options{
+		Title:   opts.title,
+		BaseURL: opts.baseURL,
+		Force:   opts.force,
+	})
+	if err != nil {
+		return err
+	}
+
+	env.Out.Printf("%d bug page(s) written, %d unchanged, %d removed\n",
+		stats.BugsWritten, stats.BugsSkipped, stats.BugsRemoved)
+
+	return nil
+}
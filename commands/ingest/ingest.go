@@ -0,0 +1,18 @@
+package ingestcmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/execenv"
+)
+
+func NewIngestCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ingest",
+		Short: "Create bugs and comments from external messages",
+	}
+
+	cmd.AddCommand(newIngestMailCommand(env))
+
+	return cmd
+}
@@ -0,0 +1,190 @@
+package ingestcmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/commands/input"
+	"github.com/MichaelMure/git-bug/ingest"
+)
+
+type ingestMailOptions struct {
+	mbox         string
+	maildir      string
+	imap         string
+	imapUser     string
+	imapMailbox  string
+	imapInsecure bool
+	dryRun       bool
+}
+
+func newIngestMailCommand(env *execenv.Env) *cobra.Command {
+	options := ingestMailOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "mail",
+		Short: "Create bugs and comments from emails",
+		Long: `Create bugs and comments from emails.
+
+The emails are read from a mbox file, a maildir, the unread emails of an IMAP mailbox, or a single
+email on the standard input, for example when called by a mail delivery agent.
+
+A new email create a bug, with the subject as title. A reply create a comment on its bug, found with
+the In-Reply-To and References headers (replies to an ingested email or to a notification), or with
+a [#HUMAN_ID] tag in the subject. The quoted text at the end of the replies is removed.
+
+The senders are matched to the identities by email, an identity is created for the unknown ones. The
+attachments are stored with the bug. An email already ingested is ignored.
+
+The password of the IMAP account is prompted for the first time, and stored in the keyring. The
+IMAP emails are marked as read once ingested.`,
+		Example: `git bug ingest mail --mbox ~/mail/bugs.mbox
+git bug ingest mail --maildir ~/Maildir/.bugs
+git bug ingest mail --imap imap.example.com --imap-user bugs@example.com
+git bug ingest mail < message.eml`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runIngestMail(env, options)
+		}),
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(&options.mbox, "mbox", "", "Read the emails from a mbox file, or - for the standard input")
+	flags.StringVar(&options.maildir, "maildir", "", "Read the emails from a maildir")
+	flags.StringVar(&options.imap, "imap", "", "Read the unread emails of an IMAP server, as HOST[:PORT]")
+	flags.StringVar(&options.imapUser, "imap-user", "", "The user of the IMAP account")
+	flags.StringVar(&options.imapMailbox, "imap-mailbox", "INBOX", "The IMAP mailbox to read")
+	flags.BoolVar(&options.imapInsecure, "imap-insecure", false, "Connect to the IMAP server without TLS")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Display what would be done, without changing anything")
+
+	cmd.MarkFlagsMutuallyExclusive("mbox", "maildir", "imap")
+
+	return cmd
+}
+
+func runIngestMail(env *execenv.Env, opts ingestMailOptions) error {
+	in := ingest.NewMailIngester(env.Backend)
+	in.DryRun = opts.dryRun
+
+	counts := make(map[ingest.MailEvent]int)
+
+	ingestOne := func(raw []byte) ingest.MailResult {
+		result := in.Ingest(raw)
+		counts[result.Event]++
+		if result.Event == ingest.MailEventError {
+			env.Err.Println(result)
+		} else {
+			env.Out.Println(result)
+		}
+		return result
+	}
+	process := func(raw []byte) error {
+		ingestOne(raw)
+		return nil
+	}
+
+	var err error
+	switch {
+	case opts.mbox == "-":
+		err = ingest.ReadMbox(env.In, process)
+
+	case opts.mbox != "":
+		var f *os.File
+		f, err = os.Open(opts.mbox)
+		if err != nil {
+			return err
+		}
+		err = ingest.ReadMbox(f, process)
+		_ = f.Close()
+
+	case opts.maildir != "":
+		err = ingest.ReadMaildir(opts.maildir, process)
+
+	case opts.imap != "":
+		var conf ingest.IMAPConfig
+		conf, err = imapConfig(env, opts)
+		if err != nil {
+			return err
+		}
+		err = ingest.ReadIMAP(conf, func(raw []byte) error {
+			result := ingestOne(raw)
+			// leave the failed and the dry-run emails unread
+			if in.DryRun || result.Event == ingest.MailEventError {
+				return ingest.ErrSkipMessage
+			}
+			return nil
+		})
+
+	default:
+		var raw []byte
+		raw, err = io.ReadAll(env.In)
+		if err != nil {
+			return err
+		}
+		err = process(raw)
+	}
+	if err != nil {
+		return err
+	}
+
+	verb := "created"
+	if opts.dryRun {
+		verb = "would be created"
+	}
+	env.Out.Printf("%d bug(s) and %d comment(s) %s, %d email(s) already ingested\n",
+		counts[ingest.MailEventBug], counts[ingest.MailEventComment], verb, counts[ingest.MailEventDuplicate])
+
+	if counts[ingest.MailEventError] > 0 {
+		return fmt.Errorf("%d email(s) couldn't be ingested", counts[ingest.MailEventError])
+	}
+	return nil
+}
+
+func imapConfig(env *execenv.Env, opts ingestMailOptions) (ingest.IMAPConfig, error) {
+	if opts.imapUser == "" {
+		return ingest.IMAPConfig{}, errors.New("the IMAP user is required, set it with --imap-user")
+	}
+
+	addr := opts.imap
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		port := "993"
+		if opts.imapInsecure {
+			port = "143"
+		}
+		addr = net.JoinHostPort(addr, port)
+	}
+
+	password, err := ingest.LoadIMAPPassword(env.Backend, opts.imapUser, addr)
+	if err != nil {
+		return ingest.IMAPConfig{}, err
+	}
+	if password == "" {
+		if !env.In.IsTerminal() {
+			return ingest.IMAPConfig{}, fmt.Errorf("no password stored for %s on %s, run this command in a terminal once to store it", opts.imapUser, addr)
+		}
+		password, err = input.PromptPassword("IMAP password", "password", input.Required)
+		if err != nil {
+			return ingest.IMAPConfig{}, err
+		}
+		if err := ingest.StoreIMAPPassword(env.Backend, opts.imapUser, addr, password); err != nil {
+			return ingest.IMAPConfig{}, err
+		}
+	}
+
+	return ingest.IMAPConfig{
+		Addr:     addr,
+		Username: opts.imapUser,
+		Password: password,
+		Mailbox:  opts.imapMailbox,
+		Insecure: opts.imapInsecure,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package ingestcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+)
+
+func TestIngestMail(t *testing.T) {
+	env, bugID := testenv.NewTestEnvAndBug(t)
+
+	mbox := "From jdoe@example.com Mon Jan  1 10:00:00 2024\n" +
+		"From: John Doe <jdoe@example.com>\n" +
+		"Subject: Re: [#" + bugID.Human() + "] this is a bug title\n" +
+		"Message-ID: <1@example.com>\n" +
+		"\n" +
+		"a reply\n" +
+		"\n" +
+		"From ada@example.com Mon Jan  1 11:00:00 2024\n" +
+		"From: Ada Lovelace <ada@example.com>\n" +
+		"Subject: A new problem\n" +
+		"Message-ID: <2@example.com>\n" +
+		"\n" +
+		"it's broken\n"
+
+	path := filepath.Join(t.TempDir(), "bugs.mbox")
+	require.NoError(t, os.WriteFile(path, []byte(mbox), 0600))
+
+	require.NoError(t, runIngestMail(env, ingestMailOptions{mbox: path, dryRun: true}))
+	require.Regexp(t, "^new comment on bug "+bugID.Human()+`: Re: \[#`+bugID.Human()+`\] this is a bug title
+new bug: A new problem
+1 bug\(s\) and 1 comment\(s\) would be created, 0 email\(s\) already ingested
+$`, env.Out.String())
+	env.Out.Reset()
+	require.Len(t, env.Backend.Bugs().AllIds(), 1)
+
+	require.NoError(t, runIngestMail(env, ingestMailOptions{mbox: path}))
+	require.Contains(t, env.Out.String(), "1 bug(s) and 1 comment(s) created, 0 email(s) already ingested\n")
+	env.Out.Reset()
+	require.Len(t, env.Backend.Bugs().AllIds(), 2)
+
+	// a single email on the standard input
+	env.In.(*execenv.TestIn).WriteString("From: Ada Lovelace <ada@example.com>\nSubject: A new problem\nMessage-ID: <2@example.com>\n\nit's broken\n")
+	require.NoError(t, runIngestMail(env, ingestMailOptions{}))
+	require.Equal(t, "already ingested <2@example.com>: A new problem\n0 bug(s) and 0 comment(s) created, 1 email(s) already ingested\n", env.Out.String())
+	env.Out.Reset()
+
+	env.In.(*execenv.TestIn).WriteString("Subject: no sender\n\nhello\n")
+	require.EqualError(t, runIngestMail(env, ingestMailOptions{}), "1 email(s) couldn't be ingested")
+
+	require.Error(t, runIngestMail(env, ingestMailOptions{imap: "localhost"}))
+}
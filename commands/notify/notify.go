@@ -0,0 +1,101 @@
+package notifycmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/notify"
+)
+
+type notifyOptions struct {
+	dryRun bool
+}
+
+func NewNotifyCommand(env *execenv.Env) *cobra.Command {
+	options := notifyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Send the email notifications about the changed bugs",
+		Long: `Send the email notifications about the bugs changed since the last run.
+
+Each identity can subscribe to a query with "git bug notify subscribe", to receive an email for
+each changed bug, or a daily digest. The first run after subscribing only records the current
+state of the bugs. The changes made by the subscribed identity itself are not notified.
+
+The notifications are also sent after "git bug pull", "git bug bridge pull" and "git bug bridge sync".
+Running this command regularly, for example from cron, allows to send them on time.
+
+Available git config:
+  git-bug.smtp.host: the SMTP server
+  git-bug.smtp.port: the port of the SMTP server (default to 587, or 465 with tls)
+  git-bug.smtp.from: the sender address of the emails
+  git-bug.smtp.username: the user to authenticate with, the password being set with "git bug notify smtp-password"
+  git-bug.smtp.tls: starttls (default), tls or none
+`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runNotify(env, options)
+		}),
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Display the emails that would be sent, without sending them")
+
+	cmd.AddCommand(newNotifySubscribeCommand(env))
+	cmd.AddCommand(newNotifyUnsubscribeCommand(env))
+	cmd.AddCommand(newNotifySubscriptionsCommand(env))
+	cmd.AddCommand(newNotifySmtpPasswordCommand(env))
+
+	return cmd
+}
+
+func runNotify(env *execenv.Env, opts notifyOptions) error {
+	if opts.dryRun {
+		report, err := notify.Preview(env.Backend, time.Now())
+		if err != nil {
+			return err
+		}
+		for _, m := range report.Sent {
+			env.Out.Printf("To: %s\nSubject: %s\n\n%s\n", m.To, m.Subject, m.Body)
+		}
+		env.Out.Printf("%d email(s) would be sent\n", len(report.Sent))
+		return nil
+	}
+
+	sender, err := notify.NewSMTPSender(env.Backend)
+	if err != nil {
+		return err
+	}
+
+	report, err := notify.Run(env.Backend, sender, time.Now())
+	if report != nil {
+		for _, m := range report.Sent {
+			env.Out.Printf("%s: %s\n", m.To, m.Subject)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	env.Out.Printf("%d email(s) sent\n", len(report.Sent))
+	return nil
+}
+
+// AfterSync send the notifications after the repository got updates, if
+// configured. A failure is reported without failing the synchronization.
+func AfterSync(env *execenv.Env) {
+	report, err := notify.AfterSync(env.Backend)
+	if err != nil {
+		env.Err.Printf("notifications: %v\n", err)
+		return
+	}
+	if report != nil && len(report.Sent) > 0 {
+		env.Out.Printf("%d notification email(s) sent\n", len(report.Sent))
+	}
+}
@@ -0,0 +1,55 @@
+package notifycmd
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/commands/input"
+	"github.com/MichaelMure/git-bug/notify"
+)
+
+func newNotifySmtpPasswordCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "smtp-password",
+		Short: "Store the password of the SMTP server in the keyring",
+		Long: `Store the password of the SMTP server in the keyring.
+
+The password is prompted for, or read from the standard input if it's not a terminal.`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runNotifySmtpPassword(env)
+		}),
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runNotifySmtpPassword(env *execenv.Env) error {
+	var password string
+	var err error
+
+	if env.In.IsTerminal() {
+		password, err = input.PromptPassword("SMTP password", "password", input.Required)
+	} else {
+		password, _ = bufio.NewReader(env.In).ReadString('\n')
+		password = strings.TrimRight(password, "\r\n")
+		if password == "" {
+			err = errors.New("no password given")
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := notify.StoreSMTPPassword(env.Backend, password); err != nil {
+		return err
+	}
+
+	env.Out.Println("SMTP password stored")
+	return nil
+}
@@ -0,0 +1,90 @@
+package notifycmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/notify"
+)
+
+type notifySubscribeOptions struct {
+	user   string
+	digest bool
+	email  string
+}
+
+func newNotifySubscribeCommand(env *execenv.Env) *cobra.Command {
+	options := notifySubscribeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "subscribe [QUERY]",
+		Short: "Subscribe to the changes of the bugs matching a query",
+		Long: `Subscribe to the changes of the bugs matching a query, or all the bugs if no query is given.
+
+The query use the same language as "git bug bug". An identity has a single subscription, subscribing again replace it.`,
+		Example: `git bug notify subscribe status:open label:security
+git bug notify subscribe --digest
+git bug notify subscribe --user 7a3c --email ada@example.com "label:ui"`,
+		PreRunE: execenv.LoadBackendEnsureUser(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runNotifySubscribe(env, options, args)
+		}),
+		ValidArgsFunction: completion.Ls(env),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&options.user, "user", "u", "", "Subscribe this identity instead of yours")
+	cmd.RegisterFlagCompletionFunc("user", completion.User(env))
+	flags.BoolVarP(&options.digest, "digest", "d", false, "Receive a daily digest instead of an email per change")
+	flags.StringVarP(&options.email, "email", "e", "", "Send the emails to this address instead of the email of the identity")
+
+	return cmd
+}
+
+func runNotifySubscribe(env *execenv.Env, opts notifySubscribeOptions, args []string) error {
+	i, err := resolveUser(env, opts.user)
+	if err != nil {
+		return err
+	}
+
+	sub := notify.Subscription{
+		IdentityId: i.Id(),
+		Query:      strings.Join(args, " "),
+		Mode:       notify.Immediate,
+		Email:      opts.email,
+	}
+	if opts.digest {
+		sub.Mode = notify.Digest
+	}
+
+	if err := notify.Subscribe(env.Backend, sub); err != nil {
+		return err
+	}
+
+	env.Out.Printf("%s subscribed to %s\n", i.DisplayName(), describe(sub))
+	return nil
+}
+
+func resolveUser(env *execenv.Env, prefix string) (*cache.IdentityCache, error) {
+	if prefix == "" {
+		return env.Backend.GetUserIdentity()
+	}
+	return env.Backend.Identities().ResolvePrefix(prefix)
+}
+
+func describe(sub notify.Subscription) string {
+	what := "all the bugs"
+	if sub.Query != "" {
+		what = "the bugs matching \"" + sub.Query + "\""
+	}
+	if sub.Mode == notify.Digest {
+		return what + ", with a daily digest"
+	}
+	return what
+}
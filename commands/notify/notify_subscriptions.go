@@ -0,0 +1,46 @@
+package notifycmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/notify"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+func newNotifySubscriptionsCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "subscriptions",
+		Aliases: []string{"ls"},
+		Short:   "List the subscriptions to the email notifications",
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runNotifySubscriptions(env)
+		}),
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+func runNotifySubscriptions(env *execenv.Env) error {
+	subs, err := notify.Subscriptions(env.Backend)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		user := sub.IdentityId.Human()
+		if excerpt, err := env.Backend.Identities().ResolveExcerpt(sub.IdentityId); err == nil {
+			user = excerpt.DisplayName()
+		}
+
+		line := colors.Magenta(user) + " " + describe(sub)
+		if sub.Email != "" {
+			line += ", to " + sub.Email
+		}
+		env.Out.Println(line)
+	}
+
+	return nil
+}
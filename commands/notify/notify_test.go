@@ -0,0 +1,62 @@
+package notifycmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/notify"
+)
+
+func TestNotifySubscribe(t *testing.T) {
+	env, userID := testenv.NewTestEnvAndUser(t)
+
+	require.NoError(t, runNotifySubscribe(env, notifySubscribeOptions{digest: true}, []string{"status:open", "label:bug"}))
+	require.Equal(t, "John Doe subscribed to the bugs matching \"status:open label:bug\", with a daily digest\n", env.Out.String())
+	env.Out.Reset()
+
+	subs, err := notify.Subscriptions(env.Backend)
+	require.NoError(t, err)
+	require.Equal(t, []notify.Subscription{{
+		IdentityId: userID,
+		Query:      "status:open label:bug",
+		Mode:       notify.Digest,
+	}}, subs)
+
+	require.NoError(t, runNotifySubscribe(env, notifySubscribeOptions{email: "john@work.com"}, nil))
+	env.Out.Reset()
+
+	require.NoError(t, runNotifySubscriptions(env))
+	require.Equal(t, "John Doe all the bugs, to john@work.com\n", env.Out.String())
+	env.Out.Reset()
+
+	// the first run only record the current state
+	require.NoError(t, runNotify(env, notifyOptions{dryRun: true}))
+	require.Equal(t, "0 email(s) would be sent\n", env.Out.String())
+	env.Out.Reset()
+
+	require.Error(t, runNotify(env, notifyOptions{}))
+
+	require.NoError(t, runNotifyUnsubscribe(env, notifyUnsubscribeOptions{}))
+	require.Equal(t, "John Doe unsubscribed\n", env.Out.String())
+	env.Out.Reset()
+
+	require.NoError(t, runNotifySubscriptions(env))
+	require.Empty(t, env.Out.String())
+}
+
+func TestNotifySmtpPassword(t *testing.T) {
+	env, _ := testenv.NewTestEnvAndUser(t)
+
+	require.Error(t, runNotifySmtpPassword(env))
+
+	env.In.(*execenv.TestIn).WriteString("secret\n")
+	require.NoError(t, runNotifySmtpPassword(env))
+	require.Equal(t, "SMTP password stored\n", env.Out.String())
+
+	item, err := env.Backend.Keyring().Get("smtp-password")
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(item.Data))
+}
@@ -0,0 +1,49 @@
+package notifycmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/notify"
+)
+
+type notifyUnsubscribeOptions struct {
+	user string
+}
+
+func newNotifyUnsubscribeCommand(env *execenv.Env) *cobra.Command {
+	options := notifyUnsubscribeOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "unsubscribe",
+		Short:   "Stop receiving the email notifications",
+		PreRunE: execenv.LoadBackendEnsureUser(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runNotifyUnsubscribe(env, options)
+		}),
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&options.user, "user", "u", "", "Unsubscribe this identity instead of yours")
+	cmd.RegisterFlagCompletionFunc("user", completion.User(env))
+
+	return cmd
+}
+
+func runNotifyUnsubscribe(env *execenv.Env, opts notifyUnsubscribeOptions) error {
+	i, err := resolveUser(env, opts.user)
+	if err != nil {
+		return err
+	}
+
+	if err := notify.Unsubscribe(env.Backend, i.Id()); err != nil {
+		return err
+	}
+
+	env.Out.Printf("%s unsubscribed\n", i.DisplayName())
+	return nil
+}
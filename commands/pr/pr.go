@@ -0,0 +1,127 @@
+package prcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/cmdjson"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+type prOptions struct {
+	statuses []string
+	format   string
+}
+
+func NewPullRequestCommand(env *execenv.Env) *cobra.Command {
+	options := prOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "List pull requests",
+		Long: `List the pull requests, the most recent first.
+
+Pull requests are imported from the bridges supporting them, like Github when
+configured with "git bug bridge new --pull-requests".`,
+		Example: `List the open pull requests:
+git bug pr --status open
+
+List the merged or closed pull requests, as JSON:
+git bug pr --status merged,closed --format json
+`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runPullRequest(env, options)
+		}),
+	}
+
+	cmd.AddCommand(newPullRequestShowCommand(env))
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringSliceVarP(&options.statuses, "status", "s", nil,
+		"Filter by status. Valid values are [open,closed,merged]")
+	cmd.RegisterFlagCompletionFunc("status", completion.From([]string{"open", "closed", "merged"}))
+	flags.StringVarP(&options.format, "format", "f", "default",
+		"Select the output formatting style. Valid values are [default,plain,json]")
+	cmd.RegisterFlagCompletionFunc("format", completion.From([]string{"default", "plain", "json"}))
+
+	return cmd
+}
+
+func runPullRequest(env *execenv.Env, opts prOptions) error {
+	statuses := make([]pullrequest.Status, len(opts.statuses))
+	for i, str := range opts.statuses {
+		status, err := pullrequest.StatusFromString(str)
+		if err != nil {
+			return err
+		}
+		statuses[i] = status
+	}
+
+	ids := env.Backend.PullRequests().QueryStatus(statuses...)
+
+	excerpts := make([]*cache.PullRequestExcerpt, len(ids))
+	for i, id := range ids {
+		excerpt, err := env.Backend.PullRequests().ResolveExcerpt(id)
+		if err != nil {
+			return err
+		}
+		excerpts[i] = excerpt
+	}
+
+	switch opts.format {
+	case "default":
+		return prsDefaultFormatter(env, excerpts)
+	case "plain":
+		return prsPlainFormatter(env, excerpts)
+	case "json":
+		return prsJsonFormatter(env, excerpts)
+	default:
+		return fmt.Errorf("unknown format %s", opts.format)
+	}
+}
+
+func prsDefaultFormatter(env *execenv.Env, excerpts []*cache.PullRequestExcerpt) error {
+	for _, pr := range excerpts {
+		author, err := env.Backend.Identities().ResolveExcerpt(pr.AuthorId)
+		if err != nil {
+			return err
+		}
+
+		env.Out.Printf("%s\t%s\t%s\t%s\t%s\n",
+			colors.Cyan(pr.Id().Human()),
+			colors.Yellow(pr.Status),
+			strings.TrimSpace(pr.Title),
+			colors.Magenta(author.DisplayName()),
+			fmt.Sprintf("%s -> %s", pr.SourceBranch, pr.TargetBranch),
+		)
+	}
+	return nil
+}
+
+func prsPlainFormatter(env *execenv.Env, excerpts []*cache.PullRequestExcerpt) error {
+	for _, pr := range excerpts {
+		env.Out.Printf("%s\t%s\t%s\n", pr.Id().Human(), pr.Status, strings.TrimSpace(pr.Title))
+	}
+	return nil
+}
+
+func prsJsonFormatter(env *execenv.Env, excerpts []*cache.PullRequestExcerpt) error {
+	jsonPrs := make([]cmdjson.PullRequestExcerpt, len(excerpts))
+	for i, pr := range excerpts {
+		jsonPr, err := cmdjson.NewPullRequestExcerpt(env.Backend, pr)
+		if err != nil {
+			return err
+		}
+		jsonPrs[i] = jsonPr
+	}
+	return env.Out.PrintJSON(jsonPrs)
+}
@@ -0,0 +1,181 @@
+package prcmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/cmdjson"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+type prShowOptions struct {
+	format string
+}
+
+func newPullRequestShowCommand(env *execenv.Env) *cobra.Command {
+	options := prShowOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "show PR_ID",
+		Short:   "Display the details of a pull request",
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runPullRequestShow(env, options, args)
+		}),
+		ValidArgsFunction: pullRequestCompletion(env),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&options.format, "format", "f", "default",
+		"Select the output formatting style. Valid values are [default,json]")
+	cmd.RegisterFlagCompletionFunc("format", completion.From([]string{"default", "json"}))
+
+	return cmd
+}
+
+func runPullRequestShow(env *execenv.Env, opts prShowOptions, args []string) error {
+	if len(args) != 1 {
+		return errors.New("a pull request id is required")
+	}
+
+	pr, err := env.Backend.PullRequests().ResolvePrefix(args[0])
+	if err != nil {
+		return err
+	}
+
+	snap := pr.Snapshot()
+
+	switch opts.format {
+	case "json":
+		return env.Out.PrintJSON(cmdjson.NewPullRequestSnapshot(snap))
+	case "default":
+		return showDefaultFormatter(env, snap)
+	default:
+		return fmt.Errorf("unknown format %s", opts.format)
+	}
+}
+
+func showDefaultFormatter(env *execenv.Env, snapshot *pullrequest.Snapshot) error {
+	// Header
+	env.Out.Printf("%s [%s] %s\n\n",
+		colors.Cyan(snapshot.Id().Human()),
+		colors.Yellow(snapshot.Status),
+		snapshot.Title,
+	)
+
+	env.Out.Printf("%s wants to merge %s into %s, opened %s\n",
+		colors.Magenta(snapshot.Author.DisplayName()),
+		snapshot.SourceBranch,
+		snapshot.TargetBranch,
+		snapshot.CreateTime.String(),
+	)
+
+	if snapshot.MergeCommit != "" {
+		env.Out.Printf("merged with commit %s\n", snapshot.MergeCommit)
+	}
+
+	// Participants
+	var participants = make([]string, len(snapshot.Participants))
+	for i := range snapshot.Participants {
+		participants[i] = snapshot.Participants[i].DisplayName()
+	}
+
+	env.Out.Printf("participants: %s\n\n",
+		strings.Join(participants, ", "),
+	)
+
+	// Commits
+	if len(snapshot.Commits) > 0 {
+		env.Out.Printf("commits:\n")
+		for _, commit := range snapshot.Commits {
+			env.Out.Printf("  %s %s\n", colors.Yellow(commit.Hash[:7]), commit.Message)
+		}
+		env.Out.Println()
+	}
+
+	indent := "  "
+
+	// Comments
+	for i, comment := range snapshot.Comments {
+		var message string
+		env.Out.Printf("%s%s #%d %s <%s>\n\n",
+			indent,
+			comment.CombinedId().Human(),
+			i,
+			comment.Author.DisplayName(),
+			comment.Author.Email(),
+		)
+
+		if comment.Message == "" {
+			message = colors.BlackBold(colors.WhiteBg("No description provided."))
+		} else {
+			message = comment.Message
+		}
+
+		env.Out.Printf("%s%s\n\n\n",
+			indent,
+			message,
+		)
+	}
+
+	// Reviews
+	for _, review := range snapshot.Reviews {
+		env.Out.Printf("%s%s %s %s\n\n",
+			indent,
+			review.CombinedId().Human(),
+			review.Author.DisplayName(),
+			colors.Yellow(review.State),
+		)
+
+		if review.Message != "" {
+			env.Out.Printf("%s%s\n\n", indent, review.Message)
+		}
+
+		for _, comment := range review.Comments {
+			env.Out.Printf("%s%s%s:%d\n%s%s%s\n\n",
+				indent, indent, comment.Path, comment.Line,
+				indent, indent, comment.Message,
+			)
+		}
+
+		env.Out.Println()
+	}
+
+	return nil
+}
+
+func pullRequestCompletion(env *execenv.Env) completion.ValidArgsFunction {
+	return func(cmd *cobra.Command, args []string, toComplete string) (completions []string, directives cobra.ShellCompDirective) {
+		if err := execenv.LoadBackend(env)(cmd, args); err != nil {
+			return completion.HandleError(err)
+		}
+		defer func() {
+			_ = env.Backend.Close()
+		}()
+
+		return pullRequestWithBackend(env.Backend, toComplete)
+	}
+}
+
+func pullRequestWithBackend(backend *cache.RepoCache, toComplete string) (completions []string, directives cobra.ShellCompDirective) {
+	for _, id := range backend.PullRequests().AllIds() {
+		if strings.Contains(id.String(), strings.TrimSpace(toComplete)) {
+			excerpt, err := backend.PullRequests().ResolveExcerpt(id)
+			if err != nil {
+				return completion.HandleError(err)
+			}
+			completions = append(completions, id.Human()+"\t"+excerpt.Title)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,46 @@
+package prcmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+	"github.com/MichaelMure/git-bug/entities/pullrequest"
+)
+
+func TestPullRequest(t *testing.T) {
+	env, _ := testenv.NewTestEnvAndUser(t)
+
+	author, err := env.Backend.GetUserIdentity()
+	require.NoError(t, err)
+
+	open, _, err := env.Backend.PullRequests().NewRaw(author, time.Now().Unix(), "open pr", "", "feature", "master", nil)
+	require.NoError(t, err)
+	merged, _, err := env.Backend.PullRequests().NewRaw(author, time.Now().Unix(), "merged pr", "", "fix", "master", nil)
+	require.NoError(t, err)
+	_, err = merged.SetStatusRaw(author, time.Now().Unix(), pullrequest.MergedStatus, strings.Repeat("a", 40), nil)
+	require.NoError(t, err)
+	require.NoError(t, merged.Commit())
+
+	err = runPullRequest(env, prOptions{format: "plain"})
+	require.NoError(t, err)
+	require.Contains(t, env.Out.String(), "open pr")
+	require.Contains(t, env.Out.String(), "merged pr")
+
+	env.Out.Reset()
+	err = runPullRequest(env, prOptions{statuses: []string{"merged"}, format: "plain"})
+	require.NoError(t, err)
+	require.Equal(t, merged.Id().Human()+"\tmerged\tmerged pr\n", env.Out.String())
+
+	env.Out.Reset()
+	err = runPullRequest(env, prOptions{statuses: []string{"unknown"}, format: "plain"})
+	require.Error(t, err)
+
+	env.Out.Reset()
+	err = runPullRequestShow(env, prShowOptions{format: "default"}, []string{open.Id().Human()})
+	require.NoError(t, err)
+	require.Contains(t, env.Out.String(), "wants to merge feature into master")
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/MichaelMure/git-bug/commands/completion"
 	"github.com/MichaelMure/git-bug/commands/execenv"
+	notifycmd "github.com/MichaelMure/git-bug/commands/notify"
 	"github.com/MichaelMure/git-bug/entity"
 )
 
@@ -55,5 +56,7 @@ func runPull(env *execenv.Env, args []string) error {
 		}
 	}
 
+	notifycmd.AfterSync(env)
+
 	return nil
 }
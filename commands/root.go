@@ -11,6 +11,9 @@ import (
 	bridgecmd "github.com/MichaelMure/git-bug/commands/bridge"
 	bugcmd "github.com/MichaelMure/git-bug/commands/bug"
 	"github.com/MichaelMure/git-bug/commands/execenv"
+	ingestcmd "github.com/MichaelMure/git-bug/commands/ingest"
+	notifycmd "github.com/MichaelMure/git-bug/commands/notify"
+	prcmd "github.com/MichaelMure/git-bug/commands/pr"
 	usercmd "github.com/MichaelMure/git-bug/commands/user"
 )
 
@@ -65,6 +68,12 @@ the same git remote you are already using to collaborate with other people.
 	env := execenv.NewEnv()
 
 	addCmdWithGroup(bugcmd.NewBugCommand(env), entityGroup)
+	addCmdWithGroup(bugcmd.NewBulkCommand(env), entityGroup)
+	addCmdWithGroup(bugcmd.NewApplyCommand(env), entityGroup)
+	addCmdWithGroup(bugcmd.NewFeedCommand(env), entityGroup)
+	addCmdWithGroup(bugcmd.NewWatchCommand(env), entityGroup)
+	addCmdWithGroup(bugcmd.NewUnwatchCommand(env), entityGroup)
+	addCmdWithGroup(prcmd.NewPullRequestCommand(env), entityGroup)
 	addCmdWithGroup(usercmd.NewUserCommand(env), entityGroup)
 	addCmdWithGroup(newLabelCommand(env), entityGroup)
 
@@ -74,6 +83,9 @@ the same git remote you are already using to collaborate with other people.
 	addCmdWithGroup(newPullCommand(env), remoteGroup)
 	addCmdWithGroup(newPushCommand(env), remoteGroup)
 	addCmdWithGroup(bridgecmd.NewBridgeCommand(env), remoteGroup)
+	addCmdWithGroup(newExportSiteCommand(env), remoteGroup)
+	addCmdWithGroup(notifycmd.NewNotifyCommand(env), remoteGroup)
+	addCmdWithGroup(ingestcmd.NewIngestCommand(env), remoteGroup)
 
 	cmd.AddCommand(newCommandsCommand(env))
 	cmd.AddCommand(newVersionCommand(env))
@@ -12,6 +12,10 @@ func newTermUICommand(env *execenv.Env) *cobra.Command {
 		Use:     "termui",
 		Aliases: []string{"tui"},
 		Short:   "Launch the terminal UI",
+		Long: `Launch the terminal UI.
+
+The list of bugs starts with the "status:open" query, or the query set in the git-bug.termui.query
+git config. For example, "status:open participant:me" lists the open bugs you opened or commented.`,
 		PreRunE: execenv.LoadBackendEnsureUser(env),
 		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
 			return runTermUI(env)
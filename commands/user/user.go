@@ -31,6 +31,8 @@ func NewUserCommand(env *execenv.Env) *cobra.Command {
 	cmd.AddCommand(newUserNewCommand(env))
 	cmd.AddCommand(newUserShowCommand(env))
 	cmd.AddCommand(newUserAdoptCommand(env))
+	cmd.AddCommand(newUserOAuthLinkCommand(env))
+	cmd.AddCommand(newUserTokenCommand(env))
 
 	flags := cmd.Flags()
 	flags.SortFlags = false
@@ -0,0 +1,53 @@
+package usercmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+)
+
+func newUserOAuthLinkCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "oauth-link USER_ID PROVIDER_USER_ID",
+		Short: "Link an identity to an account of the OAuth provider of the web UI",
+		Long: `Link an existing identity to an account of the OAuth provider configured for the web UI, given its user id at the provider (the "sub" of an OpenID Connect provider, the numeric id for GitHub).
+
+The user then logs in as this identity, as long as it's allowed by git-bug.webui.oauth.allowed-emails or git-bug.webui.oauth.allowed-subjects. An identity can only be linked once.`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runUserOAuthLink(env, args)
+		}),
+		ValidArgsFunction: completion.User(env),
+	}
+
+	return cmd
+}
+
+func runUserOAuthLink(env *execenv.Env, args []string) error {
+	conf, err := auth.LoadOAuthConfig(env.Backend.AnyConfig())
+	if err != nil {
+		return err
+	}
+	if conf == nil {
+		return fmt.Errorf("no OAuth provider configured, see \"git bug webui --help\"")
+	}
+
+	i, err := env.Backend.Identities().ResolvePrefix(args[0])
+	if err != nil {
+		return err
+	}
+
+	err = auth.LinkOAuthIdentity(env.Backend, conf, i, args[1])
+	if err != nil {
+		return err
+	}
+
+	env.Out.Printf("%s is now linked to the OAuth user %s\n", i.DisplayName(), args[1])
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package usercmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+)
+
+func TestUserOAuthLink(t *testing.T) {
+	env, userID := testenv.NewTestEnvAndUser(t)
+
+	// no provider configured
+	require.Error(t, runUserOAuthLink(env, []string{userID.Human(), "1234"}))
+
+	config := env.Repo.LocalConfig()
+	require.NoError(t, config.StoreString("git-bug.webui.oauth.client-id", "client"))
+	require.NoError(t, config.StoreString("git-bug.webui.oauth.issuer", "https://accounts.example.com"))
+	require.NoError(t, config.StoreString("git-bug.webui.oauth.allowed-subjects", "1234"))
+
+	require.NoError(t, runUserOAuthLink(env, []string{userID.Human(), "1234"}))
+	require.Equal(t, "John Doe is now linked to the OAuth user 1234\n", env.Out.String())
+
+	i, err := env.Backend.Identities().Resolve(userID)
+	require.NoError(t, err)
+	require.Equal(t, "https://accounts.example.com#1234", i.ImmutableMetadata()["oauth-subject"])
+
+	// only once
+	require.Error(t, runUserOAuthLink(env, []string{userID.Human(), "5678"}))
+}
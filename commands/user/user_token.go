@@ -0,0 +1,65 @@
+package usercmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+func newUserTokenCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token [USER_ID]",
+		Short: "List the API tokens of the web server, for all users or a given one",
+		Long: `List the API tokens of the web server, for all users or a given one.
+
+An API token allows to act as an identity on the web server started with "git bug webui --auth=login".
+The tokens are stored in the keyring of the user, but are only valid for the repository they were created in.`,
+		PreRunE: execenv.LoadBackend(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runUserToken(env, args)
+		}),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.User(env),
+	}
+
+	cmd.AddCommand(newUserTokenNewCommand(env))
+	cmd.AddCommand(newUserTokenRmCommand(env))
+
+	return cmd
+}
+
+func runUserToken(env *execenv.Env, args []string) error {
+	var identityId entity.Id
+	if len(args) == 1 {
+		i, err := env.Backend.Identities().ResolvePrefix(args[0])
+		if err != nil {
+			return err
+		}
+		identityId = i.Id()
+	}
+
+	tokens, err := auth.ListTokens(env.Backend, identityId)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		user := token.IdentityId.Human()
+		if excerpt, err := env.Backend.Identities().ResolveExcerpt(token.IdentityId); err == nil {
+			user = excerpt.DisplayName()
+		}
+
+		env.Out.Printf("%s %s %s %s\n",
+			colors.Cyan(token.Human()),
+			token.CreateTime.Format("2006-01-02"),
+			colors.Magenta(user),
+			token.Name,
+		)
+	}
+
+	return nil
+}
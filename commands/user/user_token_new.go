@@ -0,0 +1,59 @@
+package usercmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/completion"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+)
+
+type userTokenNewOptions struct {
+	name string
+}
+
+func newUserTokenNewCommand(env *execenv.Env) *cobra.Command {
+	options := userTokenNewOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "new [USER_ID]",
+		Short:   "Create an API token for the web server, for your identity or a given one",
+		PreRunE: execenv.LoadBackendEnsureUser(env),
+		RunE: execenv.CloseBackend(env, func(cmd *cobra.Command, args []string) error {
+			return runUserTokenNew(env, options, args)
+		}),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.User(env),
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&options.name, "name", "n", "", "A name to remember what the token is used for")
+
+	return cmd
+}
+
+func runUserTokenNew(env *execenv.Env, opts userTokenNewOptions, args []string) error {
+	var i *cache.IdentityCache
+	var err error
+	if len(args) == 1 {
+		i, err = env.Backend.Identities().ResolvePrefix(args[0])
+	} else {
+		i, err = env.Backend.GetUserIdentity()
+	}
+	if err != nil {
+		return err
+	}
+
+	value, token, err := auth.NewToken(env.Backend, i.Id(), opts.name)
+	if err != nil {
+		return err
+	}
+
+	env.Err.Printf("API token %s created for %s, it won't be displayed again:\n", token.Human(), i.DisplayName())
+	env.Out.Println(value)
+
+	return nil
+}
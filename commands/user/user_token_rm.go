@@ -0,0 +1,37 @@
+package usercmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/commands/execenv"
+)
+
+func newUserTokenRmCommand(env *execenv.Env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm TOKEN_ID",
+		Short:   "Revoke an API token",
+		PreRunE: execenv.LoadRepo(env),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserTokenRm(env, args)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func runUserTokenRm(env *execenv.Env, args []string) error {
+	token, err := auth.LoadTokenWithPrefix(env.Repo, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = auth.RemoveToken(env.Repo, token.Hash)
+	if err != nil {
+		return err
+	}
+
+	env.Out.Printf("API token %s revoked\n", token.Human())
+	return nil
+}
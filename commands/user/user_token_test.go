@@ -0,0 +1,34 @@
+package usercmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/api/auth"
+	"github.com/MichaelMure/git-bug/commands/bug/testenv"
+)
+
+func TestUserToken(t *testing.T) {
+	env, userID := testenv.NewTestEnvAndUser(t)
+
+	require.NoError(t, runUserTokenNew(env, userTokenNewOptions{name: "ci"}, nil))
+	value := strings.TrimSpace(env.Out.String())
+
+	token, err := auth.ResolveToken(env.Repo, value)
+	require.NoError(t, err)
+	require.Equal(t, userID, token.IdentityId)
+	require.Equal(t, "ci", token.Name)
+
+	env.Out.Reset()
+	require.NoError(t, runUserToken(env, nil))
+	require.Regexp(t, "^"+token.Human()+" [0-9-]{10} John Doe ci\n$", env.Out.String())
+
+	env.Out.Reset()
+	require.NoError(t, runUserTokenRm(env, []string{token.Human()}))
+	require.Equal(t, "API token "+token.Human()+" revoked\n", env.Out.String())
+
+	_, err = auth.ResolveToken(env.Repo, value)
+	require.ErrorIs(t, err, auth.ErrTokenNotExist)
+}
@@ -24,7 +24,9 @@ import (
 	"github.com/MichaelMure/git-bug/api/auth"
 	"github.com/MichaelMure/git-bug/api/graphql"
 	httpapi "github.com/MichaelMure/git-bug/api/http"
+	"github.com/MichaelMure/git-bug/api/rest"
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/completion"
 	"github.com/MichaelMure/git-bug/commands/execenv"
 	"github.com/MichaelMure/git-bug/entities/identity"
 	"github.com/MichaelMure/git-bug/repository"
@@ -41,6 +43,7 @@ type webUIOptions struct {
 	readOnly  bool
 	logErrors bool
 	query     string
+	auth      string
 }
 
 func newWebUICommand(env *execenv.Env) *cobra.Command {
@@ -51,8 +54,23 @@ func newWebUICommand(env *execenv.Env) *cobra.Command {
 		Short: "Launch the web UI",
 		Long: `Launch the web UI.
 
+Authentication modes:
+  local: every change is made as the user identity of the repository
+  login: the clients authenticate as an identity, with an API token (see "git bug user token")
+    given as a bearer token, or by logging in at /auth/login. The OAuth provider is used if configured,
+    and only lets in the users allowed by the config below. They log in as the identity linked to their
+    account (see "git bug user oauth-link"), or as a new identity created on their first login.
+
 Available git config:
   git-bug.webui.open [bool]: control the automatic opening of the web UI in the default browser
+  git-bug.webui.oauth.issuer: the URL of an OpenID Connect provider, to log in with it
+  git-bug.webui.oauth.client-id: the OAuth client id
+  git-bug.webui.oauth.client-secret: the OAuth client secret
+  git-bug.webui.oauth.redirect-url: the redirect URL registered with the provider (default to <address>/auth/oauth/callback)
+  git-bug.webui.oauth.auth-url, git-bug.webui.oauth.token-url, git-bug.webui.oauth.userinfo-url: the endpoints of a plain OAuth2 provider, instead of the issuer
+  git-bug.webui.oauth.scopes: the requested scopes (default to "openid profile email")
+  git-bug.webui.oauth.allowed-emails: the verified emails, or the domains as "@example.com", allowed to log in
+  git-bug.webui.oauth.allowed-subjects: the user ids of the provider allowed to log in
 `,
 		PreRunE: execenv.LoadRepo(env),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -70,6 +88,8 @@ Available git config:
 	flags.BoolVar(&options.readOnly, "read-only", false, "Whether to run the web UI in read-only mode")
 	flags.BoolVar(&options.logErrors, "log-errors", false, "Whether to log errors")
 	flags.StringVarP(&options.query, "query", "q", "", "The query to open in the web UI bug list")
+	flags.StringVar(&options.auth, "auth", "local", "The authentication mode. Valid values are [local,login]")
+	cmd.RegisterFlagCompletionFunc("auth", completion.From([]string{"local", "login"}))
 
 	return cmd
 }
@@ -94,17 +114,6 @@ func runWebUI(env *execenv.Env, opts webUIOptions) error {
 
 	router := mux.NewRouter()
 
-	// If the webUI is not read-only, use an authentication middleware with a
-	// fixed identity: the default user of the repo
-	// TODO: support dynamic authentication with OAuth
-	if !opts.readOnly {
-		author, err := identity.GetUserIdentity(env.Repo)
-		if err != nil {
-			return err
-		}
-		router.Use(auth.Middleware(author.Id()))
-	}
-
 	mrc := cache.NewMultiRepoCache()
 
 	_, events := mrc.RegisterDefaultRepository(env.Repo)
@@ -114,6 +123,38 @@ func runWebUI(env *execenv.Env, opts webUIOptions) error {
 		return err
 	}
 
+	// If the webUI is not read-only, use an authentication middleware
+	var authenticator *auth.Authenticator
+	switch {
+	case opts.readOnly:
+	case opts.auth == "local":
+		// a fixed identity: the default user of the repo
+		author, err := identity.GetUserIdentity(env.Repo)
+		if err != nil {
+			return err
+		}
+		router.Use(auth.Middleware(author.Id()))
+	case opts.auth == "login":
+		oauthConf, err := auth.LoadOAuthConfig(env.Repo.AnyConfig())
+		if err != nil {
+			return err
+		}
+		if oauthConf != nil && oauthConf.RedirectURL == "" {
+			oauthConf.RedirectURL = webUiAddr + auth.PathPrefix + "/oauth/callback"
+		}
+		repo, err := mrc.DefaultRepo()
+		if err != nil {
+			return err
+		}
+		authenticator, err = auth.NewAuthenticator(context.Background(), repo, oauthConf)
+		if err != nil {
+			return err
+		}
+		router.Use(authenticator.Middleware)
+	default:
+		return fmt.Errorf("unknown authentication mode %s", opts.auth)
+	}
+
 	var errOut io.Writer
 	if opts.logErrors {
 		errOut = env.Err
@@ -124,8 +165,20 @@ func runWebUI(env *execenv.Env, opts webUIOptions) error {
 	// Routes
 	router.Path("/playground").Handler(playground.Handler("git-bug", "/graphql"))
 	router.Path("/graphql").Handler(graphqlHandler)
+	if authenticator != nil {
+		router.PathPrefix(auth.PathPrefix + "/").Handler(authenticator.Handler())
+	}
+	router.PathPrefix(rest.PathPrefix + "/").Handler(rest.NewHandler(mrc))
 	router.Path("/gitfile/{repo}/{hash}").Handler(httpapi.NewGitFileHandler(mrc))
 	router.Path("/upload/{repo}").Methods("POST").Handler(httpapi.NewGitUploadFileHandler(mrc))
+	feedHandler := httpapi.NewFeedHandler(mrc)
+	router.Path("/feed").Methods("GET").Handler(feedHandler)
+	router.Path("/feed/{repo}").Methods("GET").Handler(feedHandler)
+	if !opts.readOnly {
+		webhookHandler := httpapi.NewBridgeWebhookHandler(mrc)
+		router.Path("/webhook/{bridge}").Methods("POST").Handler(webhookHandler)
+		router.Path("/webhook/{repo}/{bridge}").Methods("POST").Handler(webhookHandler)
+	}
 	router.PathPrefix("/").Handler(webui.NewHandler())
 
 	srv := &http.Server{
@@ -163,6 +216,14 @@ func runWebUI(env *execenv.Env, opts webUIOptions) error {
 	env.Out.Printf("Web UI: %s\n", webUiAddr)
 	env.Out.Printf("Graphql API: http://%s/graphql\n", addr)
 	env.Out.Printf("Graphql Playground: http://%s/playground\n", addr)
+	env.Out.Printf("REST API: http://%s%s/v1 (OpenAPI: http://%s%s/v1/openapi.json)\n", addr, rest.PathPrefix, addr, rest.PathPrefix)
+	env.Out.Printf("Atom feeds: http://%s/feed?q=<query> or http://%s/feed?bug=<id>\n", addr, addr)
+	if !opts.readOnly {
+		env.Out.Printf("Bridge webhooks: http://%s/webhook/<bridge>\n", addr)
+	}
+	if authenticator != nil {
+		env.Out.Printf("Login: http://%s%s/login\n", addr, auth.PathPrefix)
+	}
 	env.Out.Println("Press Ctrl+c to quit")
 
 	configOpen, err := env.Repo.AnyConfig().ReadBool(webUIOpenConfigKey)
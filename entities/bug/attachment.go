@@ -0,0 +1,102 @@
+package bug
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// MetaKeyAttachmentPrefix is the prefix of the operation metadata describing
+// an attached file, followed by the hash of the file.
+const MetaKeyAttachmentPrefix = "attachment-"
+
+// Attachment describe a file attached to a comment. The content itself is
+// stored as a git blob and referenced in the comment files, while the name,
+// MIME type and size are stored in the metadata of the operation that attached
+// the file.
+type Attachment struct {
+	Hash     repository.Hash `json:"-"`
+	Name     string          `json:"name"`
+	MimeType string          `json:"mime"`
+	Size     int64           `json:"size"`
+}
+
+// AttachmentsMetadata return the operation metadata describing the given
+// attachments, to be merged in the metadata of the operation.
+func AttachmentsMetadata(attachments []Attachment) map[string]string {
+	result := make(map[string]string, len(attachments))
+	for _, attachment := range attachments {
+		raw, err := json.Marshal(attachment)
+		if err != nil {
+			// simply panic as it would be a coding error, the struct always marshal
+			panic(err)
+		}
+		result[MetaKeyAttachmentPrefix+string(attachment.Hash)] = string(raw)
+	}
+	return result
+}
+
+// AttachmentHashes return the hashes of the given attachments, to be used as
+// the files of an operation.
+func AttachmentHashes(attachments []Attachment) []repository.Hash {
+	if len(attachments) == 0 {
+		return nil
+	}
+	result := make([]repository.Hash, len(attachments))
+	for i, attachment := range attachments {
+		result[i] = attachment.Hash
+	}
+	return result
+}
+
+// opAttachments describe the files of an operation using its metadata. Files
+// attached without metadata, for example by an older version, only have their
+// hash set.
+func opAttachments(op dag.Operation, files []repository.Hash) []Attachment {
+	if len(files) == 0 {
+		return nil
+	}
+
+	result := make([]Attachment, len(files))
+	for i, hash := range files {
+		result[i].Hash = hash
+
+		raw, ok := op.GetMetadata(MetaKeyAttachmentPrefix + string(hash))
+		if !ok {
+			continue
+		}
+		_ = json.Unmarshal([]byte(raw), &result[i])
+		result[i].Hash = hash
+		result[i].Name = sanitizeAttachmentName(result[i].Name)
+	}
+	return result
+}
+
+// sanitizeAttachmentName keep only the last element of the name of an
+// attachment. The metadata come unchecked from other repositories and bridges,
+// and the name is used as a file name when extracting the attachment, so it
+// must not point outside the current directory, nor be a hidden file like a
+// shell configuration. An empty name is returned if nothing usable is left, to
+// fall back to the hash.
+func sanitizeAttachmentName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "/" || strings.HasPrefix(name, ".") {
+		return ""
+	}
+	return name
+}
+
+// DisplayName return a name suitable to display the attachment
+func (a Attachment) DisplayName() string {
+	if strings.TrimSpace(a.Name) != "" {
+		return a.Name
+	}
+	return string(a.Hash)
+}
@@ -0,0 +1,42 @@
+package bug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestOpAttachmentsSanitizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"notes.txt", "notes.txt"},
+		{"../../.bashrc", ""},
+		{".git", ""},
+		{"dir/.hidden.txt", ""},
+		{"/etc/passwd", "passwd"},
+		{`..\..\evil.exe`, "evil.exe"},
+		{"..", ""},
+		{".", ""},
+		{"/", ""},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := repository.Hash("hash1")
+			op := NewAddCommentOp(nil, 0, "message", []repository.Hash{hash})
+			for key, value := range AttachmentsMetadata([]Attachment{{Hash: hash, Name: tt.name}}) {
+				op.SetMetadata(key, value)
+			}
+
+			attachments := opAttachments(op, op.Files)
+			require.Len(t, attachments, 1)
+			require.Equal(t, hash, attachments[0].Hash)
+			require.Equal(t, tt.expected, attachments[0].Name)
+		})
+	}
+}
@@ -22,6 +22,9 @@ type Comment struct {
 	Message string
 	Files   []repository.Hash
 
+	// Attachments describe the Files, in the same order
+	Attachments []Attachment
+
 	// Creation time of the comment.
 	// Should be used only for human display, never for ordering as we can't rely on it in a distributed system.
 	unixTime timestamp.Timestamp
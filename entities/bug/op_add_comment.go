@@ -33,12 +33,13 @@ func (op *AddCommentOperation) Apply(snapshot *Snapshot) {
 	opId := op.Id()
 
 	comment := Comment{
-		combinedId: entity.CombineIds(snapshot.Id(), opId),
-		targetId:   opId,
-		Message:    op.Message,
-		Author:     op.Author(),
-		Files:      op.Files,
-		unixTime:   timestamp.Timestamp(op.UnixTime),
+		combinedId:  entity.CombineIds(snapshot.Id(), opId),
+		targetId:    opId,
+		Message:     op.Message,
+		Author:      op.Author(),
+		Files:       op.Files,
+		Attachments: opAttachments(op, op.Files),
+		unixTime:    timestamp.Timestamp(op.UnixTime),
 	}
 
 	snapshot.Comments = append(snapshot.Comments, comment)
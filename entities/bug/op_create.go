@@ -42,11 +42,13 @@ func (op *CreateOperation) Apply(snapshot *Snapshot) {
 	snapshot.Title = op.Title
 
 	comment := Comment{
-		combinedId: entity.CombineIds(snapshot.id, opId),
-		targetId:   opId,
-		Message:    op.Message,
-		Author:     op.Author(),
-		unixTime:   timestamp.Timestamp(op.UnixTime),
+		combinedId:  entity.CombineIds(snapshot.id, opId),
+		targetId:    opId,
+		Message:     op.Message,
+		Author:      op.Author(),
+		Files:       op.Files,
+		Attachments: opAttachments(op, op.Files),
+		unixTime:    timestamp.Timestamp(op.UnixTime),
 	}
 
 	snapshot.Comments = []Comment{comment}
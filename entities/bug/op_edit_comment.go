@@ -50,11 +50,13 @@ func (op *EditCommentOperation) Apply(snapshot *Snapshot) {
 	}
 
 	comment := Comment{
-		combinedId: combinedId,
-		targetId:   op.Target,
-		Message:    op.Message,
-		Files:      op.Files,
-		unixTime:   timestamp.Timestamp(op.UnixTime),
+		combinedId:  combinedId,
+		targetId:    op.Target,
+		Author:      op.Author(),
+		Message:     op.Message,
+		Files:       op.Files,
+		Attachments: opAttachments(op, op.Files),
+		unixTime:    timestamp.Timestamp(op.UnixTime),
 	}
 
 	switch target := target.(type) {
@@ -76,6 +78,7 @@ func (op *EditCommentOperation) Apply(snapshot *Snapshot) {
 		if snapshot.Comments[i].CombinedId() == combinedId {
 			snapshot.Comments[i].Message = op.Message
 			snapshot.Comments[i].Files = op.Files
+			snapshot.Comments[i].Attachments = comment.Attachments
 			break
 		}
 	}
@@ -30,20 +30,23 @@ type CommentTimelineItem struct {
 	Author     identity.Interface
 	Message    string
 	Files      []repository.Hash
-	CreatedAt  timestamp.Timestamp
-	LastEdit   timestamp.Timestamp
-	History    []CommentHistoryStep
+	// Attachments describe the Files, in the same order
+	Attachments []Attachment
+	CreatedAt   timestamp.Timestamp
+	LastEdit    timestamp.Timestamp
+	History     []CommentHistoryStep
 }
 
 func NewCommentTimelineItem(comment Comment) CommentTimelineItem {
 	return CommentTimelineItem{
 		// id: comment.id,
-		combinedId: comment.combinedId,
-		Author:     comment.Author,
-		Message:    comment.Message,
-		Files:      comment.Files,
-		CreatedAt:  comment.unixTime,
-		LastEdit:   comment.unixTime,
+		combinedId:  comment.combinedId,
+		Author:      comment.Author,
+		Message:     comment.Message,
+		Files:       comment.Files,
+		Attachments: comment.Attachments,
+		CreatedAt:   comment.unixTime,
+		LastEdit:    comment.unixTime,
 		History: []CommentHistoryStep{
 			{
 				Message:  comment.Message,
@@ -61,6 +64,7 @@ func (c *CommentTimelineItem) CombinedId() entity.CombinedId {
 func (c *CommentTimelineItem) Append(comment Comment) {
 	c.Message = comment.Message
 	c.Files = comment.Files
+	c.Attachments = comment.Attachments
 	c.LastEdit = comment.unixTime
 	c.History = append(c.History, CommentHistoryStep{
 		Author:   comment.Author,
@@ -0,0 +1,70 @@
+package pullrequest
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/util/text"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &AddCommentOperation{}
+
+// AddCommentOperation will add a new comment in the discussion of a pull request
+type AddCommentOperation struct {
+	dag.OpBase
+	Message string `json:"message"`
+}
+
+func (op *AddCommentOperation) Id() entity.Id {
+	return dag.IdOperation(op, &op.OpBase)
+}
+
+func (op *AddCommentOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author())
+	snapshot.addParticipant(op.Author())
+
+	opId := op.Id()
+
+	snapshot.Comments = append(snapshot.Comments, Comment{
+		combinedId: entity.CombineIds(snapshot.Id(), opId),
+		targetId:   opId,
+		Message:    op.Message,
+		Author:     op.Author(),
+		unixTime:   timestamp.Timestamp(op.UnixTime),
+	})
+}
+
+func (op *AddCommentOperation) Validate() error {
+	if err := op.OpBase.Validate(op, AddCommentOp); err != nil {
+		return err
+	}
+
+	if !text.Safe(op.Message) {
+		return fmt.Errorf("message is not fully printable")
+	}
+
+	return nil
+}
+
+func NewAddCommentOp(author identity.Interface, unixTime int64, message string) *AddCommentOperation {
+	return &AddCommentOperation{
+		OpBase:  dag.NewOpBase(AddCommentOp, author, unixTime),
+		Message: message,
+	}
+}
+
+// AddComment is a convenience function to add a comment to a pull request
+func AddComment(pr Interface, author identity.Interface, unixTime int64, message string, metadata map[string]string) (entity.CombinedId, *AddCommentOperation, error) {
+	op := NewAddCommentOp(author, unixTime, message)
+	for key, val := range metadata {
+		op.SetMetadata(key, val)
+	}
+	if err := op.Validate(); err != nil {
+		return entity.UnsetCombinedId, nil, err
+	}
+	pr.Append(op)
+	return entity.CombineIds(pr.Id(), op.Id()), op, nil
+}
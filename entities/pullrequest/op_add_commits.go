@@ -0,0 +1,90 @@
+package pullrequest
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+var _ Operation = &AddCommitsOperation{}
+
+var commitHashRegexp = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// AddCommitsOperation will reference new commits in a pull request
+type AddCommitsOperation struct {
+	dag.OpBase
+	Commits []Commit `json:"commits"`
+}
+
+func (op *AddCommitsOperation) Id() entity.Id {
+	return dag.IdOperation(op, &op.OpBase)
+}
+
+func (op *AddCommitsOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author())
+
+	for _, commit := range op.Commits {
+		if !snapshot.HasCommit(commit.Hash) {
+			snapshot.Commits = append(snapshot.Commits, commit)
+		}
+	}
+}
+
+func (op *AddCommitsOperation) Validate() error {
+	if err := op.OpBase.Validate(op, AddCommitsOp); err != nil {
+		return err
+	}
+
+	if len(op.Commits) == 0 {
+		return fmt.Errorf("no commit")
+	}
+
+	for _, commit := range op.Commits {
+		if !commitHashRegexp.MatchString(commit.Hash) {
+			return fmt.Errorf("invalid commit hash %s", commit.Hash)
+		}
+		if !text.SafeOneLine(commit.Message) {
+			return fmt.Errorf("commit message has unsafe characters")
+		}
+	}
+
+	return nil
+}
+
+func NewAddCommitsOp(author identity.Interface, unixTime int64, commits []Commit) *AddCommitsOperation {
+	return &AddCommitsOperation{
+		OpBase:  dag.NewOpBase(AddCommitsOp, author, unixTime),
+		Commits: commits,
+	}
+}
+
+// AddCommits is a convenience function to reference commits in a pull request.
+// The commits already referenced are ignored, and no operation is created if
+// there is nothing new.
+func AddCommits(pr Interface, author identity.Interface, unixTime int64, commits []Commit, metadata map[string]string) (*AddCommitsOperation, error) {
+	snap := pr.Compile()
+
+	var added []Commit
+	for _, commit := range commits {
+		if !snap.HasCommit(commit.Hash) {
+			added = append(added, commit)
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	op := NewAddCommitsOp(author, unixTime, added)
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+	pr.Append(op)
+	return op, nil
+}
@@ -0,0 +1,97 @@
+package pullrequest
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/util/text"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &AddReviewOperation{}
+
+// AddReviewOperation will add a review, with its line comments, to a pull request
+type AddReviewOperation struct {
+	dag.OpBase
+	State    ReviewState     `json:"state"`
+	Message  string          `json:"message"`
+	Comments []ReviewComment `json:"comments"`
+}
+
+func (op *AddReviewOperation) Id() entity.Id {
+	return dag.IdOperation(op, &op.OpBase)
+}
+
+func (op *AddReviewOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author())
+	snapshot.addParticipant(op.Author())
+
+	opId := op.Id()
+
+	snapshot.Reviews = append(snapshot.Reviews, Review{
+		combinedId: entity.CombineIds(snapshot.Id(), opId),
+		targetId:   opId,
+		Author:     op.Author(),
+		State:      op.State,
+		Message:    op.Message,
+		Comments:   op.Comments,
+		unixTime:   timestamp.Timestamp(op.UnixTime),
+	})
+}
+
+func (op *AddReviewOperation) Validate() error {
+	if err := op.OpBase.Validate(op, AddReviewOp); err != nil {
+		return err
+	}
+
+	if err := op.State.Validate(); err != nil {
+		return errors.Wrap(err, "state")
+	}
+
+	if !text.Safe(op.Message) {
+		return fmt.Errorf("message is not fully printable")
+	}
+
+	for _, comment := range op.Comments {
+		if !text.SafeOneLine(comment.Path) {
+			return fmt.Errorf("review comment path has unsafe characters")
+		}
+		if comment.Line < 0 {
+			return fmt.Errorf("invalid review comment line")
+		}
+		if !text.Safe(comment.DiffHunk) {
+			return fmt.Errorf("review comment diff is not fully printable")
+		}
+		if !text.Safe(comment.Message) {
+			return fmt.Errorf("review comment message is not fully printable")
+		}
+	}
+
+	return nil
+}
+
+func NewAddReviewOp(author identity.Interface, unixTime int64, state ReviewState, message string, comments []ReviewComment) *AddReviewOperation {
+	return &AddReviewOperation{
+		OpBase:   dag.NewOpBase(AddReviewOp, author, unixTime),
+		State:    state,
+		Message:  message,
+		Comments: comments,
+	}
+}
+
+// AddReview is a convenience function to add a review to a pull request
+func AddReview(pr Interface, author identity.Interface, unixTime int64, state ReviewState, message string, comments []ReviewComment, metadata map[string]string) (entity.CombinedId, *AddReviewOperation, error) {
+	op := NewAddReviewOp(author, unixTime, state, message, comments)
+	for key, val := range metadata {
+		op.SetMetadata(key, val)
+	}
+	if err := op.Validate(); err != nil {
+		return entity.UnsetCombinedId, nil, err
+	}
+	pr.Append(op)
+	return entity.CombineIds(pr.Id(), op.Id()), op, nil
+}
@@ -0,0 +1,106 @@
+package pullrequest
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/util/text"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &CreateOperation{}
+
+// CreateOperation define the initial creation of a pull request
+type CreateOperation struct {
+	dag.OpBase
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	// SourceBranch is the branch holding the changes
+	SourceBranch string `json:"source"`
+	// TargetBranch is the branch the changes are proposed to be merged into
+	TargetBranch string `json:"target"`
+}
+
+func (op *CreateOperation) Id() entity.Id {
+	return dag.IdOperation(op, &op.OpBase)
+}
+
+func (op *CreateOperation) Apply(snapshot *Snapshot) {
+	// sanity check: will fail when adding a second Create
+	if snapshot.id != "" && snapshot.id != entity.UnsetId && snapshot.id != op.Id() {
+		return
+	}
+
+	// the Id of the PullRequest/Snapshot is the Id of the first Operation: CreateOperation
+	opId := op.Id()
+	snapshot.id = opId
+
+	snapshot.addActor(op.Author())
+	snapshot.addParticipant(op.Author())
+
+	snapshot.Title = op.Title
+	snapshot.SourceBranch = op.SourceBranch
+	snapshot.TargetBranch = op.TargetBranch
+
+	snapshot.Comments = []Comment{{
+		combinedId: entity.CombineIds(snapshot.id, opId),
+		targetId:   opId,
+		Message:    op.Message,
+		Author:     op.Author(),
+		unixTime:   timestamp.Timestamp(op.UnixTime),
+	}}
+	snapshot.Author = op.Author()
+	snapshot.CreateTime = op.Time()
+}
+
+func (op *CreateOperation) Validate() error {
+	if err := op.OpBase.Validate(op, CreateOp); err != nil {
+		return err
+	}
+
+	if text.Empty(op.Title) {
+		return fmt.Errorf("title is empty")
+	}
+	if !text.SafeOneLine(op.Title) {
+		return fmt.Errorf("title has unsafe characters")
+	}
+
+	if !text.Safe(op.Message) {
+		return fmt.Errorf("message is not fully printable")
+	}
+
+	if text.Empty(op.SourceBranch) || !text.SafeOneLine(op.SourceBranch) {
+		return fmt.Errorf("invalid source branch")
+	}
+	if text.Empty(op.TargetBranch) || !text.SafeOneLine(op.TargetBranch) {
+		return fmt.Errorf("invalid target branch")
+	}
+
+	return nil
+}
+
+func NewCreateOp(author identity.Interface, unixTime int64, title, message, sourceBranch, targetBranch string) *CreateOperation {
+	return &CreateOperation{
+		OpBase:       dag.NewOpBase(CreateOp, author, unixTime),
+		Title:        title,
+		Message:      message,
+		SourceBranch: sourceBranch,
+		TargetBranch: targetBranch,
+	}
+}
+
+// Create is a convenience function to create a pull request
+func Create(author identity.Interface, unixTime int64, title, message, sourceBranch, targetBranch string, metadata map[string]string) (*PullRequest, *CreateOperation, error) {
+	pr := NewPullRequest()
+	op := NewCreateOp(author, unixTime, title, message, sourceBranch, targetBranch)
+	for key, val := range metadata {
+		op.SetMetadata(key, val)
+	}
+	if err := op.Validate(); err != nil {
+		return nil, op, err
+	}
+	pr.Append(op)
+	return pr, op, nil
+}
@@ -0,0 +1,75 @@
+package pullrequest
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+)
+
+var _ Operation = &SetStatusOperation{}
+
+// SetStatusOperation will change the status of a pull request
+type SetStatusOperation struct {
+	dag.OpBase
+	Status Status `json:"status"`
+	// MergeCommit is the hash of the merge commit, for the merged status
+	MergeCommit string `json:"merge_commit,omitempty"`
+}
+
+func (op *SetStatusOperation) Id() entity.Id {
+	return dag.IdOperation(op, &op.OpBase)
+}
+
+func (op *SetStatusOperation) Apply(snapshot *Snapshot) {
+	snapshot.Status = op.Status
+	snapshot.MergeCommit = op.MergeCommit
+	snapshot.addActor(op.Author())
+}
+
+func (op *SetStatusOperation) Validate() error {
+	if err := op.OpBase.Validate(op, SetStatusOp); err != nil {
+		return err
+	}
+
+	if err := op.Status.Validate(); err != nil {
+		return errors.Wrap(err, "status")
+	}
+
+	if op.MergeCommit != "" {
+		if op.Status != MergedStatus {
+			return fmt.Errorf("merge commit on a non merged status")
+		}
+		if !commitHashRegexp.MatchString(op.MergeCommit) {
+			return fmt.Errorf("invalid merge commit hash %s", op.MergeCommit)
+		}
+	}
+
+	return nil
+}
+
+func NewSetStatusOp(author identity.Interface, unixTime int64, status Status, mergeCommit string) *SetStatusOperation {
+	return &SetStatusOperation{
+		OpBase:      dag.NewOpBase(SetStatusOp, author, unixTime),
+		Status:      status,
+		MergeCommit: mergeCommit,
+	}
+}
+
+// SetStatus is a convenience function to change the status of a pull request.
+// The merge commit is only meaningful for the merged status, and can be empty
+// if unknown.
+func SetStatus(pr Interface, author identity.Interface, unixTime int64, status Status, mergeCommit string, metadata map[string]string) (*SetStatusOperation, error) {
+	op := NewSetStatusOp(author, unixTime, status, mergeCommit)
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+	pr.Append(op)
+	return op, nil
+}
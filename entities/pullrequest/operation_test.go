@@ -0,0 +1,85 @@
+package pullrequest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const (
+	hash1 = "0123456789abcdef0123456789abcdef01234567"
+	hash2 = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+func TestValidate(t *testing.T) {
+	repo := repository.NewMockRepoClock()
+
+	rene, err := identity.NewIdentity(repo, "René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+
+	unix := time.Now().Unix()
+
+	good := []Operation{
+		NewCreateOp(rene, unix, "title", "message", "feature", "master"),
+		NewSetTitleOp(rene, unix, "title2", "title1"),
+		NewAddCommentOp(rene, unix, "message2"),
+		NewAddCommitsOp(rene, unix, []Commit{{Hash: hash1, Message: "fix the thing"}}),
+		NewAddReviewOp(rene, unix, ReviewApproved, "LGTM", nil),
+		NewAddReviewOp(rene, unix, ReviewChangesRequested, "", []ReviewComment{
+			{Path: "main.go", Line: 12, DiffHunk: "@@ -10,3 +10,4 @@\n+foo", Message: "typo"},
+		}),
+		NewSetStatusOp(rene, unix, MergedStatus, hash2),
+		NewSetStatusOp(rene, unix, ClosedStatus, ""),
+	}
+
+	for _, op := range good {
+		require.NoError(t, op.Validate())
+	}
+
+	bad := []Operation{
+		NewCreateOp(rene, unix, "multi\nline", "message", "feature", "master"),
+		NewCreateOp(rene, unix, "title", "message", "", "master"),
+		NewCreateOp(rene, unix, "title", "message", "feature", "mas\nter"),
+		NewAddCommitsOp(rene, unix, nil),
+		NewAddCommitsOp(rene, unix, []Commit{{Hash: "invalid"}}),
+		NewAddCommitsOp(rene, unix, []Commit{{Hash: hash1, Message: "multi\nline"}}),
+		NewAddReviewOp(rene, unix, 0, "message", nil),
+		NewAddReviewOp(rene, unix, ReviewCommented, "message", []ReviewComment{{Path: "main.go", Line: -1}}),
+		NewSetStatusOp(rene, unix, 1000, ""),
+		NewSetStatusOp(rene, unix, ClosedStatus, hash1),
+		NewSetStatusOp(rene, unix, MergedStatus, "invalid"),
+	}
+
+	for i, op := range bad {
+		require.Error(t, op.Validate(), "validation should have failed: %d", i)
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	dag.SerializeRoundTripTest(t, operationUnmarshaler, func(author identity.Interface, unixTime int64) (*CreateOperation, entity.Resolvers) {
+		return NewCreateOp(author, unixTime, "title", "message", "feature", "master"), nil
+	})
+	dag.SerializeRoundTripTest(t, operationUnmarshaler, func(author identity.Interface, unixTime int64) (*SetTitleOperation, entity.Resolvers) {
+		return NewSetTitleOp(author, unixTime, "title", "was"), nil
+	})
+	dag.SerializeRoundTripTest(t, operationUnmarshaler, func(author identity.Interface, unixTime int64) (*AddCommentOperation, entity.Resolvers) {
+		return NewAddCommentOp(author, unixTime, "message"), nil
+	})
+	dag.SerializeRoundTripTest(t, operationUnmarshaler, func(author identity.Interface, unixTime int64) (*AddCommitsOperation, entity.Resolvers) {
+		return NewAddCommitsOp(author, unixTime, []Commit{{Hash: hash1, Message: "fix"}}), nil
+	})
+	dag.SerializeRoundTripTest(t, operationUnmarshaler, func(author identity.Interface, unixTime int64) (*AddReviewOperation, entity.Resolvers) {
+		return NewAddReviewOp(author, unixTime, ReviewChangesRequested, "message", []ReviewComment{
+			{Path: "main.go", Line: 12, DiffHunk: "@@ -10,3 +10,4 @@", Message: "typo"},
+		}), nil
+	})
+	dag.SerializeRoundTripTest(t, operationUnmarshaler, func(author identity.Interface, unixTime int64) (*SetStatusOperation, entity.Resolvers) {
+		return NewSetStatusOp(author, unixTime, MergedStatus, hash2), nil
+	})
+}
@@ -0,0 +1,154 @@
+// Package pullrequest contains the pull request data model and low-level
+// related functions. Pull requests are imported from the bridges to keep the
+// history of the code reviews offline, along with the bugs.
+package pullrequest
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+var _ Interface = &PullRequest{}
+var _ entity.Interface = &PullRequest{}
+
+// 1: original format
+const formatVersion = 1
+
+const Typename = "pullrequest"
+const Namespace = "pullrequests"
+
+var def = dag.Definition{
+	Typename:             Typename,
+	Namespace:            Namespace,
+	OperationUnmarshaler: operationUnmarshaler,
+	FormatVersion:        formatVersion,
+}
+
+var ClockLoader = dag.ClockLoader(def)
+
+type Interface interface {
+	dag.Interface[*Snapshot, Operation]
+}
+
+// PullRequest holds the data of a pull request and of its reviews, organized
+// in a way close to how it will be persisted inside Git.
+type PullRequest struct {
+	*dag.Entity
+}
+
+// NewPullRequest create a new PullRequest
+func NewPullRequest() *PullRequest {
+	return wrapper(dag.New(def))
+}
+
+func wrapper(e *dag.Entity) *PullRequest {
+	return &PullRequest{Entity: e}
+}
+
+func simpleResolvers(repo repository.ClockedRepo) entity.Resolvers {
+	return entity.Resolvers{
+		&identity.Identity{}: identity.NewSimpleResolver(repo),
+	}
+}
+
+// Read will read a pull request from a repository
+func Read(repo repository.ClockedRepo, id entity.Id) (*PullRequest, error) {
+	return ReadWithResolver(repo, simpleResolvers(repo), id)
+}
+
+// ReadWithResolver will read a pull request from its Id, with custom resolvers
+func ReadWithResolver(repo repository.ClockedRepo, resolvers entity.Resolvers, id entity.Id) (*PullRequest, error) {
+	return dag.Read(def, wrapper, repo, resolvers, id)
+}
+
+// ReadAll read and parse all local pull requests
+func ReadAll(repo repository.ClockedRepo) <-chan entity.StreamedEntity[*PullRequest] {
+	return dag.ReadAll(def, wrapper, repo, simpleResolvers(repo))
+}
+
+// ReadAllWithResolver read and parse all local pull requests
+func ReadAllWithResolver(repo repository.ClockedRepo, resolvers entity.Resolvers) <-chan entity.StreamedEntity[*PullRequest] {
+	return dag.ReadAll(def, wrapper, repo, resolvers)
+}
+
+// ListLocalIds list all the available local pull request ids
+func ListLocalIds(repo repository.Repo) ([]entity.Id, error) {
+	return dag.ListLocalIds(def, repo)
+}
+
+// Validate check if the PullRequest data is valid
+func (pr *PullRequest) Validate() error {
+	if err := pr.Entity.Validate(); err != nil {
+		return err
+	}
+
+	// The very first Op should be a CreateOp
+	firstOp := pr.FirstOp()
+	if firstOp == nil || firstOp.Type() != CreateOp {
+		return fmt.Errorf("first operation should be a Create op")
+	}
+
+	// Check that there is no more CreateOp op
+	for i, op := range pr.Entity.Operations() {
+		if i == 0 {
+			continue
+		}
+		if op.Type() == CreateOp {
+			return fmt.Errorf("only one Create op allowed")
+		}
+	}
+
+	return nil
+}
+
+// Append add a new Operation to the PullRequest
+func (pr *PullRequest) Append(op Operation) {
+	pr.Entity.Append(op)
+}
+
+// Operations return the ordered operations
+func (pr *PullRequest) Operations() []Operation {
+	source := pr.Entity.Operations()
+	result := make([]Operation, len(source))
+	for i, op := range source {
+		result[i] = op.(Operation)
+	}
+	return result
+}
+
+// Compile a pull request in an easily usable snapshot
+func (pr *PullRequest) Compile() *Snapshot {
+	snap := &Snapshot{
+		id:     pr.Id(),
+		Status: OpenStatus,
+	}
+
+	for _, op := range pr.Operations() {
+		op.Apply(snap)
+		snap.Operations = append(snap.Operations, op)
+	}
+
+	return snap
+}
+
+// FirstOp lookup for the very first operation of the pull request.
+// For a valid PullRequest, this operation should be a CreateOp
+func (pr *PullRequest) FirstOp() Operation {
+	if fo := pr.Entity.FirstOp(); fo != nil {
+		return fo.(Operation)
+	}
+	return nil
+}
+
+// LastOp lookup for the very last operation of the pull request.
+// For a valid PullRequest, should never be nil
+func (pr *PullRequest) LastOp() Operation {
+	if lo := pr.Entity.LastOp(); lo != nil {
+		return lo.(Operation)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package pullrequest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestPullRequestCompileAndRead(t *testing.T) {
+	repo := repository.NewMockRepo()
+
+	rene, err := identity.NewIdentity(repo, "René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, rene.Commit(repo))
+
+	unix := time.Now().Unix()
+
+	pr, _, err := Create(rene, unix, "add a feature", "description", "feature", "master", nil)
+	require.NoError(t, err)
+
+	_, err = AddCommits(pr, rene, unix, []Commit{{Hash: hash1, Message: "first"}}, nil)
+	require.NoError(t, err)
+	// already referenced commits are ignored
+	op, err := AddCommits(pr, rene, unix, []Commit{{Hash: hash1, Message: "first"}}, nil)
+	require.NoError(t, err)
+	require.Nil(t, op)
+
+	_, _, err = AddComment(pr, rene, unix, "a comment", nil)
+	require.NoError(t, err)
+	_, _, err = AddReview(pr, rene, unix, ReviewChangesRequested, "please fix", []ReviewComment{
+		{Path: "main.go", Line: 3, Message: "typo"},
+	}, nil)
+	require.NoError(t, err)
+	_, err = SetTitle(pr, rene, unix, "add a better feature", nil)
+	require.NoError(t, err)
+	_, err = SetStatus(pr, rene, unix, MergedStatus, hash2, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, pr.Commit(repo))
+
+	read, err := Read(repo, pr.Id())
+	require.NoError(t, err)
+
+	snap := read.Compile()
+	require.Equal(t, pr.Id(), snap.Id())
+	require.Equal(t, "add a better feature", snap.Title)
+	require.Equal(t, "feature", snap.SourceBranch)
+	require.Equal(t, "master", snap.TargetBranch)
+	require.Equal(t, MergedStatus, snap.Status)
+	require.Equal(t, hash2, snap.MergeCommit)
+	require.Equal(t, []Commit{{Hash: hash1, Message: "first"}}, snap.Commits)
+	require.Len(t, snap.Comments, 2)
+	require.Equal(t, "description", snap.Comments[0].Message)
+	require.Len(t, snap.Reviews, 1)
+	require.Equal(t, ReviewChangesRequested, snap.Reviews[0].State)
+	require.Equal(t, "typo", snap.Reviews[0].Comments[0].Message)
+	require.Len(t, snap.Operations, 6)
+
+	ids, err := ListLocalIds(repo)
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+}
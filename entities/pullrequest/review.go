@@ -0,0 +1,136 @@
+package pullrequest
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+// ReviewState is the outcome of a review
+type ReviewState int
+
+const (
+	_ ReviewState = iota
+	ReviewCommented
+	ReviewApproved
+	ReviewChangesRequested
+	ReviewDismissed
+)
+
+func (s ReviewState) String() string {
+	switch s {
+	case ReviewCommented:
+		return "commented"
+	case ReviewApproved:
+		return "approved"
+	case ReviewChangesRequested:
+		return "changes requested"
+	case ReviewDismissed:
+		return "dismissed"
+	default:
+		return "unknown review state"
+	}
+}
+
+func (s ReviewState) Validate() error {
+	if s < ReviewCommented || s > ReviewDismissed {
+		return fmt.Errorf("invalid")
+	}
+	return nil
+}
+
+func (s ReviewState) MarshalGQL(w io.Writer) {
+	switch s {
+	case ReviewCommented:
+		_, _ = fmt.Fprintf(w, strconv.Quote("COMMENTED"))
+	case ReviewApproved:
+		_, _ = fmt.Fprintf(w, strconv.Quote("APPROVED"))
+	case ReviewChangesRequested:
+		_, _ = fmt.Fprintf(w, strconv.Quote("CHANGES_REQUESTED"))
+	case ReviewDismissed:
+		_, _ = fmt.Fprintf(w, strconv.Quote("DISMISSED"))
+	default:
+		panic("missing case")
+	}
+}
+
+func (s *ReviewState) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	switch str {
+	case "COMMENTED":
+		*s = ReviewCommented
+	case "APPROVED":
+		*s = ReviewApproved
+	case "CHANGES_REQUESTED":
+		*s = ReviewChangesRequested
+	case "DISMISSED":
+		*s = ReviewDismissed
+	default:
+		return fmt.Errorf("%s is not a valid ReviewState", str)
+	}
+	return nil
+}
+
+// ReviewComment is a comment made during a review on a specific line of the
+// changes.
+type ReviewComment struct {
+	Path string `json:"path"`
+	// Line is the line of the file the comment applies to, or 0 if unknown
+	Line int `json:"line,omitempty"`
+	// DiffHunk is the part of the diff the comment applies to
+	DiffHunk string `json:"diff_hunk,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Review represent a review of a PullRequest, with its line comments
+type Review struct {
+	// combinedId should be the result of entity.CombineIds with the PullRequest id and the id
+	// of the Operation that created the review
+	combinedId entity.CombinedId
+
+	// targetId is the Id of the Operation that created that Review
+	targetId entity.Id
+
+	Author   identity.Interface
+	State    ReviewState
+	Message  string
+	Comments []ReviewComment
+
+	// Creation time of the review.
+	// Should be used only for human display, never for ordering as we can't rely on it in a distributed system.
+	unixTime timestamp.Timestamp
+}
+
+func (r Review) CombinedId() entity.CombinedId {
+	if r.combinedId == "" {
+		// simply panic as it would be a coding error (no id provided at construction)
+		panic("no combined id")
+	}
+	return r.combinedId
+}
+
+func (r Review) TargetId() entity.Id {
+	return r.targetId
+}
+
+// UnixTime return the creation time of the review
+func (r Review) UnixTime() timestamp.Timestamp {
+	return r.unixTime
+}
+
+// FormatTimeRel format the unixTime of the review for human consumption
+func (r Review) FormatTimeRel() string {
+	return humanize.Time(r.unixTime.Time())
+}
+
+// IsAuthored is a sign post method for gqlgen
+func (r Review) IsAuthored() {}
@@ -0,0 +1,141 @@
+package pullrequest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/entity/dag"
+)
+
+var _ dag.Snapshot = &Snapshot{}
+
+// Snapshot is a compiled form of the PullRequest data structure used for storage and merge
+type Snapshot struct {
+	id entity.Id
+
+	Status       Status
+	Title        string
+	SourceBranch string
+	TargetBranch string
+	// MergeCommit is the hash of the commit that merged the pull request, if any
+	MergeCommit  string
+	Commits      []Commit
+	Comments     []Comment
+	Reviews      []Review
+	Author       identity.Interface
+	Actors       []identity.Interface
+	Participants []identity.Interface
+	CreateTime   time.Time
+
+	Operations []dag.Operation
+}
+
+// Id returns the PullRequest identifier
+func (snap *Snapshot) Id() entity.Id {
+	if snap.id == "" {
+		// simply panic as it would be a coding error (no id provided at construction)
+		panic("no id")
+	}
+	return snap.id
+}
+
+func (snap *Snapshot) AllOperations() []dag.Operation {
+	return snap.Operations
+}
+
+func (snap *Snapshot) AppendOperation(op dag.Operation) {
+	snap.Operations = append(snap.Operations, op)
+}
+
+// EditTime returns the last time a pull request was modified
+func (snap *Snapshot) EditTime() time.Time {
+	if len(snap.Operations) == 0 {
+		return time.Unix(0, 0)
+	}
+
+	return snap.Operations[len(snap.Operations)-1].Time()
+}
+
+// GetCreateMetadata return the creation metadata
+func (snap *Snapshot) GetCreateMetadata(key string) (string, bool) {
+	return snap.Operations[0].GetMetadata(key)
+}
+
+// SearchComment will search for a comment matching the given id
+func (snap *Snapshot) SearchComment(id entity.CombinedId) (*Comment, error) {
+	for _, c := range snap.Comments {
+		if c.combinedId == id {
+			return &c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("comment not found")
+}
+
+// SearchReview will search for a review matching the given id
+func (snap *Snapshot) SearchReview(id entity.CombinedId) (*Review, error) {
+	for _, r := range snap.Reviews {
+		if r.combinedId == id {
+			return &r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("review not found")
+}
+
+// HasCommit return true if the commit is already referenced by the pull request
+func (snap *Snapshot) HasCommit(hash string) bool {
+	for _, c := range snap.Commits {
+		if c.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// append the operation author to the actors list
+func (snap *Snapshot) addActor(actor identity.Interface) {
+	for _, a := range snap.Actors {
+		if actor.Id() == a.Id() {
+			return
+		}
+	}
+
+	snap.Actors = append(snap.Actors, actor)
+}
+
+// append the operation author to the participants list
+func (snap *Snapshot) addParticipant(participant identity.Interface) {
+	for _, p := range snap.Participants {
+		if participant.Id() == p.Id() {
+			return
+		}
+	}
+
+	snap.Participants = append(snap.Participants, participant)
+}
+
+// HasParticipant return true if the id is a participant
+func (snap *Snapshot) HasParticipant(id entity.Id) bool {
+	for _, p := range snap.Participants {
+		if p.Id() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HasActor return true if the id is a actor
+func (snap *Snapshot) HasActor(id entity.Id) bool {
+	for _, p := range snap.Actors {
+		if p.Id() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAuthored is a sign post method for gqlgen
+func (snap *Snapshot) IsAuthored() {}
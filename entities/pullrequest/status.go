@@ -0,0 +1,99 @@
+package pullrequest
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Status is the state of a pull request. Contrary to a bug, a pull request can
+// be closed by merging it.
+type Status int
+
+const (
+	_ Status = iota
+	OpenStatus
+	ClosedStatus
+	MergedStatus
+)
+
+func (s Status) String() string {
+	switch s {
+	case OpenStatus:
+		return "open"
+	case ClosedStatus:
+		return "closed"
+	case MergedStatus:
+		return "merged"
+	default:
+		return "unknown status"
+	}
+}
+
+func (s Status) Action() string {
+	switch s {
+	case OpenStatus:
+		return "opened"
+	case ClosedStatus:
+		return "closed"
+	case MergedStatus:
+		return "merged"
+	default:
+		return "unknown status"
+	}
+}
+
+func StatusFromString(str string) (Status, error) {
+	cleaned := strings.ToLower(strings.TrimSpace(str))
+
+	switch cleaned {
+	case "open":
+		return OpenStatus, nil
+	case "closed":
+		return ClosedStatus, nil
+	case "merged":
+		return MergedStatus, nil
+	default:
+		return 0, fmt.Errorf("unknown status")
+	}
+}
+
+func (s Status) Validate() error {
+	if s != OpenStatus && s != ClosedStatus && s != MergedStatus {
+		return fmt.Errorf("invalid")
+	}
+
+	return nil
+}
+
+func (s Status) MarshalGQL(w io.Writer) {
+	switch s {
+	case OpenStatus:
+		_, _ = fmt.Fprintf(w, strconv.Quote("OPEN"))
+	case ClosedStatus:
+		_, _ = fmt.Fprintf(w, strconv.Quote("CLOSED"))
+	case MergedStatus:
+		_, _ = fmt.Fprintf(w, strconv.Quote("MERGED"))
+	default:
+		panic("missing case")
+	}
+}
+
+func (s *Status) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	switch str {
+	case "OPEN":
+		*s = OpenStatus
+	case "CLOSED":
+		*s = ClosedStatus
+	case "MERGED":
+		*s = MergedStatus
+	default:
+		return fmt.Errorf("%s is not a valid Status", str)
+	}
+	return nil
+}
@@ -45,6 +45,8 @@ type Entity struct {
 
 	// operations that are already stored in the repository
 	ops []Operation
+	// the Lamport edit time of the operation pack each stored operation belongs to
+	opsEditTime map[entity.Id]lamport.Time
 	// operations not yet stored in the repository
 	staging []Operation
 
@@ -221,11 +223,13 @@ func read[EntityT entity.Interface](def Definition, wrapper func(e *Entity) Enti
 	// Now that we ordered the operationPacks, we have the order of the Operations
 
 	ops := make([]Operation, 0, opsCount)
+	opsEditTime := make(map[entity.Id]lamport.Time, opsCount)
 	var createTime lamport.Time
 	var editTime lamport.Time
 	for _, pack := range oppSlice {
 		for _, operation := range pack.Operations {
 			ops = append(ops, operation)
+			opsEditTime[operation.Id()] = pack.EditTime
 		}
 		if pack.CreateTime > createTime {
 			createTime = pack.CreateTime
@@ -236,11 +240,12 @@ func read[EntityT entity.Interface](def Definition, wrapper func(e *Entity) Enti
 	}
 
 	return wrapper(&Entity{
-		Definition: def,
-		ops:        ops,
-		lastCommit: rootHash,
-		createTime: createTime,
-		editTime:   editTime,
+		Definition:  def,
+		ops:         ops,
+		opsEditTime: opsEditTime,
+		lastCommit:  rootHash,
+		createTime:  createTime,
+		editTime:    editTime,
 	}), nil
 }
 
@@ -499,6 +504,12 @@ func (e *Entity) Commit(repo repository.ClockedRepo) error {
 
 		e.lastCommit = commitHash
 		e.ops = append(e.ops, toCommit...)
+		if e.opsEditTime == nil {
+			e.opsEditTime = make(map[entity.Id]lamport.Time)
+		}
+		for _, op := range toCommit {
+			e.opsEditTime[op.Id()] = e.editTime
+		}
 	}
 
 	// not strictly necessary but make equality testing easier in tests
@@ -520,3 +531,10 @@ func (e *Entity) CreateLamportTime() lamport.Time {
 func (e *Entity) EditLamportTime() lamport.Time {
 	return e.editTime
 }
+
+// OperationEditLamportTime return the Lamport edit time of the commit an
+// operation has been stored with, or false if the operation is not stored yet
+func (e *Entity) OperationEditLamportTime(id entity.Id) (lamport.Time, bool) {
+	time, ok := e.opsEditTime[id]
+	return time, ok
+}
@@ -49,6 +49,39 @@ func TestWriteReadMultipleAuthor(t *testing.T) {
 	assertEqualEntities(t, entity.Entity, read.Entity)
 }
 
+func TestOperationEditLamportTime(t *testing.T) {
+	repo, id1, id2, resolver, def := makeTestContext()
+
+	entity := wrapper(New(def))
+
+	op1 := newOp1(id1, "foo")
+	entity.Append(op1)
+	_, ok := entity.OperationEditLamportTime(op1.Id())
+	require.False(t, ok)
+	require.NoError(t, entity.Commit(repo))
+
+	op2 := newOp2(id2, "bar")
+	entity.Append(op2)
+	require.NoError(t, entity.Commit(repo))
+
+	time1, ok := entity.OperationEditLamportTime(op1.Id())
+	require.True(t, ok)
+	time2, ok := entity.OperationEditLamportTime(op2.Id())
+	require.True(t, ok)
+	require.Less(t, time1, time2)
+	require.Equal(t, entity.EditLamportTime(), time2)
+
+	read, err := Read(def, wrapper, repo, resolver, entity.Id())
+	require.NoError(t, err)
+
+	readTime1, ok := read.OperationEditLamportTime(op1.Id())
+	require.True(t, ok)
+	require.Equal(t, time1, readTime1)
+	readTime2, ok := read.OperationEditLamportTime(op2.Id())
+	require.True(t, ok)
+	require.Equal(t, time2, readTime2)
+}
+
 func assertEqualEntities(t *testing.T, a, b *Entity) {
 	t.Helper()
 
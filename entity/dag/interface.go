@@ -44,4 +44,8 @@ type Interface[SnapT Snapshot, OpT Operation] interface {
 
 	// EditLamportTime return the Lamport time of the last edit
 	EditLamportTime() lamport.Time
+
+	// OperationEditLamportTime return the Lamport edit time of the commit an
+	// operation has been stored with, or false if the operation is not stored yet
+	OperationEditLamportTime(id entity.Id) (lamport.Time, bool)
 }
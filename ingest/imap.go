@@ -0,0 +1,239 @@
+package ingest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+var keyringUnsafeRegexp = regexp.MustCompile(`[^a-zA-Z0-9.-]`)
+
+// imapPasswordKey is the keyring key of the password of an IMAP account
+func imapPasswordKey(username, addr string) string {
+	return "imap-password-" + keyringUnsafeRegexp.ReplaceAllString(username+"-"+addr, "_")
+}
+
+// StoreIMAPPassword store the password of an IMAP account in the keyring
+func StoreIMAPPassword(repo repository.RepoKeyring, username, addr, password string) error {
+	return repo.Keyring().Set(repository.Item{
+		Key:  imapPasswordKey(username, addr),
+		Data: []byte(password),
+	})
+}
+
+// LoadIMAPPassword return the password of an IMAP account stored in the
+// keyring, or an empty string
+func LoadIMAPPassword(repo repository.RepoKeyring, username, addr string) (string, error) {
+	item, err := repo.Keyring().Get(imapPasswordKey(username, addr))
+	if errors.Is(err, repository.ErrKeyringKeyNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(item.Data), nil
+}
+
+// IMAPConfig is the IMAP mailbox to read the emails from
+type IMAPConfig struct {
+	// Addr is the host and port of the server
+	Addr     string
+	Username string
+	Password string
+	// Mailbox default to INBOX
+	Mailbox string
+	// Insecure connect without TLS
+	Insecure bool
+}
+
+// ErrSkipMessage can be returned by the callback of ReadIMAP to leave an
+// email unread and continue with the next one
+var ErrSkipMessage = errors.New("skip this message")
+
+// ReadIMAP call fn with each unread email of an IMAP mailbox. An email is
+// marked as read when fn succeed.
+func ReadIMAP(conf IMAPConfig, fn func(raw []byte) error) error {
+	c, err := dialIMAP(conf)
+	if err != nil {
+		return err
+	}
+	defer c.close()
+
+	if _, err := c.command("LOGIN %s %s", imapQuote(conf.Username), imapQuote(conf.Password)); err != nil {
+		return fmt.Errorf("IMAP login: %w", err)
+	}
+
+	mailbox := conf.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	if _, err := c.command("SELECT %s", imapQuote(mailbox)); err != nil {
+		return fmt.Errorf("IMAP select %s: %w", mailbox, err)
+	}
+
+	responses, err := c.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return err
+	}
+	var uids []string
+	for _, r := range responses {
+		if fields := strings.Fields(r.line); len(fields) > 2 && fields[0] == "*" && strings.EqualFold(fields[1], "SEARCH") {
+			uids = append(uids, fields[2:]...)
+		}
+	}
+
+	for _, uid := range uids {
+		responses, err := c.command("UID FETCH %s (BODY.PEEK[])", uid)
+		if err != nil {
+			return err
+		}
+
+		var raw []byte
+		for _, r := range responses {
+			if strings.Contains(strings.ToUpper(r.line), "FETCH") && len(r.literals) > 0 {
+				raw = r.literals[0]
+			}
+		}
+		if raw == nil {
+			continue
+		}
+
+		err = fn(raw)
+		if errors.Is(err, ErrSkipMessage) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.command(`UID STORE %s +FLAGS.SILENT (\Seen)`, uid); err != nil {
+			return err
+		}
+	}
+
+	_, err = c.command("LOGOUT")
+	return err
+}
+
+type imapClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// imapResponse is a response line, with the literals it contains
+type imapResponse struct {
+	line     string
+	literals [][]byte
+}
+
+const imapTimeout = time.Minute
+
+func dialIMAP(conf IMAPConfig) (*imapClient, error) {
+	host, _, err := net.SplitHostPort(conf.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMAP address: %w", err)
+	}
+
+	dialer := &net.Dialer{Timeout: imapTimeout}
+	var conn net.Conn
+	if conf.Insecure {
+		conn, err = dialer.Dial("tcp", conf.Addr)
+	} else {
+		conn, err = tls.DialWithDialer(dialer, "tcp", conf.Addr, &tls.Config{ServerName: host})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &imapClient{conn: conn, r: bufio.NewReader(conn)}
+
+	greeting, err := c.readResponse()
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(strings.ToUpper(greeting.line), "* OK") && !strings.HasPrefix(strings.ToUpper(greeting.line), "* PREAUTH") {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected IMAP greeting: %s", greeting.line)
+	}
+
+	return c, nil
+}
+
+func (c *imapClient) close() {
+	_ = c.conn.Close()
+}
+
+// command send a command and return its untagged responses
+func (c *imapClient) command(format string, args ...interface{}) ([]imapResponse, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+
+	_ = c.conn.SetDeadline(time.Now().Add(imapTimeout))
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, err
+	}
+
+	var responses []imapResponse
+	for {
+		r, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(r.line, tag+" ") {
+			responses = append(responses, r)
+			continue
+		}
+		status := strings.TrimPrefix(r.line, tag+" ")
+		if !strings.HasPrefix(strings.ToUpper(status), "OK") {
+			return nil, fmt.Errorf("IMAP error: %s", status)
+		}
+		return responses, nil
+	}
+}
+
+var imapLiteralRegexp = regexp.MustCompile(`\{(\d+)\}$`)
+
+// readResponse read a response line, and the literals it announce
+func (c *imapClient) readResponse() (imapResponse, error) {
+	var r imapResponse
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return r, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		r.line += line
+
+		match := imapLiteralRegexp.FindStringSubmatch(line)
+		if match == nil {
+			return r, nil
+		}
+		size, err := strconv.Atoi(match[1])
+		if err != nil {
+			return r, err
+		}
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return r, err
+		}
+		r.literals = append(r.literals, literal)
+	}
+}
+
+// imapQuote format a string for an IMAP command
+func imapQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
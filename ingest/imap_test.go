@@ -0,0 +1,158 @@
+package ingest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// imapServer is a minimal IMAP server for the tests
+type imapServer struct {
+	listener net.Listener
+	messages map[string]string
+
+	mu     sync.Mutex
+	seen   []string
+	logins []string
+}
+
+func newIMAPServer(t *testing.T, messages map[string]string) *imapServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	s := &imapServer{listener: l, messages: messages}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *imapServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	_, _ = fmt.Fprint(conn, "* OK test server ready\r\n")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, command, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		fields := strings.Fields(command)
+
+		switch {
+		case fields[0] == "LOGIN":
+			s.mu.Lock()
+			s.logins = append(s.logins, command)
+			s.mu.Unlock()
+			if fields[2] != `"secret"` {
+				_, _ = fmt.Fprintf(conn, "%s NO invalid credentials\r\n", tag)
+				continue
+			}
+		case fields[0] == "SELECT":
+			_, _ = fmt.Fprintf(conn, "* %d EXISTS\r\n", len(s.messages))
+		case command == "UID SEARCH UNSEEN":
+			var uids []string
+			for uid := range s.messages {
+				uids = append(uids, uid)
+			}
+			sort.Strings(uids)
+			_, _ = fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case fields[0] == "UID" && fields[1] == "FETCH":
+			raw := s.messages[fields[2]]
+			_, _ = fmt.Fprintf(conn, "* 1 FETCH (UID %s BODY[] {%d}\r\n%s)\r\n", fields[2], len(raw), raw)
+		case fields[0] == "UID" && fields[1] == "STORE":
+			s.mu.Lock()
+			s.seen = append(s.seen, fields[2])
+			s.mu.Unlock()
+		case fields[0] == "LOGOUT":
+			_, _ = fmt.Fprintf(conn, "* BYE\r\n%s OK done\r\n", tag)
+			return
+		}
+		_, _ = fmt.Fprintf(conn, "%s OK done\r\n", tag)
+	}
+}
+
+func TestReadIMAP(t *testing.T) {
+	server := newIMAPServer(t, map[string]string{
+		"7": "Subject: first\r\n\r\nhello\r\n",
+		"9": "Subject: second\r\n\r\nworld\r\n",
+	})
+
+	conf := IMAPConfig{
+		Addr:     server.listener.Addr().String(),
+		Username: `ada"lovelace`,
+		Password: "secret",
+		Insecure: true,
+	}
+
+	var raws []string
+	err := ReadIMAP(conf, func(raw []byte) error {
+		raws = append(raws, string(raw))
+		if strings.Contains(string(raw), "second") {
+			return ErrSkipMessage
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Subject: first\r\n\r\nhello\r\n",
+		"Subject: second\r\n\r\nworld\r\n",
+	}, raws)
+
+	server.mu.Lock()
+	require.Equal(t, []string{`LOGIN "ada\"lovelace" "secret"`}, server.logins)
+	// the skipped email stay unread
+	require.Equal(t, []string{"7"}, server.seen)
+	server.mu.Unlock()
+
+	// an error stop the reading, without marking the email as read
+	err = ReadIMAP(conf, func(raw []byte) error {
+		return errors.New("failure")
+	})
+	require.EqualError(t, err, "failure")
+
+	conf.Password = "wrong"
+	err = ReadIMAP(conf, func(raw []byte) error {
+		return nil
+	})
+	require.ErrorContains(t, err, "invalid credentials")
+
+	server.mu.Lock()
+	require.Equal(t, []string{"7"}, server.seen)
+	server.mu.Unlock()
+}
+
+func TestIMAPPassword(t *testing.T) {
+	repo := repository.NewMockRepo()
+
+	password, err := LoadIMAPPassword(repo, "ada@example.com", "imap.example.com:993")
+	require.NoError(t, err)
+	require.Empty(t, password)
+
+	require.NoError(t, StoreIMAPPassword(repo, "ada@example.com", "imap.example.com:993", "secret"))
+
+	password, err = LoadIMAPPassword(repo, "ada@example.com", "imap.example.com:993")
+	require.NoError(t, err)
+	require.Equal(t, "secret", password)
+
+	password, err = LoadIMAPPassword(repo, "rene@example.com", "imap.example.com:993")
+	require.NoError(t, err)
+	require.Empty(t, password)
+}
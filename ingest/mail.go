@@ -0,0 +1,524 @@
+// Package ingest create bugs and comments from messages received outside of
+// git-bug, like emails.
+package ingest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+const (
+	// MetaKeyMessageId is the metadata key holding the Message-ID of the
+	// email an operation was created from
+	MetaKeyMessageId = "mail-message-id"
+	// MetaKeyEmail is the metadata key holding the email of an identity
+	// created for an unknown sender
+	MetaKeyEmail = "mail-email"
+)
+
+// MailEvent is what happened to an ingested email
+type MailEvent int
+
+const (
+	_ MailEvent = iota
+	// MailEventBug: a new bug was created
+	MailEventBug
+	// MailEventComment: a comment was added to an existing bug
+	MailEventComment
+	// MailEventDuplicate: the email was already ingested
+	MailEventDuplicate
+	// MailEventError: the email couldn't be ingested
+	MailEventError
+)
+
+// MailResult is the outcome of the ingestion of an email
+type MailResult struct {
+	Event     MailEvent
+	MessageId string
+	Subject   string
+	BugId     entity.Id
+	Err       error
+}
+
+func (r MailResult) String() string {
+	switch r.Event {
+	case MailEventBug:
+		if r.BugId == "" {
+			// in a dry-run
+			return fmt.Sprintf("new bug: %s", r.Subject)
+		}
+		return fmt.Sprintf("new bug %s: %s", r.BugId.Human(), r.Subject)
+	case MailEventComment:
+		return fmt.Sprintf("new comment on bug %s: %s", r.BugId.Human(), r.Subject)
+	case MailEventDuplicate:
+		return fmt.Sprintf("already ingested %s: %s", r.MessageId, r.Subject)
+	case MailEventError:
+		return fmt.Sprintf("error %s: %v", r.MessageId, r.Err)
+	default:
+		panic("unknown mail event")
+	}
+}
+
+// MailIngester create bugs from emails, or comments when they reply to a
+// known bug.
+//
+// A reply is matched to its bug with the In-Reply-To and References
+// headers, pointing to an ingested email or to a notification of git-bug,
+// or else with a [#HUMAN_ID] tag in the subject. The senders are matched to
+// the identities by email, an identity is created for unknown senders.
+type MailIngester struct {
+	repo *cache.RepoCache
+	// DryRun parse the emails and find their bug, without writing anything
+	DryRun bool
+
+	// identities by lowercase email, loaded when needed
+	identities map[string]*cache.IdentityCache
+	// bugs by Message-ID of the ingested emails, loaded when needed
+	messages map[string]entity.Id
+}
+
+func NewMailIngester(repo *cache.RepoCache) *MailIngester {
+	return &MailIngester{repo: repo}
+}
+
+// notificationIdRegexp match the Message-ID of the notifications about a bug
+var notificationIdRegexp = regexp.MustCompile(`^<bug\.([0-9a-f]{64})(\.\d+)?@git-bug>$`)
+
+// subjectTagRegexp match the [#HUMAN_ID] tag of a subject
+var subjectTagRegexp = regexp.MustCompile(`\[#([0-9a-f]{4,64})\]`)
+
+// replyPrefixRegexp match the prefixes added to the subject of replies and
+// forwards
+var replyPrefixRegexp = regexp.MustCompile(`(?i)^\s*((re|fw|fwd|aw|tr)\s*(\[\d+\])?\s*:\s*)+`)
+
+// Ingest create a bug or a comment from a RFC 5322 message
+func (in *MailIngester) Ingest(raw []byte) MailResult {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return MailResult{Event: MailEventError, Err: fmt.Errorf("invalid email: %w", err)}
+	}
+
+	messageId := strings.TrimSpace(msg.Header.Get("Message-Id"))
+	if messageId == "" {
+		// without Message-ID, the content identify the email
+		hash := sha256.Sum256(raw)
+		messageId = "<" + hex.EncodeToString(hash[:16]) + "@git-bug.ingest>"
+	}
+
+	result := MailResult{
+		MessageId: messageId,
+		Subject:   decodeHeader(msg.Header.Get("Subject")),
+	}
+	fail := func(err error) MailResult {
+		result.Event = MailEventError
+		result.Err = err
+		return result
+	}
+
+	if err := in.loadMessages(); err != nil {
+		return fail(err)
+	}
+	if id, ok := in.messages[messageId]; ok {
+		result.Event = MailEventDuplicate
+		result.BugId = id
+		return result
+	}
+
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) == 0 {
+		return fail(fmt.Errorf("invalid sender: %v", err))
+	}
+
+	date, err := msg.Header.Date()
+	if err != nil {
+		date = time.Now()
+	}
+
+	content, err := parseContent(mailHeader(msg.Header), msg.Body)
+	if err != nil {
+		return fail(err)
+	}
+
+	target, err := in.findBug(msg.Header, result.Subject)
+	if err != nil {
+		return fail(err)
+	}
+
+	title := cleanSubject(result.Subject)
+	if title == "" {
+		title = "(no subject)"
+	}
+	message := content.text
+	if target != nil {
+		message = trimQuote(message)
+	}
+
+	if in.DryRun {
+		if target != nil {
+			result.Event = MailEventComment
+			result.BugId = target.Id()
+		} else {
+			result.Event = MailEventBug
+		}
+		return result
+	}
+
+	author, err := in.identity(from[0])
+	if err != nil {
+		return fail(err)
+	}
+
+	var attachments []bug.Attachment
+	for _, part := range content.attachments {
+		attachment, err := in.repo.StoreAttachment(part.name, part.data)
+		if err != nil {
+			return fail(err)
+		}
+		if part.mime != "" {
+			attachment.MimeType = part.mime
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	metadata := bug.AttachmentsMetadata(attachments)
+	metadata[MetaKeyMessageId] = messageId
+
+	if target != nil {
+		_, _, err = target.AddCommentRaw(author, date.Unix(), message, bug.AttachmentHashes(attachments), metadata)
+		if err != nil {
+			return fail(err)
+		}
+		if err := target.Commit(); err != nil {
+			return fail(err)
+		}
+		result.Event = MailEventComment
+		result.BugId = target.Id()
+	} else {
+		b, _, err := in.repo.Bugs().NewRaw(author, date.Unix(), title, message, bug.AttachmentHashes(attachments), metadata)
+		if err != nil {
+			return fail(err)
+		}
+		result.Event = MailEventBug
+		result.BugId = b.Id()
+	}
+
+	in.messages[messageId] = result.BugId
+	return result
+}
+
+// findBug return the bug an email reply to, or nil
+func (in *MailIngester) findBug(header mail.Header, subject string) (*cache.BugCache, error) {
+	var refs []string
+	refs = append(refs, strings.Fields(header.Get("In-Reply-To"))...)
+	references := strings.Fields(header.Get("References"))
+	// the last references are the closest in the thread
+	for i := len(references) - 1; i >= 0; i-- {
+		refs = append(refs, references[i])
+	}
+
+	for _, ref := range refs {
+		if match := notificationIdRegexp.FindStringSubmatch(ref); match != nil {
+			b, err := in.repo.Bugs().Resolve(entity.Id(match[1]))
+			if err == nil {
+				return b, nil
+			}
+			if !entity.IsErrNotFound(err) {
+				return nil, err
+			}
+		}
+		if id, ok := in.messages[ref]; ok {
+			return in.repo.Bugs().Resolve(id)
+		}
+	}
+
+	if match := subjectTagRegexp.FindStringSubmatch(subject); match != nil {
+		b, err := in.repo.Bugs().ResolvePrefix(match[1])
+		if err == nil {
+			return b, nil
+		}
+		if !entity.IsErrNotFound(err) && !entity.IsErrMultipleMatch(err) {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// loadMessages index the bugs by the Message-ID of their ingested emails
+func (in *MailIngester) loadMessages() error {
+	if in.messages != nil {
+		return nil
+	}
+
+	in.messages = make(map[string]entity.Id)
+	for _, id := range in.repo.Bugs().AllIds() {
+		b, err := in.repo.Bugs().Resolve(id)
+		if err != nil {
+			return err
+		}
+		for _, op := range b.Snapshot().Operations {
+			if messageId, ok := op.GetMetadata(MetaKeyMessageId); ok {
+				in.messages[messageId] = id
+			}
+		}
+	}
+
+	return nil
+}
+
+// identity return the identity of a sender, creating it if needed
+func (in *MailIngester) identity(from *mail.Address) (*cache.IdentityCache, error) {
+	if in.identities == nil {
+		in.identities = make(map[string]*cache.IdentityCache)
+		for _, id := range in.repo.Identities().AllIds() {
+			i, err := in.repo.Identities().Resolve(id)
+			if err != nil {
+				return nil, err
+			}
+			if i.Email() != "" {
+				in.identities[strings.ToLower(i.Email())] = i
+			}
+		}
+	}
+
+	email := strings.ToLower(from.Address)
+	if i, ok := in.identities[email]; ok {
+		return i, nil
+	}
+
+	name := from.Name
+	if name == "" {
+		name, _, _ = strings.Cut(from.Address, "@")
+	}
+
+	i, err := in.repo.Identities().NewRaw(name, from.Address, "", "", nil, map[string]string{
+		MetaKeyEmail: from.Address,
+	})
+	if err != nil {
+		return nil, err
+	}
+	in.identities[email] = i
+	return i, nil
+}
+
+func cleanSubject(subject string) string {
+	subject = replyPrefixRegexp.ReplaceAllString(subject, "")
+	subject = subjectTagRegexp.ReplaceAllString(subject, "")
+	return text.CleanupOneLine(subject)
+}
+
+// quoteHeaderRegexp match the line introducing the quoted email, like
+// "On Mon, 1 Jan 2024, John <john@example.com> wrote:"
+var quoteHeaderRegexp = regexp.MustCompile(`(?i)^(on|le|am|el)\b.*(wrote|a écrit|schrieb|escribió)\s*:$`)
+
+// trimQuote remove the quoted email at the end of a reply
+func trimQuote(message string) string {
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+
+	end := len(lines)
+	for end > 0 {
+		line := strings.TrimSpace(lines[end-1])
+		if line == "" || strings.HasPrefix(line, ">") {
+			end--
+			continue
+		}
+		break
+	}
+	if end == len(lines) {
+		return message
+	}
+	if end > 0 && quoteHeaderRegexp.MatchString(strings.TrimSpace(lines[end-1])) {
+		end--
+	}
+
+	result := strings.TrimSpace(strings.Join(lines[:end], "\n"))
+	if result == "" {
+		// only a quote, keep it
+		return message
+	}
+	return result
+}
+
+var wordDecoder = &mime.WordDecoder{
+	CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		enc, err := htmlindex.Get(charset)
+		if err != nil {
+			return nil, err
+		}
+		return enc.NewDecoder().Reader(input), nil
+	},
+}
+
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// mailHeader is the subset of the header of an email or a MIME part used to
+// decode its content
+type mailHeader interface {
+	Get(key string) string
+}
+
+type mailContent struct {
+	text        string
+	html        string
+	attachments []mailAttachment
+}
+
+type mailAttachment struct {
+	name string
+	mime string
+	data []byte
+}
+
+// parseContent extract the text and the attachments of an email
+func parseContent(header mailHeader, body io.Reader) (*mailContent, error) {
+	content := &mailContent{}
+	if err := content.parsePart(header, body); err != nil {
+		return nil, err
+	}
+	if content.text == "" && content.html != "" {
+		content.text = htmlToText(content.html)
+	}
+	content.text = text.Cleanup(content.text)
+	return content, nil
+}
+
+func (c *mailContent) parsePart(header mailHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		r := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := r.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("invalid multipart email: %w", err)
+			}
+			if err := c.parsePart(part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("invalid email content: %w", err)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := decodeHeader(dispositionParams["filename"])
+	if name == "" {
+		name = decodeHeader(params["name"])
+	}
+
+	switch {
+	case disposition != "attachment" && mediaType == "text/plain" && c.text == "":
+		c.text = decodeCharset(params["charset"], data)
+	case disposition != "attachment" && mediaType == "text/html" && c.html == "":
+		c.html = decodeCharset(params["charset"], data)
+	case strings.HasPrefix(mediaType, "text/") && disposition != "attachment" && name == "":
+		// alternative versions of the text
+	default:
+		if name == "" {
+			name = "attachment"
+			if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+				name += exts[0]
+			}
+		}
+		c.attachments = append(c.attachments, mailAttachment{name: name, mime: mediaType, data: data})
+	}
+
+	return nil
+}
+
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineFilter{r})
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// newlineFilter remove the line breaks of a base64 content
+type newlineFilter struct {
+	r io.Reader
+}
+
+func (f newlineFilter) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	j := 0
+	for i := 0; i < n; i++ {
+		if p[i] != '\r' && p[i] != '\n' {
+			p[j] = p[i]
+			j++
+		}
+	}
+	return j, err
+}
+
+func decodeCharset(charset string, data []byte) string {
+	charset = strings.ToLower(charset)
+	if charset == "" || charset == "utf-8" || charset == "us-ascii" {
+		return string(data)
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return string(data)
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(decoded)
+}
+
+var (
+	htmlBreakRegexp = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</h[1-6]>`)
+	htmlTagRegexp   = regexp.MustCompile(`(?s)<[^>]*>`)
+	htmlDropRegexp  = regexp.MustCompile(`(?is)<(style|script|head)\b.*?</(style|script|head)>`)
+	blankRegexp     = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText do a rough conversion of an HTML email to text, for the
+// emails without a text version
+func htmlToText(s string) string {
+	s = htmlDropRegexp.ReplaceAllString(s, "")
+	s = htmlBreakRegexp.ReplaceAllString(s, "\n")
+	s = htmlTagRegexp.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = blankRegexp.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,192 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/notify"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func email(lines ...string) []byte {
+	return []byte(strings.Join(lines, "\r\n"))
+}
+
+func TestMailIngester(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	c, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer c.Close()
+
+	rene, err := c.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+
+	in := NewMailIngester(c)
+
+	// a new email create a bug, and an identity for the unknown sender
+	result := in.Ingest(email(
+		"From: Ada Lovelace <ada@example.com>",
+		"To: bugs@example.com",
+		"Subject: =?utf-8?q?Crash_when_saving_=C3=A9t=C3=A9?=",
+		"Message-ID: <1@example.com>",
+		"Date: Mon, 1 Jan 2024 10:00:00 +0000",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=XYZ",
+		"",
+		"--XYZ",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"The app crash when saving the file =C3=A9t=C3=A9.txt",
+		"--XYZ",
+		"Content-Type: text/plain",
+		"Content-Disposition: attachment; filename=\"log.txt\"",
+		"Content-Transfer-Encoding: base64",
+		"",
+		"cGFuaWM6IG5pbAo=",
+		"--XYZ--",
+		"",
+	))
+	require.NoError(t, result.Err)
+	require.Equal(t, MailEventBug, result.Event)
+
+	b, err := c.Bugs().Resolve(result.BugId)
+	require.NoError(t, err)
+	snap := b.Snapshot()
+	require.Equal(t, "Crash when saving été", snap.Title)
+	require.Equal(t, "The app crash when saving the file été.txt", snap.Comments[0].Message)
+	require.Equal(t, "Ada Lovelace", snap.Author.Name())
+	require.Equal(t, "ada@example.com", snap.Author.Email())
+	require.Equal(t, int64(1704103200), snap.CreateTime.Unix())
+
+	require.Len(t, snap.Comments[0].Files, 1)
+	data, err := c.ReadData(snap.Comments[0].Files[0])
+	require.NoError(t, err)
+	require.Equal(t, "panic: nil\n", string(data))
+	require.Equal(t, "log.txt", snap.Comments[0].Attachments[0].Name)
+	require.Equal(t, "text/plain", snap.Comments[0].Attachments[0].MimeType)
+
+	bugId := result.BugId
+
+	// the same email is ignored
+	result = in.Ingest(email(
+		"From: Ada Lovelace <ada@example.com>",
+		"Subject: Crash when saving",
+		"Message-ID: <1@example.com>",
+		"",
+		"The app crash",
+	))
+	require.Equal(t, MailEventDuplicate, result.Event)
+	require.Equal(t, bugId, result.BugId)
+
+	// a reply to the ingested email, from a known identity
+	result = in.Ingest(email(
+		"From: RENE@descartes.fr",
+		"Subject: Re: Crash when saving",
+		"Message-ID: <2@descartes.fr>",
+		"In-Reply-To: <1@example.com>",
+		"",
+		"I can reproduce it.",
+		"",
+		"On Mon, 1 Jan 2024, Ada Lovelace <ada@example.com> wrote:",
+		"> The app crash",
+		"",
+	))
+	require.NoError(t, result.Err)
+	require.Equal(t, MailEventComment, result.Event)
+	require.Equal(t, bugId, result.BugId)
+
+	snap = b.Snapshot()
+	require.Len(t, snap.Comments, 2)
+	require.Equal(t, "I can reproduce it.", snap.Comments[1].Message)
+	require.Equal(t, rene.Id(), snap.Comments[1].Author.Id())
+
+	// a reply to a notification
+	result = in.Ingest(email(
+		"From: ada@example.com",
+		"Subject: Re: something else",
+		"Message-ID: <3@example.com>",
+		"In-Reply-To: "+notify.BugMessageId(bugId),
+		"",
+		"Fixed by updating.",
+	))
+	require.Equal(t, MailEventComment, result.Event)
+	require.Equal(t, bugId, result.BugId)
+
+	// a reply to the previous one, with the References
+	result = in.Ingest(email(
+		"From: ada@example.com",
+		"Subject: Re: something else",
+		"Message-ID: <4@example.com>",
+		"References: <unknown@example.com> <3@example.com>",
+		"",
+		"Closing.",
+	))
+	require.Equal(t, MailEventComment, result.Event)
+	require.Equal(t, bugId, result.BugId)
+
+	// a tag in the subject
+	result = in.Ingest(email(
+		"From: ada@example.com",
+		"Subject: Fwd: [#"+bugId.Human()+"] Crash when saving",
+		"Message-ID: <5@example.com>",
+		"Content-Type: text/html; charset=iso-8859-1",
+		"",
+		"<p>Still there in version 2 &amp; \xe9t\xe9</p>",
+	))
+	require.Equal(t, MailEventComment, result.Event)
+	require.Equal(t, bugId, result.BugId)
+
+	snap = b.Snapshot()
+	require.Len(t, snap.Comments, 5)
+	require.Equal(t, "Still there in version 2 & été", snap.Comments[4].Message)
+
+	// the identity created for the first email is reused
+	require.Len(t, c.Identities().AllIds(), 2)
+
+	// a new ingester find the ingested emails in the repository
+	in = NewMailIngester(c)
+	in.DryRun = true
+	result = in.Ingest(email(
+		"From: ada@example.com",
+		"Subject: Crash when saving",
+		"Message-ID: <5@example.com>",
+		"",
+		"Closing.",
+	))
+	require.Equal(t, MailEventDuplicate, result.Event)
+
+	result = in.Ingest(email(
+		"From: ada@example.com",
+		"Subject: Another problem",
+		"Message-ID: <6@example.com>",
+		"",
+		"Something else.",
+	))
+	require.Equal(t, MailEventBug, result.Event)
+	require.Len(t, c.Bugs().AllIds(), 1)
+
+	result = in.Ingest(email(
+		"Subject: no sender",
+		"",
+		"Something else.",
+	))
+	require.Equal(t, MailEventError, result.Event)
+}
+
+func TestTrimQuote(t *testing.T) {
+	require.Equal(t, "Thanks", trimQuote("Thanks\n\nOn Tue, Bob wrote:\n> hello\n> world\n"))
+	require.Equal(t, "Thanks", trimQuote("Thanks\n> hello"))
+	require.Equal(t, "> hello\nanswer", trimQuote("> hello\nanswer"))
+	require.Equal(t, "> only a quote", trimQuote("> only a quote"))
+}
+
+func TestCleanSubject(t *testing.T) {
+	require.Equal(t, "Crash", cleanSubject("Re: RE: Fwd: [#1234abc] Crash"))
+	require.Equal(t, "Crash", cleanSubject("Re[2]: Crash"))
+	require.Equal(t, "Regression", cleanSubject("Regression"))
+}
@@ -0,0 +1,97 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// ReadMbox call fn with each email of a mbox file, in the mboxrd or mboxo
+// format
+func ReadMbox(r io.Reader, fn func(raw []byte) error) error {
+	br := bufio.NewReader(r)
+
+	var current bytes.Buffer
+	started := false
+
+	flush := func(last bool) error {
+		if !started {
+			return nil
+		}
+		raw := current.Bytes()
+		if !last {
+			// the empty line before the next separator belong to the format
+			raw = bytes.TrimSuffix(raw, []byte("\n"))
+			raw = bytes.TrimSuffix(raw, []byte("\r"))
+		}
+		err := fn(append([]byte(nil), raw...))
+		current.Reset()
+		return err
+	}
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			switch {
+			case bytes.HasPrefix(line, []byte("From ")):
+				if err := flush(false); err != nil {
+					return err
+				}
+				started = true
+			case started:
+				// unescape the quoted "From " lines
+				if mboxQuotedFromRegexp.Match(line) {
+					line = line[1:]
+				}
+				current.Write(line)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return flush(true)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+var mboxQuotedFromRegexp = regexp.MustCompile(`^>+From `)
+
+// ReadMaildir call fn with each email of a maildir, the new ones and the
+// already seen ones, oldest first
+func ReadMaildir(dir string, fn func(raw []byte) error) error {
+	var paths []string
+	for _, sub := range []string{"cur", "new"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				paths = append(paths, filepath.Join(dir, sub, entry.Name()))
+			}
+		}
+	}
+
+	// the names of the files start with the time of delivery
+	sort.Slice(paths, func(i, j int) bool {
+		return filepath.Base(paths[i]) < filepath.Base(paths[j])
+	})
+
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
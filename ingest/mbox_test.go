@@ -0,0 +1,53 @@
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMbox(t *testing.T) {
+	mbox := `From ada@example.com Mon Jan  1 10:00:00 2024
+Subject: first
+
+hello
+>From the start
+
+From rene@descartes.fr Mon Jan  1 11:00:00 2024
+Subject: second
+
+world
+`
+
+	var raws []string
+	err := ReadMbox(strings.NewReader(mbox), func(raw []byte) error {
+		raws = append(raws, string(raw))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Subject: first\n\nhello\nFrom the start\n",
+		"Subject: second\n\nworld\n",
+	}, raws)
+}
+
+func TestReadMaildir(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"cur", "new", "tmp"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, sub), 0700))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new", "1704106800.2.host"), []byte("second"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cur", "1704103200.1.host:2,S"), []byte("first"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tmp", "1704103100.0.host"), []byte("partial"), 0600))
+
+	var raws []string
+	err := ReadMaildir(dir, func(raw []byte) error {
+		raws = append(raws, string(raw))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, raws)
+}
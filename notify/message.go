@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// Message is a notification email
+type Message struct {
+	To        string
+	Subject   string
+	Date      time.Time
+	MessageId string
+	// InReplyTo thread the emails about the same bug together
+	InReplyTo string
+	Body      string
+}
+
+// BugMessageId is the id of the thread of the emails about a bug. It allows
+// to find the bug a reply is about.
+func BugMessageId(id entity.Id) string {
+	return "<bug." + id.String() + "@git-bug>"
+}
+
+// BugSubject is the subject of the emails about a bug
+func BugSubject(snap *bug.Snapshot) string {
+	return fmt.Sprintf("[#%s] %s", snap.Id().Human(), snap.Title)
+}
+
+// Bytes encode the message as RFC 5322
+func (m *Message) Bytes(from string) []byte {
+	var buf bytes.Buffer
+
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+
+	header("From", encodeAddress(from))
+	header("To", encodeAddress(m.To))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", m.Date.Format(time.RFC1123Z))
+	header("Message-ID", m.MessageId)
+	if m.InReplyTo != "" {
+		header("In-Reply-To", m.InReplyTo)
+		header("References", m.InReplyTo)
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	header("Auto-Submitted", "auto-generated")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	_, _ = w.Write([]byte(strings.ReplaceAll(m.Body, "\n", "\r\n")))
+	_ = w.Close()
+
+	return buf.Bytes()
+}
+
+// encodeAddress encode the non-ASCII names of an address
+func encodeAddress(address string) string {
+	addr, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return addr.String()
+}
+
+// Recipient return the address of the recipient, for the SMTP envelope
+func (m *Message) Recipient() (string, error) {
+	addr, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return "", fmt.Errorf("invalid email address %q: %w", m.To, err)
+	}
+	return addr.Address, nil
+}
+
+func immediateMessage(to string, sub Subscription, c bugChanges, now time.Time) *Message {
+	var body strings.Builder
+	writeBugChanges(&body, c)
+	writeFooter(&body, sub)
+
+	return &Message{
+		To:        to,
+		Subject:   BugSubject(c.snap),
+		Date:      now,
+		MessageId: fmt.Sprintf("<bug.%s.%d@git-bug>", c.snap.Id(), now.UnixNano()),
+		InReplyTo: BugMessageId(c.snap.Id()),
+		Body:      body.String(),
+	}
+}
+
+func digestMessage(to string, sub Subscription, changes []bugChanges, now time.Time) *Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%d bug(s) changed since the last digest.\n", len(changes))
+	for _, c := range changes {
+		body.WriteString("\n")
+		writeBugChanges(&body, c)
+	}
+	writeFooter(&body, sub)
+
+	return &Message{
+		To:        to,
+		Subject:   fmt.Sprintf("git-bug digest: %d bug(s) changed", len(changes)),
+		Date:      now,
+		MessageId: fmt.Sprintf("<digest.%d@git-bug>", now.UnixNano()),
+		Body:      body.String(),
+	}
+}
+
+func writeBugChanges(w *strings.Builder, c bugChanges) {
+	snap := c.snap
+
+	w.WriteString(BugSubject(snap) + "\n")
+	fmt.Fprintf(w, "Status: %s", snap.Status)
+	if len(snap.Labels) > 0 {
+		labels := make([]string, len(snap.Labels))
+		for i, l := range snap.Labels {
+			labels[i] = l.String()
+		}
+		fmt.Fprintf(w, ", labels: %s", strings.Join(labels, ", "))
+	}
+	w.WriteString("\n")
+
+	for _, item := range c.items {
+		w.WriteString("\n")
+		writeItem(w, item, c.edited[item.CombinedId()])
+	}
+}
+
+// writeItem write a timeline item, as a comment edition if edited is true
+func writeItem(w *strings.Builder, item bug.TimelineItem, edited bool) {
+	const dateLayout = "2006-01-02 15:04"
+
+	comment := func(item bug.CommentTimelineItem, action string) {
+		author := item.Author
+		if edited {
+			action = "edited a comment"
+			author = lastEditor(item)
+		}
+		fmt.Fprintf(w, "%s %s on %s:\n", author.DisplayName(), action, item.LastEdit.Time().Format(dateLayout))
+		if item.Message != "" {
+			w.WriteString("\n")
+			for _, line := range strings.Split(strings.TrimRight(item.Message, "\n"), "\n") {
+				w.WriteString("> " + line + "\n")
+			}
+		}
+	}
+
+	switch item := item.(type) {
+	case *bug.CreateTimelineItem:
+		comment(item.CommentTimelineItem, "opened the bug")
+
+	case *bug.AddCommentTimelineItem:
+		comment(item.CommentTimelineItem, "commented")
+
+	case *bug.LabelChangeTimelineItem:
+		var changes []string
+		if len(item.Added) > 0 {
+			changes = append(changes, "added the label(s) "+joinLabels(item.Added))
+		}
+		if len(item.Removed) > 0 {
+			changes = append(changes, "removed the label(s) "+joinLabels(item.Removed))
+		}
+		fmt.Fprintf(w, "%s %s on %s\n", item.Author.DisplayName(), strings.Join(changes, " and "), item.UnixTime.Time().Format(dateLayout))
+
+	case *bug.SetStatusTimelineItem:
+		action := "closed the bug"
+		if item.Status == common.OpenStatus {
+			action = "reopened the bug"
+		}
+		fmt.Fprintf(w, "%s %s on %s\n", item.Author.DisplayName(), action, item.UnixTime.Time().Format(dateLayout))
+
+	case *bug.SetTitleTimelineItem:
+		fmt.Fprintf(w, "%s changed the title from %q on %s\n", item.Author.DisplayName(), item.Was, item.UnixTime.Time().Format(dateLayout))
+	}
+}
+
+func writeFooter(w *strings.Builder, sub Subscription) {
+	w.WriteString("\n-- \n")
+	if sub.Query == "" {
+		w.WriteString("You receive this email because you subscribed to the changes of all the bugs.\n")
+	} else {
+		fmt.Fprintf(w, "You receive this email because you subscribed to the changes of the bugs matching %q.\n", sub.Query)
+	}
+}
+
+func joinLabels(labels []bug.Label) string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = `"` + l.String() + `"`
+	}
+	return strings.Join(names, ", ")
+}
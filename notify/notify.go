@@ -0,0 +1,431 @@
+// Package notify send email notifications about the changes of the bugs
+// matching the query each identity subscribed to, either immediately or as a
+// daily digest.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// subscriptionKeyPrefix is the prefix of the git config keys defining the
+// subscriptions, as git-bug.notify.<identity-id>.<key>
+const subscriptionKeyPrefix = "git-bug.notify"
+
+// DigestInterval is the minimal time between two digests
+const DigestInterval = 24 * time.Hour
+
+// Mode is how an identity is notified
+type Mode string
+
+const (
+	// Immediate send an email for each changed bug, at each run
+	Immediate Mode = "immediate"
+	// Digest send a single email with all the changes, once a day
+	Digest Mode = "digest"
+)
+
+func (m Mode) Validate() error {
+	switch m {
+	case Immediate, Digest:
+		return nil
+	default:
+		return fmt.Errorf("unknown notification mode %q, expected %s or %s", m, Immediate, Digest)
+	}
+}
+
+// Subscription is the query an identity watch for changes
+type Subscription struct {
+	IdentityId entity.Id
+	Query      string
+	Mode       Mode
+	// Email override the email of the identity, if set
+	Email string
+}
+
+func (s Subscription) Validate() error {
+	if err := s.IdentityId.Validate(); err != nil {
+		return fmt.Errorf("invalid identity id: %w", err)
+	}
+	if _, err := query.Parse(s.Query); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	return s.Mode.Validate()
+}
+
+// Subscriptions return the subscriptions configured in the repository
+func Subscriptions(repo repository.RepoConfig) ([]Subscription, error) {
+	configs, err := repo.LocalConfig().ReadAll(subscriptionKeyPrefix + ".")
+	if err != nil {
+		return nil, err
+	}
+
+	re := regexp.MustCompile(`^` + regexp.QuoteMeta(subscriptionKeyPrefix) + `\.([0-9a-f]{64})\.(query|mode|email)$`)
+
+	subs := make(map[entity.Id]*Subscription)
+	for key, value := range configs {
+		res := re.FindStringSubmatch(key)
+		if res == nil {
+			continue
+		}
+		id := entity.Id(res[1])
+		sub, ok := subs[id]
+		if !ok {
+			sub = &Subscription{IdentityId: id, Mode: Immediate}
+			subs[id] = sub
+		}
+		switch res[2] {
+		case "query":
+			sub.Query = value
+		case "mode":
+			sub.Mode = Mode(value)
+		case "email":
+			sub.Email = value
+		}
+	}
+
+	result := make([]Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if err := sub.Validate(); err != nil {
+			return nil, fmt.Errorf("subscription of %s: %w", sub.IdentityId.Human(), err)
+		}
+		result = append(result, *sub)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].IdentityId < result[j].IdentityId
+	})
+
+	return result, nil
+}
+
+// Subscribe store a subscription, replacing the previous one of the identity
+func Subscribe(repo repository.RepoConfig, sub Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	if err := Unsubscribe(repo, sub.IdentityId); err != nil {
+		return err
+	}
+
+	prefix := subscriptionKeyPrefix + "." + sub.IdentityId.String() + "."
+	if err := repo.LocalConfig().StoreString(prefix+"query", sub.Query); err != nil {
+		return err
+	}
+	if err := repo.LocalConfig().StoreString(prefix+"mode", string(sub.Mode)); err != nil {
+		return err
+	}
+	if sub.Email != "" {
+		return repo.LocalConfig().StoreString(prefix+"email", sub.Email)
+	}
+	return nil
+}
+
+// Unsubscribe remove the subscription of an identity
+func Unsubscribe(repo repository.RepoConfig, identityId entity.Id) error {
+	prefix := subscriptionKeyPrefix + "." + identityId.String()
+	configs, err := repo.LocalConfig().ReadAll(prefix + ".")
+	if err != nil {
+		return err
+	}
+	if len(configs) == 0 {
+		return nil
+	}
+	return repo.LocalConfig().RemoveAll(prefix)
+}
+
+// Report is what a run of the notifications did
+type Report struct {
+	Sent []*Message
+}
+
+// AfterSync run the notifications after the repository got updates, if
+// they are configured. It returns nil if there is nothing to do.
+func AfterSync(repo *cache.RepoCache) (*Report, error) {
+	subs, err := Subscriptions(repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return nil, nil
+	}
+
+	sender, err := NewSMTPSender(repo)
+	if errors.Is(err, ErrSMTPNotConfigured) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return Run(repo, sender, time.Now())
+}
+
+// Run check the subscriptions for changes and send the notifications that
+// are due.
+//
+// The first run of a subscription only records the current state of the
+// bugs, to not notify about the whole history.
+func Run(repo *cache.RepoCache, sender Sender, now time.Time) (*Report, error) {
+	return run(repo, sender, now, true)
+}
+
+// Preview return the notifications that are due, without sending them or
+// recording them as notified.
+func Preview(repo *cache.RepoCache, now time.Time) (*Report, error) {
+	return run(repo, &discardSender{}, now, false)
+}
+
+type discardSender struct{}
+
+func (discardSender) Send(*Message) error {
+	return nil
+}
+
+func run(repo *cache.RepoCache, sender Sender, now time.Time, save bool) (*Report, error) {
+	subs, err := Subscriptions(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := loadState(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	saveFn := func() error {
+		if !save {
+			return nil
+		}
+		return saveState(repo, st)
+	}
+
+	report := &Report{}
+
+	for _, sub := range subs {
+		err := runSubscription(repo, sender, st, saveFn, sub, now, report)
+		if err != nil {
+			err = fmt.Errorf("notifying %s: %w", sub.IdentityId.Human(), err)
+			// keep what was already sent
+			if errSave := saveFn(); errSave != nil {
+				return report, errSave
+			}
+			return report, err
+		}
+	}
+
+	return report, saveFn()
+}
+
+// bugChanges are the new timeline items of a bug
+type bugChanges struct {
+	snap  *bug.Snapshot
+	items []bug.TimelineItem
+	// edited are the comments only changed by an edition
+	edited map[entity.CombinedId]bool
+	// editTime is the Lamport edit time of the bug, once notified
+	editTime lamport.Time
+}
+
+func runSubscription(repo *cache.RepoCache, sender Sender, st *state, save func() error, sub Subscription, now time.Time, report *Report) error {
+	ist, ok := st.Identities[sub.IdentityId]
+	if ok && ist.Query != sub.Query {
+		// the set of bugs changed, start again from the current state
+		ok = false
+	}
+	if ok && sub.Mode == Digest && now.Before(ist.LastDigest.Add(DigestInterval)) {
+		return nil
+	}
+
+	q, err := query.Parse(sub.Query)
+	if err != nil {
+		return err
+	}
+	// "me" in the query is the subscribed identity
+	ids, err := repo.Bugs().QueryAs(q, func() (*cache.IdentityCache, error) {
+		return repo.Identities().Resolve(sub.IdentityId)
+	})
+	if err != nil {
+		return err
+	}
+
+	// the bugs not matching the query yet are edited after this time,
+	// as they all share the same Lamport clock
+	var editTime lamport.Time
+	for _, id := range repo.Bugs().AllIds() {
+		excerpt, err := repo.Bugs().ResolveExcerpt(id)
+		if err != nil {
+			return err
+		}
+		if excerpt.EditLamportTime > editTime {
+			editTime = excerpt.EditLamportTime
+		}
+	}
+
+	bugs := make(map[entity.Id]bugState, len(ids))
+	var changes []bugChanges
+
+	for _, id := range ids {
+		excerpt, err := repo.Bugs().ResolveExcerpt(id)
+		if err != nil {
+			return err
+		}
+		bugs[id] = bugState{EditLamportTime: excerpt.EditLamportTime}
+
+		if !ok {
+			continue
+		}
+
+		// a bug that just started to match the query only have its recent
+		// changes notified
+		since := ist.EditLamportTime
+		if prev, known := ist.Bugs[id]; known {
+			since = prev.EditLamportTime
+		}
+		if excerpt.EditLamportTime <= since {
+			continue
+		}
+
+		b, err := repo.Bugs().Resolve(id)
+		if err != nil {
+			return err
+		}
+		snap := b.Snapshot()
+		items, edited := newItems(snap, since, b.OperationEditLamportTime, sub.IdentityId)
+		if len(items) > 0 {
+			changes = append(changes, bugChanges{snap: snap, items: items, edited: edited, editTime: excerpt.EditLamportTime})
+			// until notified, keep the previous state
+			bugs[id] = bugState{EditLamportTime: since}
+		}
+	}
+
+	next := &identityState{
+		Query:           sub.Query,
+		LastRun:         now,
+		LastDigest:      now,
+		EditLamportTime: editTime,
+		Bugs:            bugs,
+	}
+	if len(changes) > 0 {
+		next.LastDigest = ist.LastDigest
+	}
+	st.Identities[sub.IdentityId] = next
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	to := sub.Email
+	if to == "" {
+		i, err := repo.Identities().Resolve(sub.IdentityId)
+		if err != nil {
+			return err
+		}
+		to = i.Email()
+	}
+	if to == "" {
+		return fmt.Errorf("no email address, set one in the subscription")
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].snap.EditTime().After(changes[j].snap.EditTime())
+	})
+
+	switch sub.Mode {
+	case Immediate:
+		for _, c := range changes {
+			m := immediateMessage(to, sub, c, now)
+			if err := sender.Send(m); err != nil {
+				return err
+			}
+			report.Sent = append(report.Sent, m)
+			next.Bugs[c.snap.Id()] = bugState{EditLamportTime: c.editTime}
+			// don't send it again if a later message fails
+			if err := save(); err != nil {
+				return err
+			}
+		}
+	case Digest:
+		m := digestMessage(to, sub, changes, now)
+		if err := sender.Send(m); err != nil {
+			return err
+		}
+		report.Sent = append(report.Sent, m)
+		for _, c := range changes {
+			next.Bugs[c.snap.Id()] = bugState{EditLamportTime: c.editTime}
+		}
+		next.LastDigest = now
+	}
+
+	return nil
+}
+
+// newItems return the timeline items changed by the operations stored after
+// a Lamport edit time, or not stored yet, ignoring the changes made by the
+// notified identity. It also return which of those items are comments only
+// changed by an edition.
+func newItems(snap *bug.Snapshot, since lamport.Time, opTime func(id entity.Id) (lamport.Time, bool), identityId entity.Id) ([]bug.TimelineItem, map[entity.CombinedId]bool) {
+	changed := make(map[entity.CombinedId]bool)
+	created := make(map[entity.CombinedId]bool)
+
+	for _, op := range snap.Operations {
+		if time, ok := opTime(op.Id()); ok && time <= since {
+			continue
+		}
+		if op.Author().Id() == identityId {
+			continue
+		}
+		switch op := op.(type) {
+		case *bug.EditCommentOperation:
+			changed[entity.CombineIds(snap.Id(), op.Target)] = true
+		default:
+			id := entity.CombineIds(snap.Id(), op.Id())
+			changed[id] = true
+			created[id] = true
+		}
+	}
+
+	var result []bug.TimelineItem
+	edited := make(map[entity.CombinedId]bool)
+
+	for _, item := range snap.Timeline {
+		switch item.(type) {
+		case *bug.CreateTimelineItem, *bug.AddCommentTimelineItem,
+			*bug.LabelChangeTimelineItem, *bug.SetStatusTimelineItem, *bug.SetTitleTimelineItem:
+		default:
+			continue
+		}
+
+		id := item.CombinedId()
+		if !changed[id] {
+			continue
+		}
+		result = append(result, item)
+		if !created[id] {
+			edited[id] = true
+		}
+	}
+
+	return result, edited
+}
+
+// lastEditor return the identity who last changed a comment
+func lastEditor(item bug.CommentTimelineItem) identity.Interface {
+	last := item.History[len(item.History)-1]
+	// the first step of the history doesn't have its author set
+	if last.Author != nil {
+		return last.Author
+	}
+	return item.Author
+}
@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+type recordSender struct {
+	sent []*Message
+}
+
+func (r *recordSender) Send(m *Message) error {
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+func TestSubscriptions(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	c, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer c.Close()
+
+	rene, err := c.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+
+	subs, err := Subscriptions(c)
+	require.NoError(t, err)
+	require.Empty(t, subs)
+
+	require.Error(t, Subscribe(c, Subscription{IdentityId: rene.Id(), Query: "status:", Mode: Immediate}))
+	require.Error(t, Subscribe(c, Subscription{IdentityId: rene.Id(), Mode: "weekly"}))
+
+	sub := Subscription{IdentityId: rene.Id(), Query: "label:bug", Mode: Digest, Email: "other@descartes.fr"}
+	require.NoError(t, Subscribe(c, sub))
+	subs, err = Subscriptions(c)
+	require.NoError(t, err)
+	require.Equal(t, []Subscription{sub}, subs)
+
+	// replacing drop the previous email
+	sub = Subscription{IdentityId: rene.Id(), Query: "status:open", Mode: Immediate}
+	require.NoError(t, Subscribe(c, sub))
+	subs, err = Subscriptions(c)
+	require.NoError(t, err)
+	require.Equal(t, []Subscription{sub}, subs)
+
+	require.NoError(t, Unsubscribe(c, rene.Id()))
+	subs, err = Subscriptions(c)
+	require.NoError(t, err)
+	require.Empty(t, subs)
+}
+
+func TestRun(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	c, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer c.Close()
+
+	rene, err := c.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, c.SetUserIdentity(rene))
+	ada, err := c.Identities().New("Ada Lovelace", "ada@lovelace.uk")
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	at := func(d time.Duration) int64 {
+		return start.Add(d).Unix()
+	}
+
+	bug1, _, err := c.Bugs().NewRaw(rene, at(0), "crash on start", "it crashes", nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, Subscribe(c, Subscription{IdentityId: rene.Id(), Query: "status:open", Mode: Immediate}))
+	require.NoError(t, Subscribe(c, Subscription{IdentityId: ada.Id(), Mode: Digest, Email: "Ada <ada@work.uk>"}))
+
+	sender := &recordSender{}
+
+	// the first run only record the state
+	report, err := Run(c, sender, start.Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, report.Sent)
+
+	_, _, err = bug1.AddCommentRaw(ada, at(2*time.Minute), "same here", nil, nil)
+	require.NoError(t, err)
+	_, _, err = bug1.ChangeLabelsRaw(rene, at(3*time.Minute), []string{"crash"}, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	bug2, _, err := c.Bugs().NewRaw(ada, at(4*time.Minute), "typo in the doc", "", nil, nil)
+	require.NoError(t, err)
+
+	// the own changes are not notified, the digest is not due yet
+	report, err = Run(c, sender, start.Add(5*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, report.Sent, 2)
+
+	require.Equal(t, "rene@descartes.fr", report.Sent[0].To)
+	require.Equal(t, "[#"+bug2.Id().Human()+"] typo in the doc", report.Sent[0].Subject)
+	require.Equal(t, BugMessageId(bug2.Id()), report.Sent[0].InReplyTo)
+	require.Contains(t, report.Sent[0].Body, "Ada Lovelace opened the bug")
+
+	require.Equal(t, "[#"+bug1.Id().Human()+"] crash on start", report.Sent[1].Subject)
+	require.Contains(t, report.Sent[1].Body, "Ada Lovelace commented")
+	require.Contains(t, report.Sent[1].Body, "> same here")
+	require.NotContains(t, report.Sent[1].Body, "René Descartes added")
+	require.NotContains(t, report.Sent[1].Body, "it crashes")
+	require.Contains(t, report.Sent[1].Body, `subscribed to the changes of the bugs matching "status:open"`)
+
+	// nothing changed
+	report, err = Run(c, sender, start.Add(6*time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, report.Sent)
+
+	// changes dated before the previous run, as pulled from another
+	// repository, are still new
+	_, _, err = bug1.EditCreateCommentRaw(ada, at(-time.Hour), "it crashes on start", nil)
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	report, err = Run(c, sender, start.Add(6*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, report.Sent, 1)
+	require.Contains(t, report.Sent[0].Body, "Ada Lovelace edited a comment")
+	require.Contains(t, report.Sent[0].Body, "> it crashes on start")
+	require.NotContains(t, report.Sent[0].Body, "same here")
+
+	_, err = bug1.CloseRaw(rene, at(7*time.Minute), nil)
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	// the digest is due
+	report, err = Run(c, sender, start.Add(time.Minute+DigestInterval))
+	require.NoError(t, err)
+	require.Len(t, report.Sent, 1)
+
+	digest := report.Sent[0]
+	require.Equal(t, "Ada <ada@work.uk>", digest.To)
+	require.Equal(t, "git-bug digest: 1 bug(s) changed", digest.Subject)
+	require.Contains(t, digest.Body, "[#"+bug1.Id().Human()+"] crash on start")
+	require.Contains(t, digest.Body, `René Descartes added the label(s) "crash"`)
+	require.Contains(t, digest.Body, "René Descartes closed the bug")
+	require.NotContains(t, digest.Body, "same here")
+	require.NotContains(t, digest.Body, "it crashes on start")
+	require.NotContains(t, digest.Body, "typo in the doc")
+
+	report, err = Run(c, sender, start.Add(2*time.Minute+DigestInterval))
+	require.NoError(t, err)
+	require.Empty(t, report.Sent)
+	require.Len(t, sender.sent, 4)
+}
+
+type failingSender struct {
+	recordSender
+	failAfter int
+}
+
+func (f *failingSender) Send(m *Message) error {
+	if len(f.sent) >= f.failAfter {
+		return fmt.Errorf("connection lost")
+	}
+	return f.recordSender.Send(m)
+}
+
+func TestRunPartialFailure(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	c, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer c.Close()
+
+	rene, err := c.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	ada, err := c.Identities().New("Ada Lovelace", "ada@lovelace.uk")
+	require.NoError(t, err)
+
+	require.NoError(t, Subscribe(c, Subscription{IdentityId: rene.Id(), Mode: Immediate}))
+
+	now := time.Now()
+
+	report, err := Run(c, &recordSender{}, now)
+	require.NoError(t, err)
+	require.Empty(t, report.Sent)
+
+	_, _, err = c.Bugs().NewRaw(ada, now.Unix(), "crash on start", "", nil, nil)
+	require.NoError(t, err)
+	_, _, err = c.Bugs().NewRaw(ada, now.Unix()+1, "typo in the doc", "", nil, nil)
+	require.NoError(t, err)
+
+	sender := &failingSender{failAfter: 1}
+	report, err = Run(c, sender, now)
+	require.Error(t, err)
+	require.Len(t, report.Sent, 1)
+	require.Contains(t, report.Sent[0].Subject, "typo in the doc")
+
+	// only the message that failed is sent again
+	sender.failAfter = 2
+	report, err = Run(c, sender, now)
+	require.NoError(t, err)
+	require.Len(t, report.Sent, 1)
+	require.Contains(t, report.Sent[0].Subject, "crash on start")
+}
@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// smtpKeyPrefix is the prefix of the git config keys of the SMTP server
+const smtpKeyPrefix = "git-bug.smtp"
+
+// smtpPasswordKey is the keyring key of the SMTP password
+const smtpPasswordKey = "smtp-password"
+
+var ErrSMTPNotConfigured = errors.New("no SMTP server configured, set " + smtpKeyPrefix + ".host and " + smtpKeyPrefix + ".from")
+
+// TLS modes of the connection to the SMTP server
+const (
+	// TLSStartTLS upgrade the connection with STARTTLS if the server support it
+	TLSStartTLS = "starttls"
+	// TLSImplicit connect with TLS directly, usually on port 465
+	TLSImplicit = "tls"
+	// TLSNone never encrypt the connection
+	TLSNone = "none"
+)
+
+// SMTPConfig is the SMTP server sending the notifications, configured with
+// the git-bug.smtp.* git config. The password is stored in the keyring.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	From     string
+	TLS      string
+}
+
+// LoadSMTPConfig read the SMTP configuration, or return ErrSMTPNotConfigured
+func LoadSMTPConfig(config repository.ConfigRead) (*SMTPConfig, error) {
+	read := func(key string, defaultValue string) (string, error) {
+		value, err := config.ReadString(smtpKeyPrefix + "." + key)
+		if errors.Is(err, repository.ErrNoConfigEntry) {
+			return defaultValue, nil
+		}
+		return value, err
+	}
+
+	conf := &SMTPConfig{}
+	var err error
+
+	if conf.Host, err = read("host", ""); err != nil {
+		return nil, err
+	}
+	if conf.From, err = read("from", ""); err != nil {
+		return nil, err
+	}
+	if conf.Host == "" || conf.From == "" {
+		return nil, ErrSMTPNotConfigured
+	}
+	if conf.Username, err = read("username", ""); err != nil {
+		return nil, err
+	}
+	if conf.TLS, err = read("tls", TLSStartTLS); err != nil {
+		return nil, err
+	}
+
+	defaultPort := "587"
+	switch conf.TLS {
+	case TLSStartTLS, TLSNone:
+	case TLSImplicit:
+		defaultPort = "465"
+	default:
+		return nil, fmt.Errorf("invalid %s.tls %q, expected %s, %s or %s", smtpKeyPrefix, conf.TLS, TLSStartTLS, TLSImplicit, TLSNone)
+	}
+
+	port, err := read("port", defaultPort)
+	if err != nil {
+		return nil, err
+	}
+	conf.Port, err = strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s.port: %w", smtpKeyPrefix, err)
+	}
+
+	return conf, nil
+}
+
+// StoreSMTPPassword store the password of the SMTP server in the keyring
+func StoreSMTPPassword(repo repository.RepoKeyring, password string) error {
+	return repo.Keyring().Set(repository.Item{
+		Key:  smtpPasswordKey,
+		Data: []byte(password),
+	})
+}
+
+func loadSMTPPassword(repo repository.RepoKeyring) (string, error) {
+	item, err := repo.Keyring().Get(smtpPasswordKey)
+	if errors.Is(err, repository.ErrKeyringKeyNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(item.Data), nil
+}
+
+// Sender send the notification emails
+type Sender interface {
+	Send(m *Message) error
+}
+
+var _ Sender = &SMTPSender{}
+
+type SMTPSender struct {
+	config   SMTPConfig
+	password string
+}
+
+type smtpRepo interface {
+	repository.RepoConfig
+	repository.RepoKeyring
+}
+
+// NewSMTPSender create a Sender with the SMTP configuration of the
+// repository
+func NewSMTPSender(repo smtpRepo) (*SMTPSender, error) {
+	conf, err := LoadSMTPConfig(repo.AnyConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := loadSMTPPassword(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SMTPSender{config: *conf, password: password}, nil
+}
+
+// From return the sender address of the emails
+func (s *SMTPSender) From() string {
+	return s.config.From
+}
+
+func (s *SMTPSender) Send(m *Message) error {
+	to, err := m.Recipient()
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	tlsConfig := &tls.Config{ServerName: s.config.Host}
+
+	var c *smtp.Client
+	if s.config.TLS == TLSImplicit {
+		conn, err := tls.Dial("tcp", addr, tlsConfig)
+		if err != nil {
+			return err
+		}
+		c, err = smtp.NewClient(conn, s.config.Host)
+		if err != nil {
+			_ = conn.Close()
+			return err
+		}
+	} else {
+		c, err = smtp.Dial(addr)
+		if err != nil {
+			return err
+		}
+	}
+	defer c.Close()
+
+	if s.config.TLS == TLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.config.Username != "" {
+		// PlainAuth refuse to send the password on an unencrypted connection,
+		// except to localhost
+		err := c.Auth(smtp.PlainAuth("", s.config.Username, s.password, s.config.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	from, err := (&Message{To: s.config.From}).Recipient()
+	if err != nil {
+		return err
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.Bytes(s.config.From)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// smtpStandIn is a minimal SMTP server recording the received emails
+type smtpStandIn struct {
+	listener net.Listener
+	received chan smtpMail
+}
+
+type smtpMail struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	s := &smtpStandIn{listener: listener, received: make(chan smtpMail, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = conn.Write([]byte(line + "\r\n"))
+	}
+
+	var mail smtpMail
+	reply("220 localhost ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch cmd {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			mail.auth = line
+			reply("235 authenticated")
+		case "MAIL":
+			mail.from = line
+			reply("250 ok")
+		case "RCPT":
+			mail.to = append(mail.to, line)
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			mail.data = data.String()
+			s.received <- mail
+			mail = smtpMail{}
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	repo := repository.NewMockRepo()
+
+	_, err := NewSMTPSender(repo)
+	require.ErrorIs(t, err, ErrSMTPNotConfigured)
+
+	server := newSMTPStandIn(t)
+
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.smtp.host", "127.0.0.1"))
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.smtp.port", strconv.Itoa(server.port())))
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.smtp.from", "git-bug <bugs@example.com>"))
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.smtp.username", "bugs"))
+	require.NoError(t, StoreSMTPPassword(repo, "secret"))
+
+	sender, err := NewSMTPSender(repo)
+	require.NoError(t, err)
+
+	err = sender.Send(&Message{
+		To:        "René Descartes <rene@descartes.fr>",
+		Subject:   "[#1234567] ça plante",
+		Date:      time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		MessageId: "<bug.1234.1@git-bug>",
+		InReplyTo: "<bug.1234@git-bug>",
+		Body:      "Ada commented:\n\n> ça plante aussi\n",
+	})
+	require.NoError(t, err)
+
+	mail := <-server.received
+	// "\x00bugs\x00secret" in base64
+	require.Equal(t, "AUTH PLAIN AGJ1Z3MAc2VjcmV0", mail.auth)
+	require.Equal(t, "MAIL FROM:<bugs@example.com>", mail.from)
+	require.Equal(t, []string{"RCPT TO:<rene@descartes.fr>"}, mail.to)
+	require.Contains(t, mail.data, "From: \"git-bug\" <bugs@example.com>\r\n")
+	require.Contains(t, mail.data, "To: =?utf-8?q?Ren=C3=A9_Descartes?= <rene@descartes.fr>\r\n")
+	require.Contains(t, mail.data, "Subject: =?utf-8?q?[#1234567]_=C3=A7a_plante?=\r\n")
+	require.Contains(t, mail.data, "Date: Fri, 01 Mar 2024 12:00:00 +0000\r\n")
+	require.Contains(t, mail.data, "In-Reply-To: <bug.1234@git-bug>\r\n")
+	require.Contains(t, mail.data, "\r\n\r\nAda commented:\r\n\r\n> =C3=A7a plante aussi\r\n")
+
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.smtp.tls", "ssl"))
+	_, err = NewSMTPSender(repo)
+	require.Error(t, err)
+}
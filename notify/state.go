@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// stateFile is where what was already notified is stored, in the local
+// storage of the repository
+const stateFile = "notify.json"
+
+type state struct {
+	Identities map[entity.Id]*identityState `json:"identities"`
+}
+
+type identityState struct {
+	// Query is the query of the subscription when the state was recorded
+	Query string `json:"query"`
+	// LastRun is when the changes were last checked
+	LastRun time.Time `json:"last_run"`
+	// LastDigest is when the last digest was sent
+	LastDigest time.Time `json:"last_digest"`
+	// EditLamportTime is the highest Lamport edit time of all the bugs when
+	// the changes were last checked
+	EditLamportTime lamport.Time `json:"edit_lamport_time"`
+	// Bugs are the matching bugs, as already notified
+	Bugs map[entity.Id]bugState `json:"bugs"`
+}
+
+type bugState struct {
+	// EditLamportTime is the Lamport edit time of the bug last notified
+	EditLamportTime lamport.Time `json:"edit_lamport_time"`
+}
+
+func loadState(repo repository.RepoStorage) (*state, error) {
+	st := &state{Identities: make(map[entity.Id]*identityState)}
+
+	f, err := repo.LocalStorage().Open(stateFile)
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("reading the notification state: %w", err)
+	}
+	if st.Identities == nil {
+		st.Identities = make(map[entity.Id]*identityState)
+	}
+
+	return st, nil
+}
+
+func saveState(repo repository.RepoStorage, st *state) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	f, err := repo.LocalStorage().OpenFile(stateFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -47,6 +47,8 @@ func Parse(query string) (*Query, error) {
 				q.Label = append(q.Label, t.value)
 			case "title":
 				q.Title = append(q.Title, t.value)
+			case "watching":
+				q.Watching = append(q.Watching, t.value)
 			case "no":
 				switch t.value {
 				case "label":
@@ -53,6 +53,10 @@ func TestParse(t *testing.T) {
 			Filters: Filters{Title: []string{"Bug titleTwo"}},
 		}},
 
+		{"watching:me", &Query{
+			Filters: Filters{Watching: []string{"me"}},
+		}},
+
 		{"no:label", &Query{
 			Filters: Filters{NoLabel: true},
 		}},
@@ -1,9 +1,22 @@
 package query
 
 import (
+	"strings"
+
 	"github.com/MichaelMure/git-bug/entities/common"
 )
 
+// Me is the special value of the identity qualifiers (author, actor,
+// participant, watching) designating the current user. It is resolved when
+// the query is executed, not when parsed.
+const Me = "me"
+
+// IsMe return true if the value of an identity qualifier designate the
+// current user
+func IsMe(value string) bool {
+	return strings.EqualFold(value, Me)
+}
+
 // Query is the intermediary representation of a Bug's query. It is either
 // produced by parsing a query string (ex: "status:open author:rene") or created
 // manually. This query doesn't do anything by itself and need to be interpreted
@@ -40,6 +53,7 @@ type Filters struct {
 	Participant []string
 	Label       []string
 	Title       []string
+	Watching    []string
 	NoLabel     bool
 }
 
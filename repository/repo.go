@@ -18,6 +18,9 @@ var (
 	ErrClockNotExist = errors.New("clock doesn't exist")
 	// ErrNotFound is the error returned when a git object can't be found
 	ErrNotFound = errors.New("ref not found")
+	// ErrSandbox is the error returned when trying to reach a remote from a
+	// sandbox repository
+	ErrSandbox = errors.New("not available in a sandbox")
 )
 
 // Repo represents a source code repository.
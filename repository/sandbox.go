@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+var _ ClockedRepo = &sandboxRepo{}
+
+// sandboxRepo is a ClockedRepo reading from another repository, but keeping in
+// memory everything written to it. It allows to find out what an operation
+// would do, without touching the real repository.
+type sandboxRepo struct {
+	base ClockedRepo
+
+	*mockRepoConfig
+	*mockRepoStorage
+	*mockRepoIndex
+
+	keyring *sandboxKeyring
+
+	// data written in the sandbox, and the refs removed from the base
+	mu          sync.Mutex
+	data        *mockRepoData
+	removedRefs map[string]struct{}
+
+	clocksMu sync.Mutex
+	clocks   map[string]lamport.Clock
+}
+
+// NewSandboxRepo create a repository on top of base, where the writes are kept
+// in memory and discarded on Close. The configuration, the data and the clocks
+// of base are visible in the sandbox. The local storage and the indexes start
+// empty, so a cache opened on top of a sandbox has to rebuild itself.
+func NewSandboxRepo(base ClockedRepo) (ClockedRepo, error) {
+	repo := &sandboxRepo{
+		base:            base,
+		mockRepoConfig:  NewMockRepoConfig(),
+		mockRepoStorage: NewMockRepoStorage(),
+		mockRepoIndex:   newMockRepoIndex(),
+		keyring: &sandboxKeyring{
+			base:    base.Keyring(),
+			items:   make(map[string]Item),
+			removed: make(map[string]struct{}),
+		},
+		data:        NewMockRepoData(),
+		removedRefs: make(map[string]struct{}),
+		clocks:      make(map[string]lamport.Clock),
+	}
+
+	// in-memory copies of the clocks, so that the base ones are never incremented
+	clocks, err := base.AllClocks()
+	if err != nil {
+		return nil, err
+	}
+	for name, c := range clocks {
+		repo.clocks[name] = lamport.NewMemClockWithTime(uint64(c.Time()))
+	}
+
+	copies := []struct {
+		from Config
+		to   *MemConfig
+	}{
+		{base.LocalConfig(), repo.localConfig},
+		{base.GlobalConfig(), repo.globalConfig},
+	}
+	for _, c := range copies {
+		values, err := c.from.ReadAll("")
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			if err := c.to.StoreString(key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return repo, nil
+}
+
+func (s *sandboxRepo) Keyring() Keyring {
+	return s.keyring
+}
+
+func (s *sandboxRepo) GetUserName() (string, error) {
+	return s.base.GetUserName()
+}
+
+func (s *sandboxRepo) GetUserEmail() (string, error) {
+	return s.base.GetUserEmail()
+}
+
+func (s *sandboxRepo) GetCoreEditor() (string, error) {
+	return s.base.GetCoreEditor()
+}
+
+func (s *sandboxRepo) GetRemotes() (map[string]string, error) {
+	return s.base.GetRemotes()
+}
+
+func (s *sandboxRepo) Close() error {
+	return nil
+}
+
+func (s *sandboxRepo) FetchRefs(remote string, prefixes ...string) (string, error) {
+	return "", ErrSandbox
+}
+
+func (s *sandboxRepo) PushRefs(remote string, prefixes ...string) (string, error) {
+	return "", ErrSandbox
+}
+
+func (s *sandboxRepo) StoreData(data []byte) (Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.StoreData(data)
+}
+
+func (s *sandboxRepo) ReadData(hash Hash) ([]byte, error) {
+	s.mu.Lock()
+	data, err := s.data.ReadData(hash)
+	s.mu.Unlock()
+	if err == ErrNotFound {
+		return s.base.ReadData(hash)
+	}
+	return data, err
+}
+
+func (s *sandboxRepo) StoreTree(mapping []TreeEntry) (Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.StoreTree(mapping)
+}
+
+func (s *sandboxRepo) ReadTree(hash Hash) ([]TreeEntry, error) {
+	s.mu.Lock()
+	entries, err := s.data.ReadTree(hash)
+	s.mu.Unlock()
+	if err == ErrNotFound {
+		return s.base.ReadTree(hash)
+	}
+	return entries, err
+}
+
+func (s *sandboxRepo) StoreCommit(treeHash Hash, parents ...Hash) (Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.StoreCommit(treeHash, parents...)
+}
+
+func (s *sandboxRepo) StoreSignedCommit(treeHash Hash, signKey *openpgp.Entity, parents ...Hash) (Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.StoreSignedCommit(treeHash, signKey, parents...)
+}
+
+func (s *sandboxRepo) ReadCommit(hash Hash) (Commit, error) {
+	s.mu.Lock()
+	commit, err := s.data.ReadCommit(hash)
+	s.mu.Unlock()
+	if err == ErrNotFound {
+		return s.base.ReadCommit(hash)
+	}
+	return commit, err
+}
+
+func (s *sandboxRepo) ResolveRef(ref string) (Hash, error) {
+	s.mu.Lock()
+	hash, err := s.data.ResolveRef(ref)
+	_, removed := s.removedRefs[ref]
+	s.mu.Unlock()
+	if err == ErrNotFound {
+		if removed {
+			return "", ErrNotFound
+		}
+		return s.base.ResolveRef(ref)
+	}
+	return hash, err
+}
+
+func (s *sandboxRepo) UpdateRef(ref string, hash Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.removedRefs, ref)
+	return s.data.UpdateRef(ref, hash)
+}
+
+func (s *sandboxRepo) RemoveRef(ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removedRefs[ref] = struct{}{}
+	return s.data.RemoveRef(ref)
+}
+
+func (s *sandboxRepo) ListRefs(refPrefix string) ([]string, error) {
+	refs, err := s.base.ListRefs(refPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	set := make(map[string]struct{})
+	for _, ref := range refs {
+		if _, removed := s.removedRefs[ref]; !removed {
+			set[ref] = struct{}{}
+		}
+	}
+	added, err := s.data.ListRefs(refPrefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range added {
+		set[ref] = struct{}{}
+	}
+
+	result := make([]string, 0, len(set))
+	for ref := range set {
+		result = append(result, ref)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func (s *sandboxRepo) RefExist(ref string) (bool, error) {
+	_, err := s.ResolveRef(ref)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *sandboxRepo) CopyRef(source string, dest string) error {
+	hash, err := s.ResolveRef(source)
+	if err != nil {
+		return err
+	}
+	return s.UpdateRef(dest, hash)
+}
+
+func (s *sandboxRepo) ListCommits(ref string) ([]Hash, error) {
+	return nonNativeListCommits(s, ref)
+}
+
+func (s *sandboxRepo) AllClocks() (map[string]lamport.Clock, error) {
+	s.clocksMu.Lock()
+	defer s.clocksMu.Unlock()
+
+	result := make(map[string]lamport.Clock, len(s.clocks))
+	for name, c := range s.clocks {
+		result[name] = c
+	}
+	return result, nil
+}
+
+func (s *sandboxRepo) GetOrCreateClock(name string) (lamport.Clock, error) {
+	s.clocksMu.Lock()
+	defer s.clocksMu.Unlock()
+
+	if c, ok := s.clocks[name]; ok {
+		return c, nil
+	}
+
+	c := lamport.NewMemClock()
+	s.clocks[name] = c
+	return c, nil
+}
+
+func (s *sandboxRepo) Increment(name string) (lamport.Time, error) {
+	c, err := s.GetOrCreateClock(name)
+	if err != nil {
+		return lamport.Time(0), err
+	}
+	return c.Increment()
+}
+
+func (s *sandboxRepo) Witness(name string, time lamport.Time) error {
+	c, err := s.GetOrCreateClock(name)
+	if err != nil {
+		return err
+	}
+	return c.Witness(time)
+}
+
+var _ Keyring = &sandboxKeyring{}
+
+// sandboxKeyring read the keyring of the base repository, but keep the changes
+// in memory
+type sandboxKeyring struct {
+	base Keyring
+
+	mu      sync.Mutex
+	items   map[string]Item
+	removed map[string]struct{}
+}
+
+func (k *sandboxKeyring) Get(key string) (Item, error) {
+	k.mu.Lock()
+	item, ok := k.items[key]
+	_, removed := k.removed[key]
+	k.mu.Unlock()
+
+	switch {
+	case ok:
+		return item, nil
+	case removed:
+		return Item{}, ErrKeyringKeyNotFound
+	default:
+		return k.base.Get(key)
+	}
+}
+
+func (k *sandboxKeyring) Set(item Item) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.removed, item.Key)
+	k.items[item.Key] = item
+	return nil
+}
+
+func (k *sandboxKeyring) Remove(key string) error {
+	if _, err := k.Get(key); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.items, key)
+	k.removed[key] = struct{}{}
+	return nil
+}
+
+func (k *sandboxKeyring) Keys() ([]string, error) {
+	keys, err := k.base.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	var result []string
+	for _, key := range keys {
+		_, removed := k.removed[key]
+		_, overridden := k.items[key]
+		if !removed && !overridden {
+			result = append(result, key)
+		}
+	}
+	for key := range k.items {
+		result = append(result, key)
+	}
+
+	return result, nil
+}
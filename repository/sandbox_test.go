@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxRepo(t *testing.T) {
+	base := CreateGoGitTestRepo(t, false)
+
+	sandbox, err := NewSandboxRepo(base)
+	require.NoError(t, err)
+
+	t.Run("Data", func(t *testing.T) {
+		RepoDataTest(t, sandbox)
+		RepoDataSignatureTest(t, sandbox)
+	})
+	t.Run("Config", func(t *testing.T) {
+		RepoConfigTest(t, sandbox)
+	})
+	t.Run("Storage", func(t *testing.T) {
+		RepoStorageTest(t, sandbox)
+	})
+	t.Run("Index", func(t *testing.T) {
+		RepoIndexTest(t, sandbox)
+	})
+	t.Run("Clocks", func(t *testing.T) {
+		RepoClockTest(t, sandbox)
+	})
+}
+
+func TestSandboxRepoIsolation(t *testing.T) {
+	base := CreateGoGitTestRepo(t, false)
+
+	// existing data
+	blob, err := base.StoreData([]byte("data"))
+	require.NoError(t, err)
+	tree, err := base.StoreTree([]TreeEntry{{ObjectType: Blob, Hash: blob, Name: "blob"}})
+	require.NoError(t, err)
+	commit, err := base.StoreCommit(tree)
+	require.NoError(t, err)
+	require.NoError(t, base.UpdateRef("refs/bugs/base", commit))
+	require.NoError(t, base.LocalConfig().StoreString("git-bug.foo", "bar"))
+	_, err = base.Increment("clock")
+	require.NoError(t, err)
+	baseClock, err := base.GetOrCreateClock("clock")
+	require.NoError(t, err)
+	baseTime := baseClock.Time()
+
+	sandbox, err := NewSandboxRepo(base)
+	require.NoError(t, err)
+
+	// the base is visible from the sandbox
+	data, err := sandbox.ReadData(blob)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+	commits, err := sandbox.ListCommits("refs/bugs/base")
+	require.NoError(t, err)
+	require.Equal(t, []Hash{commit}, commits)
+	val, err := sandbox.LocalConfig().ReadString("git-bug.foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", val)
+
+	// writes stay in the sandbox
+	blob2, err := sandbox.StoreData([]byte("sandboxed"))
+	require.NoError(t, err)
+	tree2, err := sandbox.StoreTree([]TreeEntry{{ObjectType: Blob, Hash: blob2, Name: "blob"}})
+	require.NoError(t, err)
+	commit2, err := sandbox.StoreCommit(tree2, commit)
+	require.NoError(t, err)
+	require.NoError(t, sandbox.UpdateRef("refs/bugs/sandbox", commit2))
+	require.NoError(t, sandbox.RemoveRef("refs/bugs/base"))
+	require.NoError(t, sandbox.LocalConfig().StoreString("git-bug.foo", "baz"))
+	_, err = sandbox.Increment("clock")
+	require.NoError(t, err)
+	sandboxClock, err := sandbox.GetOrCreateClock("clock")
+	require.NoError(t, err)
+	require.Equal(t, baseTime+1, sandboxClock.Time())
+
+	refs, err := sandbox.ListRefs("refs/bugs/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"refs/bugs/sandbox"}, refs)
+	commits, err = sandbox.ListCommits("refs/bugs/sandbox")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []Hash{commit2, commit}, commits)
+
+	_, err = base.ReadData(blob2)
+	require.ErrorIs(t, err, ErrNotFound)
+	refs, err = base.ListRefs("refs/bugs/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"refs/bugs/base"}, refs)
+	val, err = base.LocalConfig().ReadString("git-bug.foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", val)
+	require.Equal(t, baseTime, baseClock.Time())
+}
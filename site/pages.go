@@ -0,0 +1,555 @@
+package site
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/MichaelMure/git-bug/api/feed"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/common"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// page is the data given to the page templates. Depending on the page, only
+// some fields are set.
+type page struct {
+	// Root is the relative path to the root of the site
+	Root      string
+	SiteTitle string
+	Title     string
+	Generated time.Time
+
+	Bugs         []bugView
+	Bug          *bugPageView
+	Labels       []labelCount
+	Identity     *identityView
+	Participated *bugTable
+}
+
+type bugTable struct {
+	Root string
+	Bugs []bugView
+}
+
+type siteBug struct {
+	excerpt *cache.BugExcerpt
+	author  identityRef
+	view    bugView
+}
+
+type identityRef struct {
+	Id   entity.Id
+	Name string
+	Url  string
+}
+
+type labelView struct {
+	Name      string
+	Url       string
+	Color     template.CSS
+	TextColor template.CSS
+}
+
+type labelCount struct {
+	labelView
+	Count int
+}
+
+type bugView struct {
+	Id         entity.Id
+	HumanId    string
+	Title      string
+	Status     string
+	Labels     []labelView
+	Author     identityRef
+	CreateTime time.Time
+	EditTime   time.Time
+	Comments   int
+	Url        string
+}
+
+type bugPageView struct {
+	bugView
+	Participants []identityRef
+	Timeline     []timelineItemView
+}
+
+type timelineItemView struct {
+	Type   string
+	Anchor string
+	Author identityRef
+	Time   time.Time
+
+	Comment     bool
+	Message     string
+	Edited      bool
+	Attachments []attachmentView
+
+	Added   []labelView
+	Removed []labelView
+
+	Status string
+
+	Title string
+	Was   string
+}
+
+type attachmentView struct {
+	Name  string
+	Url   string
+	Size  string
+	Image bool
+}
+
+type identityView struct {
+	Name      string
+	Login     string
+	AvatarUrl string
+}
+
+func bugUrl(id entity.Id) string {
+	return "bugs/" + id.String() + ".html"
+}
+
+func identityUrl(id entity.Id) string {
+	return "identities/" + id.String() + ".html"
+}
+
+func attachmentUrl(hash string) string {
+	return "attachments/" + hash
+}
+
+// labelSlugs give a file name to each label. The names are readable, with a
+// hash suffix when the label is not already a plain slug. A slug only depends
+// on its label, so that the links in the pages that are not written again
+// stay valid when other labels come and go.
+func labelSlugs(bugs []*siteBug) map[string]string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, b := range bugs {
+		for _, l := range b.excerpt.Labels {
+			if !seen[l.String()] {
+				seen[l.String()] = true
+				labels = append(labels, l.String())
+			}
+		}
+	}
+	sort.Strings(labels)
+
+	slug := func(label string) string {
+		var sb strings.Builder
+		dash := false
+		for _, r := range strings.ToLower(label) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				sb.WriteRune(r)
+				dash = false
+			} else if !dash && sb.Len() > 0 {
+				sb.WriteRune('-')
+				dash = true
+			}
+		}
+		return strings.TrimSuffix(sb.String(), "-")
+	}
+
+	result := make(map[string]string, len(labels))
+	for _, l := range labels {
+		s := slug(l)
+		if s != l || s == "index" {
+			hash := sha256.Sum256([]byte(l))
+			s = strings.TrimSuffix(s+"-"+hex.EncodeToString(hash[:])[:8], "-")
+			s = strings.TrimPrefix(s, "-")
+		}
+		result[l] = s
+	}
+	return result
+}
+
+func newLabelView(l bug.Label, slugs map[string]string) labelView {
+	c := l.Color().RGBA()
+	textColor := template.CSS("#fff")
+	if 0.299*float64(c.R)+0.587*float64(c.G)+0.114*float64(c.B) > 150 {
+		textColor = "#000"
+	}
+	return labelView{
+		Name:      l.String(),
+		Url:       "labels/" + slugs[l.String()] + ".html",
+		Color:     template.CSS(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)),
+		TextColor: textColor,
+	}
+}
+
+func newLabelViews(labels []bug.Label, slugs map[string]string) []labelView {
+	result := make([]labelView, len(labels))
+	for i, l := range labels {
+		result[i] = newLabelView(l, slugs)
+	}
+	return result
+}
+
+func newBugView(excerpt *cache.BugExcerpt, author identityRef, slugs map[string]string) bugView {
+	return bugView{
+		Id:         excerpt.Id(),
+		HumanId:    excerpt.Id().Human(),
+		Title:      excerpt.Title,
+		Status:     excerpt.Status.String(),
+		Labels:     newLabelViews(excerpt.Labels, slugs),
+		Author:     author,
+		CreateTime: excerpt.CreateTime(),
+		EditTime:   excerpt.EditTime(),
+		Comments:   excerpt.LenComments,
+		Url:        bugUrl(excerpt.Id()),
+	}
+}
+
+// writeBug write the page of a bug and its attachments, and return the text
+// to index for the search
+func (g *generator) writeBug(b *siteBug) (string, error) {
+	cached, err := g.repo.Bugs().Resolve(b.excerpt.Id())
+	if err != nil {
+		return "", err
+	}
+	snap := cached.Snapshot()
+
+	view := &bugPageView{bugView: b.view}
+
+	for _, p := range snap.Participants {
+		ref, err := g.identity(p.Id())
+		if err != nil {
+			return "", err
+		}
+		view.Participants = append(view.Participants, ref)
+	}
+
+	var search strings.Builder
+
+	for _, item := range snap.Timeline {
+		var v timelineItemView
+		var author entity.Id
+
+		switch item := item.(type) {
+		case *bug.CreateTimelineItem:
+			v, err = g.commentView("create", item.CommentTimelineItem)
+			author = item.Author.Id()
+		case *bug.AddCommentTimelineItem:
+			v, err = g.commentView("comment", item.CommentTimelineItem)
+			author = item.Author.Id()
+		case *bug.LabelChangeTimelineItem:
+			v = timelineItemView{
+				Type:    "label",
+				Time:    item.UnixTime.Time(),
+				Added:   newLabelViews(item.Added, g.labels),
+				Removed: newLabelViews(item.Removed, g.labels),
+			}
+			author = item.Author.Id()
+		case *bug.SetStatusTimelineItem:
+			v = timelineItemView{
+				Type:   "status",
+				Time:   item.UnixTime.Time(),
+				Status: item.Status.String(),
+			}
+			author = item.Author.Id()
+		case *bug.SetTitleTimelineItem:
+			v = timelineItemView{
+				Type:  "title",
+				Time:  item.UnixTime.Time(),
+				Title: item.Title,
+				Was:   item.Was,
+			}
+			author = item.Author.Id()
+		default:
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		v.Anchor = item.CombinedId().Human()
+		v.Author, err = g.identity(author)
+		if err != nil {
+			return "", err
+		}
+
+		if v.Comment && search.Len() < searchTextSize {
+			search.WriteString(v.Message)
+			search.WriteString("\n")
+		}
+
+		view.Timeline = append(view.Timeline, v)
+	}
+
+	err = g.writePage(b.view.Url, "bug.html", page{Title: snap.Title, Bug: view})
+	if err != nil {
+		return "", err
+	}
+
+	text := search.String()
+	if len(text) > searchTextSize {
+		text = strings.ToValidUTF8(text[:searchTextSize], "")
+	}
+	return text, nil
+}
+
+func (g *generator) commentView(typ string, item bug.CommentTimelineItem) (timelineItemView, error) {
+	v := timelineItemView{
+		Type:    typ,
+		Time:    item.CreatedAt.Time(),
+		Comment: true,
+		Message: item.Message,
+		Edited:  item.Edited(),
+	}
+
+	for _, attachment := range item.Attachments {
+		hash := attachment.Hash.String()
+		url := attachmentUrl(hash)
+
+		// attachments are immutable, no need to write them again
+		var data []byte
+		if !g.exists(url) || attachment.MimeType == "" {
+			var err error
+			data, err = g.repo.ReadData(attachment.Hash)
+			if err != nil {
+				return v, err
+			}
+			if err := g.writeFile(url, data); err != nil {
+				return v, err
+			}
+		}
+
+		mime := attachment.MimeType
+		if mime == "" {
+			mime = http.DetectContentType(data)
+		}
+
+		size := attachment.Size
+		if size == 0 {
+			size = int64(len(data))
+		}
+
+		v.Attachments = append(v.Attachments, attachmentView{
+			Name:  attachment.DisplayName(),
+			Url:   url,
+			Size:  humanize.Bytes(uint64(size)),
+			Image: strings.HasPrefix(mime, "image/") && mime != "image/svg+xml",
+		})
+	}
+
+	return v, nil
+}
+
+func (g *generator) writeLists(bugs []*siteBug) error {
+	var all, open, closed []bugView
+	byLabel := make(map[string][]bugView)
+
+	for _, b := range bugs {
+		all = append(all, b.view)
+		switch b.excerpt.Status {
+		case common.OpenStatus:
+			open = append(open, b.view)
+		case common.ClosedStatus:
+			closed = append(closed, b.view)
+		}
+		for _, l := range b.excerpt.Labels {
+			byLabel[l.String()] = append(byLabel[l.String()], b.view)
+		}
+	}
+
+	if err := g.writePage("index.html", "list.html", page{Title: "All bugs", Bugs: all}); err != nil {
+		return err
+	}
+	if err := g.writePage("open.html", "list.html", page{Title: "Open bugs", Bugs: open}); err != nil {
+		return err
+	}
+	if err := g.writePage("closed.html", "list.html", page{Title: "Closed bugs", Bugs: closed}); err != nil {
+		return err
+	}
+
+	// drop the pages of the labels not used anymore
+	if err := os.RemoveAll(filepath.Join(g.dir, "labels")); err != nil {
+		return err
+	}
+
+	var labels []labelCount
+	for name, views := range byLabel {
+		labels = append(labels, labelCount{
+			labelView: newLabelView(bug.Label(name), g.labels),
+			Count:     len(views),
+		})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+
+	if err := g.writePage("labels/index.html", "labels.html", page{Title: "Labels", Labels: labels}); err != nil {
+		return err
+	}
+	for _, l := range labels {
+		err := g.writePage(l.Url, "list.html", page{Title: "Label " + l.Name, Bugs: byLabel[l.Name]})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) writeIdentities(bugs []*siteBug) error {
+	// drop the pages of the identities not involved anymore
+	if err := os.RemoveAll(filepath.Join(g.dir, "identities")); err != nil {
+		return err
+	}
+
+	authored := make(map[entity.Id][]bugView)
+	participated := make(map[entity.Id][]bugView)
+
+	for _, b := range bugs {
+		authored[b.excerpt.AuthorId] = append(authored[b.excerpt.AuthorId], b.view)
+		for _, id := range b.excerpt.Participants {
+			if id != b.excerpt.AuthorId {
+				participated[id] = append(participated[id], b.view)
+			}
+		}
+		for _, id := range append(b.excerpt.Actors, b.excerpt.Participants...) {
+			if _, err := g.identity(id); err != nil {
+				return err
+			}
+		}
+	}
+
+	for id, ref := range g.identities {
+		i, err := g.repo.Identities().Resolve(id)
+		if err != nil {
+			return err
+		}
+
+		url := ref.Url
+		p := page{
+			Title: ref.Name,
+			Bugs:  authored[id],
+			Identity: &identityView{
+				Name:      ref.Name,
+				Login:     i.Login(),
+				AvatarUrl: i.AvatarUrl(),
+			},
+		}
+		if len(participated[id]) > 0 {
+			p.Participated = &bugTable{
+				Root: strings.Repeat("../", strings.Count(url, "/")),
+				Bugs: participated[id],
+			}
+		}
+		if err := g.writePage(url, "identity.html", p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type searchEntry struct {
+	Id     string   `json:"id"`
+	Title  string   `json:"title"`
+	Status string   `json:"status"`
+	Author string   `json:"author"`
+	Labels []string `json:"labels"`
+	Url    string   `json:"url"`
+	Text   string   `json:"text"`
+}
+
+func (g *generator) writeSearch(bugs []*siteBug, st *state) error {
+	index := make([]searchEntry, len(bugs))
+	for i, b := range bugs {
+		labels := make([]string, len(b.view.Labels))
+		for j, l := range b.view.Labels {
+			labels[j] = l.Name
+		}
+		index[i] = searchEntry{
+			Id:     b.view.HumanId,
+			Title:  b.view.Title,
+			Status: b.view.Status,
+			Author: b.author.Name,
+			Labels: labels,
+			Url:    b.view.Url,
+			Text:   st.Bugs[b.excerpt.Id()].SearchText,
+		}
+	}
+
+	// a script rather than a JSON file, so that the search also work when
+	// the site is opened from the local filesystem
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	script := "var gitBugSearchIndex = " + string(data) + ";\n"
+	if err := g.writeFile("search.js", []byte(script)); err != nil {
+		return err
+	}
+
+	return g.writePage("search.html", "search.html", page{Title: "Search"})
+}
+
+func (g *generator) writeFeed(bugs []*siteBug) error {
+	id := "urn:git-bug:site"
+	if g.opts.BaseURL != "" {
+		id = g.opts.BaseURL
+	}
+
+	f := feed.NewFeed(id, g.opts.Title)
+	f.Links = []feed.Link{
+		{Rel: "self", Href: g.opts.BaseURL + "atom.xml"},
+		{Rel: "alternate", Href: g.opts.BaseURL + "index.html", Type: "text/html"},
+	}
+
+	for i, b := range bugs {
+		if i >= feedSize {
+			break
+		}
+
+		cached, err := g.repo.Bugs().Resolve(b.excerpt.Id())
+		if err != nil {
+			return err
+		}
+		snap := cached.Snapshot()
+
+		entryId := "urn:git-bug:bug:" + b.excerpt.Id().String()
+		if g.opts.BaseURL != "" {
+			entryId = g.opts.BaseURL + b.view.Url
+		}
+		published := feed.Time(b.view.CreateTime)
+
+		entry := feed.Entry{
+			Id:        entryId,
+			Title:     fmt.Sprintf("[%s] %s", b.view.Status, b.view.Title),
+			Updated:   feed.Time(b.view.EditTime),
+			Published: &published,
+			Authors:   []feed.Person{{Name: b.author.Name}},
+			Links:     []feed.Link{{Rel: "alternate", Href: g.opts.BaseURL + b.view.Url, Type: "text/html"}},
+		}
+		for _, l := range b.view.Labels {
+			entry.Categories = append(entry.Categories, feed.Category{Term: l.Name})
+		}
+		if len(snap.Comments) > 0 && snap.Comments[0].Message != "" {
+			entry.Content = &feed.Text{Type: "text", Body: snap.Comments[0].Message}
+		}
+
+		f.Add(entry)
+	}
+
+	var buf strings.Builder
+	if err := f.Write(&buf); err != nil {
+		return err
+	}
+	return g.writeFile("atom.xml", []byte(buf.String()))
+}
@@ -0,0 +1,330 @@
+// Package site generate a static, read-only website of the bugs of a
+// repository, suitable to publish on any static host.
+package site
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// stateFile keep track of what was generated, to only regenerate the pages
+// of the bugs that changed
+const stateFile = ".git-bug-site.json"
+
+// stateVersion must be incremented when the generated pages change, to
+// regenerate everything
+const stateVersion = 2
+
+// feedSize is the number of bugs in the Atom feed
+const feedSize = 50
+
+// searchTextSize is the maximum size of the text of a bug indexed for the
+// search
+const searchTextSize = 4000
+
+type Options struct {
+	// Title of the site
+	Title string
+	// BaseURL is the public URL of the site, used for the absolute links of the
+	// Atom feed. If empty, the links are relative.
+	BaseURL string
+	// Force regenerate all the pages, even those that didn't change
+	Force bool
+}
+
+// Stats report what a generation did
+type Stats struct {
+	BugsWritten int
+	BugsSkipped int
+	BugsRemoved int
+}
+
+type state struct {
+	Version int                     `json:"version"`
+	Title   string                  `json:"title"`
+	BaseURL string                  `json:"base_url"`
+	Bugs    map[entity.Id]*bugState `json:"bugs"`
+	// Identities are the names of the identities written in the pages, to
+	// write again the pages of the bugs of a renamed identity
+	Identities map[entity.Id]string `json:"identities"`
+}
+
+type bugState struct {
+	EditLamportTime lamport.Time `json:"edit_lamport_time"`
+	// SearchText is the text indexed for the search, kept to not have to
+	// load the bugs that didn't change
+	SearchText string `json:"search_text"`
+}
+
+type generator struct {
+	repo      *cache.RepoCache
+	dir       string
+	opts      Options
+	templates *template.Template
+	generated time.Time
+
+	identities map[entity.Id]identityRef
+	labels     map[string]string
+}
+
+// Generate write the website of the bugs of a repository in a directory. The
+// pages of the bugs that didn't change since the previous generation in the
+// same directory are kept as is.
+func Generate(repo *cache.RepoCache, dir string, opts Options) (Stats, error) {
+	var stats Stats
+
+	if opts.Title == "" {
+		opts.Title = "Bugs"
+	}
+	if opts.BaseURL != "" && !strings.HasSuffix(opts.BaseURL, "/") {
+		opts.BaseURL += "/"
+	}
+
+	templates, err := template.ParseFS(assets, "assets/*.html")
+	if err != nil {
+		return stats, err
+	}
+
+	g := &generator{
+		repo:       repo,
+		dir:        dir,
+		opts:       opts,
+		templates:  templates,
+		generated:  time.Now(),
+		identities: make(map[entity.Id]identityRef),
+	}
+
+	st, err := g.loadState()
+	if err != nil {
+		return stats, err
+	}
+
+	bugs, err := g.loadBugs()
+	if err != nil {
+		return stats, err
+	}
+
+	// bug pages
+	present := make(map[entity.Id]bool, len(bugs))
+	for _, b := range bugs {
+		present[b.excerpt.Id()] = true
+
+		renamed, err := g.renamedIdentities(b, st)
+		if err != nil {
+			return stats, err
+		}
+
+		prev, ok := st.Bugs[b.excerpt.Id()]
+		if ok && prev.EditLamportTime == b.excerpt.EditLamportTime && !renamed && g.exists(b.view.Url) {
+			stats.BugsSkipped++
+			continue
+		}
+
+		text, err := g.writeBug(b)
+		if err != nil {
+			return stats, err
+		}
+		st.Bugs[b.excerpt.Id()] = &bugState{
+			EditLamportTime: b.excerpt.EditLamportTime,
+			SearchText:      text,
+		}
+		stats.BugsWritten++
+	}
+
+	for id := range st.Bugs {
+		if present[id] {
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, bugUrl(id)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return stats, err
+		}
+		delete(st.Bugs, id)
+		stats.BugsRemoved++
+	}
+
+	st.Identities = make(map[entity.Id]string)
+	for _, b := range bugs {
+		for _, id := range bugIdentities(b) {
+			st.Identities[id] = g.identities[id].Name
+		}
+	}
+
+	// the lists and identity pages are cheap, and always regenerated
+	if err := g.writeLists(bugs); err != nil {
+		return stats, err
+	}
+	if err := g.writeIdentities(bugs); err != nil {
+		return stats, err
+	}
+	if err := g.writeSearch(bugs, st); err != nil {
+		return stats, err
+	}
+	if err := g.writeFeed(bugs); err != nil {
+		return stats, err
+	}
+
+	style, err := assets.ReadFile("assets/style.css")
+	if err != nil {
+		return stats, err
+	}
+	if err := g.writeFile("style.css", style); err != nil {
+		return stats, err
+	}
+
+	return stats, g.saveState(st)
+}
+
+func (g *generator) loadState() (*state, error) {
+	fresh := &state{
+		Version: stateVersion,
+		Title:   g.opts.Title,
+		BaseURL: g.opts.BaseURL,
+		Bugs:    make(map[entity.Id]*bugState),
+	}
+
+	if g.opts.Force {
+		return fresh, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(g.dir, stateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fresh, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", stateFile, err)
+	}
+
+	// the options are in all the pages
+	if st.Version != stateVersion || st.Title != g.opts.Title || st.BaseURL != g.opts.BaseURL || st.Bugs == nil {
+		return fresh, nil
+	}
+
+	return &st, nil
+}
+
+func (g *generator) saveState(st *state) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return g.writeFile(stateFile, data)
+}
+
+// loadBugs load the excerpts of all the bugs, most recently edited first
+func (g *generator) loadBugs() ([]*siteBug, error) {
+	ids := g.repo.Bugs().AllIds()
+
+	bugs := make([]*siteBug, 0, len(ids))
+	for _, id := range ids {
+		excerpt, err := g.repo.Bugs().ResolveExcerpt(id)
+		if err != nil {
+			return nil, err
+		}
+		author, err := g.identity(excerpt.AuthorId)
+		if err != nil {
+			return nil, err
+		}
+		bugs = append(bugs, &siteBug{excerpt: excerpt, author: author})
+	}
+
+	sort.Slice(bugs, func(i, j int) bool {
+		if bugs[i].excerpt.EditUnixTime != bugs[j].excerpt.EditUnixTime {
+			return bugs[i].excerpt.EditUnixTime > bugs[j].excerpt.EditUnixTime
+		}
+		return bugs[i].excerpt.Id() < bugs[j].excerpt.Id()
+	})
+
+	g.labels = labelSlugs(bugs)
+	for _, b := range bugs {
+		b.view = newBugView(b.excerpt, b.author, g.labels)
+	}
+
+	return bugs, nil
+}
+
+// bugIdentities return the identities whose names are in the page of a bug
+func bugIdentities(b *siteBug) []entity.Id {
+	ids := append([]entity.Id{b.excerpt.AuthorId}, b.excerpt.Actors...)
+	return append(ids, b.excerpt.Participants...)
+}
+
+// renamedIdentities tell if the name of an identity involved in a bug changed
+// since the page of the bug was written
+func (g *generator) renamedIdentities(b *siteBug, st *state) (bool, error) {
+	renamed := false
+	for _, id := range bugIdentities(b) {
+		ref, err := g.identity(id)
+		if err != nil {
+			return false, err
+		}
+		if name, ok := st.Identities[id]; !ok || name != ref.Name {
+			renamed = true
+		}
+	}
+	return renamed, nil
+}
+
+// identity return the reference to an identity page, loading it if needed
+func (g *generator) identity(id entity.Id) (identityRef, error) {
+	if ref, ok := g.identities[id]; ok {
+		return ref, nil
+	}
+	excerpt, err := g.repo.Identities().ResolveExcerpt(id)
+	if err != nil {
+		return identityRef{}, err
+	}
+	ref := identityRef{
+		Id:   id,
+		Name: excerpt.DisplayName(),
+		Url:  identityUrl(id),
+	}
+	g.identities[id] = ref
+	return ref, nil
+}
+
+func (g *generator) exists(path string) bool {
+	_, err := os.Stat(filepath.Join(g.dir, path))
+	return err == nil
+}
+
+func (g *generator) writeFile(path string, data []byte) error {
+	path = filepath.Join(g.dir, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (g *generator) writePage(path string, name string, p page) error {
+	p.SiteTitle = g.opts.Title
+	p.Generated = g.generated
+	p.Root = strings.Repeat("../", strings.Count(path, "/"))
+
+	var buf strings.Builder
+	if err := g.templates.ExecuteTemplate(&buf, name, p); err != nil {
+		return err
+	}
+	return g.writeFile(path, []byte(buf.String()))
+}
@@ -0,0 +1,159 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entities/bug"
+	"github.com/MichaelMure/git-bug/entities/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestGenerate(t *testing.T) {
+	repo := repository.CreateGoGitTestRepo(t, false)
+
+	c, err := cache.NewRepoCacheNoEvents(repo)
+	require.NoError(t, err)
+	defer c.Close()
+
+	author, err := c.Identities().New("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, c.SetUserIdentity(author))
+
+	bug1, _, err := c.Bugs().New("first bug", "the first <message>")
+	require.NoError(t, err)
+	_, _, err = bug1.ChangeLabels([]string{"kind/bug", "Kind Bug"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	hash, err := c.StoreData(png)
+	require.NoError(t, err)
+
+	bug2, _, err := c.Bugs().New("second bug", "message")
+	require.NoError(t, err)
+	_, _, err = bug2.AddCommentWithAttachments("a screenshot", []bug.Attachment{
+		{Hash: hash, Name: "screen.png", MimeType: "image/png", Size: int64(len(png))},
+	})
+	require.NoError(t, err)
+	_, err = bug2.Close()
+	require.NoError(t, err)
+	require.NoError(t, bug2.Commit())
+
+	dir := t.TempDir()
+
+	stats, err := Generate(c, dir, Options{Title: "Test bugs", BaseURL: "https://bugs.example.com"})
+	require.NoError(t, err)
+	require.Equal(t, Stats{BugsWritten: 2}, stats)
+
+	read := func(path string) string {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	for _, path := range []string{"index.html", "open.html", "closed.html", "search.html", "style.css", stateFile} {
+		require.FileExists(t, filepath.Join(dir, path))
+	}
+
+	require.Contains(t, read("index.html"), "first bug")
+	require.Contains(t, read("index.html"), "second bug")
+	require.Contains(t, read("open.html"), "first bug")
+	require.NotContains(t, read("open.html"), "second bug")
+	require.Contains(t, read("closed.html"), "second bug")
+
+	// the message is escaped
+	page1 := read(bugUrl(bug1.Id()))
+	require.Contains(t, page1, "the first &lt;message&gt;")
+
+	page2 := read(bugUrl(bug2.Id()))
+	require.Contains(t, page2, "a screenshot")
+	require.Contains(t, page2, `src="../attachments/`+hash.String()+`"`)
+	require.Equal(t, string(png), read(attachmentUrl(hash.String())))
+
+	// colliding label slugs are disambiguated
+	labels := labelSlugs([]*siteBug{{excerpt: &cache.BugExcerpt{Labels: []bug.Label{"kind/bug", "Kind Bug"}}}})
+	require.NotEqual(t, labels["kind/bug"], labels["Kind Bug"])
+	require.FileExists(t, filepath.Join(dir, "labels", labels["kind/bug"]+".html"))
+	require.FileExists(t, filepath.Join(dir, "labels", labels["Kind Bug"]+".html"))
+	require.Contains(t, read("labels/index.html"), "kind/bug")
+
+	// the slugs don't depend on the other labels, and plain labels are kept
+	// as is
+	alone := labelSlugs([]*siteBug{{excerpt: &cache.BugExcerpt{Labels: []bug.Label{"kind/bug", "bug", "index"}}}})
+	require.Equal(t, labels["kind/bug"], alone["kind/bug"])
+	require.Equal(t, "bug", alone["bug"])
+	require.NotEqual(t, "index", alone["index"])
+
+	require.Contains(t, read(identityUrl(author.Id())), "René Descartes")
+
+	search := read("search.js")
+	require.Contains(t, search, "the first \\u003cmessage\\u003e")
+	require.Contains(t, search, "a screenshot")
+
+	atom := read("atom.xml")
+	require.Contains(t, atom, "<title>Test bugs</title>")
+	require.Contains(t, atom, "https://bugs.example.com/"+bugUrl(bug1.Id()))
+	require.Contains(t, atom, `<category term="kind/bug"></category>`)
+
+	// nothing changed, the bug pages are kept
+	stats, err = Generate(c, dir, Options{Title: "Test bugs", BaseURL: "https://bugs.example.com"})
+	require.NoError(t, err)
+	require.Equal(t, Stats{BugsSkipped: 2}, stats)
+	require.Contains(t, read("search.js"), "a screenshot")
+
+	// only the edited bug is written again
+	_, _, err = bug1.AddComment("another comment")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	stats, err = Generate(c, dir, Options{Title: "Test bugs", BaseURL: "https://bugs.example.com"})
+	require.NoError(t, err)
+	require.Equal(t, Stats{BugsWritten: 1, BugsSkipped: 1}, stats)
+	require.Contains(t, read(bugUrl(bug1.Id())), "another comment")
+
+	// the pages of the bugs of a renamed identity are written again
+	other, err := c.Identities().New("Blaise Pascal", "blaise@pascal.fr")
+	require.NoError(t, err)
+	_, _, err = bug2.AddCommentRaw(other, time.Now().Unix(), "a reply", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug2.Commit())
+
+	stats, err = Generate(c, dir, Options{Title: "Test bugs", BaseURL: "https://bugs.example.com"})
+	require.NoError(t, err)
+	require.Equal(t, Stats{BugsWritten: 1, BugsSkipped: 1}, stats)
+
+	err = other.Mutate(repo, func(m *identity.Mutator) {
+		m.Name = "B. Pascal"
+	})
+	require.NoError(t, err)
+	require.NoError(t, other.Commit())
+
+	stats, err = Generate(c, dir, Options{Title: "Test bugs", BaseURL: "https://bugs.example.com"})
+	require.NoError(t, err)
+	require.Equal(t, Stats{BugsWritten: 1, BugsSkipped: 1}, stats)
+	require.Contains(t, read(bugUrl(bug2.Id())), "B. Pascal")
+
+	// changing the options regenerate everything
+	stats, err = Generate(c, dir, Options{Title: "Other title"})
+	require.NoError(t, err)
+	require.Equal(t, Stats{BugsWritten: 2}, stats)
+
+	stats, err = Generate(c, dir, Options{Title: "Other title", Force: true})
+	require.NoError(t, err)
+	require.Equal(t, Stats{BugsWritten: 2}, stats)
+
+	// removed bugs are removed from the site
+	require.NoError(t, c.Bugs().Remove(bug2.Id().String()))
+
+	stats, err = Generate(c, dir, Options{Title: "Other title"})
+	require.NoError(t, err)
+	require.Equal(t, Stats{BugsSkipped: 1, BugsRemoved: 1}, stats)
+	require.NoFileExists(t, filepath.Join(dir, bugUrl(bug2.Id())))
+	require.NotContains(t, read("index.html"), "second bug")
+}
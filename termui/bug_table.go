@@ -13,6 +13,7 @@ import (
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/query"
+	"github.com/MichaelMure/git-bug/repository"
 	"github.com/MichaelMure/git-bug/util/colors"
 )
 
@@ -24,12 +25,17 @@ const bugTableInstructionView = "bugTableInstructionView"
 const defaultRemote = "origin"
 const defaultQuery = "status:open"
 
+// defaultQueryConfigKey is the git config key of the query to start with,
+// for example "status:open participant:me"
+const defaultQueryConfigKey = "git-bug.termui.query"
+
 var bugTableHelp = helpBar{
 	{"q", "Quit"},
 	{"s", "Search"},
 	{"←↓↑→,hjkl", "Navigation"},
 	{"↵", "Open bug"},
 	{"n", "New bug"},
+	{"w", "Watch"},
 	{"i", "Pull"},
 	{"o", "Push"},
 }
@@ -44,19 +50,26 @@ type bugTable struct {
 	selectCursor int
 }
 
-func newBugTable(c *cache.RepoCache) *bugTable {
-	q, err := query.Parse(defaultQuery)
+func newBugTable(c *cache.RepoCache) (*bugTable, error) {
+	queryStr, err := c.AnyConfig().ReadString(defaultQueryConfigKey)
+	if errors.Is(err, repository.ErrNoConfigEntry) {
+		queryStr = defaultQuery
+	} else if err != nil {
+		return nil, err
+	}
+
+	q, err := query.Parse(queryStr)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("invalid %s: %w", defaultQueryConfigKey, err)
 	}
 
 	return &bugTable{
 		repo:         c,
 		query:        q,
-		queryStr:     defaultQuery,
+		queryStr:     queryStr,
 		pageCursor:   0,
 		selectCursor: 0,
-	}
+	}, nil
 }
 
 func (bt *bugTable) layout(g *gocui.Gui) error {
@@ -200,6 +213,12 @@ func (bt *bugTable) keybindings(g *gocui.Gui) error {
 		return err
 	}
 
+	// Watch
+	if err := g.SetKeybinding(bugTableView, 'w', gocui.ModNone,
+		bt.toggleWatch); err != nil {
+		return err
+	}
+
 	// Pull
 	if err := g.SetKeybinding(bugTableView, 'i', gocui.ModNone,
 		bt.pull); err != nil {
@@ -301,6 +320,11 @@ func (bt *bugTable) getColumnWidths(maxX int) map[string]int {
 func (bt *bugTable) render(v *gocui.View, maxX int) {
 	columnWidths := bt.getColumnWidths(maxX)
 
+	user, err := bt.repo.GetUserIdentity()
+	if err != nil {
+		panic(err)
+	}
+
 	for _, excerpt := range bt.excerpts {
 		summaryTxt := fmt.Sprintf("%3d", excerpt.LenComments-1)
 		if excerpt.LenComments-1 <= 0 {
@@ -324,17 +348,27 @@ func (bt *bugTable) render(v *gocui.View, maxX int) {
 			panic(err)
 		}
 
+		unread, err := bt.repo.Watches().HasUnread(user.Id(), excerpt.Id())
+		if err != nil {
+			panic(err)
+		}
+		unreadTxt := "  "
+		if unread {
+			unreadTxt = colors.Green("● ")
+		}
+
 		id := text.LeftPadMaxLine(excerpt.Id().Human(), columnWidths["id"], 0)
 		status := text.LeftPadMaxLine(excerpt.Status.String(), columnWidths["status"], 0)
-		labels := text.TruncateMax(labelsTxt.String(), minInt(columnWidths["title"]-2, 10))
-		title := text.LeftPadMaxLine(strings.TrimSpace(excerpt.Title), columnWidths["title"]-text.Len(labels), 0)
+		labels := text.TruncateMax(labelsTxt.String(), minInt(columnWidths["title"]-4, 10))
+		title := text.LeftPadMaxLine(strings.TrimSpace(excerpt.Title), columnWidths["title"]-text.Len(labels)-2, 0)
 		authorTxt := text.LeftPadMaxLine(author.DisplayName(), columnWidths["author"], 0)
 		comments := text.LeftPadMaxLine(summaryTxt, columnWidths["comments"], 0)
 		lastEdit := text.LeftPadMaxLine(humanize.Time(excerpt.EditTime()), columnWidths["lastEdit"], 1)
 
-		_, _ = fmt.Fprintf(v, "%s %s %s%s %s %s %s\n",
+		_, _ = fmt.Fprintf(v, "%s %s %s%s%s %s %s %s\n",
 			colors.Cyan(id),
 			colors.Yellow(status),
+			unreadTxt,
 			title,
 			labels,
 			colors.Magenta(authorTxt),
@@ -456,10 +490,47 @@ func (bt *bugTable) openBug(g *gocui.Gui, v *gocui.View) error {
 	if err != nil {
 		return err
 	}
+	if err := markSeen(bt.repo, id); err != nil {
+		return err
+	}
 	ui.showBug.SetBug(b)
 	return ui.activateWindow(ui.showBug)
 }
 
+func (bt *bugTable) toggleWatch(g *gocui.Gui, v *gocui.View) error {
+	if len(bt.excerpts) == 0 {
+		return nil
+	}
+	id := bt.excerpts[bt.selectCursor].Id()
+
+	user, err := bt.repo.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	watching, err := bt.repo.Watches().IsWatching(user.Id(), id)
+	if err != nil {
+		return err
+	}
+	if watching {
+		err = bt.repo.Watches().Unwatch(user.Id(), id)
+		ui.msgPopup.Activate("Watch", "Stopped watching bug "+id.Human())
+	} else {
+		err = bt.repo.Watches().Watch(user.Id(), id)
+		ui.msgPopup.Activate("Watch", "Watching bug "+id.Human())
+	}
+	return err
+}
+
+// markSeen record that the user saw the current state of a bug, if watched
+func markSeen(repo *cache.RepoCache, id entity.Id) error {
+	user, err := repo.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+	return repo.Watches().MarkSeen(user.Id(), id)
+}
+
 func (bt *bugTable) pull(g *gocui.Gui, v *gocui.View) error {
 	ui.msgPopup.Activate("Pull from remote "+defaultRemote, "...")
 
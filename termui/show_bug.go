@@ -456,6 +456,11 @@ func (sb *showBug) saveAndBack(g *gocui.Gui, v *gocui.View) error {
 	if err != nil {
 		return err
 	}
+	// the changes made while showing the bug are seen
+	err = markSeen(sb.cache, sb.bug.Id())
+	if err != nil {
+		return err
+	}
 	err = ui.activateWindow(ui.bugTable)
 	if err != nil {
 		return err
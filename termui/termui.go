@@ -50,10 +50,15 @@ type window interface {
 
 // Run will launch the termUI in the terminal
 func Run(cache *cache.RepoCache) error {
+	bugTable, err := newBugTable(cache)
+	if err != nil {
+		return err
+	}
+
 	ui = &termUI{
 		gError:      make(chan error, 1),
 		cache:       cache,
-		bugTable:    newBugTable(cache),
+		bugTable:    bugTable,
 		showBug:     newShowBug(cache),
 		labelSelect: newLabelSelect(),
 		msgPopup:    newMsgPopup(),
@@ -64,7 +69,7 @@ func Run(cache *cache.RepoCache) error {
 
 	initGui(nil)
 
-	err := <-ui.gError
+	err = <-ui.gError
 
 	type errorStack interface {
 		ErrorStack() string